@@ -44,6 +44,19 @@ func Build() *cobra.Command {
 		rpcHost                 string
 		ipfsHost                string
 		blockDifficulty         int
+		minerThreads            int
+		nodeMode                string
+		chainID                 string
+		bootstrapPeers          []string
+		minPeers                int
+		maxPeers                int
+		mdns                    bool
+		listenAddrs             []string
+		transports              []string
+		secureChannels          []string
+		natPortMap              bool
+		relayMode               string
+		relays                  []string
 
 		eosURL         string
 		eosWifPrivKey  string
@@ -146,6 +159,28 @@ For more info visit: https://github.com/c3systems/c3-go,
 				pem = cnf.PrivateKeyPath()
 				peer = cnf.Peer()
 				blockDifficulty = cnf.BlockDifficulty()
+				minerThreads = cnf.MinerThreads()
+				nodeMode = cnf.Mode()
+				chainID = cnf.ChainID()
+				bootstrapPeers = cnf.BootstrapPeers()
+				minPeers = cnf.MinPeers()
+				maxPeers = cnf.MaxPeers()
+				mdns = cnf.MDNS()
+				listenAddrs = cnf.ListenAddrs()
+				transports = cnf.Transports()
+				secureChannels = cnf.SecureChannels()
+				natPortMap = cnf.NATPortMap()
+				relayMode = cnf.RelayMode()
+				relays = cnf.Relays()
+			}
+
+			mode, err := nodetypes.ParseMode(nodeMode)
+			if err != nil {
+				return errw(err)
+			}
+			relay, err := nodetypes.ParseRelayMode(relayMode)
+			if err != nil {
+				return errw(err)
 			}
 
 			if _, err := os.Stat(pem); os.IsNotExist(err) {
@@ -175,14 +210,27 @@ For more info visit: https://github.com/c3systems/c3-go,
 			}
 
 			n, err := node.NewFullNode(&nodetypes.Config{
-				URI:     nodeURI,
-				Peer:    peer,
-				DataDir: dataDir,
+				URI:            nodeURI,
+				ListenAddrs:    listenAddrs,
+				Transports:     transports,
+				SecureChannels: secureChannels,
+				NATPortMap:     natPortMap,
+				RelayMode:      relay,
+				Relays:         relays,
+				Peer:           peer,
+				DataDir:        dataDir,
 				Keys: nodetypes.Keys{
 					PEMFile:  pem,
 					Password: password,
 				},
 				BlockDifficulty: blockDifficulty,
+				MinerThreads:    minerThreads,
+				Mode:            mode,
+				ChainID:         chainID,
+				BootstrapPeers:  bootstrapPeers,
+				MinPeers:        minPeers,
+				MaxPeers:        maxPeers,
+				MDNS:            mdns,
 				MempoolType:     mempoolType,
 				RPCHost:         rpcHost,
 				EOSClient:       eosClient,
@@ -212,6 +260,12 @@ For more info visit: https://github.com/c3systems/c3-go,
 
 	startSubCmd.Flags().StringVarP(&configPath, "config", "c", "", "filepath of the config file to use [OPTIONAL]")
 	startSubCmd.Flags().StringVarP(&nodeURI, "uri", "u", "/ip4/0.0.0.0/tcp/9000", "The host on which to run the node")
+	startSubCmd.Flags().StringSliceVar(&listenAddrs, "listen", cnf.ListenAddrs(), "Comma separated multiaddrs to listen on in addition to --uri, e.g. /ip4/0.0.0.0/tcp/9001/ws for the ws transport [OPTIONAL]")
+	startSubCmd.Flags().StringSliceVar(&transports, "transports", cnf.Transports(), "Comma separated libp2p transports to dial and listen with (tcp, ws) [OPTIONAL]")
+	startSubCmd.Flags().StringSliceVar(&secureChannels, "secure-channels", cnf.SecureChannels(), "Comma separated libp2p secure channels to negotiate, in order of preference (secio, tls) [OPTIONAL]")
+	startSubCmd.Flags().BoolVar(&natPortMap, "nat-port-map", cnf.NATPortMap(), "Map the listen ports on the NAT device with UPnP or NAT-PMP [OPTIONAL]")
+	startSubCmd.Flags().StringVar(&relayMode, "relay-mode", cnf.RelayMode(), "The circuit relay mode: off, client (dial and be dialed through relays) or server (also relay for others) [OPTIONAL]")
+	startSubCmd.Flags().StringSliceVar(&relays, "relays", cnf.Relays(), "Comma separated ipfs multiaddrs of relays to be reachable through while behind a NAT; requires --relay-mode client or server [OPTIONAL]")
 	startSubCmd.Flags().StringVarP(&peer, "peer", "p", cnf.Peer(), "A peer to which to connect")
 	startSubCmd.Flags().StringVarP(&dataDir, "data-dir", "d", cnf.DataDir(), "The directory in which to save data")
 	startSubCmd.Flags().StringVar(&pem, "pem", cnf.PrivateKeyPath(), "A pem file containing an ecdsa private key")
@@ -219,6 +273,13 @@ For more info visit: https://github.com/c3systems/c3-go,
 	startSubCmd.Flags().StringVar(&mempoolType, "mempool-type", "memory", "The mempool type to use (memory, redis) [OPTIONAL]")
 	startSubCmd.Flags().StringVarP(&rpcHost, "rpc", "", "0.0.0.0:5005", "The port to run rpc on")
 	startSubCmd.Flags().IntVar(&blockDifficulty, "difficulty", cnf.BlockDifficulty(), "The hashing difficulty for mining blocks. (1-15) [OPTIONAL]. This feature will be deprecated when C3 soon moves to Delegated Proof-of-Stake.")
+	startSubCmd.Flags().StringVar(&nodeMode, "mode", cnf.Mode(), "The node mode: miner (validates and mines blocks), full (validates blocks without mining) or light (follows block headers and verifies their merkle proofs without running dApps) [OPTIONAL]")
+	startSubCmd.Flags().StringSliceVar(&bootstrapPeers, "bootstrap-peers", cnf.BootstrapPeers(), "Comma separated peers to connect to on start, in addition to --peer [OPTIONAL]")
+	startSubCmd.Flags().IntVar(&minPeers, "min-peers", cnf.MinPeers(), "The number of connections below which the node dials known peers [OPTIONAL]")
+	startSubCmd.Flags().IntVar(&maxPeers, "max-peers", cnf.MaxPeers(), "The number of connections above which the node prunes connections [OPTIONAL]")
+	startSubCmd.Flags().BoolVar(&mdns, "mdns", cnf.MDNS(), "Discover and connect to peers on the local network [OPTIONAL]")
+	startSubCmd.Flags().StringVar(&chainID, "chain-id", cnf.ChainID(), "The chain to join. Nodes only exchange blocks and transactions with nodes of the same chain [OPTIONAL]")
+	startSubCmd.Flags().IntVar(&minerThreads, "miner-threads", cnf.MinerThreads(), "The number of proof-of-work hashing threads. Defaults to the number of CPUs [OPTIONAL]")
 
 	startSubCmd.Flags().StringVarP(&eosURL, "checkpoint-eos-url", "", "", "EOS block producer URL for checkpointing")
 	startSubCmd.Flags().StringVarP(&eosWifPrivKey, "checkpoint-eos-wif-private-key", "", "", "EOS private key for EOS account that will be used for checkpointing")
@@ -5,10 +5,13 @@ import (
 
 	"github.com/c3systems/c3-go/common/netutil"
 	"github.com/c3systems/c3-go/config"
+	pb "github.com/c3systems/c3-go/rpc/pb"
 	"github.com/spf13/cobra"
 )
 
 func peerCmd() *cobra.Command {
+	var rpcHost string
+
 	peercmd := &cobra.Command{
 		Use:   "peer",
 		Short: "Peer command",
@@ -45,7 +48,62 @@ func peerCmd() *cobra.Command {
 		},
 	}
 
-	peercmd.AddCommand(peerIDCmd)
+	peerListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List peers",
+		Long:  "Lists the peers known to a running node, and whether they're connected",
+		Args: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp := new(pb.PeersResponse)
+			if err := rpcCall(rpcHost, "c3_listPeers", nil, resp); err != nil {
+				return errw(err)
+			}
+
+			for _, p := range resp.Peers {
+				status := "disconnected"
+				if p.Connected {
+					status = "connected"
+				}
+				if p.Bootstrap {
+					status += ", bootstrap"
+				}
+
+				fmt.Printf("%s (%s)\n", p.Id, status)
+				for _, addr := range p.Addrs {
+					fmt.Printf("  %s\n", addr)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	peerConnectCmd := &cobra.Command{
+		Use:   "connect [PEER ADDRESS]",
+		Short: "Connect to a peer",
+		Long:  "Connects a running node to the peer at the address, e.g. /ip4/127.0.0.1/tcp/9000/ipfs/Qm...",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errw(ErrOnlyOneArgumentRequired)
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp := new(pb.ConnectPeerResponse)
+			if err := rpcCall(rpcHost, "c3_connectPeer", args, resp); err != nil {
+				return errw(err)
+			}
+
+			fmt.Printf("connected to %s\n", resp.Id)
+			return nil
+		},
+	}
+
+	peercmd.PersistentFlags().StringVar(&rpcHost, "rpc", DefaultRPCHost, "The rpc host of the running node")
+	peercmd.AddCommand(peerIDCmd, peerListCmd, peerConnectCmd)
 
 	return peercmd
 }
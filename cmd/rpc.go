@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "github.com/c3systems/c3-go/rpc/pb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc"
+)
+
+// DefaultRPCHost is the rpc server of a local node
+const DefaultRPCHost = "localhost:5005"
+
+const rpcTimeout = 30 * time.Second
+
+// rpcCall calls the method on the node's rpc server, unpacking the result into resp
+func rpcCall(host, method string, params []string, resp proto.Message) error {
+	conn, err := grpc.Dial(host, grpc.WithInsecure())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+
+	res, err := pb.NewC3ServiceClient(conn).Send(ctx, &pb.Request{
+		Jsonrpc: "2.0",
+		Id:      1,
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	errResp := new(pb.ErrorResponse)
+	if ptypes.Is(res.Result, errResp) {
+		if err := ptypes.UnmarshalAny(res.Result, errResp); err != nil {
+			return err
+		}
+
+		return errors.New(errResp.Message)
+	}
+
+	return ptypes.UnmarshalAny(res.Result, resp)
+}
@@ -45,10 +45,13 @@ func snapshotCmd() *cobra.Command {
 				return errw(err)
 			}
 
-			svc := snapshot.New(&snapshot.Config{
+			svc, err := snapshot.New(&snapshot.Config{
 				P2P:     n.Props().P2P,
 				Mempool: n.Props().Store,
 			})
+			if err != nil {
+				return errw(err)
+			}
 
 			snapshotImageID, err := svc.Snapshot(image, stateBlockNumber)
 			if err != nil {
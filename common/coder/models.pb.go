@@ -245,6 +245,7 @@ type StatechainBlock struct {
 	PrevBlockHash     string `protobuf:"bytes,6,opt,name=prevBlockHash,proto3" json:"prevBlockHash,omitempty"`
 	StatePrevDiffHash string `protobuf:"bytes,7,opt,name=statePrevDiffHash,proto3" json:"statePrevDiffHash,omitempty"`
 	StateCurrentHash  string `protobuf:"bytes,8,opt,name=stateCurrentHash,proto3" json:"stateCurrentHash,omitempty"`
+	StateSnapshotCID  string `protobuf:"bytes,9,opt,name=stateSnapshotCID,proto3" json:"stateSnapshotCID,omitempty"`
 }
 
 func (m *StatechainBlock) Reset()                    { *m = StatechainBlock{} }
@@ -308,6 +309,13 @@ func (m *StatechainBlock) GetStateCurrentHash() string {
 	return ""
 }
 
+func (m *StatechainBlock) GetStateSnapshotCID() string {
+	if m != nil {
+		return m.StateSnapshotCID
+	}
+	return ""
+}
+
 type TxSig struct {
 	R string `protobuf:"bytes,1,opt,name=r,proto3" json:"r,omitempty"`
 	S string `protobuf:"bytes,2,opt,name=s,proto3" json:"s,omitempty"`
@@ -793,6 +801,12 @@ func (m *StatechainBlock) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintModels(dAtA, i, uint64(len(m.StateCurrentHash)))
 		i += copy(dAtA[i:], m.StateCurrentHash)
 	}
+	if len(m.StateSnapshotCID) > 0 {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintModels(dAtA, i, uint64(len(m.StateSnapshotCID)))
+		i += copy(dAtA[i:], m.StateSnapshotCID)
+	}
 	return i, nil
 }
 
@@ -1104,6 +1118,10 @@ func (m *StatechainBlock) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovModels(uint64(l))
 	}
+	l = len(m.StateSnapshotCID)
+	if l > 0 {
+		n += 1 + l + sovModels(uint64(l))
+	}
 	return n
 }
 
@@ -2636,6 +2654,35 @@ func (m *StatechainBlock) Unmarshal(dAtA []byte) error {
 			}
 			m.StateCurrentHash = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateSnapshotCID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModels
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModels
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StateSnapshotCID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipModels(dAtA[iNdEx:])
@@ -3211,50 +3258,51 @@ var (
 func init() { proto.RegisterFile("models.proto", fileDescriptorModels) }
 
 var fileDescriptorModels = []byte{
-	// 710 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x95, 0x4b, 0x6e, 0xdb, 0x48,
-	0x10, 0x86, 0x41, 0xbd, 0x2c, 0x95, 0xe4, 0x57, 0xf9, 0x01, 0xc2, 0x18, 0x68, 0x3c, 0x9c, 0x19,
-	0xc7, 0x70, 0x0c, 0x2d, 0xec, 0x20, 0x08, 0xe2, 0x55, 0x9c, 0x04, 0xf0, 0x46, 0x81, 0x41, 0x0b,
-	0x59, 0x05, 0x01, 0xda, 0x62, 0xdb, 0x22, 0x24, 0xb2, 0x85, 0x6e, 0xca, 0xb0, 0xae, 0x91, 0x3b,
-	0x64, 0x91, 0x9b, 0x64, 0x99, 0x23, 0x04, 0xbe, 0x40, 0xae, 0x10, 0x74, 0xb1, 0x45, 0x35, 0x25,
-	0xe6, 0xb1, 0xcb, 0xae, 0xab, 0xea, 0xaf, 0x8f, 0xea, 0xbf, 0x58, 0x14, 0xb4, 0x22, 0x11, 0xf0,
-	0x91, 0xea, 0x8c, 0xa5, 0x48, 0x04, 0x56, 0xfb, 0x22, 0xe0, 0xd2, 0xfb, 0x50, 0x03, 0xe8, 0x86,
-	0x31, 0x0f, 0xce, 0x47, 0xa2, 0x3f, 0xc4, 0x53, 0x68, 0xc4, 0xfc, 0x3e, 0xa1, 0xc0, 0x75, 0xf6,
-	0x9d, 0xc3, 0xe6, 0xc9, 0x4e, 0x87, 0x94, 0x9d, 0x2e, 0x0b, 0xe3, 0xfe, 0x80, 0x85, 0x31, 0x15,
-	0xfd, 0xb9, 0x0e, 0xcf, 0x60, 0x75, 0x2c, 0xf9, 0x5d, 0x28, 0x26, 0x2a, 0x6d, 0x2c, 0xfd, 0xac,
-	0x31, 0xaf, 0xc5, 0x77, 0xb0, 0xa5, 0x12, 0x96, 0xf0, 0xb9, 0x42, 0x75, 0xd9, 0xd8, 0x2d, 0xef,
-	0x97, 0x0f, 0x9b, 0x27, 0x47, 0x33, 0x44, 0xf6, 0x0b, 0x3b, 0x57, 0xcb, 0xe2, 0xd7, 0x71, 0x22,
-	0xa7, 0x7e, 0x11, 0x06, 0x2f, 0x61, 0x3d, 0x91, 0x2c, 0x56, 0xac, 0x9f, 0x84, 0x22, 0x26, 0x72,
-	0x85, 0xc8, 0x07, 0xcb, 0xe4, 0x5e, 0x5e, 0x98, 0x52, 0x17, 0xdb, 0xf1, 0x0c, 0xea, 0x41, 0x78,
-	0x73, 0x43, 0xa8, 0x2a, 0xa1, 0xfe, 0x5e, 0x46, 0xbd, 0x32, 0x8a, 0x94, 0x91, 0x35, 0x60, 0x17,
-	0xd6, 0x22, 0x2e, 0x87, 0x23, 0xde, 0x93, 0x9c, 0x13, 0xa2, 0x46, 0x88, 0xff, 0x97, 0x11, 0xdd,
-	0x9c, 0x2e, 0x05, 0x2d, 0x34, 0xef, 0xbd, 0x07, 0xf7, 0x47, 0x76, 0xe0, 0x06, 0x94, 0x87, 0x7c,
-	0x4a, 0x33, 0x6c, 0xf8, 0xfa, 0x88, 0xc7, 0x50, 0xbd, 0x63, 0xa3, 0x09, 0x37, 0xe3, 0xd9, 0x35,
-	0xcf, 0x5c, 0x20, 0xf8, 0xa9, 0xe8, 0x79, 0xe9, 0x99, 0xb3, 0xf7, 0x16, 0xb6, 0x8b, 0x4c, 0x29,
-	0x60, 0x1f, 0xe6, 0xd9, 0x68, 0xd8, 0x56, 0xb7, 0xcd, 0xbd, 0x80, 0xd5, 0x9c, 0x43, 0x05, 0xc0,
-	0x7f, 0xf2, 0xc0, 0xa6, 0x01, 0xea, 0x36, 0x9b, 0xd4, 0x83, 0xad, 0x02, 0xa3, 0x0a, 0x78, 0x8f,
-	0xf2, 0xbc, 0xcd, 0x99, 0xe1, 0x59, 0xb3, 0x45, 0xf5, 0x06, 0x00, 0xf3, 0x02, 0x76, 0x00, 0xe7,
-	0xbe, 0xfb, 0x42, 0x24, 0x17, 0x4c, 0x0d, 0x0c, 0xbb, 0xa0, 0x82, 0x08, 0x95, 0x61, 0x18, 0x07,
-	0xf4, 0xa4, 0x86, 0x4f, 0x67, 0xdc, 0x85, 0xda, 0x80, 0xa9, 0x01, 0x57, 0xf4, 0x62, 0x37, 0x7c,
-	0x13, 0x79, 0xdf, 0x4a, 0xb0, 0x96, 0xdf, 0x0f, 0xfc, 0x0b, 0x1a, 0xd7, 0xfa, 0x60, 0x3d, 0x65,
-	0x9e, 0xc0, 0x7d, 0x68, 0x52, 0xf0, 0x66, 0x12, 0x5d, 0x73, 0x69, 0x9e, 0x61, 0xa7, 0xb2, 0xfe,
-	0x5e, 0x18, 0x71, 0xb7, 0x6c, 0xf5, 0xeb, 0x84, 0xae, 0x86, 0x11, 0xbb, 0xe5, 0x44, 0xaf, 0xa4,
-	0xd5, 0x2c, 0x81, 0x4f, 0x60, 0x87, 0xb6, 0xc8, 0xbc, 0x4b, 0x74, 0x37, 0x52, 0x56, 0x49, 0x59,
-	0x5c, 0xc4, 0xff, 0xd2, 0xfd, 0x3f, 0xcf, 0x7e, 0x75, 0x8d, 0xd4, 0xf9, 0x24, 0x6e, 0x43, 0x35,
-	0x16, 0x71, 0x9f, 0xbb, 0x2b, 0x54, 0x4d, 0x03, 0x6c, 0x03, 0xe8, 0xed, 0x08, 0xfb, 0x93, 0x51,
-	0x32, 0x75, 0xeb, 0x54, 0xb2, 0x32, 0xe8, 0x41, 0x2b, 0x0a, 0x63, 0x2e, 0x5f, 0x04, 0x81, 0xe4,
-	0x4a, 0xb9, 0x0d, 0x52, 0xe4, 0x72, 0xf8, 0x18, 0xea, 0x14, 0x5f, 0x85, 0xb7, 0x2e, 0xd0, 0x78,
-	0xd7, 0xad, 0x7d, 0xd2, 0x69, 0x3f, 0x13, 0x78, 0x07, 0x50, 0x9f, 0x65, 0xb1, 0x05, 0x8e, 0x34,
-	0x16, 0x3b, 0x52, 0x47, 0xca, 0x18, 0xea, 0x28, 0xef, 0x63, 0x09, 0xd6, 0x17, 0x56, 0xe3, 0x8f,
-	0x8e, 0x66, 0x17, 0x6a, 0xc9, 0xbd, 0x35, 0x0b, 0x13, 0xfd, 0xa6, 0xf9, 0xc7, 0xb0, 0x49, 0xb3,
-	0xbb, 0x94, 0xfc, 0x4e, 0xef, 0x10, 0x29, 0xd3, 0x41, 0x2c, 0x17, 0xf0, 0x08, 0x36, 0x28, 0xf9,
-	0x72, 0x22, 0x25, 0x8f, 0xd3, 0xf7, 0x3d, 0x1d, 0xcd, 0x52, 0xde, 0xfb, 0x17, 0xaa, 0xbd, 0xfb,
-	0x5f, 0x99, 0xf9, 0xc9, 0x81, 0xa6, 0xf5, 0x2d, 0xb0, 0x2e, 0xe3, 0xe4, 0x2e, 0x93, 0xb3, 0xa0,
-	0x54, 0x60, 0x41, 0xc4, 0x93, 0x81, 0x08, 0x8c, 0x77, 0x26, 0x42, 0x17, 0x56, 0xc6, 0x6c, 0x3a,
-	0x12, 0x2c, 0x20, 0xdb, 0x5a, 0xfe, 0x2c, 0xd4, 0xab, 0x78, 0x23, 0x45, 0x64, 0x2c, 0xa3, 0x33,
-	0xb6, 0xa1, 0xac, 0xc2, 0x5b, 0xb2, 0xa9, 0x79, 0xd2, 0x9a, 0x7d, 0xa8, 0xf4, 0x15, 0x7c, 0x5d,
-	0xf0, 0x9e, 0x42, 0x45, 0x1b, 0x81, 0x7b, 0xe9, 0x87, 0xde, 0xfa, 0x95, 0x59, 0xac, 0xb9, 0x01,
-	0x4b, 0xd8, 0x6c, 0xc5, 0xf5, 0xf9, 0xbc, 0xf5, 0xf9, 0xa1, 0xed, 0x7c, 0x79, 0x68, 0x3b, 0x5f,
-	0x1f, 0xda, 0xce, 0x75, 0x8d, 0xfe, 0x65, 0x4f, 0xbf, 0x07, 0x00, 0x00, 0xff, 0xff, 0x5f, 0xf7,
-	0x2a, 0xf4, 0x75, 0x07, 0x00, 0x00,
+	// 721 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x95, 0xdb, 0x6e, 0xd3, 0x4c,
+	0x10, 0xc7, 0xe5, 0x9c, 0x9a, 0x4c, 0xd2, 0xd3, 0xf4, 0x20, 0xab, 0xfa, 0x94, 0xaf, 0x9f, 0x3f,
+	0x28, 0x55, 0xa9, 0x72, 0xd1, 0x22, 0x84, 0xe8, 0x15, 0x6d, 0x91, 0xca, 0x45, 0x50, 0xe5, 0x46,
+	0x5c, 0x21, 0xa4, 0x6d, 0xbc, 0x6d, 0xac, 0xc4, 0xde, 0x68, 0xd7, 0xa9, 0x9a, 0xd7, 0xe0, 0x2d,
+	0x78, 0x13, 0x2e, 0xe1, 0x0d, 0x50, 0x5f, 0x80, 0x57, 0x40, 0x3b, 0xde, 0x38, 0x76, 0x6c, 0x0e,
+	0x77, 0xdc, 0xed, 0xcc, 0xfc, 0xe7, 0x67, 0xfb, 0x3f, 0x1e, 0x1b, 0x5a, 0x81, 0xf0, 0xf8, 0x48,
+	0x75, 0xc6, 0x52, 0x44, 0x02, 0xab, 0x7d, 0xe1, 0x71, 0xe9, 0x7c, 0xac, 0x01, 0x74, 0xfd, 0x90,
+	0x7b, 0xa7, 0x23, 0xd1, 0x1f, 0xe2, 0x31, 0x34, 0x42, 0x7e, 0x1f, 0x51, 0x60, 0x5b, 0xbb, 0xd6,
+	0x7e, 0xf3, 0x68, 0xab, 0x43, 0xca, 0x4e, 0x97, 0xf9, 0x61, 0x7f, 0xc0, 0xfc, 0x90, 0x8a, 0xee,
+	0x5c, 0x87, 0x27, 0xb0, 0x3c, 0x96, 0xfc, 0xce, 0x17, 0x13, 0x15, 0x37, 0x96, 0x7e, 0xd5, 0x98,
+	0xd5, 0xe2, 0x7b, 0xd8, 0x50, 0x11, 0x8b, 0xf8, 0x5c, 0xa1, 0xba, 0x6c, 0x6c, 0x97, 0x77, 0xcb,
+	0xfb, 0xcd, 0xa3, 0x83, 0x19, 0x22, 0xb9, 0xc3, 0xce, 0x55, 0x5e, 0xfc, 0x3a, 0x8c, 0xe4, 0xd4,
+	0x2d, 0xc2, 0xe0, 0x25, 0xac, 0x46, 0x92, 0x85, 0x8a, 0xf5, 0x23, 0x5f, 0x84, 0x44, 0xae, 0x10,
+	0x79, 0x2f, 0x4f, 0xee, 0x65, 0x85, 0x31, 0x75, 0xb1, 0x1d, 0x4f, 0xa0, 0xee, 0xf9, 0x37, 0x37,
+	0x84, 0xaa, 0x12, 0xea, 0xdf, 0x3c, 0xea, 0xdc, 0x28, 0x62, 0x46, 0xd2, 0x80, 0x5d, 0x58, 0x09,
+	0xb8, 0x1c, 0x8e, 0x78, 0x4f, 0x72, 0x4e, 0x88, 0x1a, 0x21, 0x1e, 0xe7, 0x11, 0xdd, 0x8c, 0x2e,
+	0x06, 0x2d, 0x34, 0xef, 0x7c, 0x00, 0xfb, 0x67, 0x76, 0xe0, 0x1a, 0x94, 0x87, 0x7c, 0x4a, 0x33,
+	0x6c, 0xb8, 0xfa, 0x88, 0x87, 0x50, 0xbd, 0x63, 0xa3, 0x09, 0x37, 0xe3, 0xd9, 0x36, 0xd7, 0x5c,
+	0x20, 0xb8, 0xb1, 0xe8, 0x65, 0xe9, 0x85, 0xb5, 0xf3, 0x0e, 0x36, 0x8b, 0x4c, 0x29, 0x60, 0xef,
+	0x67, 0xd9, 0x68, 0xd8, 0xa9, 0xee, 0x34, 0xf7, 0x02, 0x96, 0x33, 0x0e, 0x15, 0x00, 0xff, 0xcb,
+	0x02, 0x9b, 0x06, 0xa8, 0xdb, 0xd2, 0xa4, 0x1e, 0x6c, 0x14, 0x18, 0x55, 0xc0, 0x7b, 0x92, 0xe5,
+	0xad, 0xcf, 0x0c, 0x4f, 0x9a, 0x53, 0x54, 0x67, 0x00, 0x30, 0x2f, 0x60, 0x07, 0x70, 0xee, 0xbb,
+	0x2b, 0x44, 0x74, 0xc1, 0xd4, 0xc0, 0xb0, 0x0b, 0x2a, 0x88, 0x50, 0x19, 0xfa, 0xa1, 0x47, 0x57,
+	0x6a, 0xb8, 0x74, 0xc6, 0x6d, 0xa8, 0x0d, 0x98, 0x1a, 0x70, 0x45, 0x2f, 0x76, 0xc3, 0x35, 0x91,
+	0xf3, 0xbd, 0x04, 0x2b, 0xd9, 0xfd, 0xc0, 0x7f, 0xa0, 0x71, 0xad, 0x0f, 0xa9, 0xab, 0xcc, 0x13,
+	0xb8, 0x0b, 0x4d, 0x0a, 0xde, 0x4e, 0x82, 0x6b, 0x2e, 0xcd, 0x35, 0xd2, 0xa9, 0xa4, 0xbf, 0xe7,
+	0x07, 0xdc, 0x2e, 0xa7, 0xfa, 0x75, 0x42, 0x57, 0xfd, 0x80, 0xdd, 0x72, 0xa2, 0x57, 0xe2, 0x6a,
+	0x92, 0xc0, 0x67, 0xb0, 0x45, 0x5b, 0x64, 0xde, 0x25, 0x7a, 0x36, 0x52, 0x56, 0x49, 0x59, 0x5c,
+	0xc4, 0x47, 0xf1, 0xfe, 0x9f, 0x26, 0x77, 0x5d, 0x23, 0x75, 0x36, 0x89, 0x9b, 0x50, 0x0d, 0x45,
+	0xd8, 0xe7, 0xf6, 0x12, 0x55, 0xe3, 0x00, 0xdb, 0x00, 0x7a, 0x3b, 0xfc, 0xfe, 0x64, 0x14, 0x4d,
+	0xed, 0x3a, 0x95, 0x52, 0x19, 0x74, 0xa0, 0x15, 0xf8, 0x21, 0x97, 0xaf, 0x3c, 0x4f, 0x72, 0xa5,
+	0xec, 0x06, 0x29, 0x32, 0x39, 0x7c, 0x0a, 0x75, 0x8a, 0xaf, 0xfc, 0x5b, 0x1b, 0x68, 0xbc, 0xab,
+	0xa9, 0x7d, 0xd2, 0x69, 0x37, 0x11, 0x38, 0x7b, 0x50, 0x9f, 0x65, 0xb1, 0x05, 0x96, 0x34, 0x16,
+	0x5b, 0x52, 0x47, 0xca, 0x18, 0x6a, 0x29, 0xe7, 0x6b, 0x09, 0x56, 0x17, 0x56, 0xe3, 0xaf, 0x8e,
+	0x66, 0x1b, 0x6a, 0xd1, 0x7d, 0x6a, 0x16, 0x26, 0xfa, 0x43, 0xf3, 0x0f, 0x61, 0x9d, 0x66, 0x77,
+	0x29, 0xf9, 0x9d, 0xde, 0x21, 0x52, 0xc6, 0x83, 0xc8, 0x17, 0xf0, 0x00, 0xd6, 0x28, 0x79, 0x36,
+	0x91, 0x92, 0x87, 0xf1, 0xfb, 0x1e, 0x8f, 0x26, 0x97, 0x4f, 0xb4, 0x57, 0x21, 0x1b, 0xab, 0x81,
+	0x88, 0xce, 0xde, 0x9c, 0x9b, 0x21, 0xe5, 0xf2, 0xce, 0xff, 0x50, 0xed, 0xdd, 0xff, 0xce, 0xf8,
+	0x4f, 0x16, 0x34, 0x53, 0xdf, 0x8d, 0xd4, 0x83, 0x5b, 0x99, 0x07, 0xcf, 0xd8, 0x55, 0x2a, 0xb0,
+	0x2b, 0xe0, 0xd1, 0x40, 0x78, 0xc6, 0x67, 0x13, 0xa1, 0x0d, 0x4b, 0x63, 0x36, 0x1d, 0x09, 0xe6,
+	0x91, 0xc5, 0x2d, 0x77, 0x16, 0xea, 0xb5, 0xbd, 0x91, 0x22, 0x30, 0xf6, 0xd2, 0x19, 0xdb, 0x50,
+	0x56, 0xfe, 0x2d, 0x59, 0xda, 0x3c, 0x6a, 0xcd, 0x3e, 0x6a, 0xfa, 0x11, 0x5c, 0x5d, 0x70, 0x9e,
+	0x43, 0x45, 0x9b, 0x86, 0x3b, 0xf1, 0x4f, 0x21, 0x75, 0x97, 0x49, 0xac, 0xb9, 0x1e, 0x8b, 0xd8,
+	0xec, 0x73, 0xa0, 0xcf, 0xa7, 0xad, 0xcf, 0x0f, 0x6d, 0xeb, 0xcb, 0x43, 0xdb, 0xfa, 0xf6, 0xd0,
+	0xb6, 0xae, 0x6b, 0xf4, 0x47, 0x3e, 0xfe, 0x31, 0x00, 0x8c, 0x04, 0x6b, 0x1d, 0xa1, 0x07, 0x00,
+	0x00,
 }
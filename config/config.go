@@ -18,13 +18,26 @@ var fileperm = os.FileMode(0644)
 
 // NOTE: properties must be uppercase (exported) to save as TOML
 type config struct {
-	Port            int    `toml:"port"`
-	DataDir         string `toml:"dataDir"`
-	PrivateKeyPath  string `toml:"privateKey"`
-	Peer            string `toml:"peer"`
-	BlockDifficulty int    `toml:"blockDifficulty"`
-	configDir       string `toml:"-"` // NOTE: don't save to TOML
-	configFilename  string `toml:"-"` // NOTE: don't save to TOML
+	Port            int      `toml:"port"`
+	DataDir         string   `toml:"dataDir"`
+	PrivateKeyPath  string   `toml:"privateKey"`
+	Peer            string   `toml:"peer"`
+	BlockDifficulty int      `toml:"blockDifficulty"`
+	MinerThreads    int      `toml:"minerThreads"`
+	Mode            string   `toml:"mode"`
+	ChainID         string   `toml:"chainID"`
+	BootstrapPeers  []string `toml:"bootstrapPeers"`
+	MinPeers        int      `toml:"minPeers"`
+	MaxPeers        int      `toml:"maxPeers"`
+	DisableMDNS     bool     `toml:"disableMDNS"`
+	ListenAddrs     []string `toml:"listenAddrs"`
+	Transports      []string `toml:"transports"`
+	SecureChannels  []string `toml:"secureChannels"`
+	NATPortMap      bool     `toml:"natPortMap"`
+	RelayMode       string   `toml:"relayMode"`
+	Relays          []string `toml:"relays"`
+	configDir       string   `toml:"-"` // NOTE: don't save to TOML
+	configFilename  string   `toml:"-"` // NOTE: don't save to TOML
 }
 
 // Config ...
@@ -44,6 +57,11 @@ func New() *Config {
 			PrivateKeyPath:  DefaultConfigDirectory + "/" + DefaultPrivateKeyFilename,
 			Peer:            "",
 			BlockDifficulty: DefaultBlockDifficulty,
+			Mode:            DefaultNodeMode,
+			ChainID:         DefaultChainID,
+			MinPeers:        DefaultMinPeers,
+			MaxPeers:        DefaultMaxPeers,
+			RelayMode:       DefaultRelayMode,
 		},
 	}
 	if err := cnf.setupConfig(); err != nil {
@@ -75,6 +93,11 @@ func NewFromFile(filepath string) *Config {
 			PrivateKeyPath:  DefaultConfigDirectory + "/" + DefaultPrivateKeyFilename,
 			Peer:            "",
 			BlockDifficulty: DefaultBlockDifficulty,
+			Mode:            DefaultNodeMode,
+			ChainID:         DefaultChainID,
+			MinPeers:        DefaultMinPeers,
+			MaxPeers:        DefaultMaxPeers,
+			RelayMode:       DefaultRelayMode,
 		},
 	}
 	if err := cnf.setupConfig(); err != nil {
@@ -123,6 +146,77 @@ func (cnf *Config) BlockDifficulty() int {
 	return cnf.config.BlockDifficulty
 }
 
+// Mode is the node mode: miner, full or light
+func (cnf *Config) Mode() string {
+	return cnf.config.Mode
+}
+
+// MinerThreads is the number of proof-of-work hashing threads; 0 uses every CPU
+func (cnf *Config) MinerThreads() int {
+	return cnf.config.MinerThreads
+}
+
+// ChainID is the chain the node joins
+func (cnf *Config) ChainID() string {
+	return cnf.config.ChainID
+}
+
+// BootstrapPeers are the peers the node connects to on start
+func (cnf *Config) BootstrapPeers() []string {
+	return cnf.config.BootstrapPeers
+}
+
+// MinPeers is the number of connections below which the node dials known peers
+func (cnf *Config) MinPeers() int {
+	return cnf.config.MinPeers
+}
+
+// MaxPeers is the number of connections above which the node prunes connections
+func (cnf *Config) MaxPeers() int {
+	return cnf.config.MaxPeers
+}
+
+// MDNS is whether the node discovers peers on the local network
+func (cnf *Config) MDNS() bool {
+	return !cnf.config.DisableMDNS
+}
+
+// ListenAddrs are the multiaddrs the node listens on in addition to the node uri
+func (cnf *Config) ListenAddrs() []string {
+	return cnf.config.ListenAddrs
+}
+
+// Transports are the libp2p transports the node dials and listens with
+func (cnf *Config) Transports() []string {
+	if len(cnf.config.Transports) == 0 {
+		return DefaultTransports
+	}
+	return cnf.config.Transports
+}
+
+// SecureChannels are the libp2p secure channels the node negotiates, in order of preference
+func (cnf *Config) SecureChannels() []string {
+	if len(cnf.config.SecureChannels) == 0 {
+		return DefaultSecureChannels
+	}
+	return cnf.config.SecureChannels
+}
+
+// NATPortMap is whether the node maps its listen ports on the NAT device
+func (cnf *Config) NATPortMap() bool {
+	return cnf.config.NATPortMap
+}
+
+// RelayMode is how the node takes part in circuit relaying: off, client or server
+func (cnf *Config) RelayMode() string {
+	return cnf.config.RelayMode
+}
+
+// Relays are the relays the node is reachable through while it's behind a NAT
+func (cnf *Config) Relays() []string {
+	return cnf.config.Relays
+}
+
 func (cnf *Config) setupConfig() error {
 	err := cnf.makeConfigDir()
 	if err != nil {
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"runtime"
 	"time"
 )
 
@@ -32,14 +33,57 @@ var TempContainerStateFileName = "state.json"
 // TempContainerStateFilePath ...
 var TempContainerStateFilePath = fmt.Sprintf("%s/%s", TempContainerStatePath, TempContainerStateFileName)
 
+// ContainerStateEnvVar is the env var the dApp reads to find the state file
+const ContainerStateEnvVar = "C3_STATE"
+
+// TempContainerSocketPath is the directory inside of the container where the IPC socket lives
+var TempContainerSocketPath = "/var/run/c3"
+
+// TempContainerSocketFileName ...
+var TempContainerSocketFileName = "c3.sock"
+
+// TempContainerSocketFilePath ...
+var TempContainerSocketFilePath = fmt.Sprintf("%s/%s", TempContainerSocketPath, TempContainerSocketFileName)
+
+// ContainerSocketEnvVar is the env var the dApp reads to find the IPC socket
+const ContainerSocketEnvVar = "C3_SOCKET"
+
 // DockerRegistryPort ...
 const DockerRegistryPort = 5000
 
 // DefaultBlockDifficulty ...
 const DefaultBlockDifficulty = 6
 
+// DefaultNodeMode is the mode nodes start in; every node mines unless configured otherwise
+const DefaultNodeMode = "miner"
+
+// DefaultChainID is the chain the nodes join; nodes only gossip with the nodes of their chain
+const DefaultChainID = "c3-mainnet"
+
+// DefaultMinPeers is the number of connections below which nodes dial known peers
+const DefaultMinPeers = 4
+
+// DefaultMaxPeers is the number of connections above which nodes prune connections
+const DefaultMaxPeers = 50
+
+// DefaultTransports are the libp2p transports nodes dial and listen with
+var DefaultTransports = []string{"tcp"}
+
+// DefaultSecureChannels are the libp2p secure channels nodes negotiate, in order of preference
+var DefaultSecureChannels = []string{"secio"}
+
+// DefaultRelayMode is the circuit relay mode nodes start in; relaying is opt-in
+const DefaultRelayMode = "off"
+
+// StateRootForkHeight is the mainchain height from which state blocks must build on a prev state hashed to its trie root.
+// note: below it, the hash of the serialized state, from before state roots, is accepted too; chains started with state roots never accept it
+var StateRootForkHeight uint64
+
 // MinedBlockVerificationTimeout ...
 const MinedBlockVerificationTimeout = 10 * time.Minute
 
 // IPFSTimeout ...
 const IPFSTimeout = 20 * time.Second
+
+// MaxStateVerificationWorkers is the max number of image hashes whose state blocks are verified concurrently
+var MaxStateVerificationWorkers = runtime.NumCPU()
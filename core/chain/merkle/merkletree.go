@@ -166,7 +166,7 @@ func (t *Tree) CalculateHash() (string, error) {
 	var tmpContent []merkletree.Content
 	log.Printf("[merkle] calculate hash - hashes length: %v", len(t.props.Hashes))
 	for _, str := range t.props.Hashes {
-		tmpContent = append(tmpContent, testContent{
+		tmpContent = append(tmpContent, hashContent{
 			x: str,
 		})
 	}
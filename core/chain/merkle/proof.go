@@ -0,0 +1,154 @@
+package merkle
+
+import (
+	"crypto/sha256"
+
+	"github.com/c3systems/c3-go/common/hexutil"
+)
+
+// ProofStep is a sibling hash on the path from a leaf to the root
+type ProofStep struct {
+	Hash string `json:"hash"`
+	// Left is set when the sibling is the left node of the pair
+	Left bool `json:"left"`
+}
+
+// Proof is the audit path proving that a leaf hash is included in a tree with the root hash
+type Proof struct {
+	Leaf  string      `json:"leaf"`
+	Index int         `json:"index"`
+	Root  string      `json:"root"`
+	Path  []ProofStep `json:"path"`
+}
+
+// Proof returns the audit path of the leaf hash in the tree
+func (t *Tree) Proof(leaf string) (*Proof, error) {
+	if t == nil {
+		return nil, ErrNilMerkleTree
+	}
+
+	proof, err := BuildProof(t.props.Hashes, leaf)
+	if err != nil {
+		return nil, err
+	}
+
+	// note: the tree root is checked so a proof is never returned for a tree that doesn't match its hashes
+	if t.props.MerkleTreeRootHash != nil && !sameHash(*t.props.MerkleTreeRootHash, proof.Root) {
+		return nil, ErrRootMismatch
+	}
+
+	return proof, nil
+}
+
+// BuildProof returns the audit path of the leaf hash in the tree built from the hashes.
+// note: the path matches the trees built by BuildFromObjects, where an odd node is paired with itself.
+func BuildProof(hashes []string, leaf string) (*Proof, error) {
+	if len(hashes) == 0 {
+		return nil, ErrLeafNotFound
+	}
+
+	index := -1
+	level := make([][]byte, len(hashes))
+	for i, hash := range hashes {
+		b, err := hexutil.DecodeString(hash)
+		if err != nil {
+			return nil, err
+		}
+		if index == -1 && sameHash(hash, leaf) {
+			index = i
+		}
+
+		level[i] = b
+	}
+	if index == -1 {
+		return nil, ErrLeafNotFound
+	}
+
+	proof := &Proof{
+		Leaf:  hexutil.EncodeToString(level[index]),
+		Index: index,
+	}
+
+	// note: the leaf level is padded by duplicating the last leaf, same as merkletree
+	if len(level)%2 == 1 {
+		level = append(level, level[len(level)-1])
+	}
+
+	idx := index
+	for len(level) > 1 {
+		sibling := idx ^ 1
+		if sibling >= len(level) {
+			sibling = idx
+		}
+
+		proof.Path = append(proof.Path, ProofStep{
+			Hash: hexutil.EncodeToString(level[sibling]),
+			Left: sibling < idx,
+		})
+
+		var next [][]byte
+		for i := 0; i < len(level); i += 2 {
+			right := i + 1
+			if right == len(level) {
+				right = i
+			}
+
+			next = append(next, hashPair(level[i], level[right]))
+		}
+
+		level = next
+		idx /= 2
+	}
+
+	proof.Root = hexutil.EncodeToString(level[0])
+
+	return proof, nil
+}
+
+// VerifyProof checks that the audit path hashes the proof leaf up to the root hash
+func VerifyProof(proof *Proof, root string) (bool, error) {
+	if proof == nil {
+		return false, ErrNilProof
+	}
+
+	hash, err := hexutil.DecodeString(proof.Leaf)
+	if err != nil {
+		return false, err
+	}
+
+	for _, step := range proof.Path {
+		sibling, err := hexutil.DecodeString(step.Hash)
+		if err != nil {
+			return false, err
+		}
+
+		if step.Left {
+			hash = hashPair(sibling, hash)
+		} else {
+			hash = hashPair(hash, sibling)
+		}
+	}
+
+	return sameHash(hexutil.EncodeToString(hash), root), nil
+}
+
+func hashPair(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write(left)
+	h.Write(right)
+
+	return h.Sum(nil)
+}
+
+func sameHash(a, b string) bool {
+	aBytes, err := hexutil.DecodeString(a)
+	if err != nil {
+		return false
+	}
+	bBytes, err := hexutil.DecodeString(b)
+	if err != nil {
+		return false
+	}
+
+	return string(aBytes) == string(bBytes)
+}
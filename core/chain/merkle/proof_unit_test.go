@@ -0,0 +1,131 @@
+// +build unit
+
+package merkle
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/c3systems/merkletree"
+)
+
+type proofContent struct {
+	x string
+}
+
+func (p proofContent) CalculateHashBytes() ([]byte, error) {
+	h := sha256.Sum256([]byte(p.x))
+	return h[:], nil
+}
+
+func (p proofContent) Equals(other merkletree.Content) (bool, error) {
+	return p.x == other.(proofContent).x, nil
+}
+
+func buildProofTree(t *testing.T, n int) *Tree {
+	var list []merkletree.Content
+	for i := 0; i < n; i++ {
+		list = append(list, proofContent{x: fmt.Sprintf("leaf %d", i)})
+	}
+
+	tree, err := BuildFromObjects(list, StatechainBlocksKindStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tree
+}
+
+func TestProof(t *testing.T) {
+	t.Parallel()
+
+	for n := 1; n <= 17; n++ {
+		tree := buildProofTree(t, n)
+		root := *tree.Props().MerkleTreeRootHash
+
+		for idx, leaf := range tree.Props().Hashes {
+			proof, err := tree.Proof(leaf)
+			if err != nil {
+				t.Fatalf("test %d leaf %d failed\n%v", n, idx, err)
+			}
+			if proof.Index != idx || proof.Root != root {
+				t.Errorf("test %d leaf %d failed\nexpected index %d and root %s\nreceived index %d and root %s", n, idx, idx, root, proof.Index, proof.Root)
+			}
+
+			ok, err := VerifyProof(proof, root)
+			if err != nil {
+				t.Fatalf("test %d leaf %d failed\n%v", n, idx, err)
+			}
+			if !ok {
+				t.Errorf("test %d leaf %d failed\nproof didn't verify", n, idx)
+			}
+		}
+	}
+}
+
+func TestVerifyProofInvalid(t *testing.T) {
+	t.Parallel()
+
+	tree := buildProofTree(t, 5)
+	root := *tree.Props().MerkleTreeRootHash
+
+	proof, err := tree.Proof(tree.Props().Hashes[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other := buildProofTree(t, 6)
+	if ok, _ := VerifyProof(proof, *other.Props().MerkleTreeRootHash); ok {
+		t.Error("expected proof to not verify against another root")
+	}
+
+	proof.Leaf = tree.Props().Hashes[3]
+	if ok, _ := VerifyProof(proof, root); ok {
+		t.Error("expected proof of another leaf to not verify")
+	}
+
+	if _, err := tree.Proof("0x00"); err != ErrLeafNotFound {
+		t.Errorf("expected %v; received %v", ErrLeafNotFound, err)
+	}
+	if _, err := VerifyProof(nil, root); err != ErrNilProof {
+		t.Errorf("expected %v; received %v", ErrNilProof, err)
+	}
+
+	badRoot := "0x00"
+	badTree, err := New(&TreeProps{
+		MerkleTreeRootHash: &badRoot,
+		Kind:               StatechainBlocksKindStr,
+		Hashes:             tree.Props().Hashes,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := badTree.Proof(tree.Props().Hashes[0]); err != ErrRootMismatch {
+		t.Errorf("expected %v; received %v", ErrRootMismatch, err)
+	}
+}
+
+func TestCalculateHashFromHashes(t *testing.T) {
+	t.Parallel()
+
+	for n := 1; n <= 9; n++ {
+		tree := buildProofTree(t, n)
+
+		tmpTree, err := New(&TreeProps{
+			Kind:   StatechainBlocksKindStr,
+			Hashes: tree.Props().Hashes,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		hash, err := tmpTree.CalculateHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hash != *tree.Props().MerkleTreeRootHash {
+			t.Errorf("test %d failed\nexpected %s\nreceived %s", n, *tree.Props().MerkleTreeRootHash, hash)
+		}
+	}
+}
@@ -1,9 +1,9 @@
 package merkle
 
 import (
-	"crypto/sha256"
 	"errors"
 
+	"github.com/c3systems/c3-go/common/hexutil"
 	"github.com/c3systems/merkletree"
 )
 
@@ -21,7 +21,13 @@ var (
 	// ErrNilMerkleTreeRootHash ...
 	ErrNilMerkleTreeRootHash = errors.New("merkle tree hash is nil")
 	// ErrNilProps ...
-	ErrNilProps  = errors.New("props are nil")
+	ErrNilProps = errors.New("props are nil")
+	// ErrLeafNotFound ...
+	ErrLeafNotFound = errors.New("leaf hash not found in merkle tree")
+	// ErrRootMismatch ...
+	ErrRootMismatch = errors.New("merkle tree root hash doesn't match its hashes")
+	// ErrNilProof ...
+	ErrNilProof  = errors.New("proof is nil")
 	allowedKinds = []string{
 		StatechainBlocksKindStr,
 		MainchainBlocksKindStr,
@@ -56,22 +62,22 @@ type TreeProps struct {
 	Hashes             []string
 }
 
-// testContent implements the Content interface provided by merkletree and represents the content stored in the tree.
-type testContent struct {
+// hashContent implements the Content interface provided by merkletree for a leaf that is already hashed.
+type hashContent struct {
 	x string
 }
 
-// CalculateHashBytes hashes the values of a TestContent
-func (t testContent) CalculateHashBytes() ([]byte, error) {
-	h := sha256.New()
-	if _, err := h.Write([]byte(t.x)); err != nil {
-		return nil, err
-	}
-
-	return h.Sum(nil), nil
+// CalculateHashBytes returns the decoded leaf hash, same as the CalculateHashBytes of the object it was built from
+func (h hashContent) CalculateHashBytes() ([]byte, error) {
+	return hexutil.DecodeString(h.x)
 }
 
 // Equals tests for equality of two Contents
-func (t testContent) Equals(other merkletree.Content) (bool, error) {
-	return t.x == other.(testContent).x, nil
+func (h hashContent) Equals(other merkletree.Content) (bool, error) {
+	o, ok := other.(hashContent)
+	if !ok {
+		return false, nil
+	}
+
+	return sameHash(h.x, o.x), nil
 }
@@ -106,6 +106,7 @@ func (b Block) CalculateHashBytes() ([]byte, error) {
 			PrevBlockHash:     b.props.PrevBlockHash,
 			StatePrevDiffHash: b.props.StatePrevDiffHash,
 			StateCurrentHash:  b.props.StateCurrentHash,
+			StateSnapshotCID:  b.props.StateSnapshotCID,
 		},
 	}
 
@@ -176,6 +177,7 @@ func BuildCoderFromBlock(b *Block) *coder.StatechainBlock {
 		PrevBlockHash:     b.props.PrevBlockHash,
 		StatePrevDiffHash: b.props.StatePrevDiffHash,
 		StateCurrentHash:  b.props.StateCurrentHash,
+		StateSnapshotCID:  b.props.StateSnapshotCID,
 	}
 
 	// note: is there a better way to handle nil with protobuff?
@@ -228,6 +230,7 @@ func BuildBlockPropsFromCoder(tmp *coder.StatechainBlock) (*BlockProps, error) {
 		PrevBlockHash:     tmp.PrevBlockHash,
 		StatePrevDiffHash: tmp.StatePrevDiffHash,
 		StateCurrentHash:  tmp.StateCurrentHash,
+		StateSnapshotCID:  tmp.StateSnapshotCID,
 	}
 	// note: is there any better way of checking forn nil with protobuf?
 	if tmp.BlockHash != "" {
@@ -51,6 +51,8 @@ type BlockProps struct {
 	PrevBlockHash     string  `json:"prevBlockHash"`
 	StatePrevDiffHash string  `json:"statePrevDiffHash"`
 	StateCurrentHash  string  `json:"stateCurrentHash"`
+	// StateSnapshotCID is the cid of the full state, set on the blocks at a snapshot interval
+	StateSnapshotCID string `json:"stateSnapshotCID,omitempty"`
 }
 
 // Block ...
@@ -0,0 +1,121 @@
+package diffing
+
+import (
+	"fmt"
+	"strings"
+)
+
+const placeholderPrefix = "\x00c3diffing:"
+
+// placeholder marks an original line neither patch knows.
+// note: it never equals a real line; it has no newline and is never the last line, the only line without one.
+func placeholder(idx int) string {
+	return fmt.Sprintf("%s%d", placeholderPrefix, idx)
+}
+
+func isPlaceholder(line string) bool {
+	return strings.HasPrefix(line, placeholderPrefix) && !strings.HasSuffix(line, "\n")
+}
+
+// combine returns a patch equivalent to applying first and then second, like combinediff.
+// note: the original file is rebuilt from the lines the two patches know about, with placeholders for the rest.
+// Both patches are applied to it and the result is diffed against it, without diffing across placeholders.
+func combine(first, second *patch) (*patch, error) {
+	known := make(map[int]string)
+	size := 0
+
+	for _, h := range first.hunks {
+		idx := h.oldIndex()
+		for _, line := range h.oldLines() {
+			known[idx] = line
+			idx++
+		}
+		if idx > size {
+			size = idx
+		}
+	}
+
+	// note: lines of the second patch outside of the first's hunks are unchanged by it; map them back to the original
+	for _, h := range second.hunks {
+		idx := h.oldIndex()
+		for _, line := range h.oldLines() {
+			if orig, ok := intermediateToOriginal(first, idx); ok {
+				if prev, exists := known[orig]; exists && prev != line {
+					return nil, ErrPatchMismatch
+				}
+
+				known[orig] = line
+				if orig+1 > size {
+					size = orig + 1
+				}
+			}
+			idx++
+		}
+	}
+
+	original := make([]string, size)
+	for i := range original {
+		if line, ok := known[i]; ok {
+			original[i] = line
+		} else {
+			original[i] = placeholder(i)
+		}
+	}
+
+	intermediate, err := first.apply(original, true)
+	if err != nil {
+		return nil, err
+	}
+	result, err := second.apply(intermediate, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// note: the headers are the ones combinediff writes; the combined patch goes from the first patch's new file to the second's
+	combined := &patch{
+		command:   fmt.Sprintf("diff -u %s %s", headerName(first.newHeader), headerName(second.newHeader)),
+		oldHeader: first.newHeader,
+		newHeader: second.newHeader,
+	}
+
+	// note: placeholders are never touched by either patch, so they line up in both files and split them into segments
+	aStart, bStart := 0, 0
+	for aStart <= len(original) {
+		aEnd := aStart
+		for aEnd < len(original) && !isPlaceholder(original[aEnd]) {
+			aEnd++
+		}
+		bEnd := bStart
+		for bEnd < len(result) && !isPlaceholder(result[bEnd]) {
+			bEnd++
+		}
+		if (aEnd < len(original)) != (bEnd < len(result)) || (aEnd < len(original) && original[aEnd] != result[bEnd]) {
+			return nil, ErrPatchMismatch
+		}
+
+		combined.hunks = append(combined.hunks, buildHunks(original[aStart:aEnd], result[bStart:bEnd], aStart, bStart)...)
+
+		aStart, bStart = aEnd+1, bEnd+1
+	}
+
+	return combined, nil
+}
+
+// intermediateToOriginal maps a zero based line index of the file after the first patch to the original file.
+// It reports false when the line is written by one of the patch's hunks.
+func intermediateToOriginal(first *patch, idx int) (int, bool) {
+	delta := 0
+	for _, h := range first.hunks {
+		start := h.newIndex()
+		if idx < start {
+			break
+		}
+		if idx < start+h.newCount {
+			return 0, false
+		}
+
+		delta = h.newCount - h.oldCount + delta
+	}
+
+	return idx - delta, true
+}
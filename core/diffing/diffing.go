@@ -2,11 +2,10 @@ package diffing
 
 import (
 	"errors"
-	"fmt"
-	"os/exec"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 
-	"github.com/c3systems/c3-go/common/command"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -14,96 +13,122 @@ var (
 	// ErrNoDifferencesFound ...
 	ErrNoDifferencesFound = errors.New("no differences were found")
 
-	// ErrCommandNotFound ...
-	ErrCommandNotFound = errors.New("command not found")
+	// ErrMalformedPatch ...
+	ErrMalformedPatch = errors.New("malformed unified diff")
+
+	// ErrPatchMismatch is returned when a hunk doesn't match the content it's applied to
+	ErrPatchMismatch = errors.New("patch does not apply")
 )
 
-// Diff ...
-func Diff(old, new, out string, isDir bool) error {
-	if !command.Exists("diff") {
-		log.Error("[miner] error; diff command not found")
-		return ErrCommandNotFound
+// Diff returns the unified diff, same as diff -u, of old and new. name is used for both the --- and +++ headers.
+// note: no timestamps are written so the same contents always produce the same diff; no differences produce an empty diff.
+func Diff(name string, old, new []byte) []byte {
+	p := &patch{
+		oldHeader: name,
+		newHeader: name,
+		hunks:     buildHunks(splitLines(old), splitLines(new), 0, 0),
 	}
 
-	var (
-		commands []interface{}
-		s        string
-	)
+	return p.format()
+}
 
-	if isDir {
-		commands = append(commands, "-rN")
-		s = " %s"
+// CombineDiff returns a single unified diff equivalent to applying first and then second, same as combinediff
+func CombineDiff(first, second []byte) ([]byte, error) {
+	firstPatch, err := parsePatch(first)
+	if err != nil {
+		log.Errorf("[diffing] error parsing first diff; %s", err)
+		return nil, err
+	}
+	secondPatch, err := parsePatch(second)
+	if err != nil {
+		log.Errorf("[diffing] error parsing second diff; %s", err)
+		return nil, err
 	}
 
-	oldFile := filepath.Base(old)
+	combined, err := combine(firstPatch, secondPatch)
+	if err != nil {
+		log.Errorf("[diffing] error combining diffs; %s", err)
+		return nil, err
+	}
 
-	/*
-	 * note: the first two lines of a standard patch file are:
-	 *    --- oldFileName timestamp
-	 *    +++ newFileName timestamp
-	 *
-	 *    We want the oldFileName to show up on both lines, so we replace the first instance in the file with sed
-	 *
-	 */
-	commands = append(commands, old, new, old, oldFile, new, oldFile, out)
-	s += " %s %s | sed -e 's|%s|%s|' | sed -e 's|%s|%s|' > %s"
+	return combined.format(), nil
+}
 
-	// same as: git diff --minimal --unified file1 file2
-	cmd := exec.Command("sh", "-c", fmt.Sprintf("diff -uda"+s, commands...))
-	if err := cmd.Start(); err != nil {
-		return err
+// Patch applies the unified diff to orig and returns the result, same as patch -u
+func Patch(orig, diff []byte) ([]byte, error) {
+	p, err := parsePatch(diff)
+	if err != nil {
+		log.Errorf("[diffing] error parsing diff; %s", err)
+		return nil, err
 	}
 
-	return cmd.Wait()
+	lines, err := p.apply(splitLines(orig), false)
+	if err != nil {
+		log.Errorf("[diffing] error applying diff; %s", err)
+		return nil, err
+	}
+
+	return joinLines(lines), nil
 }
 
-// CombineDiff ...
-func CombineDiff(firstDiff, secondDiff, out string) error {
-	if !command.Exists("combinediff") {
-		log.Error("[miner] error; combinediff command not found")
-		return ErrCommandNotFound
+// DiffFiles writes the unified diff of the old and new files to out
+func DiffFiles(old, new, out string) error {
+	oldData, err := ioutil.ReadFile(old)
+	if err != nil {
+		return err
 	}
-
-	cmd := exec.Command("sh", "-c", fmt.Sprintf("combinediff %s %s > %s", firstDiff, secondDiff, out))
-	if err := cmd.Start(); err != nil {
-		log.Errorf("[miner] error executing combinediff command; %s", err)
+	newData, err := ioutil.ReadFile(new)
+	if err != nil {
 		return err
 	}
 
-	return cmd.Wait()
+	return ioutil.WriteFile(out, Diff(filepath.Base(old), oldData, newData), os.ModePerm)
 }
 
-// Patch ...
-func Patch(patch, orig string, backup bool, absPath bool) error {
-	if !command.Exists("patch") {
-		log.Error("[miner] error; patch command not found")
-		return ErrCommandNotFound
+// CombineDiffFiles writes the combination of the first and second diff files to out
+func CombineDiffFiles(firstDiff, secondDiff, out string) error {
+	first, err := ioutil.ReadFile(firstDiff)
+	if err != nil {
+		return err
+	}
+	second, err := ioutil.ReadFile(secondDiff)
+	if err != nil {
+		return err
 	}
 
-	var (
-		commands []interface{}
-		s        string
-	)
+	combined, err := CombineDiff(first, second)
+	if err != nil {
+		return err
+	}
 
-	commands = append(commands, "-u")
-	s = " %s"
+	return ioutil.WriteFile(out, combined, os.ModePerm)
+}
 
-	if backup {
-		commands = append(commands, "-b")
-		s += " %s"
+// PatchFile applies the diff file to the orig file in place. If backup is set, the original is kept as orig.orig.
+func PatchFile(diff, orig string, backup bool) error {
+	diffData, err := ioutil.ReadFile(diff)
+	if err != nil {
+		return err
 	}
-	if absPath {
-		commands = append(commands, "-d/ -p0")
-		s += " %s"
+	origData, err := ioutil.ReadFile(orig)
+	if err != nil {
+		return err
 	}
 
-	commands = append(commands, orig, patch)
-	s += " %s %s"
+	patched, err := Patch(origData, diffData)
+	if err != nil {
+		return err
+	}
 
-	cmd := exec.Command("sh", "-c", fmt.Sprintf("patch"+s, commands...))
-	if err := cmd.Start(); err != nil {
+	info, err := os.Stat(orig)
+	if err != nil {
 		return err
 	}
+	if backup {
+		if err := ioutil.WriteFile(orig+".orig", origData, info.Mode()); err != nil {
+			return err
+		}
+	}
 
-	return cmd.Wait()
+	return ioutil.WriteFile(orig, patched, info.Mode())
 }
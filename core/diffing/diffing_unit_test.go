@@ -5,239 +5,348 @@ package diffing
 import (
 	"fmt"
 	"io/ioutil"
+	"math/rand"
 	"os"
 	"strings"
 	"testing"
 )
 
 func TestDiff(t *testing.T) {
-	// TODO: test dir
-	wd, err := os.Getwd()
-	if err != nil {
-		t.Errorf("err getting current working directory\n%v", err)
-	}
-
-	if err := createDirIfNotExist(fmt.Sprintf("%s/.testfiles/", wd)); err != nil {
-		t.Errorf("err creating test files directory\n%v", err)
+	t.Parallel()
+
+	inputs := []struct {
+		old      string
+		new      string
+		expected string
+	}{
+		{
+			"1",
+			"12",
+			`--- state.txt
++++ state.txt
+@@ -1 +1 @@
+-1
+\ No newline at end of file
++12
+\ No newline at end of file
+`,
+		},
+		{
+			"",
+			"a\nb\n",
+			`--- state.txt
++++ state.txt
+@@ -0,0 +1,2 @@
++a
++b
+`,
+		},
+		{
+			"a\nb\n",
+			"",
+			`--- state.txt
++++ state.txt
+@@ -1,2 +0,0 @@
+-a
+-b
+`,
+		},
+		{
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n",
+			"1\n2\n3\n4\nfive\n6\n7\n8\n9\n10\n11\n12\n13\nfourteen\n15\n",
+			`--- state.txt
++++ state.txt
+@@ -2,7 +2,7 @@
+ 2
+ 3
+ 4
+-5
++five
+ 6
+ 7
+ 8
+@@ -11,5 +11,5 @@
+ 11
+ 12
+ 13
+-14
++fourteen
+ 15
+`,
+		},
+		{"same\n", "same\n", ""},
+	}
+
+	for idx, input := range inputs {
+		actual := Diff("state.txt", []byte(input.old), []byte(input.new))
+		if string(actual) != input.expected {
+			t.Errorf("test %d failed\nexpected\n%s\nreceived\n%s", idx+1, input.expected, string(actual))
+		}
 	}
-	//defer os.RemoveAll(fmt.Sprintf("%s/.testfiles", wd))
+}
 
-	f1, err := os.Create(fmt.Sprintf("%s/.testfiles/1.txt", wd))
+func TestPatch(t *testing.T) {
+	t.Parallel()
+
+	diff := `diff -u state.txt state.txt
+--- state.txt	2018-07-18 13:18:14.501825500 -0700
++++ state.txt	2018-07-18 13:18:32.026805900 -0700
+@@ -1,3 +1,3 @@
+ a
+-b
++B
+ c
+`
+
+	// note: the hunk is found even when the lines moved
+	actual, err := Patch([]byte("x\na\nb\nc\n"), []byte(diff))
 	if err != nil {
-		t.Errorf("err creating first file\n%v", err)
+		t.Fatal(err)
 	}
-	defer f1.Close()
-
-	if err := f1.Chmod(0777); err != nil {
-		t.Errorf("eff chmodding file 1\n%v", err)
+	if string(actual) != "x\na\nB\nc\n" {
+		t.Errorf("expected\n%s\nreceived\n%s", "x\na\nB\nc\n", string(actual))
 	}
 
-	if _, err := f1.WriteString("1"); err != nil {
-		t.Errorf("err writing to first file\n%v", err)
+	if _, err := Patch([]byte("a\nz\nc\n"), []byte(diff)); err != ErrPatchMismatch {
+		t.Errorf("expected %v; received %v", ErrPatchMismatch, err)
+	}
+	if _, err := Patch([]byte("a\n"), []byte("--- a\n+++ a\n@@ -1,2 +1,2 @@\n a\n")); err != ErrMalformedPatch {
+		t.Errorf("expected %v; received %v", ErrMalformedPatch, err)
 	}
+}
 
-	f1.Sync()
+func TestDiffPatchRoundTrip(t *testing.T) {
+	t.Parallel()
 
-	f2, err := os.Create(fmt.Sprintf("%s/.testfiles/2.txt", wd))
-	if err != nil {
-		t.Errorf("err creating second file\n%v", err)
-	}
-	defer f2.Close()
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		old := randomContent(r)
+		new := mutateContent(r, old)
 
-	if err := f2.Chmod(0777); err != nil {
-		t.Errorf("eff chmodding file 2\n%v", err)
+		actual, err := Patch([]byte(old), Diff("state.txt", []byte(old), []byte(new)))
+		if err != nil {
+			t.Fatalf("test %d failed\n%v", i+1, err)
+		}
+		if string(actual) != new {
+			t.Fatalf("test %d failed\nexpected %q\nreceived %q", i+1, new, string(actual))
+		}
 	}
+}
 
-	if _, err := f2.WriteString("12"); err != nil {
-		t.Errorf("err writing to second file\n%v", err)
+func TestEditScriptMinimal(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 500; i++ {
+		a := splitLines([]byte(randomContent(r)))
+		b := splitLines([]byte(mutateContent(r, strings.Join(a, ""))))
+		if r.Intn(4) == 0 {
+			b = splitLines([]byte(randomContent(r)))
+		}
+
+		var x, y, changes int
+		for _, e := range editScript(a, b) {
+			switch e.kind {
+			case editEqual:
+				if e.a != x || e.b != y || a[x] != b[y] {
+					t.Fatalf("test %d failed\nunexpected equal edit %v at %d,%d", i+1, e, x, y)
+				}
+				x++
+				y++
+			case editDelete:
+				if e.a != x {
+					t.Fatalf("test %d failed\nunexpected delete edit %v at %d,%d", i+1, e, x, y)
+				}
+				x++
+				changes++
+			case editInsert:
+				if e.b != y {
+					t.Fatalf("test %d failed\nunexpected insert edit %v at %d,%d", i+1, e, x, y)
+				}
+				y++
+				changes++
+			}
+		}
+		if x != len(a) || y != len(b) {
+			t.Fatalf("test %d failed\nthe edit script stops at %d,%d", i+1, x, y)
+		}
+
+		// note: the shortest edit script keeps the longest common subsequence
+		if expected := len(a) + len(b) - 2*lcsLength(a, b); changes != expected {
+			t.Fatalf("test %d failed\nexpected %d changes; received %d", i+1, expected, changes)
+		}
 	}
+}
 
-	f2.Sync()
+func TestEditScriptLargeRewrite(t *testing.T) {
+	t.Parallel()
 
-	if err := Diff(fmt.Sprintf("%s/.testfiles/1.txt", wd), fmt.Sprintf("%s/.testfiles/2.txt", wd), fmt.Sprintf("%s/.testfiles/12.patch", wd), false); err != nil {
-		t.Errorf("err diffing the files\n%v", err)
+	var a, b []string
+	for i := 0; i < 5000; i++ {
+		a = append(a, fmt.Sprintf("old %d\n", i))
+		b = append(b, fmt.Sprintf("new %d\n", i))
 	}
 
-	actual, err := ioutil.ReadFile(fmt.Sprintf("%s/.testfiles/12.patch", wd))
-	if err != nil {
-		t.Errorf("err reading patch file\n%v", err)
-	}
-
-	expected := `@@ -1 +1 @@
--1
-\ No newline at end of file
-+12
-\ No newline at end of file`
-
-	if !strings.Contains(string(actual), expected) {
-		t.Errorf("expected\n%s\nin\n%s", expected, string(actual))
+	if edits := editScript(a, b); len(edits) != len(a)+len(b) {
+		t.Errorf("expected %d edits; received %d", len(a)+len(b), len(edits))
 	}
 }
 
 func TestCombineDiff(t *testing.T) {
-	wd, err := os.Getwd()
-	if err != nil {
-		t.Errorf("err getting current working directory\n%v", err)
-	}
+	t.Parallel()
 
-	if err := createDirIfNotExist(fmt.Sprintf("%s/.testfiles/", wd)); err != nil {
-		t.Errorf("err creating test files directory\n%v", err)
-	}
-	defer os.RemoveAll(fmt.Sprintf("%s/.testfiles", wd))
+	diff12 := Diff("state.txt", []byte("1"), []byte("12"))
+	diff23 := Diff("state.txt", []byte("12"), []byte("123"))
 
-	f1, err := os.Create(fmt.Sprintf("%s/.testfiles/1.txt", wd))
+	combined, err := CombineDiff(diff12, diff23)
 	if err != nil {
-		t.Errorf("err creating first file\n%v", err)
+		t.Fatal(err)
 	}
-	defer f1.Close()
 
-	if err := f1.Chmod(0777); err != nil {
-		t.Errorf("eff chmodding file 1\n%v", err)
+	expected := `diff -u state.txt state.txt
+--- state.txt
++++ state.txt
+@@ -1 +1 @@
+-1
+\ No newline at end of file
++123
+\ No newline at end of file
+`
+	if string(combined) != expected {
+		t.Errorf("expected\n%s\nreceived\n%s", expected, string(combined))
 	}
+}
 
-	if _, err := f1.WriteString("1"); err != nil {
-		t.Errorf("err writing to first file\n%v", err)
-	}
+func TestCombineDiffRandom(t *testing.T) {
+	t.Parallel()
 
-	if err := f1.Sync(); err != nil {
-		t.Errorf("err synching file 1\n%v", err)
-	}
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 200; i++ {
+		s1 := randomContent(r)
+		s2 := mutateContent(r, s1)
+		s3 := mutateContent(r, s2)
 
-	f2, err := os.Create(fmt.Sprintf("%s/.testfiles/2.txt", wd))
-	if err != nil {
-		t.Errorf("err creating second file\n%v", err)
-	}
-	defer f2.Close()
+		combined, err := CombineDiff(Diff("state.txt", []byte(s1), []byte(s2)), Diff("state.txt", []byte(s2), []byte(s3)))
+		if err != nil {
+			t.Fatalf("test %d failed\n%v", i+1, err)
+		}
 
-	if err := f2.Chmod(0777); err != nil {
-		t.Errorf("eff chmodding file 2\n%v", err)
-	}
-
-	if _, err := f2.WriteString("12"); err != nil {
-		t.Errorf("err writing to second file\n%v", err)
-	}
-
-	if err := f2.Sync(); err != nil {
-		t.Errorf("err synching file 2\n%v", err)
+		actual, err := Patch([]byte(s1), combined)
+		if err != nil {
+			t.Fatalf("test %d failed\n%v\n%s", i+1, err, string(combined))
+		}
+		if string(actual) != s3 {
+			t.Fatalf("test %d failed\nexpected %q\nreceived %q", i+1, s3, string(actual))
+		}
 	}
+}
 
-	f3, err := os.Create(fmt.Sprintf("%s/.testfiles/3.txt", wd))
+func TestFiles(t *testing.T) {
+	wd, err := os.Getwd()
 	if err != nil {
-		t.Errorf("err creating second file\n%v", err)
-	}
-	defer f3.Close()
-
-	if err := f3.Chmod(0777); err != nil {
-		t.Errorf("eff chmodding file 3\n%v", err)
-	}
-
-	if _, err := f3.WriteString("123"); err != nil {
-		t.Errorf("err writing to third file\n%v", err)
+		t.Fatalf("err getting current working directory\n%v", err)
 	}
 
-	if err := f3.Sync(); err != nil {
-		t.Errorf("err synching file 3\n%v", err)
+	dir := fmt.Sprintf("%s/.testfiles", wd)
+	if err := createDirIfNotExist(dir); err != nil {
+		t.Fatalf("err creating test files directory\n%v", err)
 	}
+	defer os.RemoveAll(dir)
 
-	if err := Diff(fmt.Sprintf("%s/.testfiles/1.txt", wd), fmt.Sprintf("%s/.testfiles/2.txt", wd), fmt.Sprintf("%s/.testfiles/12.patch", wd), false); err != nil {
-		t.Errorf("err diffing the 1 and 2\n%v", err)
+	for name, content := range map[string]string{"1.txt": "1", "2.txt": "12", "3.txt": "123"} {
+		if err := ioutil.WriteFile(fmt.Sprintf("%s/%s", dir, name), []byte(content), 0777); err != nil {
+			t.Fatalf("err writing %s\n%v", name, err)
+		}
 	}
 
-	if err := Diff(fmt.Sprintf("%s/.testfiles/2.txt", wd), fmt.Sprintf("%s/.testfiles/3.txt", wd), fmt.Sprintf("%s/.testfiles/23.patch", wd), false); err != nil {
-		t.Errorf("err diffing the 2 and 3\n%v", err)
+	if err := DiffFiles(dir+"/1.txt", dir+"/2.txt", dir+"/12.patch"); err != nil {
+		t.Fatalf("err diffing 1 and 2\n%v", err)
 	}
-
-	if err := CombineDiff(fmt.Sprintf("%s/.testfiles/12.patch", wd), fmt.Sprintf("%s/.testfiles/23.patch", wd), fmt.Sprintf("%s/.testfiles/13.combined.patch", wd)); err != nil {
-		t.Errorf("err combining diffs\n%v", err)
+	if err := DiffFiles(dir+"/2.txt", dir+"/3.txt", dir+"/23.patch"); err != nil {
+		t.Fatalf("err diffing 2 and 3\n%v", err)
 	}
-
-	actual, err := ioutil.ReadFile(fmt.Sprintf("%s/.testfiles/13.combined.patch", wd))
-	if err != nil {
-		t.Errorf("err reading patch file\n%v", err)
+	if err := CombineDiffFiles(dir+"/12.patch", dir+"/23.patch", dir+"/13.combined.patch"); err != nil {
+		t.Fatalf("err combining diffs\n%v", err)
 	}
-
-	expected1 := `@@ -1 +1 @@
--1
-\ No newline at end of file
-+12`
-	expected2 := `@@ -1 +1 @@
--12
-\ No newline at end of file
-+123`
-
-	if !strings.Contains(string(actual), expected1) ||
-		!strings.Contains(string(actual), expected2) {
-		t.Errorf("expected\n%s\nand\n%s\nin\n%s", expected1, expected2, string(actual))
+	if err := PatchFile(dir+"/13.combined.patch", dir+"/1.txt", true); err != nil {
+		t.Fatalf("err patching\n%v", err)
 	}
-}
 
-func TestPatch(t *testing.T) {
-	// TODO: add tests for not backup and not absPath
-	wd, err := os.Getwd()
+	actual, err := ioutil.ReadFile(dir + "/1.txt")
 	if err != nil {
-		t.Errorf("err getting current working directory\n%v", err)
+		t.Fatalf("err reading patched file\n%v", err)
 	}
-
-	if err := createDirIfNotExist(fmt.Sprintf("%s/.testfiles/", wd)); err != nil {
-		t.Errorf("err creating test files directory\n%v", err)
-	}
-	defer os.RemoveAll(fmt.Sprintf("%s/.testfiles", wd))
-
-	f1, err := os.Create(fmt.Sprintf("%s/.testfiles/1.txt", wd))
+	backup, err := ioutil.ReadFile(dir + "/1.txt.orig")
 	if err != nil {
-		t.Errorf("err creating first file\n%v", err)
-	}
-	defer f1.Close()
-
-	if err := f1.Chmod(0777); err != nil {
-		t.Errorf("eff chmodding file 1\n%v", err)
-	}
-
-	if _, err := f1.WriteString("1"); err != nil {
-		t.Errorf("err writing to first file\n%v", err)
+		t.Fatalf("err reading backup file\n%v", err)
 	}
 
-	f1.Sync()
-
-	f2, err := os.Create(fmt.Sprintf("%s/.testfiles/2.txt", wd))
-	if err != nil {
-		t.Errorf("err creating second file\n%v", err)
+	if string(actual) != "123" || string(backup) != "1" {
+		t.Errorf("expected\n%s\nand\n%s\nreceived\n%s\nand\n%s", "123", "1", string(actual), string(backup))
 	}
-	defer f2.Close()
+}
 
-	if err := f2.Chmod(0777); err != nil {
-		t.Errorf("eff chmodding file 2\n%v", err)
+func randomContent(r *rand.Rand) string {
+	var lines []string
+	for i := r.Intn(30); i > 0; i-- {
+		lines = append(lines, fmt.Sprintf("line %d", r.Intn(10)))
 	}
 
-	if _, err := f2.WriteString("12"); err != nil {
-		t.Errorf("err writing to second file\n%v", err)
+	content := strings.Join(lines, "\n")
+	if len(lines) > 0 && r.Intn(2) == 0 {
+		content += "\n"
 	}
 
-	f2.Sync()
+	return content
+}
 
-	if err := Diff(fmt.Sprintf("%s/.testfiles/1.txt", wd), fmt.Sprintf("%s/.testfiles/2.txt", wd), fmt.Sprintf("%s/.testfiles/12.patch", wd), false); err != nil {
-		t.Errorf("err diffing the files\n%v", err)
+func mutateContent(r *rand.Rand, content string) string {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
 	}
 
-	if err := Patch(fmt.Sprintf("%s/.testfiles/12.patch", wd), fmt.Sprintf("%s/.testfiles/1.txt", wd), true, true); err != nil {
-		t.Errorf("err patching\n%v", err)
+	for i := r.Intn(5); i > 0; i-- {
+		switch idx := r.Intn(len(lines) + 1); r.Intn(3) {
+		case 0:
+			lines = append(lines[:idx], append([]string{fmt.Sprintf("new %d\n", r.Intn(10))}, lines[idx:]...)...)
+		case 1:
+			if idx < len(lines) {
+				lines = append(lines[:idx], lines[idx+1:]...)
+			}
+		default:
+			if idx < len(lines) {
+				lines[idx] = fmt.Sprintf("changed %d\n", r.Intn(10))
+			}
+		}
 	}
 
-	actual, err := ioutil.ReadFile(fmt.Sprintf("%s/.testfiles/1.txt", wd))
-	if err != nil {
-		t.Errorf("err reading patch file\n%v", err)
-	}
-	backup, err := ioutil.ReadFile(fmt.Sprintf("%s/.testfiles/1.txt.orig", wd))
-	if err != nil {
-		t.Errorf("err reading patch file\n%v", err)
+	mutated := strings.Join(lines, "")
+	if r.Intn(4) == 0 {
+		mutated = strings.TrimSuffix(mutated, "\n")
 	}
 
-	expected := "12"
-	expectedBackup := "1"
+	return mutated
+}
 
-	if expected != string(actual) || string(backup) != expectedBackup {
-		t.Errorf("expected\n%s\nand\n%s\nreceived\n%s\nand\n%s", expected, expectedBackup, string(actual), string(backup))
-	}
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			switch {
+			case a[i] == b[j]:
+				cur[j+1] = prev[j] + 1
+			case prev[j+1] > cur[j]:
+				cur[j+1] = prev[j+1]
+			default:
+				cur[j+1] = cur[j]
+			}
+		}
+		prev = cur
+	}
+
+	return prev[len(b)]
 }
 
 func createDirIfNotExist(dir string) error {
@@ -0,0 +1,122 @@
+package diffing
+
+const (
+	editEqual = iota
+	editDelete
+	editInsert
+)
+
+// edit is a single step of an edit script; a and b are line indexes into the old and new lines
+type edit struct {
+	kind int
+	a    int
+	b    int
+}
+
+// editScript returns the shortest edit script turning a into b, using the linear space variant of Myers' O(ND) algorithm
+func editScript(a, b []string) []edit {
+	s := &myersState{a: a, b: b}
+	s.diff(0, len(a), 0, len(b))
+
+	return s.edits
+}
+
+// myersState collects the edit script while the ranges of a and b are split around their middle snakes
+type myersState struct {
+	a     []string
+	b     []string
+	edits []edit
+}
+
+// diff appends the edit script turning a[aLo:aHi] into b[bLo:bHi]
+func (s *myersState) diff(aLo, aHi, bLo, bHi int) {
+	// note: the common prefix and suffix are trimmed first, state diffs usually touch a few lines of a large file
+	for aLo < aHi && bLo < bHi && s.a[aLo] == s.b[bLo] {
+		s.edits = append(s.edits, edit{kind: editEqual, a: aLo, b: bLo})
+		aLo++
+		bLo++
+	}
+	aEnd, bEnd := aHi, bHi
+	for aLo < aEnd && bLo < bEnd && s.a[aEnd-1] == s.b[bEnd-1] {
+		aEnd--
+		bEnd--
+	}
+
+	switch {
+	case aLo == aEnd:
+		for y := bLo; y < bEnd; y++ {
+			s.edits = append(s.edits, edit{kind: editInsert, a: aLo, b: y})
+		}
+	case bLo == bEnd:
+		for x := aLo; x < aEnd; x++ {
+			s.edits = append(s.edits, edit{kind: editDelete, a: x, b: bLo})
+		}
+	default:
+		x, y := s.split(aLo, aEnd, bLo, bEnd)
+		s.diff(aLo, x, bLo, y)
+		s.diff(x, aEnd, y, bEnd)
+	}
+
+	for i := 0; i < aHi-aEnd; i++ {
+		s.edits = append(s.edits, edit{kind: editEqual, a: aEnd + i, b: bEnd + i})
+	}
+}
+
+// split returns a point on a shortest edit path of the ranges, found where the forward and reverse searches overlap.
+// Only the two frontiers are kept, so the memory is linear in the length of the ranges.
+// note: the ranges are non-empty and differ in their first and last lines, so the point splits them into smaller problems
+func (s *myersState) split(aLo, aHi, bLo, bHi int) (int, int) {
+	n, m := aHi-aLo, bHi-bLo
+	max := (n + m + 1) / 2
+	offset := max + 1
+	delta := n - m
+	odd := delta%2 != 0
+
+	// note: vf holds the furthest x of the forward paths and vr the furthest distance from the end of the reverse paths, by diagonal
+	vf := make([]int, 2*max+3)
+	vr := make([]int, 2*max+3)
+	for d := 0; d <= max; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && vf[offset+k-1] < vf[offset+k+1]) {
+				x = vf[offset+k+1]
+			} else {
+				x = vf[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && s.a[aLo+x] == s.b[bLo+y] {
+				x++
+				y++
+			}
+			vf[offset+k] = x
+
+			// note: the reverse path on the same diagonal took d-1 steps
+			if odd && delta-k >= -(d-1) && delta-k <= d-1 && x+vr[offset+delta-k] >= n {
+				return aLo + x, bLo + y
+			}
+		}
+
+		for k := -d; k <= d; k += 2 {
+			var u int
+			if k == -d || (k != d && vr[offset+k-1] < vr[offset+k+1]) {
+				u = vr[offset+k+1]
+			} else {
+				u = vr[offset+k-1] + 1
+			}
+			w := u - k
+			for u < n && w < m && s.a[aHi-1-u] == s.b[bHi-1-w] {
+				u++
+				w++
+			}
+			vr[offset+k] = u
+
+			// note: the forward path on the same diagonal took d steps
+			if !odd && delta-k >= -d && delta-k <= d && u+vf[offset+delta-k] >= n {
+				return aHi - u, bHi - w
+			}
+		}
+	}
+
+	// note: unreachable, the paths always overlap by the time d reaches max
+	return aLo, bLo
+}
@@ -0,0 +1,388 @@
+package diffing
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines around each change, same as diff -u
+const contextLines = 3
+
+const noNewlineMarker = "\\ No newline at end of file"
+
+// hunk is a single @@ section of a unified diff.
+// note: lines keep their trailing newline; the last line of a file without one doesn't have it.
+type hunk struct {
+	oldStart int
+	oldCount int
+	newStart int
+	newCount int
+	lines    []hunkLine
+}
+
+type hunkLine struct {
+	op   byte
+	text string
+}
+
+// patch is a parsed single file unified diff
+type patch struct {
+	command   string // note: the optional "diff -u a b" line before the headers
+	oldHeader string
+	newHeader string
+	hunks     []*hunk
+}
+
+// splitLines splits data into lines, keeping the newlines
+func splitLines(data []byte) []string {
+	var lines []string
+	for len(data) > 0 {
+		idx := bytes.IndexByte(data, '\n')
+		if idx < 0 {
+			lines = append(lines, string(data))
+			break
+		}
+
+		lines = append(lines, string(data[:idx+1]))
+		data = data[idx+1:]
+	}
+
+	return lines
+}
+
+func joinLines(lines []string) []byte {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.WriteString(line)
+	}
+
+	return buf.Bytes()
+}
+
+// oldLines returns the lines the hunk expects in the original
+func (h *hunk) oldLines() []string {
+	var lines []string
+	for _, l := range h.lines {
+		if l.op != '+' {
+			lines = append(lines, l.text)
+		}
+	}
+
+	return lines
+}
+
+// newLines returns the lines the hunk writes in the result
+func (h *hunk) newLines() []string {
+	var lines []string
+	for _, l := range h.lines {
+		if l.op != '-' {
+			lines = append(lines, l.text)
+		}
+	}
+
+	return lines
+}
+
+// oldIndex is the zero based index of the first line the hunk replaces
+func (h *hunk) oldIndex() int {
+	if h.oldCount == 0 {
+		return h.oldStart
+	}
+
+	return h.oldStart - 1
+}
+
+// newIndex is the zero based index of the first line the hunk writes
+func (h *hunk) newIndex() int {
+	if h.newCount == 0 {
+		return h.newStart
+	}
+
+	return h.newStart - 1
+}
+
+// buildHunks groups the edit script of a and b into hunks with context.
+// aBase and bBase are the line offsets of a and b in the files they were taken from.
+func buildHunks(a, b []string, aBase, bBase int) []*hunk {
+	edits := editScript(a, b)
+
+	var (
+		hunks []*hunk
+		start = -1
+		end   = -1
+	)
+
+	flush := func() {
+		if start < 0 {
+			return
+		}
+
+		lo := start - contextLines
+		if lo < 0 {
+			lo = 0
+		}
+		hi := end + contextLines
+		if hi > len(edits)-1 {
+			hi = len(edits) - 1
+		}
+
+		h := &hunk{}
+		for i := lo; i <= hi; i++ {
+			e := edits[i]
+			switch e.kind {
+			case editEqual:
+				h.lines = append(h.lines, hunkLine{op: ' ', text: a[e.a]})
+				h.oldCount++
+				h.newCount++
+			case editDelete:
+				h.lines = append(h.lines, hunkLine{op: '-', text: a[e.a]})
+				h.oldCount++
+			case editInsert:
+				h.lines = append(h.lines, hunkLine{op: '+', text: b[e.b]})
+				h.newCount++
+			}
+		}
+
+		first := edits[lo]
+		h.oldStart = aBase + first.a + 1
+		h.newStart = bBase + first.b + 1
+		if h.oldCount == 0 {
+			h.oldStart--
+		}
+		if h.newCount == 0 {
+			h.newStart--
+		}
+
+		hunks = append(hunks, h)
+		start, end = -1, -1
+	}
+
+	for i, e := range edits {
+		if e.kind == editEqual {
+			continue
+		}
+
+		// note: changes closer than twice the context share a hunk
+		if start >= 0 && i-end > 2*contextLines {
+			flush()
+		}
+		if start < 0 {
+			start = i
+		}
+		end = i
+	}
+	flush()
+
+	return hunks
+}
+
+func formatRange(start, count int) string {
+	if count == 1 {
+		return strconv.Itoa(start)
+	}
+
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// format writes the patch in the unified format
+func (p *patch) format() []byte {
+	if len(p.hunks) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if p.command != "" {
+		fmt.Fprintf(&buf, "%s\n", p.command)
+	}
+	fmt.Fprintf(&buf, "--- %s\n", p.oldHeader)
+	fmt.Fprintf(&buf, "+++ %s\n", p.newHeader)
+
+	for _, h := range p.hunks {
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n", formatRange(h.oldStart, h.oldCount), formatRange(h.newStart, h.newCount))
+		for _, l := range h.lines {
+			buf.WriteByte(l.op)
+			buf.WriteString(l.text)
+			if !strings.HasSuffix(l.text, "\n") {
+				buf.WriteString("\n" + noNewlineMarker + "\n")
+			}
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func parseRange(s string) (int, int, error) {
+	parts := strings.SplitN(s, ",", 2)
+	start, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(parts) == 1 {
+		return start, 1, nil
+	}
+
+	count, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return start, count, nil
+}
+
+func parseHunkHeader(line string) (*hunk, error) {
+	// @@ -oldStart,oldCount +newStart,newCount @@ optional section
+	fields := strings.Fields(line)
+	if len(fields) < 4 || fields[0] != "@@" || fields[3] != "@@" ||
+		!strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return nil, ErrMalformedPatch
+	}
+
+	h := &hunk{}
+	var err error
+	if h.oldStart, h.oldCount, err = parseRange(fields[1][1:]); err != nil {
+		return nil, ErrMalformedPatch
+	}
+	if h.newStart, h.newCount, err = parseRange(fields[2][1:]); err != nil {
+		return nil, ErrMalformedPatch
+	}
+
+	return h, nil
+}
+
+func trimHeader(line, prefix string) string {
+	return strings.TrimRight(strings.TrimPrefix(line, prefix), "\r\n")
+}
+
+// headerName returns the file name of a --- or +++ header, without the timestamp
+func headerName(header string) string {
+	return strings.SplitN(header, "\t", 2)[0]
+}
+
+// parsePatch reads a single file unified diff. Lines before the --- header, such as "diff -u a b", are skipped.
+func parsePatch(data []byte) (*patch, error) {
+	lines := splitLines(data)
+	p := &patch{}
+
+	i := 0
+	for ; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ") {
+			p.oldHeader = trimHeader(lines[i], "--- ")
+			p.newHeader = trimHeader(lines[i+1], "+++ ")
+			i += 2
+			break
+		}
+	}
+
+	for i < len(lines) {
+		if !strings.HasPrefix(lines[i], "@@") {
+			// note: anything after the hunks, such as a second file, is ignored
+			break
+		}
+
+		h, err := parseHunkHeader(lines[i])
+		if err != nil {
+			return nil, err
+		}
+		i++
+
+		oldLeft, newLeft := h.oldCount, h.newCount
+		for oldLeft > 0 || newLeft > 0 {
+			if i >= len(lines) {
+				return nil, ErrMalformedPatch
+			}
+
+			line := lines[i]
+			i++
+
+			if strings.HasPrefix(line, "\\") {
+				continue
+			}
+
+			op := byte(' ')
+			text := "\n"
+			if line != "\n" && line != "" {
+				op = line[0]
+				text = line[1:]
+			}
+
+			switch op {
+			case ' ':
+				oldLeft--
+				newLeft--
+			case '-':
+				oldLeft--
+			case '+':
+				newLeft--
+			default:
+				return nil, ErrMalformedPatch
+			}
+			if oldLeft < 0 || newLeft < 0 {
+				return nil, ErrMalformedPatch
+			}
+
+			// note: the marker means the line before it is the last line of the file, without a newline
+			if i < len(lines) && strings.HasPrefix(lines[i], noNewlineMarker) {
+				text = strings.TrimSuffix(text, "\n")
+				i++
+			}
+
+			h.lines = append(h.lines, hunkLine{op: op, text: text})
+		}
+
+		p.hunks = append(p.hunks, h)
+	}
+
+	return p, nil
+}
+
+func linesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// apply applies the hunks to lines. When exact is false, each hunk is searched for around its position,
+// the way patch handles offsets.
+func (p *patch) apply(lines []string, exact bool) ([]string, error) {
+	out := make([]string, 0, len(lines))
+	pos := 0
+	delta := 0
+
+	for _, h := range p.hunks {
+		old := h.oldLines()
+		at := h.oldIndex() + delta
+
+		found := -1
+		if at >= pos && at+len(old) <= len(lines) && linesEqual(lines[at:at+len(old)], old) {
+			found = at
+		}
+		if found < 0 && !exact {
+			for offset := 1; found < 0 && (at-offset >= pos || at+offset+len(old) <= len(lines)); offset++ {
+				if lo := at - offset; lo >= pos && lo+len(old) <= len(lines) && linesEqual(lines[lo:lo+len(old)], old) {
+					found = lo
+				} else if hi := at + offset; hi >= pos && hi+len(old) <= len(lines) && linesEqual(lines[hi:hi+len(old)], old) {
+					found = hi
+				}
+			}
+		}
+		if found < 0 {
+			return nil, ErrPatchMismatch
+		}
+
+		delta += found - at
+		out = append(out, lines[pos:found]...)
+		out = append(out, h.newLines()...)
+		pos = found + len(old)
+	}
+
+	return append(out, lines[pos:]...), nil
+}
@@ -212,6 +212,7 @@ type CreateContainerConfig struct {
 	// container:host
 	Volumes map[string]string
 	Ports   map[string]string
+	Env     map[string]string
 }
 
 // CreateContainer ...
@@ -226,6 +227,7 @@ func (s *Client) CreateContainer(imageID string, cmd []string, config *CreateCon
 		Tty:          false,
 		Volumes:      map[string]struct{}{},
 		ExposedPorts: map[nat.Port]struct{}{},
+		Env:          []string{},
 	}
 
 	hostConfig := &container.HostConfig{
@@ -249,6 +251,10 @@ func (s *Client) CreateContainer(imageID string, cmd []string, config *CreateCon
 		}
 	}
 
+	for k, v := range config.Env {
+		dockerConfig.Env = append(dockerConfig.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+
 	if len(config.Ports) > 0 {
 		for k, v := range config.Ports {
 			t, err := nat.NewPort("tcp", k)
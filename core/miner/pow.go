@@ -0,0 +1,243 @@
+package miner
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/c3systems/c3-go/common/hashutil"
+	"github.com/c3systems/c3-go/core/chain/mainchain"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// nonceHexLen is the number of hex digits in a nonce, excluding the 0x prefix
+	nonceHexLen = 64
+	// counterHexLen is the number of trailing nonce hex digits a hashing worker increments
+	counterHexLen = 16
+	// hashBatchSize is the number of hashes a worker tries between checks for cancellation
+	hashBatchSize = 1024
+	// hashrateLogInterval is how often the hashrate is logged while mining
+	hashrateLogInterval = 10 * time.Second
+
+	hextable = "0123456789abcdef"
+)
+
+// ErrNonceNotFound is returned when the header template can't locate the nonce in the serialized block
+var ErrNonceNotFound = errors.New("nonce not found in serialized block")
+
+// powStats are the hashing stats of the miner's nonce search
+type powStats struct {
+	hashes  uint64 // note: first for 64 bit alignment of atomic ops
+	started int64  // unix nano
+	stopped int64  // unix nano
+}
+
+// Hashrate returns the hashes per second of the miner's nonce search, or 0 when it hasn't started
+func (s Service) Hashrate() float64 {
+	if s.pow == nil {
+		return 0
+	}
+
+	return s.pow.hashrate()
+}
+
+func (p *powStats) start() {
+	atomic.StoreUint64(&p.hashes, 0)
+	atomic.StoreInt64(&p.stopped, 0)
+	atomic.StoreInt64(&p.started, time.Now().UnixNano())
+}
+
+func (p *powStats) stop() {
+	atomic.StoreInt64(&p.stopped, time.Now().UnixNano())
+}
+
+func (p *powStats) add(n uint64) {
+	atomic.AddUint64(&p.hashes, n)
+}
+
+func (p *powStats) hashrate() float64 {
+	started := atomic.LoadInt64(&p.started)
+	if started == 0 {
+		return 0
+	}
+
+	end := atomic.LoadInt64(&p.stopped)
+	if end == 0 {
+		end = time.Now().UnixNano()
+	}
+
+	elapsed := time.Duration(end - started).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+
+	return float64(atomic.LoadUint64(&p.hashes)) / elapsed
+}
+
+// headerTemplate is the serialized block the hash is calculated from, with the offset of the nonce's hex digits.
+// note: the nonce is fixed width, so writing its digits in place hashes the same bytes as CalculateHash.
+type headerTemplate struct {
+	buf    []byte
+	offset int
+}
+
+// newHeaderTemplate serializes the block's hashed props once and locates its nonce
+func newHeaderTemplate(block *mainchain.Block) (*headerTemplate, error) {
+	if block == nil {
+		return nil, ErrNilBlock
+	}
+
+	low, err := serializeWithNonce(block, '0')
+	if err != nil {
+		return nil, err
+	}
+	high, err := serializeWithNonce(block, 'f')
+	if err != nil {
+		return nil, err
+	}
+	if len(low) != len(high) {
+		return nil, ErrNonceNotFound
+	}
+
+	offset := 0
+	for offset < len(low) && low[offset] == high[offset] {
+		offset++
+	}
+	if offset+nonceHexLen > len(low) || !bytes.Equal(low[offset+nonceHexLen:], high[offset+nonceHexLen:]) {
+		return nil, ErrNonceNotFound
+	}
+
+	return &headerTemplate{
+		buf:    low,
+		offset: offset,
+	}, nil
+}
+
+// serializeWithNonce serializes the props CalculateHashBytes hashes, with a nonce of repeated hex digits
+func serializeWithNonce(block *mainchain.Block, digit byte) ([]byte, error) {
+	props := block.Props()
+	props.BlockHash = nil
+	props.MinerSig = nil
+	props.Nonce = "0x" + string(bytes.Repeat([]byte{digit}, nonceHexLen))
+
+	return mainchain.New(&props).Serialize()
+}
+
+// meetsDifficulty returns true when the hash starts with difficulty zero hex digits, like CheckHashAgainstDifficulty
+func meetsDifficulty(hash []byte, difficulty uint64) bool {
+	if difficulty >= uint64(2*len(hash)) {
+		return false
+	}
+
+	for i := uint64(0); i < difficulty; i++ {
+		b := hash[i/2]
+		if i%2 == 0 {
+			b >>= 4
+		}
+		if b&0x0f != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// putHex writes the hex digits of n into dst, most significant first
+func putHex(dst []byte, n uint64) {
+	for i := len(dst) - 1; i >= 0; i-- {
+		dst[i] = hextable[n&0x0f]
+		n >>= 4
+	}
+}
+
+// searchNonce searches for a nonce whose block hash meets the difficulty on the number of workers.
+// Each worker starts from a random nonce and increments its trailing digits in its own copy of the template.
+func searchNonce(ctx context.Context, tmpl *headerTemplate, difficulty uint64, workers int, stats *powStats) (string, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		once  sync.Once
+		nonce string
+		wg    sync.WaitGroup
+	)
+
+	for i := 0; i < workers; i++ {
+		buf := append([]byte{}, tmpl.buf...)
+		digits := buf[tmpl.offset : tmpl.offset+nonceHexLen]
+
+		// note: the random prefix keeps the workers, and other miners, from searching the same nonces
+		prefix := make([]byte, (nonceHexLen-counterHexLen)/2)
+		if _, err := rand.Read(prefix); err != nil {
+			return "", err
+		}
+		for j, b := range prefix {
+			digits[2*j] = hextable[b>>4]
+			digits[2*j+1] = hextable[b&0x0f]
+		}
+		counter := digits[nonceHexLen-counterHexLen:]
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var n uint64
+			for {
+				if ctx.Err() != nil {
+					return
+				}
+
+				for j := 0; j < hashBatchSize; j++ {
+					putHex(counter, n)
+					n++
+
+					hash := hashutil.Hash(buf)
+					if meetsDifficulty(hash[:], difficulty) {
+						stats.add(uint64(j + 1))
+						once.Do(func() {
+							nonce = "0x" + string(digits)
+							cancel()
+						})
+						return
+					}
+				}
+				stats.add(hashBatchSize)
+			}
+		}()
+	}
+
+	go logHashrate(ctx, stats)
+
+	wg.Wait()
+	if nonce != "" {
+		return nonce, nil
+	}
+
+	return "", ctx.Err()
+}
+
+// logHashrate logs the miner's hashrate until the nonce search is done
+func logHashrate(ctx context.Context, stats *powStats) {
+	ticker := time.NewTicker(hashrateLogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			log.Printf("[miner] hashrate %.0f H/s", stats.hashrate())
+		}
+	}
+}
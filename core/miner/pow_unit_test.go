@@ -0,0 +1,137 @@
+// +build unit
+
+package miner
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/c3systems/c3-go/common/hashutil"
+	"github.com/c3systems/c3-go/common/hexutil"
+	"github.com/c3systems/c3-go/core/chain/mainchain"
+)
+
+func buildPoWBlock(t *testing.T) *mainchain.Block {
+	return mainchain.New(&mainchain.Props{
+		BlockNumber:           hexutil.EncodeUint64(7),
+		BlockTime:             hexutil.EncodeUint64(uint64(time.Now().Unix())),
+		ImageHash:             mainchain.ImageHash,
+		StateBlocksMerkleHash: "0x1234",
+		PrevBlockHash:         mainchain.GenesisBlockHash,
+		Nonce:                 "0x",
+		Difficulty:            hexutil.EncodeUint64(2),
+		MinerAddress:          "0xabcd",
+	})
+}
+
+func TestHeaderTemplate(t *testing.T) {
+	block := buildPoWBlock(t)
+	tmpl, err := newHeaderTemplate(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nonce := make([]byte, nonceHexLen/2)
+	if _, err := rand.Read(nonce); err != nil {
+		t.Fatal(err)
+	}
+	nonceHex := hexutil.EncodeToString(nonce)
+
+	buf := append([]byte{}, tmpl.buf...)
+	copy(buf[tmpl.offset:tmpl.offset+nonceHexLen], nonceHex[2:])
+
+	props := block.Props()
+	props.Nonce = nonceHex
+	expected, err := mainchain.New(&props).CalculateHashBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := hashutil.Hash(buf)
+	if string(got[:]) != string(expected) {
+		t.Errorf("expected template hash %x; received %x", expected, got)
+	}
+}
+
+func TestMeetsDifficulty(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		hash := make([]byte, 32)
+		if _, err := rand.Read(hash); err != nil {
+			t.Fatal(err)
+		}
+		// note: clear some leading nibbles so the higher difficulties are exercised
+		for j := 0; j < i%8 && j < len(hash); j++ {
+			hash[j] = 0
+		}
+
+		for difficulty := uint64(0); difficulty < 20; difficulty++ {
+			expected, err := CheckHashAgainstDifficulty(hexutil.EncodeToString(hash), difficulty)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := meetsDifficulty(hash, difficulty); got != expected {
+				t.Errorf("hash %x difficulty %d; expected %v, received %v", hash, difficulty, expected, got)
+			}
+		}
+	}
+
+	if meetsDifficulty(make([]byte, 32), 64) {
+		t.Error("expected a difficulty of the full hash length to fail")
+	}
+}
+
+func TestSearchNonce(t *testing.T) {
+	block := buildPoWBlock(t)
+	tmpl, err := newHeaderTemplate(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var difficulty uint64 = 3
+	stats := &powStats{}
+	stats.start()
+	nonce, err := searchNonce(context.Background(), tmpl, difficulty, 4, stats)
+	stats.stop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nonce) != nonceHexLen+2 {
+		t.Errorf("expected a nonce of length %d; received %s", nonceHexLen+2, nonce)
+	}
+	if stats.hashes == 0 || stats.hashrate() <= 0 {
+		t.Errorf("expected hashes to be counted; received %d hashes at %v H/s", stats.hashes, stats.hashrate())
+	}
+
+	props := block.Props()
+	props.Nonce = nonce
+	hash, err := mainchain.New(&props).CalculateHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := CheckHashAgainstDifficulty(hash, difficulty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Errorf("expected hash %s of nonce %s to meet difficulty %d", hash, nonce, difficulty)
+	}
+}
+
+func TestSearchNonceCanceled(t *testing.T) {
+	tmpl, err := newHeaderTemplate(buildPoWBlock(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// note: a difficulty of the full hash length is never met
+	if _, err := searchNonce(ctx, tmpl, 64, 2, &powStats{}); err != context.DeadlineExceeded {
+		t.Errorf("expected %v; received %v", context.DeadlineExceeded, err)
+	}
+}
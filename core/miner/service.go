@@ -4,23 +4,16 @@ import (
 	"crypto/rand"
 	"encoding/json"
 	"errors"
-	"fmt"
-	"io/ioutil"
-	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 
-	"github.com/c3systems/c3-go/common/dirutil"
-	"github.com/c3systems/c3-go/common/fileutil"
-	"github.com/c3systems/c3-go/common/hashutil"
 	"github.com/c3systems/c3-go/common/hexutil"
 	"github.com/c3systems/c3-go/core/chain/mainchain"
 	"github.com/c3systems/c3-go/core/chain/merkle"
 	"github.com/c3systems/c3-go/core/chain/statechain"
-	"github.com/c3systems/c3-go/core/diffing"
-	"github.com/c3systems/c3-go/core/p2p"
 	"github.com/c3systems/c3-go/core/sandbox"
 	methodTypes "github.com/c3systems/c3-go/core/types/methods"
 	colorlog "github.com/c3systems/c3-go/log/color"
@@ -50,6 +43,10 @@ func New(props *Props) (*Service, error) {
 			DiffsMap:            diffsMap,
 			MerkleTreesMap:      merkleTreesMap,
 		},
+		built: &builtStates{
+			images: make(map[string]*ImageStates),
+		},
+		pow: &powStats{},
 	}
 
 	nextBlock, err := s.bootstrapNextBlock()
@@ -66,6 +63,20 @@ func (s Service) Props() Props {
 	return s.props
 }
 
+// BuiltStates returns the image states the miner has built so far; they can be passed to the next miner
+// as its PrebuiltStates when this one is canceled.
+func (s Service) BuiltStates() []*ImageStates {
+	s.built.mut.Lock()
+	defer s.built.mut.Unlock()
+
+	var states []*ImageStates
+	for _, image := range s.built.images {
+		states = append(states, image)
+	}
+
+	return states
+}
+
 // SpawnMiner ...
 func (s Service) SpawnMiner() error {
 	// TODO: reward ourselves with some coin
@@ -120,6 +131,10 @@ func (s Service) buildMainchainBlockAsync() error {
 			return errors.New("image hash is required")
 		}
 
+		if s.resumeStates(imageHash, transactions) {
+			continue
+		}
+
 		wg.Add(1)
 		go func(iHash string, txs []*statechain.Transaction) {
 			defer wg.Done()
@@ -158,6 +173,9 @@ func (s Service) buildMainchainBlock() error {
 		if s.props.Context.Err() != nil {
 			return s.props.Context.Err()
 		}
+		if s.resumeStates(imageHash, transactions) {
+			continue
+		}
 
 		if err := s.buildNextStates(imageHash, transactions); err != nil {
 			log.Errorf("[miner] err mining state block for hash %s transactions %v: %v", imageHash, transactions, err)
@@ -176,41 +194,43 @@ func (s Service) mineBlock() error {
 		return err
 	}
 
-	for {
-		if s.props.Context.Err() != nil {
-			return s.props.Context.Err()
-		}
+	var (
+		nonce string
+		err   error
+	)
 
-		hash, nonce, err := s.generateHashAndNonce()
+	// NOTE: simulated is for testing, auto accepts first block hash mined
+	if s.props.Simulated {
+		nonce, err = s.generateNonce()
 		if err != nil {
-			log.Errorf("[miner] error generating hash and nonce; %s", err)
+			log.Errorf("[miner] error generating nonce; %s", err)
 			return err
 		}
-
-		check, err := CheckHashAgainstDifficulty(hash, s.props.Difficulty)
+		time.Sleep(2 * time.Second)
+	} else {
+		tmpl, err := newHeaderTemplate(s.minedBlock.NextBlock)
 		if err != nil {
-			log.Errorf("[miner] error checking hash against difficulty; %s", err)
+			log.Errorf("[miner] error building header template; %s", err)
 			return err
 		}
 
-		// NOTE: simulated is for testing, auto accepts first block hash mined
-		if s.props.Simulated {
-			check = true
-			time.Sleep(2 * time.Second)
+		s.pow.start()
+		nonce, err = searchNonce(s.props.Context, tmpl, s.props.Difficulty, s.props.Threads, s.pow)
+		s.pow.stop()
+		if err != nil {
+			log.Errorf("[miner] error searching for nonce; %s", err)
+			return err
 		}
+		log.Printf("[miner] found nonce after %d hashes at %.0f H/s", atomic.LoadUint64(&s.pow.hashes), s.Hashrate())
+	}
 
-		if check {
-			nextProps := s.minedBlock.NextBlock.Props()
-			nextProps.Nonce = nonce
-			nextBlock := mainchain.New(&nextProps)
-			s.minedBlock.NextBlock = nextBlock
-
-			log.Println("[miner] difficulty checks out")
-			return s.minedBlock.NextBlock.SetHash()
-		}
+	nextProps := s.minedBlock.NextBlock.Props()
+	nextProps.Nonce = nonce
+	nextBlock := mainchain.New(&nextProps)
+	s.minedBlock.NextBlock = nextBlock
 
-		// note: else the for loop continues and we try the next hash
-	}
+	log.Println("[miner] difficulty checks out")
+	return s.minedBlock.NextBlock.SetHash()
 }
 
 func (s Service) generateMerkle() error {
@@ -266,20 +286,6 @@ func (s Service) generateMerkle() error {
 	return nil
 }
 
-func (s Service) generateHashAndNonce() (string, string, error) {
-	nonce, err := s.generateNonce()
-	if err != nil {
-		return "", "", err
-	}
-
-	tmpProps := s.minedBlock.NextBlock.Props()
-	tmpProps.Nonce = nonce
-	tmpBlock := mainchain.New(&tmpProps)
-
-	hash, err := tmpBlock.CalculateHash()
-	return hash, nonce, err
-}
-
 func (s Service) generateNonce() (string, error) {
 	bytes := make([]byte, 32)
 	if _, err := rand.Read(bytes); err != nil {
@@ -321,7 +327,9 @@ func (s Service) bootstrapNextBlock() (*mainchain.Block, error) {
 func (s Service) buildNextStates(imageHash string, transactions []*statechain.Transaction) error {
 	var (
 		diffs          []*statechain.Diff
+		state          []byte
 		prevStateBlock *statechain.Block
+		built          = &ImageStates{ImageHash: imageHash}
 	)
 
 	log.Println(colorlog.Cyan("[miner] processing %v transactions for image hash %s", len(transactions), imageHash))
@@ -346,7 +354,7 @@ func (s Service) buildNextStates(imageHash string, transactions []*statechain.Tr
 	}
 	if isGenesisTx {
 		log.Printf("[miner] is genesis tx for image hash %s", imageHash)
-		genesisBlock, diff, err := buildGenesisStateBlock(imageHash, tx)
+		genesisBlock, diff, err := buildGenesisStateBlock(s.props.P2P, imageHash, tx)
 		if err != nil {
 			log.Printf("[miner] err buildingGenesisStateBlock\n%v", err)
 			return err
@@ -360,8 +368,25 @@ func (s Service) buildNextStates(imageHash string, transactions []*statechain.Tr
 		s.minedBlock.mut.Unlock()
 		log.Printf("[miner] mined state block for image hash %s", imageHash)
 
+		built.Transactions = append(built.Transactions, tx)
+		built.Blocks = append(built.Blocks, genesisBlock)
+		built.Diffs = append(built.Diffs, diff)
+
 		prevStateBlock = genesisBlock
 		diffs = append(diffs, diff)
+
+		for i := range diffs {
+			log.Printf("[miner] diff %v\n%s", i, diffs[i].Props().Data)
+		}
+
+		// apply the genesis diff to get the current state
+		var genesisState []byte
+		state, err = GenerateStateFromDiffs(s.props.Context, imageHash, genesisState, diffs)
+		if err != nil {
+			log.Errorf("[miner] error getting state from diffs for image hash %s\n%v", imageHash, err)
+			return err
+		}
+		StateCache(s.props.P2P).Add(imageHash, *genesisBlock.Props().BlockHash, state)
 	} else {
 		prevStateBlock, err = s.props.P2P.FetchMostRecentStateBlock(imageHash, s.props.PreviousBlock)
 		if err != nil {
@@ -374,32 +399,19 @@ func (s Service) buildNextStates(imageHash string, transactions []*statechain.Tr
 			return errors.New("prev block is nil")
 		}
 
+		if prevStateBlock.Props().BlockHash == nil {
+			log.Println("[miner] prev block hash is nil")
+			return errors.New("prev block hash is nil")
+		}
+
 		log.Printf("[miner] prev state block; block number: %s; block hash: %s", prevStateBlock.Props().BlockNumber, *prevStateBlock.Props().BlockHash)
 
-		// gather the diffs
-		diffs, err = s.GatherDiffs(prevStateBlock)
+		// note: the state is looked up in the cache before it's rebuilt from the diffs
+		state, err = reconstructState(s.props.Context, s.props.P2P, prevStateBlock)
 		if err != nil {
-			log.Errorf("[miner] error getting cid by hash for image hash %s\n%v", imageHash, err)
+			log.Errorf("[miner] error reconstructing state for image hash %s\n%v", imageHash, err)
 			return err
 		}
-		log.Printf("[miner] total diffs %v", len(diffs))
-	}
-
-	for i := range diffs {
-		log.Printf("[miner] diff %v\n%s", i, diffs[i].Props().Data)
-	}
-
-	if diffs == nil {
-		log.Errorf("[miner] error building next state for image hash %s; diffs list is nil", imageHash)
-		return errors.New("diffs is nil")
-	}
-
-	// apply the diffs to get the current state
-	var genesisState []byte
-	state, err := GenerateStateFromDiffs(s.props.Context, imageHash, genesisState, diffs)
-	if err != nil {
-		log.Errorf("[miner] error getting state from diffs for image hash %s\n%v", imageHash, err)
-		return err
 	}
 
 	colorlog.Yellow("[miner] generated state from diffs: %s", string(state))
@@ -412,111 +424,109 @@ func (s Service) buildNextStates(imageHash string, transactions []*statechain.Tr
 
 	// write to the mined block
 	s.minedBlock.mut.Lock()
-	defer s.minedBlock.mut.Unlock()
 	// note: they should all have same length
 	for i := 0; i < len(newDiffs); i++ {
 		s.minedBlock.DiffsMap[*newDiffs[i].Props().DiffHash] = newDiffs[i]
 		s.minedBlock.TransactionsMap[*transactions[i].Props().TxHash] = transactions[i]
 		s.minedBlock.StatechainBlocksMap[*newStatechainBlocks[i].Props().BlockHash] = newStatechainBlocks[i]
 	}
+	s.minedBlock.mut.Unlock()
+
+	built.Transactions = append(built.Transactions, transactions...)
+	built.Blocks = append(built.Blocks, newStatechainBlocks...)
+	built.Diffs = append(built.Diffs, newDiffs...)
+	s.recordBuiltStates(built)
 
 	return nil
 }
 
-// GatherDiffs ...
-func (s *Service) GatherDiffs(block *statechain.Block) ([]*statechain.Diff, error) {
-	var diffs []*statechain.Diff
-
-	if block == nil {
-		log.Error("[miner] can't gather diffs because block is nil; returning empty list")
-		return diffs, nil
+// resumeStates writes the image's prebuilt states to the mined block when they were built from the image's
+// pending transactions, and returns true if they were.
+// note: the node only passes the states of images the new previous block didn't touch, so the image's most
+// recent state block is the one they were built on.
+func (s Service) resumeStates(imageHash string, transactions []*statechain.Transaction) bool {
+	var prebuilt *ImageStates
+	for _, image := range s.props.PrebuiltStates {
+		if image != nil && image.ImageHash == imageHash {
+			prebuilt = image
+			break
+		}
 	}
-
-	diffCID, err := p2p.GetCIDByHash(block.Props().StatePrevDiffHash)
-	if err != nil {
-		return nil, err
+	if prebuilt == nil || !sameTransactions(prebuilt.Transactions, transactions) {
+		return false
+	}
+	if len(prebuilt.Blocks) != len(prebuilt.Transactions) || len(prebuilt.Diffs) != len(prebuilt.Transactions) {
+		return false
+	}
+	for i := range prebuilt.Blocks {
+		if prebuilt.Blocks[i] == nil || prebuilt.Blocks[i].Props().BlockHash == nil || prebuilt.Diffs[i] == nil || prebuilt.Diffs[i].Props().DiffHash == nil {
+			return false
+		}
 	}
 
-	diff, err := s.props.P2P.GetStatechainDiff(diffCID)
-	if err != nil {
-		return nil, err
+	s.minedBlock.mut.Lock()
+	for i := range prebuilt.Blocks {
+		s.minedBlock.DiffsMap[*prebuilt.Diffs[i].Props().DiffHash] = prebuilt.Diffs[i]
+		s.minedBlock.TransactionsMap[*prebuilt.Transactions[i].Props().TxHash] = prebuilt.Transactions[i]
+		s.minedBlock.StatechainBlocksMap[*prebuilt.Blocks[i].Props().BlockHash] = prebuilt.Blocks[i]
 	}
-	// note: prepend
-	diffs = append([]*statechain.Diff{diff}, diffs...)
+	s.minedBlock.mut.Unlock()
 
-	head := block
-	for head.Props().BlockNumber != mainchain.GenesisBlock.Props().BlockNumber {
-		if s.props.Context.Err() != nil {
-			return nil, s.props.Context.Err()
-		}
+	s.recordBuiltStates(prebuilt)
+	log.Printf("[miner] resumed %v state blocks for image hash %s", len(prebuilt.Blocks), imageHash)
 
-		prevStateCID, err := p2p.GetCIDByHash(head.Props().PrevBlockHash)
-		if err != nil {
-			return nil, err
-		}
+	return true
+}
 
-		prevStateBlock, err := s.props.P2P.GetStatechainBlock(prevStateCID)
-		if err != nil {
-			return nil, err
-		}
-		head = prevStateBlock
+func (s Service) recordBuiltStates(image *ImageStates) {
+	s.built.mut.Lock()
+	defer s.built.mut.Unlock()
 
-		diffCID, err := p2p.GetCIDByHash(prevStateBlock.Props().StatePrevDiffHash)
-		if err != nil {
-			return nil, err
+	s.built.images[image.ImageHash] = image
+}
+
+// sameTransactions returns true if the lists have the same transactions, in any order
+func sameTransactions(a, b []*statechain.Transaction) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	hashes := make(map[string]int)
+	for _, tx := range a {
+		if tx == nil || tx.Props().TxHash == nil {
+			return false
 		}
-		diff, err := s.props.P2P.GetStatechainDiff(diffCID)
-		if err != nil {
-			return nil, err
+		hashes[*tx.Props().TxHash]++
+	}
+	for _, tx := range b {
+		if tx == nil || tx.Props().TxHash == nil || hashes[*tx.Props().TxHash] == 0 {
+			return false
 		}
-		// note: prepend
-		diffs = append([]*statechain.Diff{diff}, diffs...)
+		hashes[*tx.Props().TxHash]--
+	}
+
+	return true
+}
+
+// GatherDiffs gathers the diffs of the block and every block before it, back to genesis
+func (s *Service) GatherDiffs(block *statechain.Block) ([]*statechain.Diff, error) {
+	if block == nil {
+		log.Error("[miner] can't gather diffs because block is nil; returning empty list")
+		return nil, nil
 	}
 
-	return diffs, nil
+	_, diffs, err := gatherDiffs(s.props.Context, s.props.P2P, block, false)
+	return diffs, err
 }
 
 func (s *Service) buildStateblocksAndDiffsFromStateAndTransactions(prevStateBlock *statechain.Block, imageHash string, state []byte, transactions []*statechain.Transaction) ([]*statechain.Block, []*statechain.Diff, error) {
 	var (
 		newDiffs            []*statechain.Diff
 		newStatechainBlocks []*statechain.Block
-		fileNames           []string
 	)
-	defer cleanupDirs(&fileNames)
-	defer cleanupFiles(&fileNames)
 
 	ts := time.Now().Unix()
 
-	stateFile, err := fileutil.CreateTempFile(fmt.Sprintf("%s/%v/%s", imageHash, ts, StateFileName))
-	if err != nil {
-		return nil, nil, err
-	}
-	fileNames = append(fileNames, stateFile.Name())
-	if _, err = stateFile.Write(state); err != nil {
-		return nil, nil, err
-	}
-	if err = stateFile.Close(); err != nil {
-		return nil, nil, err
-	}
-
-	nextStateFile, err := fileutil.CreateTempFile(fmt.Sprintf("%s/%v/nextState.txt", imageHash, ts))
-	if err != nil {
-		return nil, nil, err
-	}
-	fileNames = append(fileNames, nextStateFile.Name()) // clean up
-	if err = nextStateFile.Close(); err != nil {
-		return nil, nil, err
-	}
-
-	patchFile, err := fileutil.CreateTempFile(fmt.Sprintf("%s/%v/diff.patch", imageHash, ts))
-	if err != nil {
-		return nil, nil, err
-	}
-	fileNames = append(fileNames, patchFile.Name())
-	if err = patchFile.Close(); err != nil {
-		return nil, nil, err
-	}
-
 	runningBlockNumber, err := hexutil.DecodeUint64(prevStateBlock.Props().BlockNumber)
 	if err != nil {
 		return nil, nil, err
@@ -539,7 +549,8 @@ func (s *Service) buildStateblocksAndDiffsFromStateAndTransactions(prevStateBloc
 			return nil, nil, errors.New("nil tx hash")
 		}
 
-		var nextState []byte
+		// note: txs that don't invoke a method leave the state as is
+		nextState := runningState
 		log.Printf("[miner] tx method %s", tx.Props().Method)
 
 		if tx.Props().Method == methodTypes.InvokeMethod {
@@ -568,45 +579,28 @@ func (s *Service) buildStateblocksAndDiffsFromStateAndTransactions(prevStateBloc
 			}
 
 			log.Printf("[miner] container new state: %s", string(nextState))
-
-			if err := dirutil.CreateDirIfNotExist("/tmp/" + imageHash); err != nil {
-				return nil, nil, err
-			}
-			filepath := fmt.Sprintf("/tmp/%s/%s", imageHash, StateFileName)
-			err = ioutil.WriteFile(filepath, nextState, os.FileMode(0666))
-			if err != nil {
-				return nil, nil, err
-			}
-			log.Printf("[miner] latest state file path for image %s: %s", imageHash, filepath)
-		}
-
-		if err := ioutil.WriteFile(nextStateFile.Name(), nextState, os.ModePerm); err != nil {
-			return nil, nil, err
 		}
 
-		if err = diffing.Diff(stateFile.Name(), nextStateFile.Name(), patchFile.Name(), false); err != nil {
+		diffStruct, err := buildStateDiff(runningState, nextState)
+		if err != nil {
 			return nil, nil, err
 		}
 
-		// build the diff struct
-		diffData, err := ioutil.ReadFile(patchFile.Name())
+		nextStateHash, err := stateRootHash(s.props.P2P, nextState)
 		if err != nil {
 			return nil, nil, err
 		}
-
-		diffStruct := statechain.NewDiff(&statechain.DiffProps{
-			Data: string(diffData),
-		})
-		if err := diffStruct.SetHash(); err != nil {
-			return nil, nil, err
+		runningBlockNumber++
+		var snapshotCID string
+		if isSnapshotBlockNumber(runningBlockNumber) {
+			if snapshotCID, err = buildStateSnapshot(s.props.P2P, nextState); err != nil {
+				log.Errorf("[miner] error building state snapshot for image hash %s; %s", imageHash, err)
+				return nil, nil, err
+			}
 		}
-
-		nextStateHashBytes := hashutil.Hash(nextState)
-		nextStateHash := hexutil.EncodeToString(nextStateHashBytes[:])
 		log.Printf("[miner] state prev diff hash: %s", *diffStruct.Props().DiffHash)
 		log.Printf("[miner] state current hash: %s", nextStateHash)
 
-		runningBlockNumber++
 		nextStateStruct := statechain.New(&statechain.BlockProps{
 			BlockNumber:       hexutil.EncodeUint64(runningBlockNumber),
 			BlockTime:         hexutil.EncodeUint64(uint64(ts)),
@@ -615,21 +609,19 @@ func (s *Service) buildStateblocksAndDiffsFromStateAndTransactions(prevStateBloc
 			PrevBlockHash:     runningBlockHash,
 			StatePrevDiffHash: *diffStruct.Props().DiffHash, // note: used setHash, above so it would've erred
 			StateCurrentHash:  nextStateHash,
+			StateSnapshotCID:  snapshotCID,
 		})
 		if err := nextStateStruct.SetHash(); err != nil {
 			return nil, nil, err
 		}
 		runningBlockHash = *nextStateStruct.Props().BlockHash
+		StateCache(s.props.P2P).Add(imageHash, runningBlockHash, nextState)
 
 		newDiffs = append(newDiffs, diffStruct)
 		newStatechainBlocks = append(newStatechainBlocks, nextStateStruct)
 
 		// get ready for the next loop
 		runningState = nextState
-
-		if err := ioutil.WriteFile(stateFile.Name(), nextState, os.ModePerm); err != nil {
-			return nil, nil, err
-		}
 	}
 
 	return newStatechainBlocks, newDiffs, nil
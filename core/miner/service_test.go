@@ -30,7 +30,7 @@ func TestBuildGenesisStateBlock(t *testing.T) {
 	tx := statechain.NewTransaction(&statechain.TransactionProps{
 		ImageHash: imageHash,
 		Method:    methodTypes.InvokeMethod,
-		Payload:   []byte(`["setItem", "foo", "bar"]`),
+		Payload:   []byte(`{"0x666f6f":"0x626172"}`),
 		From:      encodedPub,
 	})
 	err = tx.SetHash()
@@ -54,7 +54,7 @@ func TestBuildGenesisStateBlock(t *testing.T) {
 	// 	t.Error(err)
 	// }
 
-	_, _, err = buildGenesisStateBlock(imageHash, txs[0])
+	_, _, err = buildGenesisStateBlock(nil, imageHash, txs[0])
 	if err != nil {
 		t.Error(err)
 	}
@@ -0,0 +1,98 @@
+// +build unit
+
+package miner
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/c3systems/c3-go/core/chain/statechain"
+)
+
+func buildImageStates(t *testing.T, imageHash string, n int) *ImageStates {
+	image := &ImageStates{
+		ImageHash: imageHash,
+	}
+
+	for i := 0; i < n; i++ {
+		txHash := fmt.Sprintf("%s-tx%d", imageHash, i)
+		tx := statechain.NewTransaction(&statechain.TransactionProps{
+			TxHash:    &txHash,
+			ImageHash: imageHash,
+		})
+
+		diff := statechain.NewDiff(&statechain.DiffProps{
+			Data: fmt.Sprintf("diff%d", i),
+		})
+		if err := diff.SetHash(); err != nil {
+			t.Fatal(err)
+		}
+
+		block := statechain.New(&statechain.BlockProps{
+			BlockNumber:       fmt.Sprintf("0x%x", i+1),
+			ImageHash:         imageHash,
+			TxHash:            txHash,
+			StatePrevDiffHash: *diff.Props().DiffHash,
+		})
+		if err := block.SetHash(); err != nil {
+			t.Fatal(err)
+		}
+
+		image.Transactions = append(image.Transactions, tx)
+		image.Diffs = append(image.Diffs, diff)
+		image.Blocks = append(image.Blocks, block)
+	}
+
+	return image
+}
+
+func TestResumeStates(t *testing.T) {
+	t.Parallel()
+
+	resumed := buildImageStates(t, "image1", 2)
+	stale := buildImageStates(t, "image2", 2)
+
+	svc, err := New(&Props{
+		Context:        context.Background(),
+		PrebuiltStates: []*ImageStates{resumed, stale},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// note: the pending transactions may be in another order
+	pending := []*statechain.Transaction{resumed.Transactions[1], resumed.Transactions[0]}
+	if ok := svc.resumeStates("image1", pending); !ok {
+		t.Fatal("expected the prebuilt states to be resumed")
+	}
+
+	// note: a new pending transaction means the states are rebuilt
+	extra := buildImageStates(t, "image2", 3)
+	if ok := svc.resumeStates("image2", extra.Transactions); ok {
+		t.Error("expected the prebuilt states not to be resumed")
+	}
+	if ok := svc.resumeStates("image3", nil); ok {
+		t.Error("expected no prebuilt states")
+	}
+
+	for i := range resumed.Blocks {
+		if block := svc.minedBlock.StatechainBlocksMap[*resumed.Blocks[i].Props().BlockHash]; block != resumed.Blocks[i] {
+			t.Errorf("state block %d\nexpected %v\nreceived %v", i+1, resumed.Blocks[i], block)
+		}
+		if tx := svc.minedBlock.TransactionsMap[*resumed.Transactions[i].Props().TxHash]; tx != resumed.Transactions[i] {
+			t.Errorf("tx %d\nexpected %v\nreceived %v", i+1, resumed.Transactions[i], tx)
+		}
+		if diff := svc.minedBlock.DiffsMap[*resumed.Diffs[i].Props().DiffHash]; diff != resumed.Diffs[i] {
+			t.Errorf("diff %d\nexpected %v\nreceived %v", i+1, resumed.Diffs[i], diff)
+		}
+	}
+	if len(svc.minedBlock.StatechainBlocksMap) != len(resumed.Blocks) {
+		t.Errorf("expected %v\nreceived %v", len(resumed.Blocks), len(svc.minedBlock.StatechainBlocksMap))
+	}
+
+	if built := svc.BuiltStates(); !reflect.DeepEqual([]*ImageStates{resumed}, built) {
+		t.Errorf("expected %v\nreceived %v", []*ImageStates{resumed}, built)
+	}
+}
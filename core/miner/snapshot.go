@@ -0,0 +1,215 @@
+package miner
+
+import (
+	"context"
+	"errors"
+
+	"github.com/c3systems/c3-go/core/chain/mainchain"
+	"github.com/c3systems/c3-go/core/chain/statechain"
+	"github.com/c3systems/c3-go/core/p2p"
+	"github.com/c3systems/c3-go/state"
+
+	blocks "github.com/ipfs/go-block-format"
+	cid "github.com/ipfs/go-cid"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrSnapshotMismatch is returned when a state snapshot doesn't hash to the state hash of its block
+var ErrSnapshotMismatch = errors.New("state snapshot doesn't match the state block")
+
+// isSnapshotBlockNumber returns true when the state block with the number commits to a snapshot of the full state
+func isSnapshotBlockNumber(blockNumber uint64) bool {
+	return blockNumber > 0 && blockNumber%StateSnapshotInterval == 0
+}
+
+// buildStateSnapshot stores the full state and returns the cid a state block commits to.
+// note: the state is serialized canonically, so every node derives the same cid from the same state.
+func buildStateSnapshot(p2pSvc p2p.Interface, st []byte) (string, error) {
+	parsed, err := state.Parse(st)
+	if err != nil {
+		return "", err
+	}
+	data, err := parsed.Serialize()
+	if err != nil {
+		return "", err
+	}
+
+	c, err := p2p.GetBytesCID(data)
+	if err != nil {
+		return "", err
+	}
+	if p2pSvc != nil {
+		if _, err := p2pSvc.SetBytes(data); err != nil {
+			log.Errorf("[miner] error storing state snapshot %s; %s", c.String(), err)
+			return "", err
+		}
+	}
+
+	return c.String(), nil
+}
+
+// FetchStateSnapshot fetches the full state the block commits to and checks it against the block's state hash
+func FetchStateSnapshot(p2pSvc p2p.Interface, block *statechain.Block) ([]byte, error) {
+	if block == nil {
+		return nil, ErrNilBlock
+	}
+
+	c, err := cid.Decode(block.Props().StateSnapshotCID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := p2pSvc.GetBytes(&c)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := stateRootHash(p2pSvc, data)
+	if err != nil {
+		return nil, err
+	}
+	defer ReleaseStateRoot(p2pSvc, root)
+	if root != block.Props().StateCurrentHash {
+		log.Errorf("[miner] state snapshot %s root %s doesn't match state current hash %s", c.String(), root, block.Props().StateCurrentHash)
+		return nil, ErrSnapshotMismatch
+	}
+
+	return data, nil
+}
+
+// GatherDiffsSinceSnapshot gathers the diffs of the blocks after the block's most recent snapshot and returns
+// the snapshot's state along with them. Without a snapshot, the diffs are gathered back to genesis and the state is nil.
+// note: a snapshot that can't be fetched or doesn't match its block is skipped in favor of an older one.
+func GatherDiffsSinceSnapshot(ctx context.Context, p2pSvc p2p.Interface, block *statechain.Block) ([]byte, []*statechain.Diff, error) {
+	return gatherDiffs(ctx, p2pSvc, block, true)
+}
+
+func gatherDiffs(ctx context.Context, p2pSvc p2p.Interface, block *statechain.Block, useSnapshots bool) ([]byte, []*statechain.Diff, error) {
+	if block == nil {
+		return nil, nil, ErrNilBlock
+	}
+
+	var diffs []*statechain.Diff
+
+	head := block
+	for {
+		if ctx.Err() != nil {
+			log.Errorf("[miner] gather diffs context error; %v", ctx.Err())
+			return nil, nil, ctx.Err()
+		}
+
+		prefetchGatherObjects(ctx, p2pSvc, head, useSnapshots)
+
+		if useSnapshots && head.Props().StateSnapshotCID != "" {
+			snapshot, err := FetchStateSnapshot(p2pSvc, head)
+			if err == nil {
+				log.Printf("[miner] syncing image hash %s from the state snapshot of block %s with %d diffs", head.Props().ImageHash, head.Props().BlockNumber, len(diffs))
+				return snapshot, diffs, nil
+			}
+
+			log.Warnf("[miner] error fetching state snapshot %s; falling back to diffs\n%v", head.Props().StateSnapshotCID, err)
+		}
+
+		diffCID, err := p2p.GetCIDByHash(head.Props().StatePrevDiffHash)
+		if err != nil {
+			log.Errorf("[miner] err getting diff cid by hash\n%v", err)
+			return nil, nil, err
+		}
+
+		diff, err := p2pSvc.GetStatechainDiff(diffCID)
+		if err != nil {
+			log.Errorf("[miner] err getting diff by cid\n%v", err)
+			return nil, nil, err
+		}
+
+		// note: prepend
+		diffs = append([]*statechain.Diff{diff}, diffs...)
+
+		if head.Props().BlockNumber == mainchain.GenesisBlock.Props().BlockNumber {
+			break
+		}
+
+		prevStateCID, err := p2p.GetCIDByHash(head.Props().PrevBlockHash)
+		if err != nil {
+			log.Errorf("[miner] err getting statechain cid by hash\n%v", err)
+			return nil, nil, err
+		}
+
+		prevStateBlock, err := p2pSvc.GetStatechainBlock(prevStateCID)
+		if err != nil {
+			log.Errorf("[miner] err getting state chain block by cid\n%v", err)
+			return nil, nil, err
+		}
+
+		head = prevStateBlock
+	}
+
+	return nil, diffs, nil
+}
+
+// prefetchGatherObjects fetches the snapshot, diff and previous state block gathering the diffs reads at the block
+// in one batch, rather than one at a time over bitswap
+func prefetchGatherObjects(ctx context.Context, p2pSvc p2p.Interface, block *statechain.Block, useSnapshots bool) {
+	var cids []string
+	if useSnapshots && block.Props().StateSnapshotCID != "" {
+		cids = append(cids, block.Props().StateSnapshotCID)
+	}
+	hashes := []string{block.Props().StatePrevDiffHash}
+	if block.Props().BlockNumber != mainchain.GenesisBlock.Props().BlockNumber {
+		hashes = append(hashes, block.Props().PrevBlockHash)
+	}
+	for _, hash := range hashes {
+		c, err := p2p.GetCIDByHash(hash)
+		if err != nil {
+			continue
+		}
+		cids = append(cids, c.String())
+	}
+
+	prefetchObjects(ctx, p2pSvc, cids)
+}
+
+// prefetchObjects fetches the objects of the cids that aren't stored locally from peers in one batch, and stores them
+// so they're read locally.
+// note: it's best effort; the objects that aren't fetched are read over bitswap.
+func prefetchObjects(ctx context.Context, p2pSvc p2p.Interface, cids []string) {
+	props := p2pSvc.Props()
+	if props.FetchObjectsFN == nil || props.BlockStore == nil {
+		return
+	}
+
+	var missing []string
+	for _, cidStr := range cids {
+		c, err := cid.Decode(cidStr)
+		if err != nil {
+			continue
+		}
+		if ok, err := props.BlockStore.Has(c); err == nil && !ok {
+			missing = append(missing, cidStr)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	objects, err := props.FetchObjectsFN(ctx, missing)
+	if err != nil {
+		log.Warnf("[miner] err prefetching %d objects; falling back to bitswap\n%v", len(missing), err)
+		return
+	}
+
+	for cidStr, data := range objects {
+		c, err := cid.Decode(cidStr)
+		if err != nil {
+			continue
+		}
+		block, err := blocks.NewBlockWithCid(data, c)
+		if err != nil {
+			log.Warnf("[miner] err building prefetched block %s\n%v", cidStr, err)
+			continue
+		}
+		if err := props.BlockStore.Put(block); err != nil {
+			log.Warnf("[miner] err storing prefetched block %s\n%v", cidStr, err)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+// +build unit
+
+package miner
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	blocks "github.com/ipfs/go-block-format"
+	ds "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	bstore "github.com/ipfs/go-ipfs-blockstore"
+
+	"github.com/c3systems/c3-go/core/chain/mainchain"
+	"github.com/c3systems/c3-go/core/chain/statechain"
+	"github.com/c3systems/c3-go/core/p2p"
+	"github.com/c3systems/c3-go/core/p2p/mock"
+	"github.com/c3systems/c3-go/state"
+	"github.com/c3systems/c3-go/trie"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestIsSnapshotBlockNumber(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		blockNumber uint64
+		expected    bool
+	}{
+		{0, false},
+		{1, false},
+		{StateSnapshotInterval - 1, false},
+		{StateSnapshotInterval, true},
+		{StateSnapshotInterval + 1, false},
+		{StateSnapshotInterval * 3, true},
+	}
+
+	for idx, tt := range tests {
+		if ok := isSnapshotBlockNumber(tt.blockNumber); ok != tt.expected {
+			t.Errorf("test %d failed\nexpected %v\nreceived %v", idx+1, tt.expected, ok)
+		}
+	}
+}
+
+func TestGatherDiffsSinceSnapshot(t *testing.T) {
+	t.Parallel()
+
+	// 1. mock the p2p service
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockP2P := mock_p2p.NewMockInterface(mockCtrl)
+	mockP2P.
+		EXPECT().
+		Props().
+		Return(p2p.Props{}).
+		AnyTimes()
+
+	// 2. build the snapshot and the blocks after it
+	snapshot, err := state.State{"foo": "bar", "hello": "world"}.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := trie.NewMemDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := state.RootFromBytes(db, snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshotCID, err := buildStateSnapshot(nil, snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshotBlock := statechain.New(&statechain.BlockProps{
+		BlockNumber:       "0x64",
+		StatePrevDiffHash: "foo",
+		PrevBlockHash:     "bar",
+		StateCurrentHash:  root,
+		StateSnapshotCID:  snapshotCID,
+	})
+	block := statechain.New(&statechain.BlockProps{
+		BlockNumber:       "0x65",
+		StatePrevDiffHash: "foo",
+		PrevBlockHash:     "bar",
+	})
+	diff := statechain.NewDiff(&statechain.DiffProps{
+		Data: "foobar",
+	})
+
+	// 3. add the expected mockp2p calls and returns
+	gomock.InOrder(
+		mockP2P.
+			EXPECT().
+			GetStatechainDiff(gomock.Any()).
+			Return(diff, nil),
+		mockP2P.
+			EXPECT().
+			GetStatechainBlock(gomock.Any()).
+			Return(snapshotBlock, nil),
+		mockP2P.
+			EXPECT().
+			GetBytes(gomock.Any()).
+			Return(snapshot, nil),
+	)
+
+	// 4. run the function
+	st, diffs, err := GatherDiffsSinceSnapshot(context.Background(), mockP2P, block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 5. compare to the expected
+	if !reflect.DeepEqual(snapshot, st) {
+		t.Errorf("expected %s\nreceived %s", snapshot, st)
+	}
+	if expected := []*statechain.Diff{diff}; !reflect.DeepEqual(expected, diffs) {
+		t.Errorf("expected %v\nreceived %v", expected, diffs)
+	}
+
+	// 6. a snapshot that doesn't match its block falls back to the diffs
+	genesisBlock := statechain.New(&statechain.BlockProps{
+		BlockNumber:       mainchain.GenesisBlock.Props().BlockNumber,
+		StatePrevDiffHash: "foo",
+		PrevBlockHash:     "bar",
+	})
+	badSnapshotBlock := statechain.New(&statechain.BlockProps{
+		BlockNumber:       "0x64",
+		StatePrevDiffHash: "foo",
+		PrevBlockHash:     "bar",
+		StateCurrentHash:  "0xbad",
+		StateSnapshotCID:  snapshotCID,
+	})
+
+	gomock.InOrder(
+		mockP2P.
+			EXPECT().
+			GetBytes(gomock.Any()).
+			Return(snapshot, nil),
+		mockP2P.
+			EXPECT().
+			GetStatechainDiff(gomock.Any()).
+			Return(diff, nil),
+		mockP2P.
+			EXPECT().
+			GetStatechainBlock(gomock.Any()).
+			Return(genesisBlock, nil),
+		mockP2P.
+			EXPECT().
+			GetStatechainDiff(gomock.Any()).
+			Return(diff, nil),
+	)
+
+	st, diffs, err = GatherDiffsSinceSnapshot(context.Background(), mockP2P, badSnapshotBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st != nil {
+		t.Errorf("expected no snapshot\nreceived %s", st)
+	}
+	if expected := []*statechain.Diff{diff, diff}; !reflect.DeepEqual(expected, diffs) {
+		t.Errorf("expected %v\nreceived %v", expected, diffs)
+	}
+}
+
+func TestPrefetchObjects(t *testing.T) {
+	t.Parallel()
+
+	// 1. store one object locally and serve the others from a fake peer
+	blockStore := bstore.NewBlockstore(dssync.MutexWrap(ds.NewMapDatastore()))
+	local := blocks.NewBlock([]byte("local"))
+	if err := blockStore.Put(local); err != nil {
+		t.Fatal(err)
+	}
+	remote := blocks.NewBlock([]byte("remote"))
+	unknown := blocks.NewBlock([]byte("unknown"))
+
+	var requested []string
+	props := p2p.Props{
+		BlockStore: blockStore,
+		FetchObjectsFN: func(ctx context.Context, cids []string) (map[string][]byte, error) {
+			requested = cids
+			return map[string][]byte{remote.Cid().String(): remote.RawData()}, nil
+		},
+	}
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockP2P := mock_p2p.NewMockInterface(mockCtrl)
+	mockP2P.
+		EXPECT().
+		Props().
+		Return(props).
+		AnyTimes()
+
+	// 2. only the objects that aren't stored locally are fetched, in one batch
+	prefetchObjects(context.Background(), mockP2P, []string{local.Cid().String(), remote.Cid().String(), unknown.Cid().String()})
+
+	if expected := []string{remote.Cid().String(), unknown.Cid().String()}; !reflect.DeepEqual(expected, requested) {
+		t.Errorf("expected %v\nreceived %v", expected, requested)
+	}
+
+	// 3. the fetched objects are stored so they're read locally
+	if ok, err := blockStore.Has(remote.Cid()); err != nil || !ok {
+		t.Errorf("expected the fetched object to be stored; %v", err)
+	}
+	if ok, err := blockStore.Has(unknown.Cid()); err != nil || ok {
+		t.Errorf("expected the unknown object not to be stored; %v", err)
+	}
+}
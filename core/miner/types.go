@@ -15,6 +15,8 @@ import (
 const (
 	// StateFileName ...
 	StateFileName string = "state.txt"
+	// StateSnapshotInterval is the number of state blocks between the snapshots of an image's full state
+	StateSnapshotInterval uint64 = 100
 )
 
 var (
@@ -47,12 +49,32 @@ type Props struct {
 	PendingTransactions []*statechain.Transaction
 	RemoveTx            func(hash string) error
 	Simulated           bool
+	Threads             int // note: the number of hashing workers; defaults to the number of CPUs
+	// PrebuiltStates are the state blocks a canceled miner built for images the new previous block didn't touch;
+	// they're reused when they were built from the image's pending transactions
+	PrebuiltStates []*ImageStates
 }
 
 // Service ...
 type Service struct {
 	props      Props
 	minedBlock *MinedBlock
+	built      *builtStates
+	pow        *powStats
+}
+
+// ImageStates are the state blocks, and their transactions and diffs, mined for an image hash
+type ImageStates struct {
+	ImageHash    string
+	Transactions []*statechain.Transaction
+	Blocks       []*statechain.Block
+	Diffs        []*statechain.Diff
+}
+
+// builtStates are the image states built by the miner, by image hash
+type builtStates struct {
+	mut    sync.Mutex
+	images map[string]*ImageStates
 }
 
 // MinedBlock ...
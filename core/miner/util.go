@@ -3,15 +3,11 @@ package miner
 import (
 	"context"
 	"errors"
-	"fmt"
-	"io/ioutil"
-	"os"
+	"math/big"
 	"sort"
 	"time"
 
 	"github.com/c3systems/c3-go/common/c3crypto"
-	"github.com/c3systems/c3-go/common/fileutil"
-	"github.com/c3systems/c3-go/common/hashutil"
 	"github.com/c3systems/c3-go/common/hexutil"
 	"github.com/c3systems/c3-go/core/chain/mainchain"
 	"github.com/c3systems/c3-go/core/chain/merkle"
@@ -21,6 +17,8 @@ import (
 	"github.com/c3systems/c3-go/core/sandbox"
 	methodTypes "github.com/c3systems/c3-go/core/types/methods"
 	colorlog "github.com/c3systems/c3-go/log/color"
+	"github.com/c3systems/c3-go/state"
+	"github.com/c3systems/c3-go/trie"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -64,6 +62,38 @@ func CheckHashAgainstDifficulty(hashHex string, difficulty uint64) (bool, error)
 	return true, nil
 }
 
+// CheckRequiredDifficulty returns true when the block was mined at the difficulty the chain requires, or higher.
+// note: the genesis block isn't mined.
+func CheckRequiredDifficulty(block *mainchain.Block, required uint64) (bool, error) {
+	if block == nil {
+		return false, ErrNilBlock
+	}
+	if block.Props().BlockNumber == mainchain.GenesisBlock.Props().BlockNumber {
+		return true, nil
+	}
+
+	difficulty, err := hexutil.DecodeUint64(block.Props().Difficulty)
+	if err != nil {
+		return false, err
+	}
+
+	return difficulty >= required, nil
+}
+
+// BlockWork returns the expected number of hashes it took to mine the block, 16 to the power of its difficulty
+func BlockWork(block *mainchain.Block) (*big.Int, error) {
+	if block == nil {
+		return nil, ErrNilBlock
+	}
+
+	difficulty, err := hexutil.DecodeUint64(block.Props().Difficulty)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).Exp(big.NewInt(16), new(big.Int).SetUint64(difficulty), nil), nil
+}
+
 // BuildTxsMap ...
 func BuildTxsMap(txs []*statechain.Transaction) statechain.TransactionsMap {
 	txsMap := make(statechain.TransactionsMap)
@@ -131,6 +161,72 @@ func VerifyTransaction(tx *statechain.Transaction) (bool, error) {
 
 // VerifyMinedBlock ...
 func VerifyMinedBlock(ctx context.Context, p2pSvc p2p.Interface, sbSvc sandbox.Interface, minedBlock *MinedBlock) (bool, error) {
+	ok, err := VerifyMinedBlockHeader(ctx, minedBlock)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	return VerifyStateBlocksFromMinedBlock(ctx, p2pSvc, sbSvc, minedBlock)
+}
+
+// VerifyMinedBlockProofs verifies the mined block's header and the merkle proof of its state blocks without executing the dApps.
+// note: used by light nodes, which trust the states the state blocks commit to.
+func VerifyMinedBlockProofs(ctx context.Context, minedBlock *MinedBlock) (bool, error) {
+	ok, err := VerifyMinedBlockHeader(ctx, minedBlock)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	return VerifyMerkleTreeFromMinedBlock(ctx, minedBlock)
+}
+
+// VerifyBlock verifies a mainchain block on its own: its hash, difficulty and miner sig.
+// note: unlike VerifyMinedBlockHeader, it doesn't need the previous block, e.g. for the head blocks of peers.
+func VerifyBlock(block *mainchain.Block) (bool, error) {
+	if block == nil {
+		return false, ErrNilBlock
+	}
+	if block.Props().BlockHash == nil {
+		return false, nil
+	}
+	if block.Props().BlockNumber == mainchain.GenesisBlock.Props().BlockNumber {
+		return *block.Props().BlockHash == mainchain.GenesisBlockHash, nil
+	}
+	if block.Props().ImageHash != mainchain.ImageHash || block.Props().MinerSig == nil {
+		return false, nil
+	}
+
+	tmpHash, err := block.CalculateHash()
+	if err != nil {
+		return false, err
+	}
+	if *block.Props().BlockHash != tmpHash {
+		return false, nil
+	}
+
+	ok, err := CheckBlockHashAgainstDifficulty(block)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	pub, err := c3crypto.DecodeAddress(block.Props().MinerAddress)
+	if err != nil {
+		return false, err
+	}
+	sigR, err := hexutil.DecodeBigInt(block.Props().MinerSig.R)
+	if err != nil {
+		return false, err
+	}
+	sigS, err := hexutil.DecodeBigInt(block.Props().MinerSig.S)
+	if err != nil {
+		return false, err
+	}
+
+	return c3crypto.Verify(pub, []byte(*block.Props().BlockHash), sigR, sigS)
+}
+
+// VerifyMinedBlockHeader verifies the mined block's hash, difficulty, miner sig and its link to the previous block
+func VerifyMinedBlockHeader(ctx context.Context, minedBlock *MinedBlock) (bool, error) {
 	ch := make(chan interface{})
 
 	go func() {
@@ -298,384 +394,7 @@ func VerifyMinedBlock(ctx context.Context, p2pSvc p2p.Interface, sbSvc sandbox.I
 
 		case bool:
 			ok, _ := v.(bool)
-			if !ok {
-				return false, nil
-			}
-
-			return VerifyStateBlocksFromMinedBlock(ctx, p2pSvc, sbSvc, minedBlock)
-
-		default:
-			log.Errorf("[miner] received unknown message of type %T\n%v", v, v)
-			return false, errors.New("received message of unknown type")
-
-		}
-
-	case <-ctx.Done():
-		return false, ctx.Err()
-	}
-}
-
-// VerifyStateBlocksFromMinedBlock ...
-// note: this function also checks the merkle tree. That check is not required to be performed, separately.
-func VerifyStateBlocksFromMinedBlock(ctx context.Context, p2pSvc p2p.Interface, sbSvc sandbox.Interface, minedBlock *MinedBlock) (bool, error) {
-	ch := make(chan interface{})
-
-	go func() {
-		if minedBlock.NextBlock == nil {
-			log.Error("[miner] nil next block")
-			ch <- false
-
-			return
-		}
-		if len(minedBlock.StatechainBlocksMap) != len(minedBlock.TransactionsMap) {
-			log.Error("[miner] len state blocks map != len tx map")
-			ch <- false
-
-			return
-		}
-		// note: ok to have nil map? e.g. in the case that no transactions were included in the main block
-		//if minedBlock.StatechainBlocksMap == nil {
-		//log.Println("nil state blocks map")
-		//return false, nil
-		//}
-		if minedBlock.MerkleTreesMap == nil {
-			log.Error("[miner] nil merkle trees map")
-			ch <- false
-
-			return
-		}
-
-		// 1. Verify state blocks merkle hash
-		ok, err := VerifyMerkleTreeFromMinedBlock(ctx, minedBlock)
-		if err != nil {
-			log.Errorf("[miner] err verifying merkle tree\n%v", err)
-			ch <- err
-
-			return
-		}
-		if !ok {
-			log.Error("[miner] merkle tree didn't verify")
-			ch <- false
-
-			return
-		}
-		if ctx.Err() != nil {
-			return
-		}
-
-		// 2. Verify each state block
-		// first, group them by image hash
-		groupedBlocks, err := groupStateBlocksByImageHash(minedBlock.StatechainBlocksMap)
-		if err != nil {
-			log.Errorf("[miner] err grouping state blocks\n%v", err)
-			ch <- err
-
-			return
-		}
-
-		for _, blocks := range groupedBlocks {
-			if ctx.Err() != nil {
-				return
-			}
-
-			// order by block number
-			orderedBlocks, err := orderStatechainBlocks(blocks)
-			if err != nil {
-				log.Errorf("[miner] err ordering state blocks\n%v", err)
-				ch <- err
-
-				return
-			}
-
-			if orderedBlocks == nil || len(orderedBlocks) == 0 {
-				continue
-			}
-
-			if orderedBlocks[0] == nil {
-				ch <- errors.New("nil block")
-
-				return
-			}
-
-			// TODO: improve this if/else hell
-			if orderedBlocks[0].Props().BlockNumber == mainchain.GenesisBlock.Props().BlockNumber {
-				// check if there's already a genesis block
-				block, err := p2pSvc.FetchMostRecentStateBlock(orderedBlocks[0].Props().ImageHash, minedBlock.NextBlock)
-				if err != nil {
-					ch <- err
-
-					return
-				}
-
-				if block == nil {
-					tx, ok := minedBlock.TransactionsMap[orderedBlocks[0].Props().TxHash]
-					if !ok {
-						ch <- errors.New("tx not included")
-
-						return
-					}
-					if tx == nil {
-						ch <- errors.New("tx is nil")
-
-						return
-					}
-
-					genesisBlock, _, err := buildGenesisStateBlock(orderedBlocks[0].Props().ImageHash, tx)
-					if err != nil {
-						ch <- err
-
-						return
-					}
-
-					block = genesisBlock
-				}
-				if orderedBlocks[0].Props().BlockHash == nil {
-					ch <- false
-
-					return
-				}
-				hash, err := orderedBlocks[0].CalculateHash()
-				if err != nil {
-					ch <- err
-
-					return
-				}
-				if hash != *orderedBlocks[0].Props().BlockHash {
-					ch <- false
-
-					return
-				}
-
-				if block.Props().BlockHash == nil {
-					ch <- errors.New("nil blockhash")
-
-					return
-				}
-				if *block.Props().BlockHash != hash {
-					ch <- false
-
-					return
-				}
-
-				orderedBlocks = orderedBlocks[1:]
-				if len(orderedBlocks) == 0 {
-					continue
-				}
-
-			}
-
-			prevBlockHash := orderedBlocks[0].Props().PrevBlockHash
-			prevBlockCID, err := p2p.GetCIDByHash(prevBlockHash)
-			if err != nil {
-				log.Errorf("[miner] err getting cid by has\n%v", err)
-				ch <- err
-
-				return
-			}
-			// TODO: check that this is the actual prev block on the blockchain
-			prevBlock, err := p2pSvc.GetStatechainBlock(prevBlockCID)
-			if err != nil {
-				log.Errorf("[miner] err getting state block\n%v", err)
-				ch <- err
-
-				return
-			}
-			if prevBlock == nil {
-				ch <- errors.New("got nil prev block")
-
-				return
-			}
-			if prevBlock.Props().BlockHash == nil {
-				ch <- errors.New("got nil prev block hash")
-
-				return
-			}
-			// note: checked for nil pointer, above
-			if *prevBlock.Props().BlockHash != prevBlockHash {
-				ch <- false
-
-				return
-			}
-
-			prevState, err := fetchCurrentState(ctx, p2pSvc, prevBlock)
-			if err != nil {
-				log.Errorf("[miner] err fetching current state\n%v", err)
-				ch <- err
-
-				return
-			}
-			prevStateHash := hashutil.HashToHexString(prevState)
-			if prevStateHash != prevBlock.Props().StateCurrentHash {
-				ch <- false
-
-				return
-			}
-
-			for _, block := range orderedBlocks {
-				if ctx.Err() != nil {
-					return
-				}
-
-				// 2a. block must have a hash
-				if block == nil || block.Props().BlockHash == nil {
-					ch <- false
-
-					return
-				}
-
-				// 2b. Block #'s must be sequential
-				prevBlockNumber, err := hexutil.DecodeUint64(prevBlock.Props().BlockNumber)
-				if err != nil {
-					log.Errorf("[miner] err decoding prev block # 2b\n%v", err)
-					ch <- err
-
-					return
-				}
-				blockNumber, err := hexutil.DecodeUint64(block.Props().BlockNumber)
-				if err != nil {
-					log.Errorf("[miner] err decoding block # 2b\n %v", err)
-					ch <- err
-
-					return
-				}
-				if prevBlockNumber+1 != blockNumber {
-					ch <- false
-
-					return
-				}
-
-				// 2c. verify the block hash
-				tmpHash, err := block.CalculateHash()
-				if err != nil {
-					log.Errorf("[miner] err calculating block hash 2c\n%v", err)
-					ch <- err
-
-					return
-				}
-				// note: checked nil BlockHash, above
-				if tmpHash != *block.Props().BlockHash {
-					ch <- false
-
-					return
-				}
-
-				// 2d. verify the block tx
-				// note: can't have a state block without transactions?
-				tx, ok := minedBlock.TransactionsMap[block.Props().TxHash]
-				if !ok || tx == nil {
-					txCID, err := p2p.GetCIDByHash(block.Props().TxHash)
-					if err != nil {
-						ch <- err
-
-						return
-					}
-
-					tx, err = p2pSvc.GetStatechainTransaction(txCID)
-					if err != nil {
-						ch <- err
-
-						return
-					}
-					if tx == nil {
-						ch <- errors.New("nil tx")
-
-						return
-					}
-				}
-
-				ok, err = VerifyTransaction(tx)
-				if err != nil {
-					log.Errorf("[miner] err verifying tx 2d\n %v", err)
-					ch <- err
-
-					return
-				}
-				if !ok {
-					ch <- false
-
-					return
-				}
-				if ctx.Err() != nil {
-					return
-				}
-
-				nextStateBlock, nextDiff, nextState, err := buildNextStateFromPrevState(p2pSvc, sbSvc, prevState, block, tx)
-				if err != nil {
-					log.Errorf("[miner] err building next state from prev state\n %v", err)
-					ch <- err
-
-					return
-				}
-				if nextStateBlock == nil {
-					ch <- errors.New("nil state block")
-
-					return
-				}
-				if nextDiff == nil {
-					ch <- errors.New("nil diff")
-
-					return
-				}
-				if nextState == nil {
-					ch <- errors.New("nil next state")
-
-					return
-				}
-				if nextStateBlock.Props().BlockHash == nil {
-					ch <- errors.New("nil block hash")
-
-					return
-				}
-				if nextDiff.Props().DiffHash == nil {
-					ch <- errors.New("nil diff hash")
-
-					return
-				}
-				if ctx.Err() != nil {
-					return
-				}
-
-				// 2e. verify current state hash
-				if nextStateBlock.Props().StateCurrentHash != block.Props().StateCurrentHash {
-					ch <- false
-
-					return
-				}
-
-				// 2f. verify prevDiff
-				if nextStateBlock.Props().StatePrevDiffHash != block.Props().StatePrevDiffHash {
-					ch <- false
-
-					return
-				}
-
-				// set prev to current for next loop
-				prevState = nextState
-				prevBlock = block
-			}
-		}
-		if ctx.Err() != nil {
-			return
-		}
-
-		ch <- true
-		return
-	}()
-
-	select {
-	case v := <-ch:
-		switch v.(type) {
-		case error:
-			err, _ := v.(error)
-			return false, err
-
-		case bool:
-			ok, _ := v.(bool)
-			if !ok {
-				return false, nil
-			}
-
-			return true, nil
+			return ok, nil
 
 		default:
 			log.Errorf("[miner] received unknown message of type %T\n%v", v, v)
@@ -700,28 +419,11 @@ func buildNextStateFromPrevState(p2pSvc p2p.Interface, sbSvc sandbox.Interface,
 	}
 
 	ts := time.Now().Unix()
-	outPatchFile, err := fileutil.CreateTempFile(fmt.Sprintf("%s/%v/diff.patch", prevBlock.Props().ImageHash, ts))
-	if err != nil {
-		return nil, nil, nil, err
-	}
-	defer os.Remove(outPatchFile.Name()) // clean up
-	if err = outPatchFile.Close(); err != nil {
-		return nil, nil, nil, err
-	}
-	prevStateFile, err := fileutil.CreateTempFile(fmt.Sprintf("%s/%v/%s", prevBlock.Props().ImageHash, ts, StateFileName))
-	if err != nil {
-		return nil, nil, nil, err
-	}
-	defer os.Remove(prevStateFile.Name()) // clean up
-	if _, err := prevStateFile.Write(prevState); err != nil {
-		return nil, nil, nil, err
-	}
-	if err := prevStateFile.Close(); err != nil {
-		return nil, nil, nil, err
-	}
-	prevStateFileName := prevStateFile.Name()
 
-	var nextState []byte
+	var (
+		nextState []byte
+		err       error
+	)
 	if tx.Props().Method == methodTypes.InvokeMethod {
 		payload := tx.Props().Payload
 
@@ -738,44 +440,27 @@ func buildNextStateFromPrevState(p2pSvc p2p.Interface, sbSvc sandbox.Interface,
 		}
 
 		//log.Printf("[miner] container new state: %s", string(nextState))
-		nextStateFile, err := fileutil.CreateTempFile(fmt.Sprintf("%s/%v/nextState.txt", prevBlock.Props().ImageHash, ts))
+		diffStruct, err := buildStateDiff(prevState, nextState)
 		if err != nil {
 			return nil, nil, nil, err
 		}
-		defer os.Remove(nextStateFile.Name()) // clean up
-
-		if _, err = nextStateFile.Write(nextState); err != nil {
-			return nil, nil, nil, err
-		}
-		if err = nextStateFile.Close(); err != nil {
-			return nil, nil, nil, err
-		}
-
-		if err = diffing.Diff(prevStateFileName, nextStateFile.Name(), outPatchFile.Name(), false); err != nil {
-			return nil, nil, nil, err
-		}
 
-		// build the diff struct
-		diffData, err := ioutil.ReadFile(outPatchFile.Name())
+		prevBlockNumber, err := hexutil.DecodeUint64(prevBlock.Props().BlockNumber)
 		if err != nil {
 			return nil, nil, nil, err
 		}
+		prevBlockNumber++
 
-		diffStruct := statechain.NewDiff(&statechain.DiffProps{
-			Data: string(diffData),
-		})
-		if err = diffStruct.SetHash(); err != nil {
-			return nil, nil, nil, err
-		}
-
-		prevBlockNumber, err := hexutil.DecodeUint64(prevBlock.Props().BlockNumber)
+		nextStateHash, err := stateRootHash(p2pSvc, nextState)
 		if err != nil {
 			return nil, nil, nil, err
 		}
-		prevBlockNumber++
-
-		nextStateHashBytes := hashutil.Hash(nextState)
-		nextStateHash := hexutil.EncodeToString(nextStateHashBytes[:])
+		var snapshotCID string
+		if isSnapshotBlockNumber(prevBlockNumber) {
+			if snapshotCID, err = buildStateSnapshot(p2pSvc, nextState); err != nil {
+				return nil, nil, nil, err
+			}
+		}
 		log.Printf("[miner] state prev diff hash: %s", *diffStruct.Props().DiffHash)
 		log.Printf("[miner] state current hash: %s", nextStateHash)
 		nextStateStruct := statechain.New(&statechain.BlockProps{
@@ -786,6 +471,7 @@ func buildNextStateFromPrevState(p2pSvc p2p.Interface, sbSvc sandbox.Interface,
 			PrevBlockHash:     *prevBlock.Props().BlockHash,
 			StatePrevDiffHash: *diffStruct.Props().DiffHash, // note: used setHash, above so it would've erred
 			StateCurrentHash:  nextStateHash,
+			StateSnapshotCID:  snapshotCID,
 		})
 		if err := nextStateStruct.SetHash(); err != nil {
 			return nil, nil, nil, err
@@ -798,8 +484,34 @@ func buildNextStateFromPrevState(p2pSvc p2p.Interface, sbSvc sandbox.Interface,
 	return nil, nil, nil, errors.New("tx doesn't affect state")
 }
 
+// buildStateDiff builds a diff of the set/delete ops that transition the prev state into the next state
+func buildStateDiff(prevState, nextState []byte) (*statechain.Diff, error) {
+	prev, err := state.Parse(prevState)
+	if err != nil {
+		return nil, err
+	}
+	next, err := state.Parse(nextState)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := state.Diff(prev, next).Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	diffStruct := statechain.NewDiff(&statechain.DiffProps{
+		Data: string(data),
+	})
+	if err := diffStruct.SetHash(); err != nil {
+		return nil, err
+	}
+
+	return diffStruct, nil
+}
+
 // TODO: improve
-func buildGenesisStateBlock(imageHash string, tx *statechain.Transaction) (*statechain.Block, *statechain.Diff, error) {
+func buildGenesisStateBlock(p2pSvc p2p.Interface, imageHash string, tx *statechain.Transaction) (*statechain.Block, *statechain.Diff, error) {
 	log.Printf("[miner] building genesis state block for image hash %s", imageHash)
 
 	ts := time.Now().Unix()
@@ -816,62 +528,28 @@ func buildGenesisStateBlock(imageHash string, tx *statechain.Transaction) (*stat
 	log.Printf("[miner] tx method %s", tx.Props().Method)
 
 	// initial state
-	nextState := tx.Props().Payload
-	log.Printf("[miner] container initial state: %s", string(nextState))
-
-	stateFile, err := fileutil.CreateTempFile(fmt.Sprintf("%s/%v/%s", imageHash, ts, StateFileName))
+	genesisState, err := state.Parse(tx.Props().Payload)
 	if err != nil {
+		log.Errorf("[miner] genesis payload is not a valid state for image hash %s", imageHash)
 		return nil, nil, err
 	}
-	defer os.Remove(stateFile.Name()) // clean up
-	if err = stateFile.Close(); err != nil {
-		return nil, nil, err
-	}
-
-	nextStateFile, err := fileutil.CreateTempFile(fmt.Sprintf("%s/%v/nextState.txt", imageHash, ts))
+	nextState, err := genesisState.Serialize()
 	if err != nil {
 		return nil, nil, err
 	}
-	defer os.Remove(nextStateFile.Name()) // clean up
-	if _, err = nextStateFile.Write(nextState); err != nil {
-		return nil, nil, err
-	}
-	if err = nextStateFile.Close(); err != nil {
-		return nil, nil, err
-	}
+	log.Printf("[miner] container initial state: %s", string(nextState))
 
-	outPatchFile, err := fileutil.CreateTempFile(fmt.Sprintf("%s/%v/diff.patch", imageHash, ts))
+	diffStruct, err := buildStateDiff(nil, nextState)
 	if err != nil {
 		return nil, nil, err
 	}
-	defer os.Remove(outPatchFile.Name()) // clean up
-	if err = outPatchFile.Close(); err != nil {
-		return nil, nil, err
-	}
-
-	log.Printf("[miner] diffing the files:\ntmp state: %s\nnext state: %s\nout patch: %s", stateFile.Name(), nextStateFile.Name(), outPatchFile.Name())
 
-	if err = diffing.Diff(stateFile.Name(), nextStateFile.Name(), outPatchFile.Name(), false); err != nil {
-		return nil, nil, err
-	}
+	log.Println(colorlog.Yellow("[miner] genesis diff data: %s", diffStruct.Props().Data))
 
-	// build the diff struct
-	diffData, err := ioutil.ReadFile(outPatchFile.Name())
+	nextStateHash, err := stateRootHash(p2pSvc, nextState)
 	if err != nil {
 		return nil, nil, err
 	}
-
-	log.Println(colorlog.Yellow("[miner] diff data from patch file: %s", string(diffData)))
-
-	diffStruct := statechain.NewDiff(&statechain.DiffProps{
-		Data: string(diffData),
-	})
-	if err := diffStruct.SetHash(); err != nil {
-		return nil, nil, err
-	}
-
-	nextStateHashBytes := hashutil.Hash(nextState)
-	nextStateHash := hexutil.EncodeToString(nextStateHashBytes[:])
 	log.Printf("[miner] state prev diff hash: %s", *diffStruct.Props().DiffHash)
 	log.Printf("[miner] state current hash: %s", nextStateHash)
 	nextStateStruct := statechain.New(&statechain.BlockProps{
@@ -891,6 +569,76 @@ func buildGenesisStateBlock(imageHash string, tx *statechain.Transaction) (*stat
 	return nextStateStruct, diffStruct, nil
 }
 
+// StateCache returns the node's cache of reconstructed states, or nil if it has none
+func StateCache(p2pSvc p2p.Interface) *state.Cache {
+	if p2pSvc == nil {
+		return nil
+	}
+
+	return p2pSvc.Props().StateCache
+}
+
+// reconstructState returns the state of the image after the block, from the cache or the block's most recent
+// snapshot and the diffs after it
+func reconstructState(ctx context.Context, p2pSvc p2p.Interface, block *statechain.Block) ([]byte, error) {
+	imageHash := block.Props().ImageHash
+	cache := StateCache(p2pSvc)
+	if st, ok := cache.Get(imageHash, *block.Props().BlockHash); ok {
+		log.Printf("[miner] state cache hit for image hash %s and state block hash %s", imageHash, *block.Props().BlockHash)
+		return st, nil
+	}
+
+	// gather the diffs since the most recent snapshot
+	snapshot, diffs, err := GatherDiffsSinceSnapshot(ctx, p2pSvc, block)
+	if err != nil {
+		log.Errorf("[miner] error gathering diffs\n%v", err)
+		return nil, err
+	}
+
+	// apply the diffs to the snapshot to get the current state
+	st := snapshot
+	if len(diffs) > 0 {
+		if st, err = GenerateStateFromDiffs(ctx, imageHash, snapshot, diffs); err != nil {
+			log.Errorf("[miner] error generating state from diffs\n%v", err)
+			return nil, err
+		}
+	}
+
+	cache.Add(imageHash, *block.Props().BlockHash, st)
+	return st, nil
+}
+
+// StateDatabase returns the node's state trie db.
+// note: without one the trie is kept in memory; the root hash doesn't depend on the db.
+func StateDatabase(p2pSvc p2p.Interface) trie.Database {
+	if p2pSvc != nil {
+		if db := p2pSvc.Props().StateDB; db != nil {
+			return db
+		}
+	}
+
+	db, _ := trie.NewMemDatabase()
+	return db
+}
+
+// stateRootHash stores the trie of the serialized state and returns its root hash
+func stateRootHash(p2pSvc p2p.Interface, st []byte) (string, error) {
+	root, err := state.RootFromBytes(StateDatabase(p2pSvc), st)
+	if err != nil {
+		log.Errorf("[miner] error building state root; %s", err)
+		return "", err
+	}
+
+	return root, nil
+}
+
+// ReleaseStateRoot unpins the trie stored for the state root, once the node retained it or has no more use for it
+func ReleaseStateRoot(p2pSvc p2p.Interface, rootHash string) {
+	if err := state.ReleaseRoot(StateDatabase(p2pSvc), rootHash); err != nil {
+		log.Errorf("[miner] error releasing state root %s; %s", rootHash, err)
+	}
+}
+
 func fetchCurrentState(ctx context.Context, p2pSvc p2p.Interface, block *statechain.Block) ([]byte, error) {
 	ch := make(chan interface{})
 
@@ -911,22 +659,8 @@ func fetchCurrentState(ctx context.Context, p2pSvc p2p.Interface, block *statech
 			return
 		}
 
-		// gather the diffs
-		diffs, err := GatherDiffs(ctx, p2pSvc, block)
+		state, err := reconstructState(ctx, p2pSvc, block)
 		if err != nil {
-			log.Errorf("[miner] error gathering diffs\n%v", err)
-			ch <- err
-
-			return
-		}
-
-		// apply the diffs to get the current state
-		// TODO: get the genesis state of the block?
-		var genesisState []byte
-		imageHash := block.Props().ImageHash
-		state, err := GenerateStateFromDiffs(ctx, imageHash, genesisState, diffs)
-		if err != nil {
-			log.Errorf("[miner] error reading state file\n%v", err)
 			ch <- err
 
 			return
@@ -964,125 +698,87 @@ func fetchCurrentState(ctx context.Context, p2pSvc p2p.Interface, block *statech
 	}
 }
 
-// GatherDiffs ...
+// GatherDiffs gathers the diffs of the block and every block before it, back to genesis
 func GatherDiffs(ctx context.Context, p2pSvc p2p.Interface, block *statechain.Block) ([]*statechain.Diff, error) {
-	var diffs []*statechain.Diff
-
-	// gather the diffs
-	diffCID, err := p2p.GetCIDByHash(block.Props().StatePrevDiffHash)
+	_, diffs, err := gatherDiffs(ctx, p2pSvc, block, false)
 	if err != nil {
-		log.Errorf("[miner] err getting diff cid by hash\n%v", err)
 		return nil, err
 	}
 
-	diff, err := p2pSvc.GetStatechainDiff(diffCID)
-	if err != nil {
-		log.Errorf("[miner] err getting diff by cid\n%v", err)
-		return nil, err
+	if diffs == nil {
+		log.Error("[miner] error; diffs is nil")
+		return nil, errors.New("diffs is nil")
 	}
 
-	log.Printf("[miner] state chain diff; %v", diff)
+	return diffs, nil
+}
 
-	// note: prepend
-	diffs = append([]*statechain.Diff{diff}, diffs...)
+// GenerateStateFromDiffs ...
+// note: diffs are either state ops or, for blocks mined before ops, unified patches
+func GenerateStateFromDiffs(ctx context.Context, imageHash string, genesisState []byte, diffs []*statechain.Diff) ([]byte, error) {
+	if len(diffs) == 0 {
+		return nil, errors.New("nil diffs")
+	}
 
-	head := block
-	for head.Props().BlockNumber != mainchain.GenesisBlock.Props().BlockNumber {
+	current := genesisState
+	for i := 0; i < len(diffs); {
 		if ctx.Err() != nil {
-			log.Errorf("[miner] gather diffs context error; %v", err)
 			return nil, ctx.Err()
 		}
-
-		prevStateCID, err := p2p.GetCIDByHash(head.Props().PrevBlockHash)
-		if err != nil {
-			log.Errorf("[miner] err getting statechain cid by hash\n%v", err)
-			return nil, err
+		if diffs[i] == nil {
+			return nil, ErrNilDiff
 		}
 
-		prevStateBlock, err := p2pSvc.GetStatechainBlock(prevStateCID)
-		if err != nil {
-			log.Errorf("[miner] err getting state chain block by cid\n%v", err)
-			return nil, err
+		// note: consecutive patches are combined and applied at once
+		j := i
+		for j < len(diffs) && diffs[j] != nil && !state.IsOps(diffs[j].Props().Data) {
+			j++
 		}
+		if j > i {
+			next, err := generateStateFromPatches(ctx, imageHash, current, diffs[i:j])
+			if err != nil {
+				return nil, err
+			}
 
-		head = prevStateBlock
+			current = next
+			i = j
+			continue
+		}
 
-		diffCID, err := p2p.GetCIDByHash(prevStateBlock.Props().StatePrevDiffHash)
+		ops, err := state.DecodeOps([]byte(diffs[i].Props().Data))
 		if err != nil {
-			log.Errorf("[miner] err getting diffCID by hash\n%v", err)
+			log.Errorf("[miner] error decoding state ops; %s", err)
 			return nil, err
 		}
-
-		diff, err := p2pSvc.GetStatechainDiff(diffCID)
+		st, err := state.Parse(current)
 		if err != nil {
-			log.Errorf("[miner] err gitting diff by cid\n%v", err)
+			log.Errorf("[miner] error parsing state; %s", err)
+			return nil, err
+		}
+		if err := st.Apply(ops); err != nil {
+			log.Errorf("[miner] error applying state ops; %s", err)
+			return nil, err
+		}
+		if current, err = st.Serialize(); err != nil {
 			return nil, err
 		}
 
-		// note: prepend
-		diffs = append([]*statechain.Diff{diff}, diffs...)
-	}
-
-	if diffs == nil {
-		log.Error("[miner] error; diffs is nil")
-		return nil, errors.New("diffs is nil")
+		i++
 	}
 
-	return diffs, nil
+	return current, nil
 }
 
-// GenerateStateFromDiffs ...
-func GenerateStateFromDiffs(ctx context.Context, imageHash string, genesisState []byte, diffs []*statechain.Diff) ([]byte, error) {
+func generateStateFromPatches(ctx context.Context, imageHash string, genesisState []byte, diffs []*statechain.Diff) ([]byte, error) {
 	combinedDiff, err := generateCombinedDiffs(ctx, imageHash, diffs)
 	if err != nil {
 		log.Errorf("[miner] error generating combined diffs; %s", err)
 		return nil, err
 	}
 
-	ts := time.Now().Unix()
-	var fileNames []string
-	defer cleanupDirs(&fileNames)
-	defer cleanupFiles(&fileNames)
-
-	tmpStateFile, err := fileutil.CreateTempFile(fmt.Sprintf("%s/%v/%s", imageHash, ts, StateFileName))
-	if err != nil {
-		log.Errorf("[miner] error generating tmp state file; %s", err)
-		return nil, err
-	}
-
-	fileNames = append(fileNames, tmpStateFile.Name())
-	if _, err := tmpStateFile.Write(genesisState); err != nil {
-		log.Errorf("[miner] error writing to genesis state to tmp state file; %s", err)
-		return nil, err
-	}
-	if err := tmpStateFile.Close(); err != nil {
-		log.Errorf("[miner] error closing tmp state file; %s", err)
-		return nil, err
-	}
-
-	combinedPatchFile, err := fileutil.CreateTempFile(fmt.Sprintf("%s/%v/combined.patch", imageHash, ts))
+	state, err := diffing.Patch(genesisState, combinedDiff)
 	if err != nil {
-		log.Errorf("[miner] error creating combined patch file; %s", err)
-		return nil, err
-	}
-	fileNames = append(fileNames, combinedPatchFile.Name())
-	if _, err := combinedPatchFile.Write(combinedDiff); err != nil {
-		log.Errorf("[miner] error writing combined diff to combined patch file; %s", err)
-		return nil, err
-	}
-	if err := combinedPatchFile.Close(); err != nil {
-		log.Errorf("[miner] error closing combined patch file; %s", err)
-		return nil, err
-	}
-
-	// now apply the combined patch file to the state
-	if err := diffing.Patch(combinedPatchFile.Name(), tmpStateFile.Name(), false, true); err != nil {
-		log.Errorf("[miner] error applying combined patch file; %s", err)
-		return nil, err
-	}
-	state, err := ioutil.ReadFile(tmpStateFile.Name())
-	if err != nil {
-		log.Errorf("[miner] error reading tmp state file; %s", err)
+		log.Errorf("[miner] error applying combined patch for image hash %s; %s", imageHash, err)
 		return nil, err
 	}
 
@@ -1090,106 +786,29 @@ func GenerateStateFromDiffs(ctx context.Context, imageHash string, genesisState
 }
 
 func generateCombinedDiffs(ctx context.Context, imageHash string, diffs []*statechain.Diff) ([]byte, error) {
-	ts := time.Now().Unix()
-	var fileNames []string
-	defer cleanupDirs(&fileNames)
-	defer cleanupFiles(&fileNames)
-
 	if diffs == nil || len(diffs) == 0 {
 		return nil, errors.New("nil diffs")
 	}
 
-	combinedPatchFile, err := fileutil.CreateTempFile(fmt.Sprintf("%s/%v/combined.patch", imageHash, ts))
-	if err != nil {
-		log.Errorf("[miner] error creating combined patch file; %s", err)
-		return nil, err
-	}
-	fileNames = append(fileNames, combinedPatchFile.Name())
-	if _, err := combinedPatchFile.Write([]byte(diffs[0].Props().Data)); err != nil {
-		log.Errorf("[miner] error writing to genesis state to tmp state file; %s", err)
-		return nil, err
-	}
-	if err := combinedPatchFile.Close(); err != nil {
-		log.Errorf("[miner] error closing combined patch file; %s", err)
-		return nil, err
-	}
-
-	tmpPatchFile, err := fileutil.CreateTempFile(fmt.Sprintf("%s/%v/tmp.patch", imageHash, ts))
-	if err != nil {
-		log.Errorf("[miner] error creating tmp patch file; %s", err)
-		return nil, err
-	}
-	fileNames = append(fileNames, tmpPatchFile.Name())
-	if err := tmpPatchFile.Close(); err != nil {
-		log.Errorf("[miner] error closing tmp patch file; %s", err)
-		return nil, err
-	}
-	tmpPatchFile1, err := fileutil.CreateTempFile(fmt.Sprintf("%s/%v/tmp1.patch", imageHash, ts))
-	if err != nil {
-		log.Errorf("[miner] error creating tmp patch file; %s", err)
-		return nil, err
-	}
-	fileNames = append(fileNames, tmpPatchFile1.Name())
-	if err := tmpPatchFile1.Close(); err != nil {
-		log.Errorf("[miner] error closing tmp patch file 1; %s", err)
-		return nil, err
-	}
-
+	combined := []byte(diffs[0].Props().Data)
 	for i := 1; i < len(diffs); i++ {
 		if ctx.Err() != nil {
-			log.Errorf("[miner] error diffing; %s", err)
+			log.Errorf("[miner] error diffing; %s", ctx.Err())
 			return nil, ctx.Err()
 		}
 
-		prevComb, err := ioutil.ReadFile(combinedPatchFile.Name())
-		if err != nil {
-			log.Errorf("[miner] error reading previous combined patch file; %s", err)
-			return nil, err
-		}
-		if err := ioutil.WriteFile(tmpPatchFile.Name(), prevComb, os.ModePerm); err != nil {
-			log.Errorf("[miner] error writing to tmp patch file; %s", err)
-			return nil, err
-		}
-
-		if err := ioutil.WriteFile(tmpPatchFile1.Name(), []byte(diffs[i].Props().Data), os.ModePerm); err != nil {
-			log.Errorf("[miner] error writing to tmp patch file; %s", err)
-			return nil, err
-		}
-
-		// reading for debug logging
-		combinedPathFileData, err := ioutil.ReadFile(combinedPatchFile.Name())
-		if err != nil {
-			return nil, err
-		}
-		// reading for debug logging
-		tmpPatchFileData, err := ioutil.ReadFile(tmpPatchFile.Name())
-		if err != nil {
-			return nil, err
-		}
-
-		log.Printf("[miner] combining diffs\n%s\n%s\n%s\n%s\nout: %s", combinedPatchFile.Name(), string(combinedPathFileData), tmpPatchFile.Name(), string(tmpPatchFileData), combinedPatchFile.Name())
-
-		if err := diffing.CombineDiff(tmpPatchFile.Name(), tmpPatchFile1.Name(), combinedPatchFile.Name()); err != nil {
-			log.Errorf("[miner] error invoking diffing combined diff with combined patch file, tmp patch file and combined patch file; %s", err)
-			return nil, err
-		}
-
-		// reading for debug logging
-		combinedPathFileData, err = ioutil.ReadFile(combinedPatchFile.Name())
+		next, err := diffing.CombineDiff(combined, []byte(diffs[i].Props().Data))
 		if err != nil {
+			log.Errorf("[miner] error combining diffs for image hash %s; %s", imageHash, err)
 			return nil, err
 		}
 
-		log.Printf("[miner] combined diffs\n%s\n%s", combinedPatchFile.Name(), string(combinedPathFileData))
+		combined = next
 	}
 
-	patch, err := ioutil.ReadFile(combinedPatchFile.Name())
-	if err != nil {
-		log.Errorf("[miner] error reading combined patch file; %s", err)
-		return nil, err
-	}
+	log.Printf("[miner] combined diffs\n%s", string(combined))
 
-	return patch, nil
+	return combined, nil
 }
 
 // VerifyMerkleTreeFromMinedBlock ...
@@ -1360,32 +979,3 @@ func groupStateBlocksByImageHash(stateBlocksMap map[string]*statechain.Block) (m
 
 	return ret, nil
 }
-
-func cleanupFiles(fileNames *[]string) error {
-	if fileNames == nil {
-		log.Info("no files to remove")
-		return nil
-	}
-
-	if err := fileutil.RemoveFiles(*fileNames); err != nil {
-		log.Errorf("[miner] err cleaning up files; %v", err)
-		return err
-	}
-
-	return nil
-}
-
-func cleanupDirs(fileNames *[]string) error {
-	if fileNames == nil {
-		log.Info("no files to remove")
-		return nil
-	}
-
-	dirs := fileutil.DirsFromFiles(*fileNames)
-
-	if err := fileutil.RemoveDirs(dirs); err != nil {
-		log.Errorf("err removing dirs\n%v", err)
-	}
-
-	return nil
-}
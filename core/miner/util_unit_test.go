@@ -7,10 +7,10 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"os"
 	"reflect"
 	"testing"
-	"time"
 
 	"github.com/c3systems/c3-go/common/c3crypto"
 	"github.com/c3systems/c3-go/common/fileutil"
@@ -18,6 +18,7 @@ import (
 	"github.com/c3systems/c3-go/common/hexutil"
 	"github.com/c3systems/c3-go/core/chain/mainchain"
 	"github.com/c3systems/c3-go/core/chain/statechain"
+	"github.com/c3systems/c3-go/core/p2p"
 	"github.com/c3systems/c3-go/core/p2p/mock"
 
 	"github.com/golang/mock/gomock"
@@ -158,6 +159,82 @@ func TestCheckHashAgainstDifficulty(t *testing.T) {
 	}
 }
 
+func TestCheckRequiredDifficulty(t *testing.T) {
+	t.Parallel()
+
+	type input struct {
+		block    *mainchain.Block
+		required uint64
+	}
+	type test struct {
+		input    input
+		expected bool
+	}
+
+	tests := []test{
+		test{
+			input: input{
+				block:    mainchain.New(&mainchain.Props{BlockNumber: hexutil.EncodeUint64(1), Difficulty: hexutil.EncodeUint64(5)}),
+				required: 4,
+			},
+			expected: true,
+		},
+		test{
+			input: input{
+				block:    mainchain.New(&mainchain.Props{BlockNumber: hexutil.EncodeUint64(1), Difficulty: hexutil.EncodeUint64(4)}),
+				required: 4,
+			},
+			expected: true,
+		},
+		test{
+			input: input{
+				block:    mainchain.New(&mainchain.Props{BlockNumber: hexutil.EncodeUint64(1), Difficulty: hexutil.EncodeUint64(0)}),
+				required: 4,
+			},
+			expected: false,
+		},
+		test{
+			input: input{
+				block:    &mainchain.GenesisBlock,
+				required: 4,
+			},
+			expected: true,
+		},
+	}
+
+	for idx, tt := range tests {
+		ok, err := CheckRequiredDifficulty(tt.input.block, tt.input.required)
+		if err != nil {
+			t.Fatalf("test %d failed\nreceived err %v", idx+1, err)
+		}
+
+		if tt.expected != ok {
+			t.Errorf("test %d failed\nexpected %v\nreceived %v", idx+1, tt.expected, ok)
+		}
+	}
+}
+
+func TestBlockWork(t *testing.T) {
+	t.Parallel()
+
+	tests := map[uint64]int64{
+		0: 1,
+		1: 16,
+		3: 4096,
+	}
+
+	for difficulty, expected := range tests {
+		work, err := BlockWork(mainchain.New(&mainchain.Props{Difficulty: hexutil.EncodeUint64(difficulty)}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if work.Cmp(big.NewInt(expected)) != 0 {
+			t.Errorf("difficulty %d failed\nexpected %v\nreceived %v", difficulty, expected, work)
+		}
+	}
+}
+
 func TestBuildTxsMap(t *testing.T) {
 	t.Parallel()
 
@@ -307,6 +384,11 @@ func TestGatherDiffs(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockP2P := mock_p2p.NewMockInterface(mockCtrl)
+	mockP2P.
+		EXPECT().
+		Props().
+		Return(p2p.Props{}).
+		AnyTimes()
 
 	// 2. build a fake statechain blocks and diff
 	block := statechain.New(&statechain.BlockProps{
@@ -391,16 +473,11 @@ func TestGatherDiffs(t *testing.T) {
 func TestGenerateStateFromDiffs(t *testing.T) {
 	t.Parallel()
 
-	genesisState, err := ioutil.ReadFile("./test_data/tmp/state.txt")
+	genesisState, err := ioutil.ReadFile("./test_data/state.txt")
 	if err != nil {
 		t.Fatal(err)
 	}
-	defer func() {
-		if err := ioutil.WriteFile("./test_data/tmp/state.txt", genesisState, os.ModePerm); err != nil {
-			t.Logf("err returning genesis state file to it's original state\n%v", err)
-		}
-	}()
-	expectedState, err := ioutil.ReadFile("./test_data/tmp/state3.txt")
+	expectedState, err := ioutil.ReadFile("./test_data/state3.txt")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -443,6 +520,39 @@ func TestGenerateStateFromDiffs(t *testing.T) {
 	}
 }
 
+func TestGenerateStateFromOpsDiffs(t *testing.T) {
+	t.Parallel()
+
+	genesisState := []byte(`{"` + hexutil.EncodeString("foo") + `":"` + hexutil.EncodeString("bar") + `"}`)
+
+	states := []string{
+		`{"` + hexutil.EncodeString("foo") + `":"` + hexutil.EncodeString("baz") + `"}`,
+		`{"` + hexutil.EncodeString("foo") + `":"` + hexutil.EncodeString("baz") + `","` + hexutil.EncodeString("hello") + `":"` + hexutil.EncodeString("world") + `"}`,
+		`{"` + hexutil.EncodeString("hello") + `":"` + hexutil.EncodeString("world") + `"}`,
+	}
+
+	var diffs []*statechain.Diff
+	prevState := genesisState
+	for _, s := range states {
+		diff, err := buildStateDiff(prevState, []byte(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		diffs = append(diffs, diff)
+		prevState = []byte(s)
+	}
+
+	state, err := GenerateStateFromDiffs(context.TODO(), "fakeImage", genesisState, diffs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if states[len(states)-1] != string(state) {
+		t.Errorf("expected %s\nreceived %s", states[len(states)-1], string(state))
+	}
+}
+
 func TestGenerateCombinedDiffs(t *testing.T) {
 	t.Parallel()
 
@@ -487,6 +597,21 @@ func TestGenerateCombinedDiffs(t *testing.T) {
 	if string(expected) != string(received) {
 		t.Errorf("expected %s\n\n\nreceived %s", string(expected), string(received))
 	}
+
+	// note: the test data was generated by combinediff
+	expected, err = ioutil.ReadFile("./test_data/12.patch")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received, err = generateCombinedDiffs(context.TODO(), "fakeImage", diffs[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(expected) != string(received) {
+		t.Errorf("expected %s\n\n\nreceived %s", string(expected), string(received))
+	}
 }
 
 func TestIsGenesisTransaction(t *testing.T) {
@@ -680,69 +805,6 @@ func TestGroupStateBlocksByImageHash(t *testing.T) {
 	}
 }
 
-func TestCleanupFiles(t *testing.T) {
-	t.Parallel()
-
-	// 1. nil fileNames should return
-	cleanupFiles(nil)
-
-	// 2. an array of names should be cleaned
-	f1, err := os.Create("./test_data/foo")
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer os.Remove(f1.Name())
-	if err = f1.Close(); err != nil {
-		t.Fatal(err)
-	}
-
-	f2, err := os.Create("./test_data/bar")
-	if err != nil {
-		t.Fatal(err)
-	}
-	if err = f2.Close(); err != nil {
-		t.Fatal(err)
-	}
-
-	fileNames := &[]string{
-		f1.Name(),
-		f2.Name(),
-	}
-
-	done := false
-	const (
-		wait    time.Duration = 5 * time.Second
-		maxWait int           = 5
-	)
-
-	// note: important to test this with defer because that's how it's used in code
-	go func() {
-		defer func() { done = true }()
-		defer cleanupFiles(fileNames)
-	}()
-
-	for i := 0; i < maxWait; i++ {
-		if !done {
-			time.Sleep(wait)
-		} else {
-			break
-		}
-
-		if i == maxWait-1 {
-			t.Error("could not wait for cleanup")
-		}
-	}
-
-	if _, err = os.Stat(f1.Name()); !os.IsNotExist(err) {
-		t.Errorf("expected file %s to not exist", f1.Name())
-	}
-	if _, err = os.Stat(f2.Name()); !os.IsNotExist(err) {
-		t.Errorf("expected file %s to not exist", f2.Name())
-	}
-
-	// 3. TODO: test that an error is printed to stdout
-}
-
 func TestMakeTempFile(t *testing.T) {
 	t.Parallel()
 
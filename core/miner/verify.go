@@ -0,0 +1,480 @@
+package miner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/c3systems/c3-go/common/hashutil"
+	"github.com/c3systems/c3-go/common/hexutil"
+	"github.com/c3systems/c3-go/config"
+	"github.com/c3systems/c3-go/core/chain/mainchain"
+	"github.com/c3systems/c3-go/core/chain/statechain"
+	"github.com/c3systems/c3-go/core/p2p"
+	"github.com/c3systems/c3-go/core/sandbox"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// StateBlockFailure describes why the state blocks of an image in a mined block didn't verify
+type StateBlockFailure struct {
+	ImageHash   string `json:"imageHash,omitempty"`
+	BlockHash   string `json:"blockHash,omitempty"`
+	BlockNumber string `json:"blockNumber,omitempty"`
+	Reason      string `json:"reason"`
+	// Err is set when the block couldn't be verified, e.g. a diff couldn't be fetched, rather than being invalid
+	Err error `json:"-"`
+}
+
+// String ...
+func (f StateBlockFailure) String() string {
+	var parts []string
+	if f.ImageHash != "" {
+		parts = append(parts, fmt.Sprintf("image hash %s", f.ImageHash))
+	}
+	if f.BlockNumber != "" {
+		parts = append(parts, fmt.Sprintf("state block %s", f.BlockNumber))
+	}
+	if f.BlockHash != "" {
+		parts = append(parts, fmt.Sprintf("state block hash %s", f.BlockHash))
+	}
+	parts = append(parts, f.Reason)
+
+	return strings.Join(parts, "; ")
+}
+
+// VerificationReport is the result of verifying the state blocks of a mined block
+type VerificationReport struct {
+	Valid bool `json:"valid"`
+	// Images is the number of image hashes whose state blocks were verified
+	Images int `json:"images"`
+	// Failures are sorted by image hash; the images canceled after the first failure aren't reported
+	Failures []*StateBlockFailure `json:"failures,omitempty"`
+}
+
+// Err returns the first error that kept a state block from being verified, if any
+func (r *VerificationReport) Err() error {
+	if r == nil {
+		return nil
+	}
+
+	for _, failure := range r.Failures {
+		if failure.Err != nil {
+			return failure.Err
+		}
+	}
+
+	return nil
+}
+
+// String ...
+func (r *VerificationReport) String() string {
+	if r == nil {
+		return ""
+	}
+	if r.Valid {
+		return fmt.Sprintf("verified the state blocks of %v images", r.Images)
+	}
+
+	var failures []string
+	for _, failure := range r.Failures {
+		failures = append(failures, failure.String())
+	}
+
+	return fmt.Sprintf("state blocks did not verify\n%s", strings.Join(failures, "\n"))
+}
+
+func invalidStateBlock(block *statechain.Block, reason string) *StateBlockFailure {
+	failure := &StateBlockFailure{
+		Reason: reason,
+	}
+	if block != nil {
+		failure.ImageHash = block.Props().ImageHash
+		failure.BlockNumber = block.Props().BlockNumber
+		if block.Props().BlockHash != nil {
+			failure.BlockHash = *block.Props().BlockHash
+		}
+	}
+
+	return failure
+}
+
+func failedStateBlock(block *statechain.Block, reason string, err error) *StateBlockFailure {
+	failure := invalidStateBlock(block, fmt.Sprintf("%s; %s", reason, err))
+	failure.Err = err
+
+	return failure
+}
+
+// VerifyStateBlocksFromMinedBlock ...
+// note: this function also checks the merkle tree. That check is not required to be performed, separately.
+func VerifyStateBlocksFromMinedBlock(ctx context.Context, p2pSvc p2p.Interface, sbSvc sandbox.Interface, minedBlock *MinedBlock) (bool, error) {
+	report, err := VerifyStateBlocksWithReport(ctx, p2pSvc, sbSvc, minedBlock)
+	if err != nil {
+		return false, err
+	}
+	if !report.Valid {
+		log.Errorf("[miner] %s", report)
+		return false, report.Err()
+	}
+
+	return true, nil
+}
+
+// VerifyStateBlocksWithReport verifies the state blocks of the mined block and reports which ones failed and why.
+// The state blocks of each image hash are verified concurrently, by at most config.MaxStateVerificationWorkers,
+// and the remaining images are canceled after the first failure.
+// note: an error is only returned when the context is done before the state blocks were verified.
+func VerifyStateBlocksWithReport(ctx context.Context, p2pSvc p2p.Interface, sbSvc sandbox.Interface, minedBlock *MinedBlock) (*VerificationReport, error) {
+	report := new(VerificationReport)
+	fail := func(failure *StateBlockFailure) (*VerificationReport, error) {
+		report.Failures = append(report.Failures, failure)
+		return report, nil
+	}
+
+	if minedBlock == nil || minedBlock.NextBlock == nil {
+		return fail(invalidStateBlock(nil, "nil next block"))
+	}
+	if len(minedBlock.StatechainBlocksMap) != len(minedBlock.TransactionsMap) {
+		return fail(invalidStateBlock(nil, "len state blocks map != len tx map"))
+	}
+	// note: ok to have nil map? e.g. in the case that no transactions were included in the main block
+	if minedBlock.MerkleTreesMap == nil {
+		return fail(invalidStateBlock(nil, "nil merkle trees map"))
+	}
+
+	// 1. Verify state blocks merkle hash
+	ok, err := VerifyMerkleTreeFromMinedBlock(ctx, minedBlock)
+	if err != nil {
+		return fail(failedStateBlock(nil, "err verifying merkle tree", err))
+	}
+	if !ok {
+		return fail(invalidStateBlock(nil, "merkle tree didn't verify"))
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// 2. Verify each state block
+	// first, group them by image hash
+	groupedBlocks, err := groupStateBlocksByImageHash(minedBlock.StatechainBlocksMap)
+	if err != nil {
+		return fail(failedStateBlock(nil, "err grouping state blocks", err))
+	}
+
+	imageHashes := make([]string, 0, len(groupedBlocks))
+	for imageHash := range groupedBlocks {
+		imageHashes = append(imageHashes, imageHash)
+	}
+	sort.Strings(imageHashes)
+	report.Images = len(imageHashes)
+
+	workers := config.MaxStateVerificationWorkers
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(imageHashes) {
+		workers = len(imageHashes)
+	}
+
+	// note: the first failure cancels the images that are still being verified
+	verifyCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mut      sync.Mutex
+		failures []*StateBlockFailure
+		jobs     = make(chan string)
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for imageHash := range jobs {
+				failure := verifyImageStateBlocks(verifyCtx, p2pSvc, sbSvc, minedBlock, groupedBlocks[imageHash])
+				if failure == nil {
+					continue
+				}
+
+				mut.Lock()
+				// note: the images canceled by a failure aren't failures themselves
+				if failure.Err == nil || verifyCtx.Err() == nil {
+					if failure.ImageHash == "" {
+						failure.ImageHash = imageHash
+					}
+					failures = append(failures, failure)
+				}
+				mut.Unlock()
+
+				cancel()
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		for _, imageHash := range imageHashes {
+			select {
+			case jobs <- imageHash:
+			case <-verifyCtx.Done():
+			}
+			if verifyCtx.Err() != nil {
+				break
+			}
+		}
+		close(jobs)
+		wg.Wait()
+	}()
+
+	// note: a sandbox run can't be interrupted, so the workers are left to finish when the context is done
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if len(failures) == 0 && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].ImageHash < failures[j].ImageHash
+	})
+	report.Failures = failures
+	report.Valid = len(failures) == 0
+
+	return report, nil
+}
+
+// verifyImageStateBlocks verifies the state blocks of an image by replaying their transactions on the previous state.
+// It returns nil when the blocks are valid.
+func verifyImageStateBlocks(ctx context.Context, p2pSvc p2p.Interface, sbSvc sandbox.Interface, minedBlock *MinedBlock, blocks []*statechain.Block) *StateBlockFailure {
+	// order by block number
+	orderedBlocks, err := orderStatechainBlocks(blocks)
+	if err != nil {
+		return failedStateBlock(nil, "err ordering state blocks", err)
+	}
+	if len(orderedBlocks) == 0 {
+		return nil
+	}
+	if orderedBlocks[0] == nil {
+		return failedStateBlock(nil, "nil block", errors.New("nil block"))
+	}
+
+	if orderedBlocks[0].Props().BlockNumber == mainchain.GenesisBlock.Props().BlockNumber {
+		if failure := verifyGenesisStateBlock(p2pSvc, minedBlock, orderedBlocks[0]); failure != nil {
+			return failure
+		}
+
+		orderedBlocks = orderedBlocks[1:]
+		if len(orderedBlocks) == 0 {
+			return nil
+		}
+	}
+
+	first := orderedBlocks[0]
+	prevBlockHash := first.Props().PrevBlockHash
+	prevBlockCID, err := p2p.GetCIDByHash(prevBlockHash)
+	if err != nil {
+		return failedStateBlock(first, "err getting prev block cid by hash", err)
+	}
+	// TODO: check that this is the actual prev block on the blockchain
+	prevBlock, err := p2pSvc.GetStatechainBlock(prevBlockCID)
+	if err != nil {
+		return failedStateBlock(first, "err getting prev state block", err)
+	}
+	if prevBlock == nil {
+		return failedStateBlock(first, "got nil prev block", errors.New("nil prev block"))
+	}
+	if prevBlock.Props().BlockHash == nil {
+		return failedStateBlock(first, "got nil prev block hash", errors.New("nil prev block hash"))
+	}
+	// note: checked for nil pointer, above
+	if *prevBlock.Props().BlockHash != prevBlockHash {
+		return invalidStateBlock(first, "prev block hash doesn't match the fetched prev block")
+	}
+
+	prevState, err := fetchCurrentState(ctx, p2pSvc, prevBlock)
+	if err != nil {
+		return failedStateBlock(first, "err fetching current state", err)
+	}
+	prevStateHash, err := stateRootHash(p2pSvc, prevState)
+	if err != nil {
+		return failedStateBlock(first, "err building state root", err)
+	}
+	// note: the prev state's trie is retained with its block, if this node accepted it
+	defer ReleaseStateRoot(p2pSvc, prevStateHash)
+	// note: below the fork height, the prev block may have been mined before state roots and hash the serialized state
+	if prevStateHash != prevBlock.Props().StateCurrentHash && (!acceptsLegacyStateHash(minedBlock) || hashutil.HashToHexString(prevState) != prevBlock.Props().StateCurrentHash) {
+		return invalidStateBlock(first, "prev state doesn't match the prev block state hash")
+	}
+
+	for _, block := range orderedBlocks {
+		if ctx.Err() != nil {
+			return failedStateBlock(block, "verification canceled", ctx.Err())
+		}
+
+		// 2a. block must have a hash
+		if block == nil || block.Props().BlockHash == nil {
+			return invalidStateBlock(block, "nil block hash")
+		}
+
+		// 2b. Block #'s must be sequential
+		prevBlockNumber, err := hexutil.DecodeUint64(prevBlock.Props().BlockNumber)
+		if err != nil {
+			return failedStateBlock(block, "err decoding prev block #", err)
+		}
+		blockNumber, err := hexutil.DecodeUint64(block.Props().BlockNumber)
+		if err != nil {
+			return failedStateBlock(block, "err decoding block #", err)
+		}
+		if prevBlockNumber+1 != blockNumber {
+			return invalidStateBlock(block, fmt.Sprintf("block # doesn't follow prev block # %v", prevBlockNumber))
+		}
+
+		// 2c. verify the block hash
+		tmpHash, err := block.CalculateHash()
+		if err != nil {
+			return failedStateBlock(block, "err calculating block hash", err)
+		}
+		// note: checked nil BlockHash, above
+		if tmpHash != *block.Props().BlockHash {
+			return invalidStateBlock(block, fmt.Sprintf("block hash doesn't match calculated hash %s", tmpHash))
+		}
+
+		// 2d. verify the block tx
+		// note: can't have a state block without transactions?
+		tx, ok := minedBlock.TransactionsMap[block.Props().TxHash]
+		if !ok || tx == nil {
+			txCID, err := p2p.GetCIDByHash(block.Props().TxHash)
+			if err != nil {
+				return failedStateBlock(block, "err getting tx cid by hash", err)
+			}
+
+			tx, err = p2pSvc.GetStatechainTransaction(txCID)
+			if err != nil {
+				return failedStateBlock(block, "err getting tx", err)
+			}
+			if tx == nil {
+				return failedStateBlock(block, "nil tx", errors.New("nil tx"))
+			}
+		}
+
+		ok, err = VerifyTransaction(tx)
+		if err != nil {
+			return failedStateBlock(block, "err verifying tx", err)
+		}
+		if !ok {
+			return invalidStateBlock(block, "tx didn't verify")
+		}
+		if ctx.Err() != nil {
+			return failedStateBlock(block, "verification canceled", ctx.Err())
+		}
+
+		nextStateBlock, nextDiff, nextState, err := buildNextStateFromPrevState(p2pSvc, sbSvc, prevState, block, tx)
+		if err != nil {
+			return failedStateBlock(block, "err building next state from prev state", err)
+		}
+		if nextStateBlock == nil || nextStateBlock.Props().BlockHash == nil {
+			return failedStateBlock(block, "nil state block", errors.New("nil state block"))
+		}
+		if nextDiff == nil || nextDiff.Props().DiffHash == nil {
+			return failedStateBlock(block, "nil diff", errors.New("nil diff"))
+		}
+		if nextState == nil {
+			return failedStateBlock(block, "nil next state", errors.New("nil next state"))
+		}
+
+		// 2e. verify current state hash
+		if nextStateBlock.Props().StateCurrentHash != block.Props().StateCurrentHash {
+			ReleaseStateRoot(p2pSvc, nextStateBlock.Props().StateCurrentHash)
+			return invalidStateBlock(block, fmt.Sprintf("state current hash doesn't match replayed state hash %s", nextStateBlock.Props().StateCurrentHash))
+		}
+
+		// 2f. verify prevDiff
+		if nextStateBlock.Props().StatePrevDiffHash != block.Props().StatePrevDiffHash {
+			return invalidStateBlock(block, fmt.Sprintf("state prev diff hash doesn't match replayed diff hash %s", nextStateBlock.Props().StatePrevDiffHash))
+		}
+
+		// 2g. verify the state snapshot
+		if nextStateBlock.Props().StateSnapshotCID != block.Props().StateSnapshotCID {
+			return invalidStateBlock(block, fmt.Sprintf("state snapshot cid doesn't match replayed snapshot cid %s", nextStateBlock.Props().StateSnapshotCID))
+		}
+
+		// note: the state is verified against the block, so it's cached for the blocks that follow
+		StateCache(p2pSvc).Add(block.Props().ImageHash, *block.Props().BlockHash, nextState)
+
+		// set prev to current for next loop
+		prevState = nextState
+		prevBlock = block
+	}
+
+	return nil
+}
+
+// acceptsLegacyStateHash returns true when the mined block is below the state root fork height
+func acceptsLegacyStateHash(minedBlock *MinedBlock) bool {
+	if minedBlock == nil || minedBlock.NextBlock == nil {
+		return false
+	}
+
+	height, err := hexutil.DecodeUint64(minedBlock.NextBlock.Props().BlockNumber)
+	if err != nil {
+		return false
+	}
+
+	return height < config.StateRootForkHeight
+}
+
+// verifyGenesisStateBlock checks the genesis state block of an image against the image's existing genesis block,
+// or the one built from its transaction
+func verifyGenesisStateBlock(p2pSvc p2p.Interface, minedBlock *MinedBlock, genesis *statechain.Block) *StateBlockFailure {
+	// check if there's already a genesis block
+	block, err := p2pSvc.FetchMostRecentStateBlock(genesis.Props().ImageHash, minedBlock.NextBlock)
+	if err != nil {
+		return failedStateBlock(genesis, "err fetching most recent state block", err)
+	}
+
+	if block == nil {
+		tx, ok := minedBlock.TransactionsMap[genesis.Props().TxHash]
+		if !ok {
+			return failedStateBlock(genesis, "tx not included", errors.New("tx not included"))
+		}
+		if tx == nil {
+			return failedStateBlock(genesis, "tx is nil", errors.New("tx is nil"))
+		}
+
+		genesisBlock, _, err := buildGenesisStateBlock(p2pSvc, genesis.Props().ImageHash, tx)
+		if err != nil {
+			return failedStateBlock(genesis, "err building genesis state block", err)
+		}
+
+		block = genesisBlock
+	}
+	if genesis.Props().BlockHash == nil {
+		return invalidStateBlock(genesis, "nil block hash")
+	}
+	hash, err := genesis.CalculateHash()
+	if err != nil {
+		return failedStateBlock(genesis, "err calculating block hash", err)
+	}
+	if hash != *genesis.Props().BlockHash {
+		return invalidStateBlock(genesis, fmt.Sprintf("block hash doesn't match calculated hash %s", hash))
+	}
+
+	if block.Props().BlockHash == nil {
+		return failedStateBlock(genesis, "nil genesis block hash", errors.New("nil blockhash"))
+	}
+	if *block.Props().BlockHash != hash {
+		return invalidStateBlock(genesis, fmt.Sprintf("block hash doesn't match genesis block hash %s", *block.Props().BlockHash))
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+// +build unit
+
+package miner
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/c3systems/c3-go/config"
+	"github.com/c3systems/c3-go/core/chain/mainchain"
+	"github.com/c3systems/c3-go/core/chain/merkle"
+	"github.com/c3systems/c3-go/core/chain/statechain"
+	"github.com/c3systems/c3-go/core/p2p/mock"
+
+	"github.com/c3systems/merkletree"
+	"github.com/golang/mock/gomock"
+)
+
+func buildVerifiableMinedBlock(t *testing.T, blocks []*statechain.Block) *MinedBlock {
+	minedBlock := &MinedBlock{
+		StatechainBlocksMap: make(map[string]*statechain.Block),
+		TransactionsMap:     make(map[string]*statechain.Transaction),
+		DiffsMap:            make(map[string]*statechain.Diff),
+		MerkleTreesMap:      make(map[string]*merkle.Tree),
+	}
+
+	var list []merkletree.Content
+	for _, block := range blocks {
+		minedBlock.StatechainBlocksMap[*block.Props().BlockHash] = block
+		minedBlock.TransactionsMap[block.Props().TxHash] = nil
+		list = append(list, block)
+	}
+
+	tree, err := merkle.BuildFromObjects(list, merkle.StatechainBlocksKindStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := *tree.Props().MerkleTreeRootHash
+	minedBlock.MerkleTreesMap[root] = tree
+	minedBlock.NextBlock = mainchain.New(&mainchain.Props{
+		StateBlocksMerkleHash: root,
+	})
+
+	return minedBlock
+}
+
+func TestVerifyStateBlocksWithReport(t *testing.T) {
+	t.Parallel()
+
+	// 1. mock the p2p service
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockP2P := mock_p2p.NewMockInterface(mockCtrl)
+
+	// 2. build the state blocks of a few images, whose prev blocks don't match
+	var blocks []*statechain.Block
+	for i := 0; i < 3; i++ {
+		block := statechain.New(&statechain.BlockProps{
+			BlockNumber:   "0x2",
+			ImageHash:     fmt.Sprintf("image%d", i),
+			TxHash:        fmt.Sprintf("tx%d", i),
+			PrevBlockHash: fmt.Sprintf("prev%d", i),
+		})
+		if err := block.SetHash(); err != nil {
+			t.Fatal(err)
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	prevHash := "other"
+	prevBlock := statechain.New(&statechain.BlockProps{
+		BlockHash:   &prevHash,
+		BlockNumber: "0x1",
+	})
+	mockP2P.
+		EXPECT().
+		GetStatechainBlock(gomock.Any()).
+		Return(prevBlock, nil).
+		AnyTimes()
+
+	// 3. run the function
+	report, err := VerifyStateBlocksWithReport(context.Background(), mockP2P, nil, buildVerifiableMinedBlock(t, blocks))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 4. compare to the expected
+	if report.Valid {
+		t.Fatal("expected the state blocks not to verify")
+	}
+	if report.Images != len(blocks) {
+		t.Errorf("expected %v\nreceived %v", len(blocks), report.Images)
+	}
+	// note: the first failure cancels the images that are still being verified
+	if len(report.Failures) == 0 || len(report.Failures) > len(blocks) {
+		t.Fatalf("expected between 1 and %v failures\nreceived %v", len(blocks), len(report.Failures))
+	}
+	for idx, failure := range report.Failures {
+		if failure.Err != nil {
+			t.Errorf("failure %d\nexpected no err\nreceived %v", idx+1, failure.Err)
+		}
+		if failure.Reason != "prev block hash doesn't match the fetched prev block" {
+			t.Errorf("failure %d\nreceived reason %q", idx+1, failure.Reason)
+		}
+		if failure.BlockNumber != "0x2" || failure.ImageHash == "" || failure.BlockHash == "" {
+			t.Errorf("failure %d\nexpected the failed block\nreceived %v", idx+1, failure)
+		}
+	}
+	if report.Err() != nil {
+		t.Errorf("expected no err\nreceived %v", report.Err())
+	}
+
+	ok, err := VerifyStateBlocksFromMinedBlock(context.Background(), mockP2P, nil, buildVerifiableMinedBlock(t, blocks))
+	if err != nil || ok {
+		t.Errorf("expected false, nil\nreceived %v, %v", ok, err)
+	}
+}
+
+func TestVerifyStateBlocksWithReportNilBlock(t *testing.T) {
+	t.Parallel()
+
+	report, err := VerifyStateBlocksWithReport(context.Background(), nil, nil, &MinedBlock{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Valid || len(report.Failures) != 1 || report.Failures[0].Reason != "nil next block" {
+		t.Errorf("expected an invalid report\nreceived %v", report)
+	}
+}
+
+func TestAcceptsLegacyStateHash(t *testing.T) {
+	forkHeight := config.StateRootForkHeight
+	defer func() { config.StateRootForkHeight = forkHeight }()
+
+	minedBlock := func(number string) *MinedBlock {
+		return &MinedBlock{NextBlock: mainchain.New(&mainchain.Props{BlockNumber: number})}
+	}
+
+	config.StateRootForkHeight = 0
+	if acceptsLegacyStateHash(minedBlock("0x0")) {
+		t.Error("expected the legacy state hash to be rejected without a fork height")
+	}
+
+	config.StateRootForkHeight = 10
+	if !acceptsLegacyStateHash(minedBlock("0x9")) {
+		t.Error("expected the legacy state hash to be accepted below the fork height")
+	}
+	if acceptsLegacyStateHash(minedBlock("0xa")) {
+		t.Error("expected the legacy state hash to be rejected at the fork height")
+	}
+	if acceptsLegacyStateHash(nil) {
+		t.Error("expected the legacy state hash to be rejected without a mined block")
+	}
+}
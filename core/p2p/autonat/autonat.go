@@ -0,0 +1,289 @@
+package autonat
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	circuit "github.com/libp2p/go-libp2p-circuit"
+	net "github.com/libp2p/go-libp2p-net"
+	peer "github.com/libp2p/go-libp2p-peer"
+	ma "github.com/multiformats/go-multiaddr"
+	log "github.com/sirupsen/logrus"
+)
+
+// Reachability is whether the node's peers can dial it
+type Reachability string
+
+const (
+	// ReachabilityUnknown is the reachability until enough peers dialed the node back
+	ReachabilityUnknown Reachability = "unknown"
+	// ReachabilityPublic nodes were dialed back by at least one peer
+	ReachabilityPublic Reachability = "public"
+	// ReachabilityPrivate nodes weren't dialed back by any peer, e.g. they're behind a NAT
+	ReachabilityPrivate Reachability = "private"
+)
+
+const (
+	// DefaultInterval is how often the reachability is checked
+	DefaultInterval = 15 * time.Minute
+	// DefaultPeersPerCheck is the number of peers asked to dial the node back in a check
+	DefaultPeersPerCheck = 4
+	// MinFailures is the number of peers that must fail to dial the node back for it to be private
+	MinFailures = 2
+
+	firstCheckDelay = 30 * time.Second
+	dialBackTimeout = 30 * time.Second
+)
+
+// Props ...
+type Props struct {
+	Network       net.Network
+	DialBackFN    func(ctx context.Context, peerID peer.ID, addrs []ma.Multiaddr) ([]ma.Multiaddr, error) // note: asks the peer to dial the node back, returning the addrs it was dialed on
+	NATAddrsFN    func() []ma.Multiaddr                                                                   // note: optional; the addrs mapped on the NAT device
+	Relays        []string                                                                                // note: optional; ipfs multiaddrs of the relays the node is reachable through while private
+	Interval      time.Duration                                                                           // note: defaults to DefaultInterval
+	PeersPerCheck int                                                                                     // note: defaults to DefaultPeersPerCheck
+}
+
+// Status ...
+type Status struct {
+	Reachability Reachability
+	PublicAddrs  []ma.Multiaddr // note: the addrs peers dialed the node back on
+	NATAddrs     []ma.Multiaddr // note: the addrs mapped on the NAT device
+	RelayAddrs   []ma.Multiaddr // note: the relay circuit addrs advertised while private
+	Checked      time.Time
+}
+
+// Service periodically asks peers to dial the node back on its addrs to detect whether it's reachable.
+// While it isn't, the node advertises circuit addrs through its relays instead.
+type Service struct {
+	props      Props
+	relayAddrs []ma.Multiaddr
+	mut        sync.RWMutex
+	status     Status
+}
+
+// New ...
+func New(props *Props) (*Service, error) {
+	if props == nil {
+		return nil, errors.New("props are required")
+	}
+	if props.Network == nil {
+		return nil, errors.New("network is required")
+	}
+	if props.DialBackFN == nil {
+		return nil, errors.New("dial back fn is required")
+	}
+
+	p := *props
+	if p.Interval == 0 {
+		p.Interval = DefaultInterval
+	}
+	if p.PeersPerCheck == 0 {
+		p.PeersPerCheck = DefaultPeersPerCheck
+	}
+
+	s := &Service{
+		props:  p,
+		status: Status{Reachability: ReachabilityUnknown},
+	}
+	for _, relay := range p.Relays {
+		addr, err := ma.NewMultiaddr(relay + "/p2p-circuit")
+		if err != nil {
+			return nil, err
+		}
+
+		s.relayAddrs = append(s.relayAddrs, addr)
+	}
+
+	return s, nil
+}
+
+// Run checks the reachability shortly after start, then every interval, until the context is done
+func (s *Service) Run(ctx context.Context) {
+	timer := time.NewTimer(firstCheckDelay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			s.Check(ctx)
+			timer.Reset(s.props.Interval)
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Check asks some connected peers to dial the node back and updates the reachability.
+// An inconclusive check, e.g. without connected peers, keeps the last reachability.
+func (s *Service) Check(ctx context.Context) Reachability {
+	addrs := s.dialableAddrs()
+	peers := s.props.Network.Peers()
+	rand.Shuffle(len(peers), func(i, j int) { peers[i], peers[j] = peers[j], peers[i] })
+	if len(peers) > s.props.PeersPerCheck {
+		peers = peers[:s.props.PeersPerCheck]
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mut       sync.Mutex
+		successes int
+		failures  int
+		public    []ma.Multiaddr
+	)
+	if len(addrs) > 0 {
+		for _, peerID := range peers {
+			wg.Add(1)
+			go func(peerID peer.ID) {
+				defer wg.Done()
+
+				ctx, cancel := context.WithTimeout(ctx, dialBackTimeout)
+				defer cancel()
+
+				dialed, err := s.props.DialBackFN(ctx, peerID, addrs)
+				if err != nil {
+					// note: the peer didn't answer, so it doesn't count either way
+					log.Warnf("[autonat] err requesting dial back from peer %s\n%v", peerID.Pretty(), err)
+					return
+				}
+
+				mut.Lock()
+				defer mut.Unlock()
+				if len(dialed) == 0 {
+					failures++
+					return
+				}
+
+				successes++
+				public = mergeAddrs(public, dialed)
+			}(peerID)
+		}
+		wg.Wait()
+	}
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	prev := s.status.Reachability
+	s.status.Reachability = reachability(prev, successes, failures)
+	if s.status.Reachability == ReachabilityPublic && successes > 0 {
+		s.status.PublicAddrs = public
+	}
+	if s.status.Reachability != ReachabilityPublic {
+		s.status.PublicAddrs = nil
+	}
+	s.status.Checked = time.Now()
+
+	if s.status.Reachability != prev {
+		log.Printf("[autonat] reachability is %s; %v of %v peers dialed the node back", s.status.Reachability, successes, successes+failures)
+	}
+
+	return s.status.Reachability
+}
+
+// Status is the last checked reachability, with the node's NAT and relay addrs
+func (s *Service) Status() Status {
+	s.mut.RLock()
+	status := s.status
+	s.mut.RUnlock()
+
+	status.NATAddrs = s.natAddrs()
+	if status.Reachability == ReachabilityPrivate {
+		status.RelayAddrs = s.relayAddrs
+	}
+
+	return status
+}
+
+// AddrsFactory advertises the relay circuit addrs while the node is private.
+// note: implements the basic host's AddrsFactory
+func (s *Service) AddrsFactory(addrs []ma.Multiaddr) []ma.Multiaddr {
+	s.mut.RLock()
+	private := s.status.Reachability == ReachabilityPrivate
+	s.mut.RUnlock()
+
+	if !private {
+		return addrs
+	}
+
+	return mergeAddrs(addrs, s.relayAddrs)
+}
+
+// dialableAddrs are the node's listen and NAT mapped addrs peers could dial it on
+func (s *Service) dialableAddrs() []ma.Multiaddr {
+	listenAddrs, err := s.props.Network.InterfaceListenAddresses()
+	if err != nil {
+		log.Errorf("[autonat] err getting listen addrs\n%v", err)
+	}
+
+	var addrs []ma.Multiaddr
+	for _, addr := range mergeAddrs(listenAddrs, s.natAddrs()) {
+		if isLoopback(addr) || isCircuit(addr) {
+			continue
+		}
+
+		addrs = append(addrs, addr)
+	}
+
+	return addrs
+}
+
+func (s *Service) natAddrs() []ma.Multiaddr {
+	if s.props.NATAddrsFN == nil {
+		return nil
+	}
+
+	return s.props.NATAddrsFN()
+}
+
+// reachability is the reachability after a check; a node is public once dialed back, and private once enough peers failed to
+func reachability(prev Reachability, successes, failures int) Reachability {
+	switch {
+	case successes > 0:
+		return ReachabilityPublic
+	case failures >= MinFailures:
+		return ReachabilityPrivate
+	default:
+		return prev
+	}
+}
+
+func isCircuit(addr ma.Multiaddr) bool {
+	_, err := addr.ValueForProtocol(circuit.P_CIRCUIT)
+	return err == nil
+}
+
+func isLoopback(addr ma.Multiaddr) bool {
+	if ip, err := addr.ValueForProtocol(ma.P_IP4); err == nil {
+		return strings.HasPrefix(ip, "127.")
+	}
+	if ip, err := addr.ValueForProtocol(ma.P_IP6); err == nil {
+		return ip == "::1"
+	}
+
+	return false
+}
+
+// mergeAddrs merges the lists, dropping duplicates
+func mergeAddrs(lists ...[]ma.Multiaddr) []ma.Multiaddr {
+	seen := make(map[string]bool)
+	var merged []ma.Multiaddr
+	for _, list := range lists {
+		for _, addr := range list {
+			if seen[string(addr.Bytes())] {
+				continue
+			}
+
+			seen[string(addr.Bytes())] = true
+			merged = append(merged, addr)
+		}
+	}
+
+	return merged
+}
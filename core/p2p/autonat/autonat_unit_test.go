@@ -0,0 +1,191 @@
+// +build unit
+
+package autonat
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	net "github.com/libp2p/go-libp2p-net"
+	peer "github.com/libp2p/go-libp2p-peer"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+type testNetwork struct {
+	net.Network
+	peers []peer.ID
+	addrs []ma.Multiaddr
+}
+
+func (n *testNetwork) Peers() []peer.ID {
+	return append([]peer.ID(nil), n.peers...)
+}
+
+func (n *testNetwork) InterfaceListenAddresses() ([]ma.Multiaddr, error) {
+	return n.addrs, nil
+}
+
+func mustAddr(t *testing.T, s string) ma.Multiaddr {
+	addr, err := ma.NewMultiaddr(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return addr
+}
+
+// dialBackFN dials back with the result registered for the peer
+func dialBackFN(results map[peer.ID]interface{}) func(context.Context, peer.ID, []ma.Multiaddr) ([]ma.Multiaddr, error) {
+	return func(ctx context.Context, peerID peer.ID, addrs []ma.Multiaddr) ([]ma.Multiaddr, error) {
+		switch v := results[peerID].(type) {
+		case error:
+			return nil, v
+		case bool:
+			if v {
+				return addrs[:1], nil
+			}
+		}
+
+		return nil, nil
+	}
+}
+
+func TestReachability(t *testing.T) {
+	tests := []struct {
+		prev      Reachability
+		successes int
+		failures  int
+		expected  Reachability
+	}{
+		{ReachabilityUnknown, 0, 0, ReachabilityUnknown},
+		{ReachabilityUnknown, 0, MinFailures - 1, ReachabilityUnknown},
+		{ReachabilityUnknown, 0, MinFailures, ReachabilityPrivate},
+		{ReachabilityUnknown, 1, MinFailures, ReachabilityPublic},
+		{ReachabilityPublic, 0, 0, ReachabilityPublic},
+		{ReachabilityPublic, 0, MinFailures, ReachabilityPrivate},
+		{ReachabilityPrivate, 0, 1, ReachabilityPrivate},
+		{ReachabilityPrivate, 1, 0, ReachabilityPublic},
+	}
+
+	for i, tt := range tests {
+		if got := reachability(tt.prev, tt.successes, tt.failures); got != tt.expected {
+			t.Errorf("test %v: expected %s; received %s", i, tt.expected, got)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	public := mustAddr(t, "/ip4/1.2.3.4/tcp/9000")
+	network := &testNetwork{
+		peers: []peer.ID{"a", "b", "c"},
+		addrs: []ma.Multiaddr{mustAddr(t, "/ip4/127.0.0.1/tcp/9000")},
+	}
+	results := make(map[peer.ID]interface{})
+
+	s, err := New(&Props{
+		Network:    network,
+		DialBackFN: dialBackFN(results),
+		NATAddrsFN: func() []ma.Multiaddr { return []ma.Multiaddr{public} },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// note: peers that don't answer don't count either way
+	results["a"] = errors.New("no answer")
+	results["b"] = errors.New("no answer")
+	results["c"] = false
+	if got := s.Check(context.Background()); got != ReachabilityUnknown {
+		t.Errorf("expected %s; received %s", ReachabilityUnknown, got)
+	}
+
+	results["b"] = false
+	if got := s.Check(context.Background()); got != ReachabilityPrivate {
+		t.Errorf("expected %s; received %s", ReachabilityPrivate, got)
+	}
+
+	results["a"] = true
+	if got := s.Check(context.Background()); got != ReachabilityPublic {
+		t.Errorf("expected %s; received %s", ReachabilityPublic, got)
+	}
+	status := s.Status()
+	if len(status.PublicAddrs) != 1 || !status.PublicAddrs[0].Equal(public) {
+		t.Errorf("expected public addrs [%s]; received %v", public, status.PublicAddrs)
+	}
+	if status.Checked.IsZero() {
+		t.Error("expected the check time to be set")
+	}
+
+	// note: without peers the check is inconclusive
+	network.peers = nil
+	if got := s.Check(context.Background()); got != ReachabilityPublic {
+		t.Errorf("expected %s; received %s", ReachabilityPublic, got)
+	}
+}
+
+func TestCheckWithoutDialableAddrs(t *testing.T) {
+	network := &testNetwork{
+		peers: []peer.ID{"a", "b"},
+		addrs: []ma.Multiaddr{mustAddr(t, "/ip4/127.0.0.1/tcp/9000")},
+	}
+
+	var called bool
+	s, err := New(&Props{
+		Network: network,
+		DialBackFN: func(context.Context, peer.ID, []ma.Multiaddr) ([]ma.Multiaddr, error) {
+			called = true
+			return nil, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := s.Check(context.Background()); got != ReachabilityUnknown {
+		t.Errorf("expected %s; received %s", ReachabilityUnknown, got)
+	}
+	if called {
+		t.Error("expected no dial back requests for loopback addrs")
+	}
+}
+
+func TestAddrsFactory(t *testing.T) {
+	relay := "/ip4/5.6.7.8/tcp/9000/ipfs/QmNnooDu7bfjPFoTZYxMNLWUQJyrVwtbZg5gBMjTezGAJN"
+	listen := []ma.Multiaddr{mustAddr(t, "/ip4/10.0.0.2/tcp/9000")}
+	results := map[peer.ID]interface{}{"a": false, "b": false}
+
+	s, err := New(&Props{
+		Network:    &testNetwork{peers: []peer.ID{"a", "b"}, addrs: listen},
+		DialBackFN: dialBackFN(results),
+		Relays:     []string{relay},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if addrs := s.AddrsFactory(listen); len(addrs) != 1 {
+		t.Errorf("expected only the listen addr before the node is private; received %v", addrs)
+	}
+
+	if got := s.Check(context.Background()); got != ReachabilityPrivate {
+		t.Fatalf("expected %s; received %s", ReachabilityPrivate, got)
+	}
+	addrs := s.AddrsFactory(listen)
+	if len(addrs) != 2 || addrs[1].String() != relay+"/p2p-circuit" {
+		t.Errorf("expected the relay circuit addr to be advertised; received %v", addrs)
+	}
+	if status := s.Status(); len(status.RelayAddrs) != 1 {
+		t.Errorf("expected 1 relay addr; received %v", status.RelayAddrs)
+	}
+}
+
+func TestNewInvalidRelay(t *testing.T) {
+	if _, err := New(&Props{
+		Network:    &testNetwork{},
+		DialBackFN: dialBackFN(nil),
+		Relays:     []string{"not a multiaddr"},
+	}); err == nil {
+		t.Error("expected an error for an invalid relay addr")
+	}
+}
@@ -31,6 +31,7 @@ type Interface interface {
 	GetStatechainDiff(c *cid.Cid) (*statechain.Diff, error)
 	GetMerkleTree(c *cid.Cid) (*merkle.Tree, error)
 	GetBytes(c *cid.Cid) ([]byte, error)
+	GetLocalBytes(c *cid.Cid) ([]byte, error)
 	GetLatestBlock() (*mainchain.Block, error)
 	FetchMostRecentStateBlock(imageHash string, block *mainchain.Block) (*statechain.Block, error)
 }
@@ -0,0 +1,37 @@
+package libp2ptls
+
+import (
+	"crypto/tls"
+
+	connsec "github.com/libp2p/go-conn-security"
+	ci "github.com/libp2p/go-libp2p-crypto"
+	peer "github.com/libp2p/go-libp2p-peer"
+)
+
+type conn struct {
+	*tls.Conn
+
+	localPeer peer.ID
+	privKey   ci.PrivKey
+
+	remotePeer   peer.ID
+	remotePubKey ci.PubKey
+}
+
+var _ connsec.Conn = &conn{}
+
+func (c *conn) LocalPeer() peer.ID {
+	return c.localPeer
+}
+
+func (c *conn) LocalPrivateKey() ci.PrivKey {
+	return c.privKey
+}
+
+func (c *conn) RemotePeer() peer.ID {
+	return c.remotePeer
+}
+
+func (c *conn) RemotePublicKey() ci.PubKey {
+	return c.remotePubKey
+}
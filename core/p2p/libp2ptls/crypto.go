@@ -0,0 +1,201 @@
+package libp2ptls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	ci "github.com/libp2p/go-libp2p-crypto"
+	peer "github.com/libp2p/go-libp2p-peer"
+)
+
+const certValidityPeriod = 100 * 365 * 24 * time.Hour // ~100 years
+const certificatePrefix = "libp2p-tls-handshake:"
+const alpn string = "libp2p"
+
+// extensionID is the libp2p public key extension of the certificates
+var extensionID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 53594, 1, 1}
+
+type signedKey struct {
+	PubKey    []byte
+	Signature []byte
+}
+
+// Identity is the certificate securing the conns of a peer
+type Identity struct {
+	config tls.Config
+}
+
+// NewIdentity builds the identity of the peer's key
+func NewIdentity(privKey ci.PrivKey) (*Identity, error) {
+	cert, err := keyToCertificate(privKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Identity{
+		config: tls.Config{
+			MinVersion:         tls.VersionTLS13,
+			InsecureSkipVerify: true, // note: not insecure; the cert chain is verified by VerifyPeerCertificate
+			ClientAuth:         tls.RequireAnyClientCert,
+			Certificates:       []tls.Certificate{*cert},
+			VerifyPeerCertificate: func(_ [][]byte, _ [][]*x509.Certificate) error {
+				panic("tls config not specialized for peer")
+			},
+			NextProtos:             []string{alpn},
+			SessionTicketsDisabled: true,
+		},
+	}, nil
+}
+
+// ConfigForAny is ConfigForPeer("")
+func (i *Identity) ConfigForAny() (*tls.Config, <-chan ci.PubKey) {
+	return i.ConfigForPeer("")
+}
+
+// ConfigForPeer builds a single use tls config that verifies the peer's certificate chain
+// and sends the peer's public key on the channel. Any peer is accepted when the peer id is empty.
+func (i *Identity) ConfigForPeer(remote peer.ID) (*tls.Config, <-chan ci.PubKey) {
+	keyCh := make(chan ci.PubKey, 1)
+	// note: the config is cloned, since it's shared by the listener and concurrent dials
+	conf := i.config.Clone()
+	// note: with InsecureSkipVerify the verified chains are always empty, so the raw certs are parsed
+	conf.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		defer close(keyCh)
+
+		chain := make([]*x509.Certificate, len(rawCerts))
+		for i := 0; i < len(rawCerts); i++ {
+			cert, err := x509.ParseCertificate(rawCerts[i])
+			if err != nil {
+				return err
+			}
+			chain[i] = cert
+		}
+
+		pubKey, err := PubKeyFromCertChain(chain)
+		if err != nil {
+			return err
+		}
+		if remote != "" && !remote.MatchesPublicKey(pubKey) {
+			return errors.New("peer IDs don't match")
+		}
+
+		keyCh <- pubKey
+		return nil
+	}
+
+	return conf, keyCh
+}
+
+// PubKeyFromCertChain verifies the certificate chain and returns the remote peer's public key
+func PubKeyFromCertChain(chain []*x509.Certificate) (ci.PubKey, error) {
+	if len(chain) != 1 {
+		return nil, errors.New("expected one certificates in the chain")
+	}
+
+	cert := chain[0]
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: pool}); err != nil {
+		// note: the x509 err would be sent on the wire, so it's wrapped
+		return nil, fmt.Errorf("certificate verification failed: %s", err)
+	}
+
+	var (
+		found  bool
+		keyExt pkix.Extension
+	)
+	// note: unknown extensions are skipped
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(extensionID) {
+			keyExt = ext
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, errors.New("expected certificate to contain the key extension")
+	}
+
+	var sk signedKey
+	if _, err := asn1.Unmarshal(keyExt.Value, &sk); err != nil {
+		return nil, fmt.Errorf("unmarshalling signed certificate failed: %s", err)
+	}
+	pubKey, err := ci.UnmarshalPublicKey(sk.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshalling public key failed: %s", err)
+	}
+	certKeyPub, err := x509.MarshalPKIXPublicKey(cert.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	valid, err := pubKey.Verify(append([]byte(certificatePrefix), certKeyPub...), sk.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("signature verification failed: %s", err)
+	}
+	if !valid {
+		return nil, errors.New("signature invalid")
+	}
+
+	return pubKey, nil
+}
+
+// keyToCertificate builds a self signed certificate whose key is signed by the peer's key
+func keyToCertificate(sk ci.PrivKey) (*tls.Certificate, error) {
+	certKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	keyBytes, err := ci.MarshalPublicKey(sk.GetPublic())
+	if err != nil {
+		return nil, err
+	}
+	certKeyPub, err := x509.MarshalPKIXPublicKey(certKey.Public())
+	if err != nil {
+		return nil, err
+	}
+	signature, err := sk.Sign(append([]byte(certificatePrefix), certKeyPub...))
+	if err != nil {
+		return nil, err
+	}
+	value, err := asn1.Marshal(signedKey{
+		PubKey:    keyBytes,
+		Signature: signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sn, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		return nil, err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: sn,
+		NotBefore:    time.Time{},
+		NotAfter:     time.Now().Add(certValidityPeriod),
+		// note: the extra extensions end up in the certificate's extensions
+		ExtraExtensions: []pkix.Extension{
+			{Id: extensionID, Value: value},
+		},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, certKey.Public(), certKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Certificate{
+		Certificate: [][]byte{certDER},
+		PrivateKey:  certKey,
+	}, nil
+}
@@ -0,0 +1,127 @@
+// Package libp2ptls is the libp2p tls 1.3 secure channel, so the node can negotiate tls with peers that don't speak secio.
+// note: it's a port of github.com/libp2p/go-libp2p-tls to the libp2p interfaces vendored here.
+package libp2ptls
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"sync"
+
+	connsec "github.com/libp2p/go-conn-security"
+	ci "github.com/libp2p/go-libp2p-crypto"
+	peer "github.com/libp2p/go-libp2p-peer"
+)
+
+// ID is the multistream protocol id of the secure channel
+const ID = "/tls/1.0.0"
+
+// Transport secures the conns of a peer with tls
+type Transport struct {
+	identity *Identity
+
+	localPeer peer.ID
+	privKey   ci.PrivKey
+}
+
+var _ connsec.Transport = &Transport{}
+
+// New builds the tls secure channel of the peer's key
+func New(key ci.PrivKey) (*Transport, error) {
+	id, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := NewIdentity(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transport{
+		identity:  identity,
+		localPeer: id,
+		privKey:   key,
+	}, nil
+}
+
+// SecureInbound runs the tls handshake as the server
+func (t *Transport) SecureInbound(ctx context.Context, insecure net.Conn) (connsec.Conn, error) {
+	config, keyCh := t.identity.ConfigForAny()
+	cs, err := t.handshake(ctx, tls.Server(insecure, config), keyCh)
+	if err != nil {
+		insecure.Close()
+	}
+
+	return cs, err
+}
+
+// SecureOutbound runs the tls handshake as the client.
+// note: in tls 1.3 the client sends its certificate along with its finished message, so the handshake doesn't fail
+// when the server refuses the certificate; the server closes the conn instead, which the client notices on its first read.
+func (t *Transport) SecureOutbound(ctx context.Context, insecure net.Conn, p peer.ID) (connsec.Conn, error) {
+	config, keyCh := t.identity.ConfigForPeer(p)
+	cs, err := t.handshake(ctx, tls.Client(insecure, config), keyCh)
+	if err != nil {
+		insecure.Close()
+	}
+
+	return cs, err
+}
+
+func (t *Transport) handshake(ctx context.Context, tlsConn *tls.Conn, keyCh <-chan ci.PubKey) (connsec.Conn, error) {
+	// note: the conn is closed when the context is done, to abort the handshake
+	select {
+	case <-ctx.Done():
+		tlsConn.Close()
+	default:
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	// note: doesn't return before the handshake is done or the context is canceled
+	defer wg.Wait()
+	defer close(done)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			tlsConn.Close()
+		}
+	}()
+
+	if err := tlsConn.Handshake(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+
+		return nil, err
+	}
+
+	// note: the key is sent by the verification during the handshake, so this doesn't block
+	var remotePubKey ci.PubKey
+	select {
+	case remotePubKey = <-keyCh:
+	default:
+	}
+	if remotePubKey == nil {
+		return nil, errors.New("expected the remote pub key to be set by the handshake")
+	}
+
+	remotePeer, err := peer.IDFromPublicKey(remotePubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conn{
+		Conn:         tlsConn,
+		localPeer:    t.localPeer,
+		privKey:      t.privKey,
+		remotePeer:   remotePeer,
+		remotePubKey: remotePubKey,
+	}, nil
+}
@@ -0,0 +1,91 @@
+// +build unit
+
+package libp2ptls
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	ci "github.com/libp2p/go-libp2p-crypto"
+	peer "github.com/libp2p/go-libp2p-peer"
+)
+
+func newTestTransport(t *testing.T) (*Transport, peer.ID) {
+	priv, _, err := ci.GenerateKeyPair(ci.Ed25519, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tpt, err := New(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tpt, tpt.localPeer
+}
+
+// handshake secures both ends of a loopback conn, dialing the expected peer
+func handshake(t *testing.T, server, client *Transport, expected peer.ID) (serverErr, clientErr error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	clientConn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverConn, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		conn, err := server.SecureInbound(context.Background(), serverConn)
+		if err == nil {
+			if conn.RemotePeer() != client.localPeer {
+				t.Errorf("expected the server to authenticate %s; received %s", client.localPeer.Pretty(), conn.RemotePeer().Pretty())
+			}
+			// note: the server reads the client's message, so a refused certificate fails the client's read
+			_, err = conn.Read(make([]byte, 1))
+			conn.Close()
+		}
+
+		errs <- err
+	}()
+
+	conn, err := client.SecureOutbound(context.Background(), clientConn, expected)
+	if err == nil {
+		if conn.RemotePeer() != server.localPeer {
+			t.Errorf("expected the client to authenticate %s; received %s", server.localPeer.Pretty(), conn.RemotePeer().Pretty())
+		}
+		_, err = conn.Write([]byte{1})
+		conn.Close()
+	}
+
+	return <-errs, err
+}
+
+func TestHandshake(t *testing.T) {
+	server, serverID := newTestTransport(t)
+	client, _ := newTestTransport(t)
+
+	serverErr, clientErr := handshake(t, server, client, serverID)
+	if serverErr != nil || clientErr != nil {
+		t.Errorf("expected the handshake to succeed; received %v, %v", serverErr, clientErr)
+	}
+}
+
+func TestHandshakeWrongPeer(t *testing.T) {
+	server, _ := newTestTransport(t)
+	client, _ := newTestTransport(t)
+	_, otherID := newTestTransport(t)
+
+	// note: the client refuses the server, since it isn't the peer it dialed
+	if _, clientErr := handshake(t, server, client, otherID); clientErr == nil {
+		t.Error("expected the client to refuse the server")
+	}
+}
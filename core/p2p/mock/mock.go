@@ -231,6 +231,19 @@ func (mr *MockInterfaceMockRecorder) GetBytes(c interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBytes", reflect.TypeOf((*MockInterface)(nil).GetBytes), c)
 }
 
+// GetLocalBytes mocks base method
+func (m *MockInterface) GetLocalBytes(c *go_cid.Cid) ([]byte, error) {
+	ret := m.ctrl.Call(m, "GetLocalBytes", c)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLocalBytes indicates an expected call of GetLocalBytes
+func (mr *MockInterfaceMockRecorder) GetLocalBytes(c interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocalBytes", reflect.TypeOf((*MockInterface)(nil).GetLocalBytes), c)
+}
+
 // GetLatestBlock mocks base method
 func (m *MockInterface) GetLatestBlock() (*mainchain.Block, error) {
 	ret := m.ctrl.Call(m, "GetLatestBlock")
@@ -0,0 +1,459 @@
+package peermanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	ipfsaddr "github.com/ipfs/go-ipfs-addr"
+	host "github.com/libp2p/go-libp2p-host"
+	net "github.com/libp2p/go-libp2p-net"
+	peer "github.com/libp2p/go-libp2p-peer"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	ma "github.com/multiformats/go-multiaddr"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultMinPeers is the number of connections below which the manager dials known peers
+	DefaultMinPeers = 4
+	// DefaultMaxPeers is the number of connections above which the manager prunes connections
+	DefaultMaxPeers = 50
+	// DefaultInterval is how often the connections are checked and the peerstore persisted
+	DefaultInterval = 30 * time.Second
+
+	dialTimeout = 10 * time.Second
+
+	// maxStoredPeers is the number of most recently seen peers persisted
+	maxStoredPeers = 200
+	// storedPeerMaxAge is how long a peer that isn't seen again stays persisted
+	storedPeerMaxAge = 7 * 24 * time.Hour
+	// storedAddrTTL is how long the loaded addrs are kept in the peerstore; connecting to the peer renews them
+	storedAddrTTL = time.Hour
+)
+
+// ErrNoBootstrapPeer is returned when none of the bootstrap peers could be connected to
+var ErrNoBootstrapPeer = errors.New("couldn't connect to any bootstrap peer")
+
+// Props ...
+type Props struct {
+	Host           host.Host
+	BootstrapPeers []string             // note: ipfs multiaddrs, e.g. /ip4/127.0.0.1/tcp/9000/ipfs/Qm...
+	PeerstorePath  string               // note: optional; the file the known peers are persisted to
+	MinPeers       int                  // note: defaults to DefaultMinPeers
+	MaxPeers       int                  // note: defaults to DefaultMaxPeers
+	Interval       time.Duration        // note: defaults to DefaultInterval
+	BannedFN       func(p peer.ID) bool // note: optional; banned peers aren't dialed
+}
+
+// PeerInfo is a known peer
+type PeerInfo struct {
+	ID        peer.ID
+	Addrs     []ma.Multiaddr
+	Connected bool
+	Bootstrap bool
+}
+
+// Manager keeps the node connected to between the min and max number of peers.
+// It dials the bootstrap peers, then the peers in the peerstore, which is persisted across restarts.
+type Manager struct {
+	props     Props
+	mut       sync.Mutex // note: guards the peerstore file and the seen times
+	bootstrap []*peerstore.PeerInfo
+	seen      map[peer.ID]time.Time // note: when the peers were last connected to, including in earlier runs
+}
+
+// New builds the manager, loading the persisted peers into the host's peerstore
+func New(props *Props) (*Manager, error) {
+	if props == nil {
+		return nil, errors.New("props are required")
+	}
+	if props.Host == nil {
+		return nil, errors.New("host is required")
+	}
+
+	p := *props
+	if p.MinPeers == 0 {
+		p.MinPeers = DefaultMinPeers
+	}
+	if p.MaxPeers == 0 {
+		p.MaxPeers = DefaultMaxPeers
+	}
+	if p.MaxPeers < p.MinPeers {
+		return nil, fmt.Errorf("max peers %v is less than min peers %v", p.MaxPeers, p.MinPeers)
+	}
+	if p.Interval == 0 {
+		p.Interval = DefaultInterval
+	}
+
+	m := &Manager{
+		props: p,
+		seen:  make(map[peer.ID]time.Time),
+	}
+	for _, addr := range p.BootstrapPeers {
+		pinfo, err := ParseAddr(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		m.bootstrap = append(m.bootstrap, pinfo)
+		p.Host.Peerstore().AddAddrs(pinfo.ID, pinfo.Addrs, peerstore.PermanentAddrTTL)
+	}
+
+	// note: the known peers are only a head start; a node starts without them rather than fail on a corrupt file
+	if err := m.load(); err != nil {
+		log.Errorf("[peermanager] err loading peerstore %s; ignoring it\n%v", p.PeerstorePath, err)
+	}
+
+	return m, nil
+}
+
+// ParseAddr parses an ipfs multiaddr, e.g. /ip4/127.0.0.1/tcp/9000/ipfs/Qm...
+func ParseAddr(addr string) (*peerstore.PeerInfo, error) {
+	iaddr, err := ipfsaddr.ParseString(addr)
+	if err != nil {
+		return nil, fmt.Errorf("err parsing peer addr %s\n%v", addr, err)
+	}
+
+	pinfo, err := peerstore.InfoFromP2pAddr(iaddr.Multiaddr())
+	if err != nil {
+		return nil, fmt.Errorf("err getting peer info from addr %s\n%v", addr, err)
+	}
+
+	return pinfo, nil
+}
+
+// Bootstrap connects to the bootstrap peers.
+// It fails only if there are bootstrap peers and none of them could be connected to.
+func (m *Manager) Bootstrap(ctx context.Context) error {
+	var connected int
+	for _, pinfo := range m.bootstrap {
+		if err := m.dial(ctx, *pinfo); err != nil {
+			log.Errorf("[peermanager] err connecting to bootstrap peer %s\n%v", pinfo.ID.Pretty(), err)
+			continue
+		}
+
+		connected++
+	}
+
+	if len(m.bootstrap) > 0 && connected == 0 {
+		return ErrNoBootstrapPeer
+	}
+
+	return nil
+}
+
+// Run keeps the number of connections between the min and max, and persists the peerstore, until the context is done
+func (m *Manager) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.props.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.fill(ctx)
+			m.prune()
+			if err := m.Save(); err != nil {
+				log.Errorf("[peermanager] err saving peerstore\n%v", err)
+			}
+
+		case <-ctx.Done():
+			if err := m.Save(); err != nil {
+				log.Errorf("[peermanager] err saving peerstore\n%v", err)
+			}
+			return
+		}
+	}
+}
+
+// Connect connects to the peer at the ipfs multiaddr, adding it to the peerstore
+func (m *Manager) Connect(ctx context.Context, addr string) (peer.ID, error) {
+	pinfo, err := ParseAddr(addr)
+	if err != nil {
+		return "", err
+	}
+	if m.banned(pinfo.ID) {
+		return "", fmt.Errorf("peer %s is banned", pinfo.ID.Pretty())
+	}
+
+	m.props.Host.Peerstore().AddAddrs(pinfo.ID, pinfo.Addrs, peerstore.PermanentAddrTTL)
+	if err := m.dial(ctx, *pinfo); err != nil {
+		return "", err
+	}
+
+	return pinfo.ID, nil
+}
+
+// Peers are the known peers, sorted by id
+func (m *Manager) Peers() []PeerInfo {
+	self := m.props.Host.ID()
+	ps := m.props.Host.Peerstore()
+	network := m.props.Host.Network()
+
+	var peers []PeerInfo
+	for _, p := range ps.Peers() {
+		if p == self {
+			continue
+		}
+
+		peers = append(peers, PeerInfo{
+			ID:        p,
+			Addrs:     ps.Addrs(p),
+			Connected: network.Connectedness(p) == net.Connected,
+			Bootstrap: m.isBootstrap(p),
+		})
+	}
+
+	sort.Slice(peers, func(i, j int) bool { return peers[i].ID < peers[j].ID })
+	return peers
+}
+
+// HandlePeerFound adds the peers found by mDNS to the peerstore, connecting to them while under the max peers
+// note: implements the mDNS discovery notifee
+func (m *Manager) HandlePeerFound(pinfo peerstore.PeerInfo) {
+	if pinfo.ID == m.props.Host.ID() || m.banned(pinfo.ID) {
+		return
+	}
+
+	m.props.Host.Peerstore().AddAddrs(pinfo.ID, pinfo.Addrs, peerstore.PermanentAddrTTL)
+	if len(m.props.Host.Network().Peers()) >= m.props.MaxPeers {
+		log.Printf("[peermanager] found peer %s; at max peers, not connecting", pinfo.ID.Pretty())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+	if err := m.dial(ctx, pinfo); err != nil {
+		log.Printf("[peermanager] found peer %s\nerr connecting %v", pinfo.Addrs, err)
+		return
+	}
+
+	log.Printf("[peermanager] found peer %s\nadded to peerstore and connected", pinfo.Addrs)
+}
+
+// fill dials the known peers while under the min peers, bootstrap peers first
+func (m *Manager) fill(ctx context.Context) {
+	network := m.props.Host.Network()
+	need := m.props.MinPeers - len(network.Peers())
+	if need <= 0 {
+		return
+	}
+
+	candidates := m.Peers()
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Bootstrap && !candidates[j].Bootstrap })
+
+	for _, candidate := range candidates {
+		if need <= 0 {
+			return
+		}
+		if candidate.Connected || len(candidate.Addrs) == 0 || m.banned(candidate.ID) {
+			continue
+		}
+
+		if err := m.dial(ctx, peerstore.PeerInfo{ID: candidate.ID, Addrs: candidate.Addrs}); err != nil {
+			log.Warnf("[peermanager] err connecting to peer %s\n%v", candidate.ID.Pretty(), err)
+			continue
+		}
+
+		need--
+	}
+}
+
+// prune closes the connections over the max peers, keeping the bootstrap peers
+func (m *Manager) prune() {
+	network := m.props.Host.Network()
+	peers := network.Peers()
+	excess := len(peers) - m.props.MaxPeers
+	if excess <= 0 {
+		return
+	}
+
+	sort.Slice(peers, func(i, j int) bool { return peers[i] < peers[j] })
+	for _, p := range peers {
+		if excess <= 0 {
+			return
+		}
+		if m.isBootstrap(p) {
+			continue
+		}
+
+		if err := network.ClosePeer(p); err != nil {
+			log.Warnf("[peermanager] err disconnecting peer %s\n%v", p.Pretty(), err)
+			continue
+		}
+
+		log.Printf("[peermanager] pruned connection to peer %s", p.Pretty())
+		excess--
+	}
+}
+
+func (m *Manager) dial(ctx context.Context, pinfo peerstore.PeerInfo) error {
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	if err := m.props.Host.Connect(ctx, pinfo); err != nil {
+		return err
+	}
+
+	m.mut.Lock()
+	m.seen[pinfo.ID] = time.Now()
+	m.mut.Unlock()
+
+	return nil
+}
+
+func (m *Manager) isBootstrap(p peer.ID) bool {
+	for _, pinfo := range m.bootstrap {
+		if pinfo.ID == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *Manager) banned(p peer.ID) bool {
+	return m.props.BannedFN != nil && m.props.BannedFN(p)
+}
+
+// peerstoreFile is the persisted format of the peerstore: base58 peer ids to their multiaddrs and when they were last seen
+type peerstoreFile map[string]storedPeer
+
+// storedPeer is a persisted peer
+type storedPeer struct {
+	Addrs    []string  `json:"addrs"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// load adds the persisted peers to the host's peerstore.
+// note: their addrs expire unless the peers are connected to, so unreachable peers are forgotten.
+func (m *Manager) load() error {
+	if m.props.PeerstorePath == "" {
+		return nil
+	}
+
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	data, err := ioutil.ReadFile(m.props.PeerstorePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var peers peerstoreFile
+	if err := json.Unmarshal(data, &peers); err != nil {
+		return err
+	}
+
+	for id, stored := range peers {
+		p, err := peer.IDB58Decode(id)
+		if err != nil {
+			log.Warnf("[peermanager] err decoding stored peer %s\n%v", id, err)
+			continue
+		}
+		if p == m.props.Host.ID() || m.banned(p) || time.Since(stored.LastSeen) > storedPeerMaxAge {
+			continue
+		}
+
+		var addrs []ma.Multiaddr
+		for _, addrStr := range stored.Addrs {
+			addr, err := ma.NewMultiaddr(addrStr)
+			if err != nil {
+				log.Warnf("[peermanager] err decoding stored addr %s\n%v", addrStr, err)
+				continue
+			}
+
+			addrs = append(addrs, addr)
+		}
+
+		m.seen[p] = stored.LastSeen
+		m.props.Host.Peerstore().AddAddrs(p, addrs, storedAddrTTL)
+	}
+
+	return nil
+}
+
+// Save persists the most recently seen peers that have addrs and aren't banned.
+// note: the file is replaced atomically, so a crash mid-save doesn't corrupt it.
+func (m *Manager) Save() error {
+	if m.props.PeerstorePath == "" {
+		return nil
+	}
+
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	now := time.Now()
+	var stored []PeerInfo
+	for _, pinfo := range m.Peers() {
+		if pinfo.Connected {
+			m.seen[pinfo.ID] = now
+		}
+		lastSeen, ok := m.seen[pinfo.ID]
+		if !ok || now.Sub(lastSeen) > storedPeerMaxAge || len(pinfo.Addrs) == 0 || m.banned(pinfo.ID) {
+			continue
+		}
+
+		stored = append(stored, pinfo)
+	}
+
+	sort.SliceStable(stored, func(i, j int) bool { return m.seen[stored[i].ID].After(m.seen[stored[j].ID]) })
+	if len(stored) > maxStoredPeers {
+		stored = stored[:maxStoredPeers]
+	}
+
+	peers := make(peerstoreFile)
+	for _, pinfo := range stored {
+		var addrs []string
+		for _, addr := range pinfo.Addrs {
+			addrs = append(addrs, addr.String())
+		}
+		peers[peer.IDB58Encode(pinfo.ID)] = storedPeer{
+			Addrs:    addrs,
+			LastSeen: m.seen[pinfo.ID],
+		}
+	}
+
+	data, err := json.Marshal(peers)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(m.props.PeerstorePath, data, 0644)
+}
+
+// writeFileAtomic writes the data to a temp file in the same directory, then renames it over the file
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,291 @@
+// +build unit
+
+package peermanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	csms "github.com/libp2p/go-conn-security-multistream"
+	lCrypt "github.com/libp2p/go-libp2p-crypto"
+	host "github.com/libp2p/go-libp2p-host"
+	net "github.com/libp2p/go-libp2p-net"
+	peer "github.com/libp2p/go-libp2p-peer"
+	"github.com/libp2p/go-libp2p-peerstore/pstoremem"
+	secio "github.com/libp2p/go-libp2p-secio"
+	swarm "github.com/libp2p/go-libp2p-swarm"
+	tptu "github.com/libp2p/go-libp2p-transport-upgrader"
+	bhost "github.com/libp2p/go-libp2p/p2p/host/basic"
+	tcp "github.com/libp2p/go-tcp-transport"
+	ma "github.com/multiformats/go-multiaddr"
+	msmux "github.com/whyrusleeping/go-smux-multistream"
+	yamux "github.com/whyrusleeping/go-smux-yamux"
+)
+
+func newTestHost(t *testing.T) host.Host {
+	priv, pub, err := lCrypt.GenerateKeyPair(lCrypt.Ed25519, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, err := peer.IDFromPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ps := pstoremem.NewPeerstore()
+	if err := ps.AddPrivKey(pid, priv); err != nil {
+		t.Fatal(err)
+	}
+	if err := ps.AddPubKey(pid, pub); err != nil {
+		t.Fatal(err)
+	}
+
+	n := swarm.NewSwarm(context.Background(), pid, ps, nil)
+	secMuxer := new(csms.SSMuxer)
+	secMuxer.AddTransport(secio.ID, &secio.Transport{LocalID: pid, PrivateKey: priv})
+	stMuxer := msmux.NewBlankTransport()
+	stMuxer.AddTransport("/yamux/1.0.0", yamux.DefaultTransport)
+	if err := n.AddTransport(tcp.NewTCPTransport(&tptu.Upgrader{Secure: secMuxer, Muxer: stMuxer, Filters: n.Filters})); err != nil {
+		t.Fatal(err)
+	}
+
+	listen, err := ma.NewMultiaddr("/ip4/127.0.0.1/tcp/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.AddListenAddr(listen); err != nil {
+		t.Fatal(err)
+	}
+
+	return bhost.New(n)
+}
+
+func addrOf(h host.Host) string {
+	return fmt.Sprintf("%s/ipfs/%s", h.Addrs()[0], h.ID().Pretty())
+}
+
+func TestBootstrapAndPersist(t *testing.T) {
+	dir, err := ioutil.TempDir("", "peermanager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	remote := newTestHost(t)
+	defer remote.Close()
+
+	local := newTestHost(t)
+	path := filepath.Join(dir, "peers.json")
+	m, err := New(&Props{
+		Host:           local,
+		BootstrapPeers: []string{addrOf(remote)},
+		PeerstorePath:  path,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Bootstrap(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if local.Network().Connectedness(remote.ID()) != net.Connected {
+		t.Fatal("expected to be connected to the bootstrap peer")
+	}
+
+	peers := m.Peers()
+	if len(peers) != 1 || peers[0].ID != remote.ID() || !peers[0].Connected || !peers[0].Bootstrap {
+		t.Fatalf("expected the connected bootstrap peer; received %v", peers)
+	}
+
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+	local.Close()
+
+	// note: a restarted node knows the peers of the last run
+	restarted := newTestHost(t)
+	defer restarted.Close()
+	m, err = New(&Props{Host: restarted, PeerstorePath: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restarted.Peerstore().Addrs(remote.ID())) == 0 {
+		t.Fatal("expected the persisted peer to be loaded")
+	}
+
+	m.fill(context.Background())
+	if restarted.Network().Connectedness(remote.ID()) != net.Connected {
+		t.Error("expected to dial the persisted peer while under the min peers")
+	}
+}
+
+func TestConnectAndPrune(t *testing.T) {
+	local := newTestHost(t)
+	defer local.Close()
+
+	var banned peer.ID
+	m, err := New(&Props{
+		Host:     local,
+		MinPeers: 1,
+		MaxPeers: 1,
+		BannedFN: func(p peer.ID) bool { return p == banned },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var remotes []host.Host
+	for i := 0; i < 3; i++ {
+		remote := newTestHost(t)
+		defer remote.Close()
+		remotes = append(remotes, remote)
+	}
+
+	banned = remotes[2].ID()
+	if _, err := m.Connect(context.Background(), addrOf(remotes[2])); err == nil {
+		t.Error("expected connecting to a banned peer to fail")
+	}
+
+	for _, remote := range remotes[:2] {
+		id, err := m.Connect(context.Background(), addrOf(remote))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != remote.ID() {
+			t.Errorf("expected peer %s; received %s", remote.ID().Pretty(), id.Pretty())
+		}
+	}
+	if len(local.Network().Peers()) != 2 {
+		t.Fatalf("expected 2 connections; received %v", len(local.Network().Peers()))
+	}
+
+	m.prune()
+	if len(local.Network().Peers()) != 1 {
+		t.Errorf("expected the connections to be pruned to the max; received %v", len(local.Network().Peers()))
+	}
+}
+
+func TestLoadCorruptPeerstore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "peermanager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "peers.json")
+	if err := ioutil.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	local := newTestHost(t)
+	defer local.Close()
+
+	// note: the node starts without the known peers rather than fail
+	m, err := New(&Props{Host: local, PeerstorePath: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Peers()) != 0 {
+		t.Errorf("expected no known peers; received %v", m.Peers())
+	}
+
+	// note: the next save replaces the corrupt file
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var peers peerstoreFile
+	if err := json.Unmarshal(data, &peers); err != nil {
+		t.Errorf("expected the saved peerstore to be valid; %v", err)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("expected no temp files to be left; received %d files", len(files))
+	}
+}
+
+func TestSaveSkipsBannedAndStalePeers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "peermanager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var remotes []host.Host
+	for i := 0; i < 3; i++ {
+		remote := newTestHost(t)
+		defer remote.Close()
+		remotes = append(remotes, remote)
+	}
+	recent, stale, banned := remotes[0], remotes[1], remotes[2]
+
+	// note: the peers were persisted by an earlier run
+	path := filepath.Join(dir, "peers.json")
+	stored := peerstoreFile{
+		recent.ID().Pretty(): {Addrs: []string{recent.Addrs()[0].String()}, LastSeen: time.Now().Add(-time.Hour)},
+		stale.ID().Pretty():  {Addrs: []string{stale.Addrs()[0].String()}, LastSeen: time.Now().Add(-2 * storedPeerMaxAge)},
+		banned.ID().Pretty(): {Addrs: []string{banned.Addrs()[0].String()}, LastSeen: time.Now()},
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	local := newTestHost(t)
+	defer local.Close()
+	bannedID := banned.ID()
+	m, err := New(&Props{
+		Host:          local,
+		PeerstorePath: path,
+		BannedFN:      func(p peer.ID) bool { return p == bannedID },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	peers := m.Peers()
+	if len(peers) != 1 || peers[0].ID != recent.ID() {
+		t.Fatalf("expected only the recently seen peer to be loaded; received %v", peers)
+	}
+
+	// note: a peer banned during the run isn't persisted
+	bannedID = ""
+	if _, err := m.Connect(context.Background(), addrOf(banned)); err != nil {
+		t.Fatal(err)
+	}
+	bannedID = banned.ID()
+
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, err = ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved peerstoreFile
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != 1 {
+		t.Errorf("expected only the recently seen peer to be saved; received %v", saved)
+	}
+	if _, ok := saved[recent.ID().Pretty()]; !ok {
+		t.Errorf("expected the recently seen peer to be saved; received %v", saved)
+	}
+}
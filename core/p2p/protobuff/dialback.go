@@ -0,0 +1,180 @@
+package protobuff
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	inet "github.com/libp2p/go-libp2p-net"
+	peer "github.com/libp2p/go-libp2p-peer"
+	ma "github.com/multiformats/go-multiaddr"
+
+	pb "github.com/c3systems/c3-go/core/p2p/protobuff/pb"
+	"github.com/c3systems/c3-go/core/p2p/reputation"
+	protobufCodec "github.com/multiformats/go-multicodec/protobuf"
+)
+
+// pattern: /protocol-name/request-or-response-message/version
+const dialBackRequest = "/dialback/dialbackreq/0.0.1"
+const dialBackResponse = "/dialback/dialbackresp/0.0.1"
+
+// MaxDialBackAddrs is the max number of multiaddrs a dial back request asks to be dialed on
+const MaxDialBackAddrs = 8
+
+// DialBack dials peers back on the multiaddrs they ask for, so they learn whether they're reachable
+type DialBack struct {
+	node       *Node     // local host
+	requests   *requests // used to deliver responses to the requests awaiting them
+	dialBackFN func(peerID peer.ID, addrs []ma.Multiaddr) ([]ma.Multiaddr, error)
+}
+
+// NewDialBack ...
+func NewDialBack(node *Node, dialBackFN func(peerID peer.ID, addrs []ma.Multiaddr) ([]ma.Multiaddr, error)) *DialBack {
+	d := DialBack{
+		node:       node,
+		requests:   newRequests(),
+		dialBackFN: dialBackFN,
+	}
+	node.SetStreamHandler(dialBackRequest, d.onDialBackRequest)
+	node.SetStreamHandler(dialBackResponse, d.onDialBackResponse)
+
+	return &d
+}
+
+// remote peer requests handler
+func (d *DialBack) onDialBackRequest(s inet.Stream) {
+	// get request data
+	data := &pb.DialBackRequest{}
+	decoder := protobufCodec.Multicodec(nil).Decoder(bufio.NewReader(s))
+	if err := decoder.Decode(data); err != nil {
+		log.Errorf("[p2p] %s", err)
+		d.node.penalize(s.Conn().RemotePeer(), reputation.OffenseUndecodableMessage)
+		return
+	}
+
+	if valid := d.node.authenticateMessage(data, data.MessageData); !valid {
+		log.Error("[p2p] failed to authenticate message")
+		d.node.penalize(s.Conn().RemotePeer(), reputation.OffenseUndecodableMessage)
+		return
+	}
+
+	remotePeer := s.Conn().RemotePeer()
+	resp := &pb.DialBackResponse{
+		MessageData: d.node.NewMessageData(data.MessageData.Id, false),
+	}
+
+	// note: peers are only dialed on the ip they connected from, so the protocol can't be used to dial third parties
+	addrs := sameIPAddrs(data.Addrs, s.Conn().RemoteMultiaddr())
+	switch {
+	case d.dialBackFN == nil:
+		resp.Message = "dial back isn't served"
+
+	case len(addrs) == 0:
+		// note: the peer isn't dialable on the addrs it asked for, so the response is a failed dial back rather than a refusal
+
+	default:
+		dialed, err := d.dialBackFN(remotePeer, addrs)
+		if err != nil {
+			resp.Message = err.Error()
+		}
+		for _, addr := range dialed {
+			resp.Addrs = append(resp.Addrs, addr.String())
+		}
+	}
+
+	// sign the data
+	signature, err := d.node.signProtoMessage(resp)
+	if err != nil {
+		log.Errorf("[p2p] failed to sign response\n%v", err)
+		return
+	}
+
+	// add the signature to the message
+	resp.MessageData.Sign = string(signature)
+
+	s, respErr := d.node.NewStream(context.Background(), remotePeer, dialBackResponse)
+	if respErr != nil {
+		log.Errorf("[p2p] %s", respErr)
+		return
+	}
+
+	if ok := d.node.sendProtoMessage(resp, s); ok {
+		log.Printf("[p2p] %s: dialed %s back on %v of %v addrs.", s.Conn().LocalPeer().String(), remotePeer.String(), len(resp.Addrs), len(data.Addrs))
+	}
+}
+
+// remote peer response handler
+func (d *DialBack) onDialBackResponse(s inet.Stream) {
+	d.node.onResponse(s, d.requests, &pb.DialBackResponse{})
+}
+
+// RequestDialBack asks the peer to dial the local node back on the multiaddrs, returning the multiaddrs it was dialed on.
+// It fails when the peer refuses to dial back, e.g. when it doesn't serve dial backs.
+func (d *DialBack) RequestDialBack(ctx context.Context, peerID peer.ID, addrs []string) ([]string, error) {
+	if len(addrs) > MaxDialBackAddrs {
+		addrs = addrs[:MaxDialBackAddrs]
+	}
+
+	data, err := d.node.newRequestData()
+	if err != nil {
+		return nil, err
+	}
+
+	req := &pb.DialBackRequest{
+		MessageData: data,
+		Addrs:       addrs,
+	}
+
+	v, err := d.node.sendRequest(ctx, d.requests, peerID, dialBackRequest, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := v.(*pb.DialBackResponse)
+	if resp.Message != "" {
+		return nil, fmt.Errorf("peer refused dial back: %s", resp.Message)
+	}
+
+	return resp.Addrs, nil
+}
+
+// sameIPAddrs are the valid multiaddrs, up to MaxDialBackAddrs, on the ip of the remote multiaddr
+func sameIPAddrs(addrs []string, remote ma.Multiaddr) []ma.Multiaddr {
+	remoteIP, ok := multiaddrIP(remote)
+	if !ok {
+		return nil
+	}
+
+	var same []ma.Multiaddr
+	for _, addr := range addrs {
+		if len(same) == MaxDialBackAddrs {
+			break
+		}
+
+		maddr, err := ma.NewMultiaddr(addr)
+		if err != nil {
+			continue
+		}
+		if ip, ok := multiaddrIP(maddr); ok && ip == remoteIP {
+			same = append(same, maddr)
+		}
+	}
+
+	return same
+}
+
+func multiaddrIP(addr ma.Multiaddr) (string, bool) {
+	if addr == nil {
+		return "", false
+	}
+	if ip, err := addr.ValueForProtocol(ma.P_IP4); err == nil {
+		return ip, true
+	}
+	if ip, err := addr.ValueForProtocol(ma.P_IP6); err == nil {
+		return ip, true
+	}
+
+	return "", false
+}
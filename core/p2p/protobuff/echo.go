@@ -3,7 +3,6 @@ package protobuff
 import (
 	"bufio"
 	"context"
-	"errors"
 	"fmt"
 
 	pb "github.com/c3systems/c3-go/core/p2p/protobuff/pb"
@@ -11,7 +10,6 @@ import (
 	inet "github.com/libp2p/go-libp2p-net"
 	peer "github.com/libp2p/go-libp2p-peer"
 	protobufCodec "github.com/multiformats/go-multicodec/protobuf"
-	uuid "github.com/satori/go.uuid"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -19,20 +17,15 @@ import (
 const echoRequest = "/echo/echoreq/0.0.1"
 const echoResponse = "/echo/echoresp/0.0.1"
 
-type echoRequestWrapper struct {
-	resp chan interface{}
-	req  *pb.EchoRequest
-}
-
 // Echo ...
 type Echo struct {
-	node     *Node                          // local host
-	requests map[string]*echoRequestWrapper // used to access request data from response handlers
+	node     *Node     // local host
+	requests *requests // used to deliver responses to the requests awaiting them
 }
 
 // NewEcho ...
 func NewEcho(node *Node) *Echo {
-	e := Echo{node: node, requests: make(map[string]*echoRequestWrapper)}
+	e := Echo{node: node, requests: newRequests()}
 	node.SetStreamHandler(echoRequest, e.onEchoRequest)
 	node.SetStreamHandler(echoResponse, e.onEchoResponse)
 
@@ -82,7 +75,7 @@ func (e *Echo) onEchoRequest(s inet.Stream) {
 
 	s, respErr := e.node.NewStream(context.Background(), s.Conn().RemotePeer(), echoResponse)
 	if respErr != nil {
-		log.Error("[p2p] %s", respErr)
+		log.Errorf("[p2p] %s", respErr)
 		return
 	}
 
@@ -95,75 +88,25 @@ func (e *Echo) onEchoRequest(s inet.Stream) {
 
 // remote echo response handler
 func (e *Echo) onEchoResponse(s inet.Stream) {
-	data := &pb.EchoResponse{}
-	decoder := protobufCodec.Multicodec(nil).Decoder(bufio.NewReader(s))
-	if err := decoder.Decode(data); err != nil {
-		log.Errorf("[p2p] err decoding echo response\n%v", err)
-		return
-	}
-
-	// locate request data and remove it if found
-	reqW, ok := e.requests[data.MessageData.Id]
-	if ok {
-		// remove request from map as we have processed it here
-		delete(e.requests, data.MessageData.Id)
-	} else {
-		log.Error("[p2p] failed to locate request data boject for response")
-		return
-	}
-
-	// authenticate message content
-	valid := e.node.authenticateMessage(data, data.MessageData)
-
-	if !valid {
-		reqW.resp <- errors.New("Failed to authenticate message")
-		return
-	}
-
-	reqW.resp <- data
+	e.node.onResponse(s, e.requests, &pb.EchoResponse{})
 }
 
-// SendEcho ...
-func (e *Echo) SendEcho(peerID peer.ID, resp chan interface{}) error {
-	// log.Printf("[p2p] %s: Sending echo to: %s....", e.node.ID(), peerID)
-
-	// create message data
-	id, err := uuid.NewV4()
+// SendEcho sends an echo request to the peer, returning the echoed message
+func (e *Echo) SendEcho(ctx context.Context, peerID peer.ID) (string, error) {
+	data, err := e.node.newRequestData()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	req := &pb.EchoRequest{
-		MessageData: e.node.NewMessageData(id.String(), false),
+		MessageData: data,
 		Message:     fmt.Sprintf("Echo from %s", e.node.ID()),
 	}
 
-	signature, err := e.node.signProtoMessage(req)
+	resp, err := e.node.sendRequest(ctx, e.requests, peerID, echoRequest, req)
 	if err != nil {
-		log.Error("[p2p] failed to sign message")
-		return err
+		return "", err
 	}
 
-	// add the signature to the message
-	req.MessageData.Sign = string(signature)
-
-	s, err := e.node.NewStream(context.Background(), peerID, echoRequest)
-	if err != nil {
-		log.Errorf("[p2p] %s", err)
-		return err
-	}
-
-	ok := e.node.sendProtoMessage(req, s)
-
-	if !ok {
-		return errors.New("failed to send message")
-	}
-
-	// store request so response handler has access to it
-	e.requests[req.MessageData.Id] = &echoRequestWrapper{
-		resp: resp,
-		req:  req,
-	}
-	// log.Printf("[p2p] %s: Echo to: %s was sent. Message Id: %s, Message: %s", e.node.ID(), peerID, req.MessageData.Id, req.Message)
-	return nil
+	return resp.(*pb.EchoResponse).Message, nil
 }
@@ -0,0 +1,148 @@
+package protobuff
+
+import (
+	"bufio"
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	inet "github.com/libp2p/go-libp2p-net"
+	peer "github.com/libp2p/go-libp2p-peer"
+
+	pb "github.com/c3systems/c3-go/core/p2p/protobuff/pb"
+	"github.com/c3systems/c3-go/core/p2p/reputation"
+	protobufCodec "github.com/multiformats/go-multicodec/protobuf"
+)
+
+// pattern: /protocol-name/request-or-response-message/version
+const getBlocksRequest = "/getblocks/getblocksreq/0.0.1"
+const getBlocksResponse = "/getblocks/getblocksresp/0.0.1"
+
+const (
+	// MaxBlocksPerRequest is the max number of blocks a get blocks response holds
+	MaxBlocksPerRequest uint64 = 64
+
+	// maxResponseSize is the max number of payload bytes in a batched response.
+	// note: msgio rejects messages over 8mb
+	maxResponseSize = 4 * 1024 * 1024
+)
+
+// GetBlocks serves the mined blocks in a range of mainchain block heights
+type GetBlocks struct {
+	node        *Node     // local host
+	requests    *requests // used to deliver responses to the requests awaiting them
+	getBlocksFN func(fromHeight, count uint64) ([][]byte, error)
+}
+
+// NewGetBlocks ...
+func NewGetBlocks(node *Node, getBlocksFN func(fromHeight, count uint64) ([][]byte, error)) *GetBlocks {
+	g := GetBlocks{
+		node:        node,
+		requests:    newRequests(),
+		getBlocksFN: getBlocksFN,
+	}
+	node.SetStreamHandler(getBlocksRequest, g.onGetBlocksRequest)
+	node.SetStreamHandler(getBlocksResponse, g.onGetBlocksResponse)
+
+	return &g
+}
+
+// remote peer requests handler
+func (g *GetBlocks) onGetBlocksRequest(s inet.Stream) {
+	// get request data
+	data := &pb.GetBlocksRequest{}
+	decoder := protobufCodec.Multicodec(nil).Decoder(bufio.NewReader(s))
+	if err := decoder.Decode(data); err != nil {
+		log.Errorf("[p2p] %s", err)
+		g.node.penalize(s.Conn().RemotePeer(), reputation.OffenseUndecodableMessage)
+		return
+	}
+
+	if valid := g.node.authenticateMessage(data, data.MessageData); !valid {
+		log.Error("[p2p] failed to authenticate message")
+		g.node.penalize(s.Conn().RemotePeer(), reputation.OffenseUndecodableMessage)
+		return
+	}
+
+	count := data.Count
+	if count > MaxBlocksPerRequest {
+		count = MaxBlocksPerRequest
+	}
+
+	var blocks [][]byte
+	if g.getBlocksFN != nil && count > 0 {
+		var err error
+		blocks, err = g.getBlocksFN(data.FromHeight, count)
+		if err != nil {
+			log.Errorf("[p2p] err getting blocks from height %v\n%v", data.FromHeight, err)
+			return
+		}
+	}
+	blocks = truncateToSize(blocks)
+
+	resp := &pb.GetBlocksResponse{
+		MessageData: g.node.NewMessageData(data.MessageData.Id, false),
+		Blocks:      blocks,
+	}
+
+	// sign the data
+	signature, err := g.node.signProtoMessage(resp)
+	if err != nil {
+		log.Errorf("[p2p] failed to sign response\n%v", err)
+		return
+	}
+
+	// add the signature to the message
+	resp.MessageData.Sign = string(signature)
+
+	s, respErr := g.node.NewStream(context.Background(), s.Conn().RemotePeer(), getBlocksResponse)
+	if respErr != nil {
+		log.Errorf("[p2p] %s", respErr)
+		return
+	}
+
+	if ok := g.node.sendProtoMessage(resp, s); ok {
+		log.Printf("[p2p] %s: %v blocks from height %v sent to %s.", s.Conn().LocalPeer().String(), len(blocks), data.FromHeight, s.Conn().RemotePeer().String())
+	}
+}
+
+// remote peer response handler
+func (g *GetBlocks) onGetBlocksResponse(s inet.Stream) {
+	g.node.onResponse(s, g.requests, &pb.GetBlocksResponse{})
+}
+
+// FetchBlocks fetches count serialized mined blocks, starting at the mainchain block height, from the peer.
+// note: the peer may respond with fewer blocks than requested, e.g. when it reaches its head block.
+func (g *GetBlocks) FetchBlocks(ctx context.Context, peerID peer.ID, fromHeight, count uint64) ([][]byte, error) {
+	data, err := g.node.newRequestData()
+	if err != nil {
+		return nil, err
+	}
+
+	req := &pb.GetBlocksRequest{
+		MessageData: data,
+		FromHeight:  fromHeight,
+		Count:       count,
+	}
+
+	resp, err := g.node.sendRequest(ctx, g.requests, peerID, getBlocksRequest, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.(*pb.GetBlocksResponse).Blocks, nil
+}
+
+// truncateToSize drops the trailing items that don't fit in a response
+func truncateToSize(items [][]byte) [][]byte {
+	var size int
+	for i, item := range items {
+		size += len(item)
+		if size > maxResponseSize {
+			log.Warnf("[p2p] response truncated to %v of %v items", i, len(items))
+			return items[:i]
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,146 @@
+package protobuff
+
+import (
+	"bufio"
+	"context"
+	"errors"
+
+	log "github.com/sirupsen/logrus"
+
+	inet "github.com/libp2p/go-libp2p-net"
+	peer "github.com/libp2p/go-libp2p-peer"
+
+	pb "github.com/c3systems/c3-go/core/p2p/protobuff/pb"
+	"github.com/c3systems/c3-go/core/p2p/reputation"
+	protobufCodec "github.com/multiformats/go-multicodec/protobuf"
+)
+
+// pattern: /protocol-name/request-or-response-message/version
+const getObjectsRequest = "/getobjects/getobjectsreq/0.0.1"
+const getObjectsResponse = "/getobjects/getobjectsresp/0.0.1"
+
+// MaxObjectsPerRequest is the max number of cids a get objects request asks for
+const MaxObjectsPerRequest = 256
+
+// GetObjects serves the stored objects, e.g. blocks, transactions, diffs and merkle trees, by cid
+type GetObjects struct {
+	node         *Node     // local host
+	requests     *requests // used to deliver responses to the requests awaiting them
+	getObjectsFN func(cids []string) ([]string, [][]byte, error)
+}
+
+// NewGetObjects ...
+func NewGetObjects(node *Node, getObjectsFN func(cids []string) ([]string, [][]byte, error)) *GetObjects {
+	g := GetObjects{
+		node:         node,
+		requests:     newRequests(),
+		getObjectsFN: getObjectsFN,
+	}
+	node.SetStreamHandler(getObjectsRequest, g.onGetObjectsRequest)
+	node.SetStreamHandler(getObjectsResponse, g.onGetObjectsResponse)
+
+	return &g
+}
+
+// remote peer requests handler
+func (g *GetObjects) onGetObjectsRequest(s inet.Stream) {
+	// get request data
+	data := &pb.GetObjectsRequest{}
+	decoder := protobufCodec.Multicodec(nil).Decoder(bufio.NewReader(s))
+	if err := decoder.Decode(data); err != nil {
+		log.Errorf("[p2p] %s", err)
+		g.node.penalize(s.Conn().RemotePeer(), reputation.OffenseUndecodableMessage)
+		return
+	}
+
+	if valid := g.node.authenticateMessage(data, data.MessageData); !valid {
+		log.Error("[p2p] failed to authenticate message")
+		g.node.penalize(s.Conn().RemotePeer(), reputation.OffenseUndecodableMessage)
+		return
+	}
+
+	cids := data.Cids
+	if len(cids) > MaxObjectsPerRequest {
+		cids = cids[:MaxObjectsPerRequest]
+	}
+
+	var (
+		found   []string
+		objects [][]byte
+	)
+	if g.getObjectsFN != nil && len(cids) > 0 {
+		var err error
+		found, objects, err = g.getObjectsFN(cids)
+		if err != nil {
+			log.Errorf("[p2p] err getting objects\n%v", err)
+			return
+		}
+		if len(found) != len(objects) {
+			log.Errorf("[p2p] got %v objects for %v cids", len(objects), len(found))
+			return
+		}
+	}
+	objects = truncateToSize(objects)
+	found = found[:len(objects)]
+
+	resp := &pb.GetObjectsResponse{
+		MessageData: g.node.NewMessageData(data.MessageData.Id, false),
+		Cids:        found,
+		Objects:     objects,
+	}
+
+	// sign the data
+	signature, err := g.node.signProtoMessage(resp)
+	if err != nil {
+		log.Errorf("[p2p] failed to sign response\n%v", err)
+		return
+	}
+
+	// add the signature to the message
+	resp.MessageData.Sign = string(signature)
+
+	s, respErr := g.node.NewStream(context.Background(), s.Conn().RemotePeer(), getObjectsResponse)
+	if respErr != nil {
+		log.Errorf("[p2p] %s", respErr)
+		return
+	}
+
+	if ok := g.node.sendProtoMessage(resp, s); ok {
+		log.Printf("[p2p] %s: %v of %v objects sent to %s.", s.Conn().LocalPeer().String(), len(objects), len(data.Cids), s.Conn().RemotePeer().String())
+	}
+}
+
+// remote peer response handler
+func (g *GetObjects) onGetObjectsResponse(s inet.Stream) {
+	g.node.onResponse(s, g.requests, &pb.GetObjectsResponse{})
+}
+
+// FetchObjects fetches the objects of the cids from the peer, returning the found cids and their objects.
+// note: the peer omits the objects it doesn't have.
+func (g *GetObjects) FetchObjects(ctx context.Context, peerID peer.ID, cids []string) ([]string, [][]byte, error) {
+	if len(cids) > MaxObjectsPerRequest {
+		return nil, nil, errors.New("too many cids in request")
+	}
+
+	data, err := g.node.newRequestData()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req := &pb.GetObjectsRequest{
+		MessageData: data,
+		Cids:        cids,
+	}
+
+	v, err := g.node.sendRequest(ctx, g.requests, peerID, getObjectsRequest, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := v.(*pb.GetObjectsResponse)
+	if len(resp.Cids) != len(resp.Objects) {
+		return nil, nil, errors.New("cids and objects length mismatch")
+	}
+
+	return resp.Cids, resp.Objects, nil
+}
@@ -0,0 +1,132 @@
+package protobuff
+
+import (
+	"bufio"
+	"context"
+	"errors"
+
+	log "github.com/sirupsen/logrus"
+
+	inet "github.com/libp2p/go-libp2p-net"
+	peer "github.com/libp2p/go-libp2p-peer"
+
+	pb "github.com/c3systems/c3-go/core/p2p/protobuff/pb"
+	"github.com/c3systems/c3-go/core/p2p/reputation"
+	protobufCodec "github.com/multiformats/go-multicodec/protobuf"
+)
+
+// pattern: /protocol-name/request-or-response-message/version
+const getTransactionsRequest = "/gettransactions/gettransactionsreq/0.0.1"
+const getTransactionsResponse = "/gettransactions/gettransactionsresp/0.0.1"
+
+// MaxTransactionsPerRequest is the max number of hashes a get transactions request asks for
+const MaxTransactionsPerRequest = 256
+
+// GetTransactions serves the transactions announced by hash, so peers only fetch the ones they haven't seen
+type GetTransactions struct {
+	node              *Node     // local host
+	requests          *requests // used to deliver responses to the requests awaiting them
+	getTransactionsFN func(hashes []string) ([][]byte, error)
+}
+
+// NewGetTransactions ...
+func NewGetTransactions(node *Node, getTransactionsFN func(hashes []string) ([][]byte, error)) *GetTransactions {
+	g := GetTransactions{
+		node:              node,
+		requests:          newRequests(),
+		getTransactionsFN: getTransactionsFN,
+	}
+	node.SetStreamHandler(getTransactionsRequest, g.onGetTransactionsRequest)
+	node.SetStreamHandler(getTransactionsResponse, g.onGetTransactionsResponse)
+
+	return &g
+}
+
+// remote peer requests handler
+func (g *GetTransactions) onGetTransactionsRequest(s inet.Stream) {
+	// get request data
+	data := &pb.GetTransactionsRequest{}
+	decoder := protobufCodec.Multicodec(nil).Decoder(bufio.NewReader(s))
+	if err := decoder.Decode(data); err != nil {
+		log.Errorf("[p2p] %s", err)
+		g.node.penalize(s.Conn().RemotePeer(), reputation.OffenseUndecodableMessage)
+		return
+	}
+
+	if valid := g.node.authenticateMessage(data, data.MessageData); !valid {
+		log.Error("[p2p] failed to authenticate message")
+		g.node.penalize(s.Conn().RemotePeer(), reputation.OffenseUndecodableMessage)
+		return
+	}
+
+	hashes := data.Hashes
+	if len(hashes) > MaxTransactionsPerRequest {
+		hashes = hashes[:MaxTransactionsPerRequest]
+	}
+
+	var txs [][]byte
+	if g.getTransactionsFN != nil && len(hashes) > 0 {
+		var err error
+		txs, err = g.getTransactionsFN(hashes)
+		if err != nil {
+			log.Errorf("[p2p] err getting transactions\n%v", err)
+			return
+		}
+	}
+	txs = truncateToSize(txs)
+
+	resp := &pb.GetTransactionsResponse{
+		MessageData:  g.node.NewMessageData(data.MessageData.Id, false),
+		Transactions: txs,
+	}
+
+	// sign the data
+	signature, err := g.node.signProtoMessage(resp)
+	if err != nil {
+		log.Errorf("[p2p] failed to sign response\n%v", err)
+		return
+	}
+
+	// add the signature to the message
+	resp.MessageData.Sign = string(signature)
+
+	s, respErr := g.node.NewStream(context.Background(), s.Conn().RemotePeer(), getTransactionsResponse)
+	if respErr != nil {
+		log.Errorf("[p2p] %s", respErr)
+		return
+	}
+
+	if ok := g.node.sendProtoMessage(resp, s); ok {
+		log.Printf("[p2p] %s: %v of %v transactions sent to %s.", s.Conn().LocalPeer().String(), len(txs), len(hashes), s.Conn().RemotePeer().String())
+	}
+}
+
+// remote peer response handler
+func (g *GetTransactions) onGetTransactionsResponse(s inet.Stream) {
+	g.node.onResponse(s, g.requests, &pb.GetTransactionsResponse{})
+}
+
+// FetchTransactions fetches the serialized transactions of the hashes from the peer.
+// note: the peer omits the transactions it doesn't have, and the transactions aren't checked against the hashes.
+func (g *GetTransactions) FetchTransactions(ctx context.Context, peerID peer.ID, hashes []string) ([][]byte, error) {
+	if len(hashes) > MaxTransactionsPerRequest {
+		return nil, errors.New("too many hashes in request")
+	}
+
+	data, err := g.node.newRequestData()
+	if err != nil {
+		return nil, err
+	}
+
+	req := &pb.GetTransactionsRequest{
+		MessageData: data,
+		Hashes:      hashes,
+	}
+
+	resp, err := g.node.sendRequest(ctx, g.requests, peerID, getTransactionsRequest, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.(*pb.GetTransactionsResponse).Transactions, nil
+}
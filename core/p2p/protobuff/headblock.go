@@ -3,7 +3,6 @@ package protobuff
 import (
 	"bufio"
 	"context"
-	"errors"
 
 	log "github.com/sirupsen/logrus"
 
@@ -12,23 +11,18 @@ import (
 
 	"github.com/c3systems/c3-go/core/chain/mainchain"
 	pb "github.com/c3systems/c3-go/core/p2p/protobuff/pb"
+	"github.com/c3systems/c3-go/core/p2p/reputation"
 	protobufCodec "github.com/multiformats/go-multicodec/protobuf"
-	uuid "github.com/satori/go.uuid"
 )
 
 // pattern: /protocol-name/request-or-response-message/version
 const headBlockRequest = "/headblock/headblockreq/0.0.1"
 const headBlockResponse = "/headblock/headblockresp/0.0.1"
 
-type headBlockRequestWrapper struct {
-	resp chan interface{}
-	req  *pb.HeadBlockRequest
-}
-
 // HeadBlock ...
 type HeadBlock struct {
-	node           *Node                               // local host
-	requests       map[string]*headBlockRequestWrapper // used to access request data from response handlers
+	node           *Node     // local host
+	requests       *requests // used to deliver responses to the requests awaiting them
 	getHeadBlockFN func() (mainchain.Block, error)
 }
 
@@ -36,7 +30,7 @@ type HeadBlock struct {
 func NewHeadBlock(node *Node, getHeadBlockFN func() (mainchain.Block, error)) *HeadBlock {
 	h := HeadBlock{
 		node:           node,
-		requests:       make(map[string]*headBlockRequestWrapper),
+		requests:       newRequests(),
 		getHeadBlockFN: getHeadBlockFN,
 	}
 	node.SetStreamHandler(headBlockRequest, h.onHeadBlockRequest)
@@ -56,6 +50,7 @@ func (h *HeadBlock) onHeadBlockRequest(s inet.Stream) {
 	err := decoder.Decode(data)
 	if err != nil {
 		log.Errorf("[p2p] %s", err)
+		h.node.penalize(s.Conn().RemotePeer(), reputation.OffenseUndecodableMessage)
 		return
 	}
 
@@ -64,6 +59,7 @@ func (h *HeadBlock) onHeadBlockRequest(s inet.Stream) {
 
 	if !valid {
 		log.Error("[p2p] failed to authenticate message")
+		h.node.penalize(s.Conn().RemotePeer(), reputation.OffenseUndecodableMessage)
 		return
 	}
 
@@ -112,74 +108,29 @@ func (h *HeadBlock) onHeadBlockRequest(s inet.Stream) {
 
 // remote peer response handler
 func (h *HeadBlock) onHeadBlockResponse(s inet.Stream) {
-	data := &pb.HeadBlockResponse{}
-	decoder := protobufCodec.Multicodec(nil).Decoder(bufio.NewReader(s))
-	if err := decoder.Decode(data); err != nil {
-		log.Errorf("[p2p] err decoding headblock response\n%v", err)
-		return
-	}
-
-	// locate request data and remove it if found
-	reqW, ok := h.requests[data.MessageData.Id]
-	if ok {
-		// remove request from map as we have processed it here
-		delete(h.requests, data.MessageData.Id)
-	} else {
-		log.Error("[p2p] ailed to locate request data boject for response")
-		return
-	}
-
-	// authenticate message content
-	valid := h.node.authenticateMessage(data, data.MessageData)
-
-	if !valid {
-		reqW.resp <- errors.New("Failed to authenticate message")
-		return
-	}
-
-	reqW.resp <- data
+	h.node.onResponse(s, h.requests, &pb.HeadBlockResponse{})
 }
 
-// FetchHeadBlock ...
-func (h *HeadBlock) FetchHeadBlock(peerID peer.ID, resp chan interface{}) error {
-	// log.Printf("[p2p] %s: Sending headblock to: %s....", e.node.ID(), peerID)
-
-	// create message data
-	id, err := uuid.NewV4()
+// FetchHeadBlock fetches the peer's head block
+func (h *HeadBlock) FetchHeadBlock(ctx context.Context, peerID peer.ID) (*mainchain.Block, error) {
+	data, err := h.node.newRequestData()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req := &pb.HeadBlockRequest{
-		MessageData: h.node.NewMessageData(id.String(), false),
-	}
-
-	signature, err := h.node.signProtoMessage(req)
-	if err != nil {
-		log.Error("[p2p] failed to sign message")
-		return err
+		MessageData: data,
 	}
 
-	// add the signature to the message
-	req.MessageData.Sign = string(signature)
-
-	s, err := h.node.NewStream(context.Background(), peerID, headBlockRequest)
+	resp, err := h.node.sendRequest(ctx, h.requests, peerID, headBlockRequest, req)
 	if err != nil {
-		log.Errorf("[p2p] %s", err)
-		return err
+		return nil, err
 	}
 
-	ok := h.node.sendProtoMessage(req, s)
-
-	if !ok {
-		return errors.New("failed to send message")
+	block := new(mainchain.Block)
+	if err := block.Deserialize(resp.(*pb.HeadBlockResponse).HeadBlockBytes); err != nil {
+		return nil, err
 	}
 
-	// store request so response handler has access to it
-	h.requests[req.MessageData.Id] = &headBlockRequestWrapper{
-		resp: resp,
-		req:  req,
-	}
-	// log.Printf("[p2p] %s: Headblock to: %s was sent. Message Id: %s, Message: %s", e.node.ID(), peerID, req.MessageData.Id, req.Message)
-	return nil
+	return block, nil
 }
@@ -1,6 +1,9 @@
 package protobuff
 
 import (
+	"context"
+
+	"github.com/c3systems/c3-go/core/chain/mainchain"
 	pb "github.com/c3systems/c3-go/core/p2p/protobuff/pb"
 	peer "github.com/libp2p/go-libp2p-peer"
 )
@@ -8,7 +11,12 @@ import (
 // Interface ...
 type Interface interface {
 	NewMessageData(messageID string, gossip bool) *pb.MessageData
-	SendEcho(peerID peer.ID, resp chan interface{}) error
-	FetchHeadBlock(peerID peer.ID, resp chan interface{}) error
-	SendTransaction(peerID peer.ID, txBytes []byte, resp chan interface{}) error
+	SendEcho(ctx context.Context, peerID peer.ID) (string, error)
+	FetchHeadBlock(ctx context.Context, peerID peer.ID) (*mainchain.Block, error)
+	SendTransaction(ctx context.Context, peerID peer.ID, txBytes []byte) (string, error)
+	FetchBlocks(ctx context.Context, peerID peer.ID, fromHeight, count uint64) ([][]byte, error)
+	FetchObjects(ctx context.Context, peerID peer.ID, cids []string) ([]string, [][]byte, error)
+	ExchangeStatus(ctx context.Context, peerID peer.ID) (*PeerStatus, error)
+	RequestDialBack(ctx context.Context, peerID peer.ID, addrs []string) ([]string, error)
+	FetchTransactions(ctx context.Context, peerID peer.ID, hashes []string) ([][]byte, error)
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/c3systems/c3-go/core/chain/mainchain"
 	"github.com/c3systems/c3-go/core/chain/statechain"
 	pb "github.com/c3systems/c3-go/core/p2p/protobuff/pb"
+	"github.com/c3systems/c3-go/core/p2p/reputation"
 	nodetypes "github.com/c3systems/c3-go/node/types"
 
 	"github.com/gogo/protobuf/proto"
@@ -15,6 +16,7 @@ import (
 	host "github.com/libp2p/go-libp2p-host"
 	inet "github.com/libp2p/go-libp2p-net"
 	peer "github.com/libp2p/go-libp2p-peer"
+	ma "github.com/multiformats/go-multiaddr"
 	protobufCodec "github.com/multiformats/go-multicodec/protobuf"
 	log "github.com/sirupsen/logrus"
 )
@@ -28,6 +30,12 @@ type Props struct {
 	GetHeadBlockFN         func() (mainchain.Block, error)
 	BroadcastTransactionFN func(tx *statechain.Transaction) (*nodetypes.SendTxResponse, error)
 	AddPendingTxFN         func(tx *statechain.Transaction) error
+	GetBlocksFN            func(fromHeight, count uint64) ([][]byte, error)                   // note: optional; serves serialized mined blocks
+	GetObjectsFN           func(cids []string) ([]string, [][]byte, error)                    // note: optional; serves the found cids and their objects
+	PenalizeFN             func(peerID peer.ID, offense reputation.Offense)                   // note: optional; called when a peer sends a bad request
+	GetStatusFN            func() (*PeerStatus, error)                                        // note: the local status exchanged on connect
+	DialBackFN             func(peerID peer.ID, addrs []ma.Multiaddr) ([]ma.Multiaddr, error) // note: optional; dials peers back for their reachability checks
+	GetTransactionsFN      func(hashes []string) ([][]byte, error)                            // note: optional; serves the serialized transactions announced by hash
 }
 
 // Node type - a p2p host implementing one or more p2p protocols
@@ -36,7 +44,14 @@ type Node struct {
 	*Echo               // echo protocol impl
 	*HeadBlock          // headblock protocol impl
 	*ProcessTransaction // process transaction impl
+	*GetBlocks          // get blocks protocol impl
+	*GetObjects         // get objects protocol impl
+	*Status             // status protocol impl
+	*DialBack           // dial back protocol impl
+	*GetTransactions    // get transactions protocol impl
 	// add other protocols here...
+
+	penalizeFN func(peerID peer.ID, offense reputation.Offense)
 }
 
 // NewNode creates a new node with its implemented protocols
@@ -45,13 +60,25 @@ func NewNode(props *Props) (*Node, error) {
 		return nil, errors.New("nil props")
 	}
 
-	node := &Node{Host: props.Host}
+	node := &Node{Host: props.Host, penalizeFN: props.PenalizeFN}
 	node.Echo = NewEcho(node)
 	node.HeadBlock = NewHeadBlock(node, props.GetHeadBlockFN)
 	node.ProcessTransaction = NewProcessTransaction(node, props.BroadcastTransactionFN, props.AddPendingTxFN)
+	node.GetBlocks = NewGetBlocks(node, props.GetBlocksFN)
+	node.GetObjects = NewGetObjects(node, props.GetObjectsFN)
+	node.Status = NewStatus(node, props.GetStatusFN)
+	node.DialBack = NewDialBack(node, props.DialBackFN)
+	node.GetTransactions = NewGetTransactions(node, props.GetTransactionsFN)
 	return node, nil
 }
 
+// penalize reports the peer's offense, if a penalize fn was provided
+func (n *Node) penalize(peerID peer.ID, offense reputation.Offense) {
+	if n.penalizeFN != nil {
+		n.penalizeFN(peerID, offense)
+	}
+}
+
 // Authenticate incoming p2p message
 // message: a protobufs go data object
 // data: common p2p message data
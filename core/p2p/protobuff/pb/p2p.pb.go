@@ -15,6 +15,16 @@
 		HeadBlockResponse
 		ProcessTransactionRequest
 		ProcessTransactionResponse
+		GetBlocksRequest
+		GetBlocksResponse
+		GetObjectsRequest
+		GetObjectsResponse
+		StatusRequest
+		StatusResponse
+		DialBackRequest
+		DialBackResponse
+		GetTransactionsRequest
+		GetTransactionsResponse
 */
 package protocols_p2p
 
@@ -261,6 +271,368 @@ func (m *ProcessTransactionResponse) GetHash() string {
 	return ""
 }
 
+// a protocol define a set of reuqest and responses
+type GetBlocksRequest struct {
+	MessageData *MessageData `protobuf:"bytes,1,opt,name=messageData" json:"messageData,omitempty"`
+	FromHeight  uint64       `protobuf:"varint,2,opt,name=fromHeight,proto3" json:"fromHeight,omitempty"`
+	Count       uint64       `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (m *GetBlocksRequest) Reset()                    { *m = GetBlocksRequest{} }
+func (m *GetBlocksRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetBlocksRequest) ProtoMessage()               {}
+func (*GetBlocksRequest) Descriptor() ([]byte, []int) { return fileDescriptorP2P, []int{7} }
+
+func (m *GetBlocksRequest) GetMessageData() *MessageData {
+	if m != nil {
+		return m.MessageData
+	}
+	return nil
+}
+
+func (m *GetBlocksRequest) GetFromHeight() uint64 {
+	if m != nil {
+		return m.FromHeight
+	}
+	return 0
+}
+
+func (m *GetBlocksRequest) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type GetBlocksResponse struct {
+	MessageData *MessageData `protobuf:"bytes,1,opt,name=messageData" json:"messageData,omitempty"`
+	// response specific data
+	// each block is a serialized mined block: a mainchain block with its statechain blocks, transactions, diffs and merkle trees
+	Blocks [][]byte `protobuf:"bytes,2,rep,name=blocks" json:"blocks,omitempty"`
+}
+
+func (m *GetBlocksResponse) Reset()                    { *m = GetBlocksResponse{} }
+func (m *GetBlocksResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetBlocksResponse) ProtoMessage()               {}
+func (*GetBlocksResponse) Descriptor() ([]byte, []int) { return fileDescriptorP2P, []int{8} }
+
+func (m *GetBlocksResponse) GetMessageData() *MessageData {
+	if m != nil {
+		return m.MessageData
+	}
+	return nil
+}
+
+func (m *GetBlocksResponse) GetBlocks() [][]byte {
+	if m != nil {
+		return m.Blocks
+	}
+	return nil
+}
+
+// a protocol define a set of reuqest and responses
+type GetObjectsRequest struct {
+	MessageData *MessageData `protobuf:"bytes,1,opt,name=messageData" json:"messageData,omitempty"`
+	Cids        []string     `protobuf:"bytes,2,rep,name=cids" json:"cids,omitempty"`
+}
+
+func (m *GetObjectsRequest) Reset()                    { *m = GetObjectsRequest{} }
+func (m *GetObjectsRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetObjectsRequest) ProtoMessage()               {}
+func (*GetObjectsRequest) Descriptor() ([]byte, []int) { return fileDescriptorP2P, []int{9} }
+
+func (m *GetObjectsRequest) GetMessageData() *MessageData {
+	if m != nil {
+		return m.MessageData
+	}
+	return nil
+}
+
+func (m *GetObjectsRequest) GetCids() []string {
+	if m != nil {
+		return m.Cids
+	}
+	return nil
+}
+
+type GetObjectsResponse struct {
+	MessageData *MessageData `protobuf:"bytes,1,opt,name=messageData" json:"messageData,omitempty"`
+	// response specific data
+	// the objects are the data of the cids with the same index; missing objects are omitted along with their cids
+	Cids    []string `protobuf:"bytes,2,rep,name=cids" json:"cids,omitempty"`
+	Objects [][]byte `protobuf:"bytes,3,rep,name=objects" json:"objects,omitempty"`
+}
+
+func (m *GetObjectsResponse) Reset()                    { *m = GetObjectsResponse{} }
+func (m *GetObjectsResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetObjectsResponse) ProtoMessage()               {}
+func (*GetObjectsResponse) Descriptor() ([]byte, []int) { return fileDescriptorP2P, []int{10} }
+
+func (m *GetObjectsResponse) GetMessageData() *MessageData {
+	if m != nil {
+		return m.MessageData
+	}
+	return nil
+}
+
+func (m *GetObjectsResponse) GetCids() []string {
+	if m != nil {
+		return m.Cids
+	}
+	return nil
+}
+
+func (m *GetObjectsResponse) GetObjects() [][]byte {
+	if m != nil {
+		return m.Objects
+	}
+	return nil
+}
+
+// a protocol define a set of reuqest and responses
+type StatusRequest struct {
+	MessageData *MessageData `protobuf:"bytes,1,opt,name=messageData" json:"messageData,omitempty"`
+	// the sender's status
+	ProtocolVersion uint64   `protobuf:"varint,2,opt,name=protocolVersion,proto3" json:"protocolVersion,omitempty"`
+	ChainID         string   `protobuf:"bytes,3,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	GenesisHash     string   `protobuf:"bytes,4,opt,name=genesisHash,proto3" json:"genesisHash,omitempty"`
+	HeadHeight      uint64   `protobuf:"varint,5,opt,name=headHeight,proto3" json:"headHeight,omitempty"`
+	Capabilities    []string `protobuf:"bytes,6,rep,name=capabilities" json:"capabilities,omitempty"`
+	CodecVersion    uint64   `protobuf:"varint,7,opt,name=codecVersion,proto3" json:"codecVersion,omitempty"`
+}
+
+func (m *StatusRequest) Reset()                    { *m = StatusRequest{} }
+func (m *StatusRequest) String() string            { return proto.CompactTextString(m) }
+func (*StatusRequest) ProtoMessage()               {}
+func (*StatusRequest) Descriptor() ([]byte, []int) { return fileDescriptorP2P, []int{11} }
+
+func (m *StatusRequest) GetMessageData() *MessageData {
+	if m != nil {
+		return m.MessageData
+	}
+	return nil
+}
+
+func (m *StatusRequest) GetProtocolVersion() uint64 {
+	if m != nil {
+		return m.ProtocolVersion
+	}
+	return 0
+}
+
+func (m *StatusRequest) GetChainID() string {
+	if m != nil {
+		return m.ChainID
+	}
+	return ""
+}
+
+func (m *StatusRequest) GetGenesisHash() string {
+	if m != nil {
+		return m.GenesisHash
+	}
+	return ""
+}
+
+func (m *StatusRequest) GetHeadHeight() uint64 {
+	if m != nil {
+		return m.HeadHeight
+	}
+	return 0
+}
+
+func (m *StatusRequest) GetCapabilities() []string {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
+func (m *StatusRequest) GetCodecVersion() uint64 {
+	if m != nil {
+		return m.CodecVersion
+	}
+	return 0
+}
+
+type StatusResponse struct {
+	MessageData *MessageData `protobuf:"bytes,1,opt,name=messageData" json:"messageData,omitempty"`
+	// response specific data
+	// the responder's status
+	ProtocolVersion uint64   `protobuf:"varint,2,opt,name=protocolVersion,proto3" json:"protocolVersion,omitempty"`
+	ChainID         string   `protobuf:"bytes,3,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	GenesisHash     string   `protobuf:"bytes,4,opt,name=genesisHash,proto3" json:"genesisHash,omitempty"`
+	HeadHeight      uint64   `protobuf:"varint,5,opt,name=headHeight,proto3" json:"headHeight,omitempty"`
+	Capabilities    []string `protobuf:"bytes,6,rep,name=capabilities" json:"capabilities,omitempty"`
+	CodecVersion    uint64   `protobuf:"varint,7,opt,name=codecVersion,proto3" json:"codecVersion,omitempty"`
+}
+
+func (m *StatusResponse) Reset()                    { *m = StatusResponse{} }
+func (m *StatusResponse) String() string            { return proto.CompactTextString(m) }
+func (*StatusResponse) ProtoMessage()               {}
+func (*StatusResponse) Descriptor() ([]byte, []int) { return fileDescriptorP2P, []int{12} }
+
+func (m *StatusResponse) GetMessageData() *MessageData {
+	if m != nil {
+		return m.MessageData
+	}
+	return nil
+}
+
+func (m *StatusResponse) GetProtocolVersion() uint64 {
+	if m != nil {
+		return m.ProtocolVersion
+	}
+	return 0
+}
+
+func (m *StatusResponse) GetChainID() string {
+	if m != nil {
+		return m.ChainID
+	}
+	return ""
+}
+
+func (m *StatusResponse) GetGenesisHash() string {
+	if m != nil {
+		return m.GenesisHash
+	}
+	return ""
+}
+
+func (m *StatusResponse) GetHeadHeight() uint64 {
+	if m != nil {
+		return m.HeadHeight
+	}
+	return 0
+}
+
+func (m *StatusResponse) GetCapabilities() []string {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
+func (m *StatusResponse) GetCodecVersion() uint64 {
+	if m != nil {
+		return m.CodecVersion
+	}
+	return 0
+}
+
+// a protocol define a set of reuqest and responses
+type DialBackRequest struct {
+	MessageData *MessageData `protobuf:"bytes,1,opt,name=messageData" json:"messageData,omitempty"`
+	// the multiaddrs the sender asks to be dialed back on
+	Addrs []string `protobuf:"bytes,2,rep,name=addrs" json:"addrs,omitempty"`
+}
+
+func (m *DialBackRequest) Reset()                    { *m = DialBackRequest{} }
+func (m *DialBackRequest) String() string            { return proto.CompactTextString(m) }
+func (*DialBackRequest) ProtoMessage()               {}
+func (*DialBackRequest) Descriptor() ([]byte, []int) { return fileDescriptorP2P, []int{13} }
+
+func (m *DialBackRequest) GetMessageData() *MessageData {
+	if m != nil {
+		return m.MessageData
+	}
+	return nil
+}
+
+func (m *DialBackRequest) GetAddrs() []string {
+	if m != nil {
+		return m.Addrs
+	}
+	return nil
+}
+
+type DialBackResponse struct {
+	MessageData *MessageData `protobuf:"bytes,1,opt,name=messageData" json:"messageData,omitempty"`
+	// response specific data
+	// the multiaddrs the sender was dialed back on; empty when every dial failed
+	Addrs   []string `protobuf:"bytes,2,rep,name=addrs" json:"addrs,omitempty"`
+	Message string   `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (m *DialBackResponse) Reset()                    { *m = DialBackResponse{} }
+func (m *DialBackResponse) String() string            { return proto.CompactTextString(m) }
+func (*DialBackResponse) ProtoMessage()               {}
+func (*DialBackResponse) Descriptor() ([]byte, []int) { return fileDescriptorP2P, []int{14} }
+
+func (m *DialBackResponse) GetMessageData() *MessageData {
+	if m != nil {
+		return m.MessageData
+	}
+	return nil
+}
+
+func (m *DialBackResponse) GetAddrs() []string {
+	if m != nil {
+		return m.Addrs
+	}
+	return nil
+}
+
+func (m *DialBackResponse) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+// a protocol define a set of reuqest and responses
+type GetTransactionsRequest struct {
+	MessageData *MessageData `protobuf:"bytes,1,opt,name=messageData" json:"messageData,omitempty"`
+	// the hashes of the requested transactions
+	Hashes []string `protobuf:"bytes,2,rep,name=hashes" json:"hashes,omitempty"`
+}
+
+func (m *GetTransactionsRequest) Reset()                    { *m = GetTransactionsRequest{} }
+func (m *GetTransactionsRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetTransactionsRequest) ProtoMessage()               {}
+func (*GetTransactionsRequest) Descriptor() ([]byte, []int) { return fileDescriptorP2P, []int{15} }
+
+func (m *GetTransactionsRequest) GetMessageData() *MessageData {
+	if m != nil {
+		return m.MessageData
+	}
+	return nil
+}
+
+func (m *GetTransactionsRequest) GetHashes() []string {
+	if m != nil {
+		return m.Hashes
+	}
+	return nil
+}
+
+type GetTransactionsResponse struct {
+	MessageData *MessageData `protobuf:"bytes,1,opt,name=messageData" json:"messageData,omitempty"`
+	// response specific data
+	// the serialized transactions the responder has, in no particular order
+	Transactions [][]byte `protobuf:"bytes,2,rep,name=transactions" json:"transactions,omitempty"`
+}
+
+func (m *GetTransactionsResponse) Reset()                    { *m = GetTransactionsResponse{} }
+func (m *GetTransactionsResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetTransactionsResponse) ProtoMessage()               {}
+func (*GetTransactionsResponse) Descriptor() ([]byte, []int) { return fileDescriptorP2P, []int{16} }
+
+func (m *GetTransactionsResponse) GetMessageData() *MessageData {
+	if m != nil {
+		return m.MessageData
+	}
+	return nil
+}
+
+func (m *GetTransactionsResponse) GetTransactions() [][]byte {
+	if m != nil {
+		return m.Transactions
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*MessageData)(nil), "protocols.p2p.MessageData")
 	proto.RegisterType((*EchoRequest)(nil), "protocols.p2p.EchoRequest")
@@ -269,6 +641,16 @@ func init() {
 	proto.RegisterType((*HeadBlockResponse)(nil), "protocols.p2p.HeadBlockResponse")
 	proto.RegisterType((*ProcessTransactionRequest)(nil), "protocols.p2p.ProcessTransactionRequest")
 	proto.RegisterType((*ProcessTransactionResponse)(nil), "protocols.p2p.ProcessTransactionResponse")
+	proto.RegisterType((*GetBlocksRequest)(nil), "protocols.p2p.GetBlocksRequest")
+	proto.RegisterType((*GetBlocksResponse)(nil), "protocols.p2p.GetBlocksResponse")
+	proto.RegisterType((*GetObjectsRequest)(nil), "protocols.p2p.GetObjectsRequest")
+	proto.RegisterType((*GetObjectsResponse)(nil), "protocols.p2p.GetObjectsResponse")
+	proto.RegisterType((*StatusRequest)(nil), "protocols.p2p.StatusRequest")
+	proto.RegisterType((*StatusResponse)(nil), "protocols.p2p.StatusResponse")
+	proto.RegisterType((*DialBackRequest)(nil), "protocols.p2p.DialBackRequest")
+	proto.RegisterType((*DialBackResponse)(nil), "protocols.p2p.DialBackResponse")
+	proto.RegisterType((*GetTransactionsRequest)(nil), "protocols.p2p.GetTransactionsRequest")
+	proto.RegisterType((*GetTransactionsResponse)(nil), "protocols.p2p.GetTransactionsResponse")
 }
 func (m *MessageData) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
@@ -547,148 +929,2249 @@ func (m *ProcessTransactionResponse) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
-func encodeVarintP2P(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *GetBlocksRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+	return dAtA[:n], nil
 }
-func (m *MessageData) Size() (n int) {
+
+func (m *GetBlocksRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.ClientVersion)
-	if l > 0 {
-		n += 1 + l + sovP2P(uint64(l))
-	}
-	if m.Timestamp != 0 {
-		n += 1 + sovP2P(uint64(m.Timestamp))
+	if m.MessageData != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(m.MessageData.Size()))
+		n7, err := m.MessageData.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n7
 	}
-	l = len(m.Id)
-	if l > 0 {
-		n += 1 + l + sovP2P(uint64(l))
+	if m.FromHeight != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(m.FromHeight))
 	}
-	if m.Gossip {
-		n += 2
+	if m.Count != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(m.Count))
 	}
-	l = len(m.NodeId)
-	if l > 0 {
-		n += 1 + l + sovP2P(uint64(l))
+	return i, nil
+}
+
+func (m *GetBlocksResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	l = len(m.NodePubKey)
-	if l > 0 {
-		n += 1 + l + sovP2P(uint64(l))
+	return dAtA[:n], nil
+}
+
+func (m *GetBlocksResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.MessageData != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(m.MessageData.Size()))
+		n8, err := m.MessageData.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
 	}
-	l = len(m.Sign)
-	if l > 0 {
-		n += 1 + l + sovP2P(uint64(l))
+	if len(m.Blocks) > 0 {
+		for _, b := range m.Blocks {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintP2P(dAtA, i, uint64(len(b)))
+			i += copy(dAtA[i:], b)
+		}
 	}
-	return n
+	return i, nil
 }
 
-func (m *EchoRequest) Size() (n int) {
+func (m *GetObjectsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetObjectsRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
 	if m.MessageData != nil {
-		l = m.MessageData.Size()
-		n += 1 + l + sovP2P(uint64(l))
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(m.MessageData.Size()))
+		n9, err := m.MessageData.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n9
 	}
-	l = len(m.Message)
-	if l > 0 {
-		n += 1 + l + sovP2P(uint64(l))
+	if len(m.Cids) > 0 {
+		for _, s := range m.Cids {
+			dAtA[i] = 0x12
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
 	}
-	return n
+	return i, nil
 }
 
-func (m *EchoResponse) Size() (n int) {
+func (m *GetObjectsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetObjectsResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
 	if m.MessageData != nil {
-		l = m.MessageData.Size()
-		n += 1 + l + sovP2P(uint64(l))
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(m.MessageData.Size()))
+		n10, err := m.MessageData.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n10
 	}
-	l = len(m.Message)
-	if l > 0 {
-		n += 1 + l + sovP2P(uint64(l))
+	if len(m.Cids) > 0 {
+		for _, s := range m.Cids {
+			dAtA[i] = 0x12
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
 	}
-	return n
+	if len(m.Objects) > 0 {
+		for _, b := range m.Objects {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintP2P(dAtA, i, uint64(len(b)))
+			i += copy(dAtA[i:], b)
+		}
+	}
+	return i, nil
 }
 
-func (m *HeadBlockRequest) Size() (n int) {
+func (m *StatusRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StatusRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
 	if m.MessageData != nil {
-		l = m.MessageData.Size()
-		n += 1 + l + sovP2P(uint64(l))
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(m.MessageData.Size()))
+		n11, err := m.MessageData.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n11
 	}
-	return n
+	if m.ProtocolVersion != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(m.ProtocolVersion))
+	}
+	if len(m.ChainID) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(len(m.ChainID)))
+		i += copy(dAtA[i:], m.ChainID)
+	}
+	if len(m.GenesisHash) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(len(m.GenesisHash)))
+		i += copy(dAtA[i:], m.GenesisHash)
+	}
+	if m.HeadHeight != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(m.HeadHeight))
+	}
+	if len(m.Capabilities) > 0 {
+		for _, s := range m.Capabilities {
+			dAtA[i] = 0x32
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.CodecVersion != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(m.CodecVersion))
+	}
+	return i, nil
 }
 
-func (m *HeadBlockResponse) Size() (n int) {
+func (m *StatusResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StatusResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
 	if m.MessageData != nil {
-		l = m.MessageData.Size()
-		n += 1 + l + sovP2P(uint64(l))
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(m.MessageData.Size()))
+		n12, err := m.MessageData.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n12
 	}
-	l = len(m.HeadBlockBytes)
-	if l > 0 {
-		n += 1 + l + sovP2P(uint64(l))
+	if m.ProtocolVersion != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(m.ProtocolVersion))
 	}
-	return n
+	if len(m.ChainID) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(len(m.ChainID)))
+		i += copy(dAtA[i:], m.ChainID)
+	}
+	if len(m.GenesisHash) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(len(m.GenesisHash)))
+		i += copy(dAtA[i:], m.GenesisHash)
+	}
+	if m.HeadHeight != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(m.HeadHeight))
+	}
+	if len(m.Capabilities) > 0 {
+		for _, s := range m.Capabilities {
+			dAtA[i] = 0x32
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.CodecVersion != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(m.CodecVersion))
+	}
+	return i, nil
 }
 
-func (m *ProcessTransactionRequest) Size() (n int) {
+func (m *DialBackRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DialBackRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
 	if m.MessageData != nil {
-		l = m.MessageData.Size()
-		n += 1 + l + sovP2P(uint64(l))
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(m.MessageData.Size()))
+		n13, err := m.MessageData.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n13
 	}
-	l = len(m.TxBytes)
-	if l > 0 {
-		n += 1 + l + sovP2P(uint64(l))
+	if len(m.Addrs) > 0 {
+		for _, s := range m.Addrs {
+			dAtA[i] = 0x12
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
 	}
-	return n
+	return i, nil
 }
 
-func (m *ProcessTransactionResponse) Size() (n int) {
+func (m *DialBackResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DialBackResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
 	if m.MessageData != nil {
-		l = m.MessageData.Size()
-		n += 1 + l + sovP2P(uint64(l))
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(m.MessageData.Size()))
+		n14, err := m.MessageData.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n14
 	}
-	if m.Success {
-		n += 2
+	if len(m.Addrs) > 0 {
+		for _, s := range m.Addrs {
+			dAtA[i] = 0x12
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
 	}
-	l = len(m.Message)
-	if l > 0 {
-		n += 1 + l + sovP2P(uint64(l))
+	if len(m.Message) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(len(m.Message)))
+		i += copy(dAtA[i:], m.Message)
 	}
-	l = len(m.Hash)
-	if l > 0 {
-		n += 1 + l + sovP2P(uint64(l))
+	return i, nil
+}
+
+func (m *GetTransactionsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetTransactionsRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.MessageData != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(m.MessageData.Size()))
+		n15, err := m.MessageData.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n15
+	}
+	if len(m.Hashes) > 0 {
+		for _, s := range m.Hashes {
+			dAtA[i] = 0x12
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	return i, nil
+}
+
+func (m *GetTransactionsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetTransactionsResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.MessageData != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintP2P(dAtA, i, uint64(m.MessageData.Size()))
+		n16, err := m.MessageData.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n16
+	}
+	if len(m.Transactions) > 0 {
+		for _, b := range m.Transactions {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintP2P(dAtA, i, uint64(len(b)))
+			i += copy(dAtA[i:], b)
+		}
+	}
+	return i, nil
+}
+
+func encodeVarintP2P(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *MessageData) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.ClientVersion)
+	if l > 0 {
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovP2P(uint64(m.Timestamp))
+	}
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	if m.Gossip {
+		n += 2
+	}
+	l = len(m.NodeId)
+	if l > 0 {
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	l = len(m.NodePubKey)
+	if l > 0 {
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	l = len(m.Sign)
+	if l > 0 {
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	return n
+}
+
+func (m *EchoRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.MessageData != nil {
+		l = m.MessageData.Size()
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	return n
+}
+
+func (m *EchoResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.MessageData != nil {
+		l = m.MessageData.Size()
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	return n
+}
+
+func (m *HeadBlockRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.MessageData != nil {
+		l = m.MessageData.Size()
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	return n
+}
+
+func (m *HeadBlockResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.MessageData != nil {
+		l = m.MessageData.Size()
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	l = len(m.HeadBlockBytes)
+	if l > 0 {
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	return n
+}
+
+func (m *ProcessTransactionRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.MessageData != nil {
+		l = m.MessageData.Size()
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	l = len(m.TxBytes)
+	if l > 0 {
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	return n
+}
+
+func (m *ProcessTransactionResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.MessageData != nil {
+		l = m.MessageData.Size()
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	if m.Success {
+		n += 2
+	}
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	return n
+}
+
+func (m *GetBlocksRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.MessageData != nil {
+		l = m.MessageData.Size()
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	if m.FromHeight != 0 {
+		n += 1 + sovP2P(uint64(m.FromHeight))
+	}
+	if m.Count != 0 {
+		n += 1 + sovP2P(uint64(m.Count))
+	}
+	return n
+}
+
+func (m *GetBlocksResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.MessageData != nil {
+		l = m.MessageData.Size()
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	if len(m.Blocks) > 0 {
+		for _, b := range m.Blocks {
+			l = len(b)
+			n += 1 + l + sovP2P(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *GetObjectsRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.MessageData != nil {
+		l = m.MessageData.Size()
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	if len(m.Cids) > 0 {
+		for _, s := range m.Cids {
+			l = len(s)
+			n += 1 + l + sovP2P(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *GetObjectsResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.MessageData != nil {
+		l = m.MessageData.Size()
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	if len(m.Cids) > 0 {
+		for _, s := range m.Cids {
+			l = len(s)
+			n += 1 + l + sovP2P(uint64(l))
+		}
+	}
+	if len(m.Objects) > 0 {
+		for _, b := range m.Objects {
+			l = len(b)
+			n += 1 + l + sovP2P(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *StatusRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.MessageData != nil {
+		l = m.MessageData.Size()
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	if m.ProtocolVersion != 0 {
+		n += 1 + sovP2P(uint64(m.ProtocolVersion))
+	}
+	l = len(m.ChainID)
+	if l > 0 {
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	l = len(m.GenesisHash)
+	if l > 0 {
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	if m.HeadHeight != 0 {
+		n += 1 + sovP2P(uint64(m.HeadHeight))
+	}
+	if len(m.Capabilities) > 0 {
+		for _, s := range m.Capabilities {
+			l = len(s)
+			n += 1 + l + sovP2P(uint64(l))
+		}
+	}
+	if m.CodecVersion != 0 {
+		n += 1 + sovP2P(uint64(m.CodecVersion))
+	}
+	return n
+}
+
+func (m *StatusResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.MessageData != nil {
+		l = m.MessageData.Size()
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	if m.ProtocolVersion != 0 {
+		n += 1 + sovP2P(uint64(m.ProtocolVersion))
+	}
+	l = len(m.ChainID)
+	if l > 0 {
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	l = len(m.GenesisHash)
+	if l > 0 {
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	if m.HeadHeight != 0 {
+		n += 1 + sovP2P(uint64(m.HeadHeight))
+	}
+	if len(m.Capabilities) > 0 {
+		for _, s := range m.Capabilities {
+			l = len(s)
+			n += 1 + l + sovP2P(uint64(l))
+		}
+	}
+	if m.CodecVersion != 0 {
+		n += 1 + sovP2P(uint64(m.CodecVersion))
+	}
+	return n
+}
+
+func (m *DialBackRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.MessageData != nil {
+		l = m.MessageData.Size()
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	if len(m.Addrs) > 0 {
+		for _, s := range m.Addrs {
+			l = len(s)
+			n += 1 + l + sovP2P(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *DialBackResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.MessageData != nil {
+		l = m.MessageData.Size()
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	if len(m.Addrs) > 0 {
+		for _, s := range m.Addrs {
+			l = len(s)
+			n += 1 + l + sovP2P(uint64(l))
+		}
+	}
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	return n
+}
+
+func (m *GetTransactionsRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.MessageData != nil {
+		l = m.MessageData.Size()
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	if len(m.Hashes) > 0 {
+		for _, s := range m.Hashes {
+			l = len(s)
+			n += 1 + l + sovP2P(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *GetTransactionsResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.MessageData != nil {
+		l = m.MessageData.Size()
+		n += 1 + l + sovP2P(uint64(l))
+	}
+	if len(m.Transactions) > 0 {
+		for _, b := range m.Transactions {
+			l = len(b)
+			n += 1 + l + sovP2P(uint64(l))
+		}
+	}
+	return n
+}
+
+func sovP2P(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozP2P(x uint64) (n int) {
+	return sovP2P(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *MessageData) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowP2P
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MessageData: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MessageData: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientVersion", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ClientVersion = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Gossip", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Gossip = bool(v != 0)
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NodeId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NodeId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NodePubKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NodePubKey = append(m.NodePubKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.NodePubKey == nil {
+				m.NodePubKey = []byte{}
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sign", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sign = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipP2P(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthP2P
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *EchoRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowP2P
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: EchoRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: EchoRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageData", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MessageData == nil {
+				m.MessageData = &MessageData{}
+			}
+			if err := m.MessageData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Message = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipP2P(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthP2P
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *EchoResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowP2P
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: EchoResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: EchoResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageData", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MessageData == nil {
+				m.MessageData = &MessageData{}
+			}
+			if err := m.MessageData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Message = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipP2P(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthP2P
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HeadBlockRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowP2P
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HeadBlockRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HeadBlockRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageData", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MessageData == nil {
+				m.MessageData = &MessageData{}
+			}
+			if err := m.MessageData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipP2P(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthP2P
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HeadBlockResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowP2P
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HeadBlockResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HeadBlockResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageData", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MessageData == nil {
+				m.MessageData = &MessageData{}
+			}
+			if err := m.MessageData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HeadBlockBytes", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HeadBlockBytes = append(m.HeadBlockBytes[:0], dAtA[iNdEx:postIndex]...)
+			if m.HeadBlockBytes == nil {
+				m.HeadBlockBytes = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipP2P(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthP2P
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ProcessTransactionRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowP2P
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ProcessTransactionRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ProcessTransactionRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageData", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MessageData == nil {
+				m.MessageData = &MessageData{}
+			}
+			if err := m.MessageData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TxBytes", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TxBytes = append(m.TxBytes[:0], dAtA[iNdEx:postIndex]...)
+			if m.TxBytes == nil {
+				m.TxBytes = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipP2P(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthP2P
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ProcessTransactionResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowP2P
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ProcessTransactionResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ProcessTransactionResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageData", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MessageData == nil {
+				m.MessageData = &MessageData{}
+			}
+			if err := m.MessageData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Success", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Success = bool(v != 0)
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Message = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipP2P(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthP2P
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetBlocksRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowP2P
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetBlocksRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetBlocksRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageData", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MessageData == nil {
+				m.MessageData = &MessageData{}
+			}
+			if err := m.MessageData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FromHeight", wireType)
+			}
+			m.FromHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FromHeight |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipP2P(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthP2P
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetBlocksResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowP2P
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetBlocksResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetBlocksResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageData", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MessageData == nil {
+				m.MessageData = &MessageData{}
+			}
+			if err := m.MessageData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Blocks", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Blocks = append(m.Blocks, make([]byte, postIndex-iNdEx))
+			copy(m.Blocks[len(m.Blocks)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipP2P(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthP2P
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetObjectsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowP2P
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetObjectsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetObjectsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageData", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MessageData == nil {
+				m.MessageData = &MessageData{}
+			}
+			if err := m.MessageData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cids", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Cids = append(m.Cids, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipP2P(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthP2P
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetObjectsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowP2P
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetObjectsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetObjectsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageData", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MessageData == nil {
+				m.MessageData = &MessageData{}
+			}
+			if err := m.MessageData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cids", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Cids = append(m.Cids, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Objects", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Objects = append(m.Objects, make([]byte, postIndex-iNdEx))
+			copy(m.Objects[len(m.Objects)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipP2P(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthP2P
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	return n
-}
 
-func sovP2P(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
-}
-func sozP2P(x uint64) (n int) {
-	return sovP2P(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	return nil
 }
-func (m *MessageData) Unmarshal(dAtA []byte) error {
+func (m *StatusRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -711,17 +3194,17 @@ func (m *MessageData) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: MessageData: wiretype end group for non-group")
+			return fmt.Errorf("proto: StatusRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: MessageData: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: StatusRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ClientVersion", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageData", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowP2P
@@ -731,26 +3214,30 @@ func (m *MessageData) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthP2P
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ClientVersion = string(dAtA[iNdEx:postIndex])
+			if m.MessageData == nil {
+				m.MessageData = &MessageData{}
+			}
+			if err := m.MessageData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ProtocolVersion", wireType)
 			}
-			m.Timestamp = 0
+			m.ProtocolVersion = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowP2P
@@ -760,14 +3247,14 @@ func (m *MessageData) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Timestamp |= (int64(b) & 0x7F) << shift
+				m.ProtocolVersion |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ChainID", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -792,31 +3279,11 @@ func (m *MessageData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Id = string(dAtA[iNdEx:postIndex])
+			m.ChainID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Gossip", wireType)
-			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowP2P
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				v |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			m.Gossip = bool(v != 0)
-		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field NodeId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field GenesisHash", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -841,13 +3308,13 @@ func (m *MessageData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.NodeId = string(dAtA[iNdEx:postIndex])
+			m.GenesisHash = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 6:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field NodePubKey", wireType)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HeadHeight", wireType)
 			}
-			var byteLen int
+			m.HeadHeight = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowP2P
@@ -857,26 +3324,14 @@ func (m *MessageData) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				m.HeadHeight |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthP2P
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.NodePubKey = append(m.NodePubKey[:0], dAtA[iNdEx:postIndex]...)
-			if m.NodePubKey == nil {
-				m.NodePubKey = []byte{}
-			}
-			iNdEx = postIndex
-		case 7:
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Sign", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Capabilities", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -901,8 +3356,27 @@ func (m *MessageData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Sign = string(dAtA[iNdEx:postIndex])
+			m.Capabilities = append(m.Capabilities, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CodecVersion", wireType)
+			}
+			m.CodecVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CodecVersion |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipP2P(dAtA[iNdEx:])
@@ -924,7 +3398,7 @@ func (m *MessageData) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *EchoRequest) Unmarshal(dAtA []byte) error {
+func (m *StatusResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -934,30 +3408,111 @@ func (m *EchoRequest) Unmarshal(dAtA []byte) error {
 			if shift >= 64 {
 				return ErrIntOverflowP2P
 			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StatusResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StatusResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageData", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthP2P
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MessageData == nil {
+				m.MessageData = &MessageData{}
+			}
+			if err := m.MessageData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProtocolVersion", wireType)
+			}
+			m.ProtocolVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ProtocolVersion |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChainID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthP2P
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: EchoRequest: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: EchoRequest: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			m.ChainID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MessageData", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field GenesisHash", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowP2P
@@ -967,28 +3522,43 @@ func (m *EchoRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthP2P
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.MessageData == nil {
-				m.MessageData = &MessageData{}
+			m.GenesisHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HeadHeight", wireType)
 			}
-			if err := m.MessageData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.HeadHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HeadHeight |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
-		case 2:
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Capabilities", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1013,8 +3583,27 @@ func (m *EchoRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Message = string(dAtA[iNdEx:postIndex])
+			m.Capabilities = append(m.Capabilities, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CodecVersion", wireType)
+			}
+			m.CodecVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowP2P
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CodecVersion |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipP2P(dAtA[iNdEx:])
@@ -1036,7 +3625,7 @@ func (m *EchoRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *EchoResponse) Unmarshal(dAtA []byte) error {
+func (m *DialBackRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1059,10 +3648,10 @@ func (m *EchoResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: EchoResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: DialBackRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: EchoResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DialBackRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -1100,7 +3689,7 @@ func (m *EchoResponse) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Addrs", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1125,7 +3714,7 @@ func (m *EchoResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Message = string(dAtA[iNdEx:postIndex])
+			m.Addrs = append(m.Addrs, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -1148,7 +3737,7 @@ func (m *EchoResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *HeadBlockRequest) Unmarshal(dAtA []byte) error {
+func (m *DialBackResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1171,10 +3760,10 @@ func (m *HeadBlockRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: HeadBlockRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: DialBackResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: HeadBlockRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DialBackResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -1210,61 +3799,11 @@ func (m *HeadBlockRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipP2P(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthP2P
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *HeadBlockResponse) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowP2P
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: HeadBlockResponse: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: HeadBlockResponse: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MessageData", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Addrs", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowP2P
@@ -1274,30 +3813,26 @@ func (m *HeadBlockResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthP2P
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.MessageData == nil {
-				m.MessageData = &MessageData{}
-			}
-			if err := m.MessageData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Addrs = append(m.Addrs, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
-		case 2:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field HeadBlockBytes", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowP2P
@@ -1307,22 +3842,20 @@ func (m *HeadBlockResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthP2P
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.HeadBlockBytes = append(m.HeadBlockBytes[:0], dAtA[iNdEx:postIndex]...)
-			if m.HeadBlockBytes == nil {
-				m.HeadBlockBytes = []byte{}
-			}
+			m.Message = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -1345,7 +3878,7 @@ func (m *HeadBlockResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ProcessTransactionRequest) Unmarshal(dAtA []byte) error {
+func (m *GetTransactionsRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1368,10 +3901,10 @@ func (m *ProcessTransactionRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ProcessTransactionRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: GetTransactionsRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ProcessTransactionRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: GetTransactionsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -1409,9 +3942,9 @@ func (m *ProcessTransactionRequest) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TxBytes", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Hashes", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowP2P
@@ -1421,22 +3954,20 @@ func (m *ProcessTransactionRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthP2P
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TxBytes = append(m.TxBytes[:0], dAtA[iNdEx:postIndex]...)
-			if m.TxBytes == nil {
-				m.TxBytes = []byte{}
-			}
+			m.Hashes = append(m.Hashes, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -1459,7 +3990,7 @@ func (m *ProcessTransactionRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ProcessTransactionResponse) Unmarshal(dAtA []byte) error {
+func (m *GetTransactionsResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1482,10 +4013,10 @@ func (m *ProcessTransactionResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ProcessTransactionResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: GetTransactionsResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ProcessTransactionResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: GetTransactionsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -1522,59 +4053,10 @@ func (m *ProcessTransactionResponse) Unmarshal(dAtA []byte) error {
 			}
 			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Success", wireType)
-			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowP2P
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				v |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			m.Success = bool(v != 0)
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowP2P
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthP2P
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Message = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Transactions", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowP2P
@@ -1584,20 +4066,20 @@ func (m *ProcessTransactionResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthP2P
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Hash = string(dAtA[iNdEx:postIndex])
+			m.Transactions = append(m.Transactions, make([]byte, postIndex-iNdEx))
+			copy(m.Transactions[len(m.Transactions)-1], dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -1728,29 +4210,45 @@ var (
 func init() { proto.RegisterFile("p2p.proto", fileDescriptorP2P) }
 
 var fileDescriptorP2P = []byte{
-	// 374 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x53, 0xc1, 0x6a, 0xdb, 0x40,
-	0x10, 0xed, 0xda, 0xae, 0x6d, 0x8d, 0x6c, 0xe3, 0xee, 0xa1, 0x6c, 0x4d, 0x11, 0x42, 0x94, 0xa2,
-	0x93, 0x0e, 0xee, 0xb5, 0x27, 0xd3, 0x42, 0x4b, 0x29, 0x98, 0xa5, 0xf4, 0xbe, 0x96, 0xb6, 0xd6,
-	0x52, 0x5b, 0xbb, 0xd1, 0xac, 0x21, 0xfe, 0x93, 0x9c, 0xf3, 0x2f, 0x81, 0x1c, 0xf3, 0x09, 0xc1,
-	0xf9, 0x91, 0xa0, 0x8d, 0x85, 0xe5, 0x90, 0xa3, 0x72, 0xd2, 0xbc, 0xc7, 0xcc, 0xbc, 0x79, 0x3c,
-	0x2d, 0x78, 0x66, 0x6e, 0x12, 0x53, 0x6a, 0xab, 0xe9, 0xd8, 0x7d, 0x52, 0xbd, 0xc1, 0xc4, 0xcc,
-	0x4d, 0x74, 0x43, 0xc0, 0xff, 0x2d, 0x11, 0xc5, 0x5a, 0x7e, 0x13, 0x56, 0xd0, 0x4f, 0x30, 0x4e,
-	0x37, 0x4a, 0x16, 0xf6, 0xaf, 0x2c, 0x51, 0xe9, 0x82, 0x91, 0x90, 0xc4, 0x1e, 0x3f, 0x27, 0xe9,
-	0x47, 0xf0, 0xac, 0xda, 0x4a, 0xb4, 0x62, 0x6b, 0x58, 0x27, 0x24, 0x71, 0x97, 0x9f, 0x08, 0x3a,
-	0x81, 0x8e, 0xca, 0x58, 0xd7, 0x0d, 0x76, 0x54, 0x46, 0xdf, 0x43, 0x7f, 0xad, 0x11, 0x95, 0x61,
-	0xbd, 0x90, 0xc4, 0x43, 0x7e, 0x44, 0x15, 0x5f, 0xe8, 0x4c, 0xfe, 0xcc, 0xd8, 0x5b, 0xd7, 0x7b,
-	0x44, 0x34, 0x00, 0xa8, 0xaa, 0xe5, 0x6e, 0xf5, 0x4b, 0xee, 0x59, 0x3f, 0x24, 0xf1, 0x88, 0x37,
-	0x18, 0x4a, 0xa1, 0x87, 0x6a, 0x5d, 0xb0, 0x81, 0x9b, 0x72, 0x75, 0x24, 0xc1, 0xff, 0x9e, 0xe6,
-	0x9a, 0xcb, 0x8b, 0x9d, 0x44, 0x4b, 0xbf, 0x82, 0xbf, 0x3d, 0xb9, 0x72, 0x26, 0xfc, 0xf9, 0x2c,
-	0x39, 0xf3, 0x9e, 0x34, 0x7c, 0xf3, 0x66, 0x3b, 0x65, 0x30, 0x38, 0x42, 0x67, 0xce, 0xe3, 0x35,
-	0x8c, 0xfe, 0xc1, 0xe8, 0x49, 0x06, 0x8d, 0x2e, 0x50, 0xbe, 0x9a, 0xce, 0x12, 0xa6, 0x3f, 0xa4,
-	0xc8, 0x16, 0x1b, 0x9d, 0xfe, 0x6f, 0xc5, 0x53, 0xb4, 0x87, 0x77, 0x8d, 0x8d, 0xad, 0x9c, 0xff,
-	0x19, 0x26, 0x79, 0xbd, 0x72, 0xb1, 0xb7, 0x12, 0x9d, 0x8b, 0x11, 0x7f, 0xc6, 0x46, 0x08, 0x1f,
-	0x96, 0xa5, 0x4e, 0x25, 0xe2, 0x9f, 0x52, 0x14, 0x28, 0x52, 0xab, 0x74, 0xd1, 0x5a, 0x52, 0xf6,
-	0xb2, 0xa9, 0x5d, 0xc3, 0xe8, 0x9a, 0xc0, 0xec, 0x25, 0xd5, 0xb6, 0x82, 0xc3, 0x5d, 0x5a, 0xed,
-	0x76, 0xb2, 0x43, 0x5e, 0xc3, 0x66, 0xa4, 0xdd, 0xb3, 0x48, 0xab, 0xbf, 0x36, 0x17, 0x98, 0xbb,
-	0x37, 0xe0, 0x71, 0x57, 0x2f, 0xa6, 0xb7, 0x87, 0x80, 0xdc, 0x1d, 0x02, 0x72, 0x7f, 0x08, 0xc8,
-	0xd5, 0x43, 0xf0, 0x66, 0xd5, 0x77, 0x17, 0x7c, 0x79, 0x0c, 0x00, 0x00, 0xff, 0xff, 0x70, 0x9e,
-	0x0d, 0x67, 0xb2, 0x03, 0x00, 0x00,
+	// 640 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x55, 0xc1, 0x6e, 0x13, 0x3d,
+	0x10, 0xfe, 0x9d, 0xa4, 0x69, 0x33, 0x49, 0xdb, 0xd4, 0xfa, 0x15, 0x96, 0x0a, 0x45, 0x91, 0x85,
+	0x50, 0x4e, 0x39, 0x94, 0x2b, 0xa7, 0xa8, 0xa8, 0xad, 0x10, 0xa2, 0x32, 0x88, 0xbb, 0xe3, 0x9d,
+	0x26, 0x86, 0x64, 0xbd, 0xc4, 0x8e, 0x44, 0xc5, 0x85, 0x13, 0xcf, 0x80, 0x04, 0x27, 0xde, 0x05,
+	0x89, 0x23, 0x8f, 0x80, 0xca, 0x8b, 0xa0, 0xf5, 0xae, 0x15, 0xa7, 0x84, 0x9b, 0xb9, 0x70, 0x8a,
+	0xbf, 0x4f, 0xde, 0xf9, 0xe6, 0x9b, 0x19, 0x4f, 0xa0, 0x95, 0x9f, 0xe4, 0xa3, 0x7c, 0xa9, 0xad,
+	0xa6, 0xfb, 0xee, 0x47, 0xea, 0xb9, 0x19, 0xe5, 0x27, 0x39, 0xfb, 0x4a, 0xa0, 0xfd, 0x14, 0x8d,
+	0x11, 0x53, 0x3c, 0x15, 0x56, 0xd0, 0xfb, 0xb0, 0x2f, 0xe7, 0x0a, 0x33, 0xfb, 0x12, 0x97, 0x46,
+	0xe9, 0x2c, 0x21, 0x03, 0x32, 0x6c, 0xf1, 0x4d, 0x92, 0xde, 0x83, 0x96, 0x55, 0x0b, 0x34, 0x56,
+	0x2c, 0xf2, 0xa4, 0x36, 0x20, 0xc3, 0x3a, 0x5f, 0x13, 0xf4, 0x00, 0x6a, 0x2a, 0x4d, 0xea, 0xee,
+	0xc3, 0x9a, 0x4a, 0x69, 0x0f, 0x9a, 0x53, 0x6d, 0x8c, 0xca, 0x93, 0xc6, 0x80, 0x0c, 0xf7, 0x78,
+	0x85, 0x0a, 0x3e, 0xd3, 0x29, 0x5e, 0xa4, 0xc9, 0x8e, 0xbb, 0x5b, 0x21, 0xda, 0x07, 0x28, 0x4e,
+	0x97, 0xab, 0xc9, 0x13, 0xbc, 0x4e, 0x9a, 0x03, 0x32, 0xec, 0xf0, 0x80, 0xa1, 0x14, 0x1a, 0x46,
+	0x4d, 0xb3, 0x64, 0xd7, 0x7d, 0xe5, 0xce, 0x0c, 0xa1, 0xfd, 0x58, 0xce, 0x34, 0xc7, 0x37, 0x2b,
+	0x34, 0x96, 0x3e, 0x82, 0xf6, 0x62, 0xed, 0xca, 0x99, 0x68, 0x9f, 0x1c, 0x8f, 0x36, 0xbc, 0x8f,
+	0x02, 0xdf, 0x3c, 0xbc, 0x4e, 0x13, 0xd8, 0xad, 0xa0, 0x33, 0xd7, 0xe2, 0x1e, 0xb2, 0x2b, 0xe8,
+	0x94, 0x32, 0x26, 0xd7, 0x99, 0xc1, 0xbf, 0xa6, 0x73, 0x09, 0xdd, 0x73, 0x14, 0xe9, 0x78, 0xae,
+	0xe5, 0xeb, 0x28, 0x9e, 0xd8, 0x35, 0x1c, 0x05, 0x11, 0xa3, 0xa4, 0xff, 0x00, 0x0e, 0x66, 0x3e,
+	0xe4, 0xf8, 0xda, 0xa2, 0x71, 0x2e, 0x3a, 0xfc, 0x16, 0xcb, 0x0c, 0xdc, 0xbd, 0x5c, 0x6a, 0x89,
+	0xc6, 0xbc, 0x58, 0x8a, 0xcc, 0x08, 0x69, 0x95, 0xce, 0xa2, 0x75, 0xca, 0xbe, 0x0d, 0xb5, 0x3d,
+	0x64, 0x5f, 0x08, 0x1c, 0x6f, 0x53, 0x8d, 0xd5, 0x38, 0xb3, 0x92, 0x45, 0x6c, 0x27, 0xbb, 0xc7,
+	0x3d, 0x0c, 0x5b, 0x5a, 0xdf, 0x68, 0x69, 0x31, 0xb5, 0x33, 0x61, 0x66, 0xee, 0x0d, 0xb4, 0xb8,
+	0x3b, 0xb3, 0x0f, 0x04, 0xba, 0x67, 0x68, 0x5d, 0xad, 0x4c, 0x9c, 0x8a, 0xf4, 0x01, 0xae, 0x96,
+	0x7a, 0x71, 0x8e, 0x6a, 0x3a, 0xb3, 0x2e, 0xbb, 0x06, 0x0f, 0x18, 0xfa, 0x3f, 0xec, 0x48, 0xbd,
+	0xca, 0xac, 0x4b, 0xaf, 0xc1, 0x4b, 0xc0, 0x14, 0x1c, 0x05, 0x79, 0x44, 0xa9, 0x51, 0x0f, 0x9a,
+	0x13, 0x17, 0x2f, 0xa9, 0x0d, 0xea, 0xc3, 0x0e, 0xaf, 0x10, 0x43, 0x27, 0xf5, 0x6c, 0xf2, 0x0a,
+	0xa5, 0x8d, 0xe4, 0x99, 0x42, 0x43, 0xaa, 0xb4, 0x14, 0x6a, 0x71, 0x77, 0x66, 0xef, 0x09, 0xd0,
+	0x50, 0x27, 0x8a, 0xa7, 0x2d, 0x42, 0x45, 0xc7, 0x75, 0x29, 0x92, 0xd4, 0x9d, 0x51, 0x0f, 0xd9,
+	0xa7, 0x1a, 0xec, 0x3f, 0xb7, 0xc2, 0xae, 0x22, 0xd9, 0x1c, 0xc2, 0xa1, 0xbf, 0xe9, 0xb7, 0x73,
+	0xd9, 0xdf, 0xdb, 0x74, 0x91, 0x93, 0x9c, 0x09, 0x95, 0x5d, 0x9c, 0xfa, 0x29, 0xac, 0x20, 0x1d,
+	0x40, 0x7b, 0x8a, 0x19, 0x1a, 0x65, 0xce, 0xd7, 0xc3, 0x18, 0x52, 0xc5, 0x00, 0x15, 0xef, 0xb7,
+	0x1a, 0xa0, 0x9d, 0x72, 0x80, 0xd6, 0x0c, 0x65, 0xd0, 0x91, 0x22, 0x17, 0x13, 0x35, 0x57, 0x56,
+	0xa1, 0x49, 0x9a, 0xae, 0x16, 0x1b, 0x9c, 0xbb, 0xa3, 0x53, 0x94, 0x3e, 0xcd, 0x5d, 0x17, 0x65,
+	0x83, 0x63, 0x9f, 0x6b, 0x70, 0xe0, 0xab, 0x13, 0xa5, 0x39, 0xff, 0x56, 0x79, 0x10, 0x0e, 0x4f,
+	0x95, 0x98, 0x8f, 0x45, 0xa4, 0x3f, 0x80, 0xe2, 0xe1, 0x8b, 0x34, 0x5d, 0xfa, 0xe1, 0x2d, 0x41,
+	0xf1, 0x4c, 0xba, 0x6b, 0x9d, 0x28, 0x7d, 0xd8, 0x2a, 0xf4, 0xe7, 0xc5, 0xc8, 0x32, 0xe8, 0x9d,
+	0xa1, 0x0d, 0x96, 0x74, 0xa4, 0xe7, 0xd2, 0x83, 0x66, 0xb1, 0x64, 0xd1, 0x27, 0x52, 0x21, 0xf6,
+	0x0e, 0xee, 0xfc, 0xa6, 0x17, 0xc5, 0x38, 0x83, 0x8e, 0x0d, 0xa2, 0x56, 0x7b, 0x6f, 0x83, 0x1b,
+	0x77, 0xbf, 0xdd, 0xf4, 0xc9, 0xf7, 0x9b, 0x3e, 0xf9, 0x71, 0xd3, 0x27, 0x1f, 0x7f, 0xf6, 0xff,
+	0x9b, 0x34, 0x5d, 0xf4, 0x87, 0xbf, 0x06, 0x00, 0x0d, 0xf6, 0xc7, 0xd8, 0xa4, 0x09, 0x00, 0x00,
 }
@@ -3,7 +3,6 @@ package protobuff
 import (
 	"bufio"
 	"context"
-	"errors"
 	"fmt"
 
 	log "github.com/sirupsen/logrus"
@@ -11,27 +10,22 @@ import (
 	"github.com/c3systems/c3-go/core/chain/statechain"
 	"github.com/c3systems/c3-go/core/miner"
 	pb "github.com/c3systems/c3-go/core/p2p/protobuff/pb"
+	"github.com/c3systems/c3-go/core/p2p/reputation"
 	nodetypes "github.com/c3systems/c3-go/node/types"
 
 	inet "github.com/libp2p/go-libp2p-net"
 	peer "github.com/libp2p/go-libp2p-peer"
 	protobufCodec "github.com/multiformats/go-multicodec/protobuf"
-	uuid "github.com/satori/go.uuid"
 )
 
 // pattern: /protocol-name/request-or-response-message/version
 const processTransactionRequest = "/processtransaction/processtransactionreq/0.0.1"
 const processTransactionResponse = "/processtransaction/processtransactionresp/0.0.1"
 
-type processTransactionRequestWrapper struct {
-	resp chan interface{}
-	req  *pb.ProcessTransactionRequest
-}
-
 // ProcessTransaction ...
 type ProcessTransaction struct {
-	node                   *Node                                        // local host
-	requests               map[string]*processTransactionRequestWrapper // used to access request data from response handlers
+	node                   *Node     // local host
+	requests               *requests // used to deliver responses to the requests awaiting them
 	broadcastTransactionFN func(tx *statechain.Transaction) (*nodetypes.SendTxResponse, error)
 	addPendingTxFN         func(tx *statechain.Transaction) error
 }
@@ -40,7 +34,7 @@ type ProcessTransaction struct {
 func NewProcessTransaction(node *Node, broadcastTransactionFN func(tx *statechain.Transaction) (*nodetypes.SendTxResponse, error), addPendingTxFN func(tx *statechain.Transaction) error) *ProcessTransaction {
 	p := ProcessTransaction{
 		node:                   node,
-		requests:               make(map[string]*processTransactionRequestWrapper),
+		requests:               newRequests(),
 		broadcastTransactionFN: broadcastTransactionFN,
 		addPendingTxFN:         addPendingTxFN,
 	}
@@ -61,6 +55,7 @@ func (p *ProcessTransaction) onProcessTransactionRequest(s inet.Stream) {
 	err := decoder.Decode(data)
 	if err != nil {
 		log.Errorf("[p2p] %s", err)
+		p.node.penalize(s.Conn().RemotePeer(), reputation.OffenseUndecodableMessage)
 		return
 	}
 
@@ -69,6 +64,7 @@ func (p *ProcessTransaction) onProcessTransactionRequest(s inet.Stream) {
 
 	if !valid {
 		log.Error("[p2p] failed to authenticate message")
+		p.node.penalize(s.Conn().RemotePeer(), reputation.OffenseUndecodableMessage)
 		return
 	}
 
@@ -79,6 +75,7 @@ func (p *ProcessTransaction) onProcessTransactionRequest(s inet.Stream) {
 	// interpret the tx
 	tx := new(statechain.Transaction)
 	if err := tx.Deserialize(data.TxBytes); err != nil {
+		p.node.penalize(s.Conn().RemotePeer(), reputation.OffenseUndecodableMessage)
 		resp.Success = false
 		resp.Message = fmt.Sprintf("err deserializing tx: %v", err)
 
@@ -88,6 +85,7 @@ func (p *ProcessTransaction) onProcessTransactionRequest(s inet.Stream) {
 
 	ok, err := miner.VerifyTransaction(tx)
 	if err != nil {
+		p.node.penalize(s.Conn().RemotePeer(), reputation.OffenseInvalidTransaction)
 		resp.Success = false
 		resp.Message = fmt.Sprintf("err verifying tx: %v", err)
 
@@ -95,6 +93,7 @@ func (p *ProcessTransaction) onProcessTransactionRequest(s inet.Stream) {
 		return
 	}
 	if !ok {
+		p.node.penalize(s.Conn().RemotePeer(), reputation.OffenseInvalidTransaction)
 		resp.Success = false
 		resp.Message = "invalid transaction"
 
@@ -158,75 +157,31 @@ func (p *ProcessTransaction) sendResp(resp *pb.ProcessTransactionResponse, s ine
 
 // remote peer response handler
 func (p *ProcessTransaction) onProcessTransactionResponse(s inet.Stream) {
-	data := &pb.ProcessTransactionResponse{}
-	decoder := protobufCodec.Multicodec(nil).Decoder(bufio.NewReader(s))
-	if err := decoder.Decode(data); err != nil {
-		log.Errorf("[p2p] err decoding process transaction response\n%v", err)
-		return
-	}
-
-	// locate request data and remove it if found
-	reqW, ok := p.requests[data.MessageData.Id]
-	if ok {
-		// remove request from map as we have processed it here
-		delete(p.requests, data.MessageData.Id)
-	} else {
-		log.Error("[p2p] failed to locate request data object for response")
-		return
-	}
-
-	// authenticate message content
-	valid := p.node.authenticateMessage(data, data.MessageData)
-
-	if !valid {
-		reqW.resp <- errors.New("Failed to authenticate message")
-		return
-	}
-
-	reqW.resp <- data
+	p.node.onResponse(s, p.requests, &pb.ProcessTransactionResponse{})
 }
 
-// SendTransaction ...
-func (p *ProcessTransaction) SendTransaction(peerID peer.ID, txBytes []byte, resp chan interface{}) error {
-	// log.Printf("[p2p] %s: Sending process transaction to: %s....", e.node.ID(), peerID)
-
-	// create message data
-	id, err := uuid.NewV4()
+// SendTransaction sends the serialized tx to the peer to process, returning the tx hash.
+// It fails when the peer rejects the tx.
+func (p *ProcessTransaction) SendTransaction(ctx context.Context, peerID peer.ID, txBytes []byte) (string, error) {
+	data, err := p.node.newRequestData()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	req := &pb.ProcessTransactionRequest{
-		MessageData: p.node.NewMessageData(id.String(), false),
+		MessageData: data,
 		TxBytes:     txBytes,
 	}
 
-	signature, err := p.node.signProtoMessage(req)
+	v, err := p.node.sendRequest(ctx, p.requests, peerID, processTransactionRequest, req)
 	if err != nil {
-		log.Error("[p2p] failed to sign message")
-		return err
+		return "", err
 	}
 
-	// add the signature to the message
-	req.MessageData.Sign = string(signature)
-
-	s, err := p.node.NewStream(context.Background(), peerID, processTransactionRequest)
-	if err != nil {
-		log.Errorf("[p2p] %s", err)
-		return err
-	}
-
-	ok := p.node.sendProtoMessage(req, s)
-
-	if !ok {
-		return errors.New("failed to send message")
+	resp := v.(*pb.ProcessTransactionResponse)
+	if !resp.Success {
+		return "", fmt.Errorf("peer rejected tx: %s", resp.Message)
 	}
 
-	// store request so response handler has access to it
-	p.requests[req.MessageData.Id] = &processTransactionRequestWrapper{
-		resp: resp,
-		req:  req,
-	}
-	// log.Printf("[p2p] %s: process transaction to: %s was sent. Message Id: %s, Message: %s", e.node.ID(), peerID, req.MessageData.Id, req.Message)
-	return nil
+	return resp.Hash, nil
 }
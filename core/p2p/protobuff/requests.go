@@ -0,0 +1,152 @@
+package protobuff
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/gogo/protobuf/proto"
+	inet "github.com/libp2p/go-libp2p-net"
+	peer "github.com/libp2p/go-libp2p-peer"
+	protocol "github.com/libp2p/go-libp2p-protocol"
+
+	pb "github.com/c3systems/c3-go/core/p2p/protobuff/pb"
+	protobufCodec "github.com/multiformats/go-multicodec/protobuf"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ErrUnauthenticatedResponse is returned when a response isn't signed by the peer it claims to be from
+var ErrUnauthenticatedResponse = errors.New("failed to authenticate message")
+
+// message is a protocol request or response
+type message interface {
+	proto.Message
+	GetMessageData() *pb.MessageData
+}
+
+// pendingRequest is a request awaiting its response
+type pendingRequest struct {
+	peerID peer.ID
+	resp   chan message
+}
+
+// requests correlates a protocol's responses with the requests awaiting them by message id.
+// note: a response is only accepted from the peer the request was sent to
+type requests struct {
+	mut     sync.Mutex
+	pending map[string]*pendingRequest
+}
+
+func newRequests() *requests {
+	return &requests{
+		pending: make(map[string]*pendingRequest),
+	}
+}
+
+// add registers a request to the peer, returning the channel its response is delivered on
+func (r *requests) add(id string, peerID peer.ID) <-chan message {
+	// note: buffered, so the response handler never blocks on a request that's given up
+	ch := make(chan message, 1)
+
+	r.mut.Lock()
+	r.pending[id] = &pendingRequest{
+		peerID: peerID,
+		resp:   ch,
+	}
+	r.mut.Unlock()
+
+	return ch
+}
+
+// remove forgets the request, so a late response is dropped
+func (r *requests) remove(id string) {
+	r.mut.Lock()
+	delete(r.pending, id)
+	r.mut.Unlock()
+}
+
+// resolve delivers the peer's response to the request awaiting it.
+// It returns false when no request to the peer awaits the response's message id.
+func (r *requests) resolve(peerID peer.ID, resp message) bool {
+	id := resp.GetMessageData().GetId()
+
+	r.mut.Lock()
+	req, ok := r.pending[id]
+	if ok && req.peerID == peerID {
+		delete(r.pending, id)
+	}
+	r.mut.Unlock()
+	if !ok || req.peerID != peerID {
+		return false
+	}
+
+	req.resp <- resp
+	return true
+}
+
+// newRequestData generates the message data of a request, with a unique message id
+func (n *Node) newRequestData() (*pb.MessageData, error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	return n.NewMessageData(id.String(), false), nil
+}
+
+// sendRequest signs the request, sends it to the peer on the protocol and waits for the response with the request's message id.
+// It returns when the response arrives or the context is done, whichever is first.
+func (n *Node) sendRequest(ctx context.Context, reqs *requests, peerID peer.ID, pid protocol.ID, req message) (message, error) {
+	signature, err := n.signProtoMessage(req)
+	if err != nil {
+		log.Error("[p2p] failed to sign message")
+		return nil, err
+	}
+
+	// add the signature to the message
+	data := req.GetMessageData()
+	data.Sign = string(signature)
+
+	// note: registered before sending, so a fast response finds it
+	ch := reqs.add(data.Id, peerID)
+	defer reqs.remove(data.Id)
+
+	s, err := n.NewStream(ctx, peerID, pid)
+	if err != nil {
+		log.Errorf("[p2p] %s", err)
+		return nil, err
+	}
+
+	if ok := n.sendProtoMessage(req, s); !ok {
+		return nil, errors.New("failed to send message")
+	}
+
+	select {
+	case resp := <-ch:
+		// authenticate message content
+		if valid := n.authenticateMessage(resp, resp.GetMessageData()); !valid {
+			return nil, ErrUnauthenticatedResponse
+		}
+
+		return resp, nil
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// onResponse decodes the response on the stream and delivers it to the request awaiting it
+func (n *Node) onResponse(s inet.Stream, reqs *requests, resp message) {
+	decoder := protobufCodec.Multicodec(nil).Decoder(bufio.NewReader(s))
+	if err := decoder.Decode(resp); err != nil {
+		log.Errorf("[p2p] err decoding %T\n%v", resp, err)
+		return
+	}
+
+	if ok := reqs.resolve(s.Conn().RemotePeer(), resp); !ok {
+		log.Errorf("[p2p] failed to locate request data object for %T from %s", resp, s.Conn().RemotePeer().Pretty())
+	}
+}
@@ -0,0 +1,170 @@
+// +build unit
+
+package protobuff
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	pb "github.com/c3systems/c3-go/core/p2p/protobuff/pb"
+
+	csms "github.com/libp2p/go-conn-security-multistream"
+	lCrypt "github.com/libp2p/go-libp2p-crypto"
+	peer "github.com/libp2p/go-libp2p-peer"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	pstoremem "github.com/libp2p/go-libp2p-peerstore/pstoremem"
+	secio "github.com/libp2p/go-libp2p-secio"
+	swarm "github.com/libp2p/go-libp2p-swarm"
+	tptu "github.com/libp2p/go-libp2p-transport-upgrader"
+	bhost "github.com/libp2p/go-libp2p/p2p/host/basic"
+	tcp "github.com/libp2p/go-tcp-transport"
+	ma "github.com/multiformats/go-multiaddr"
+	msmux "github.com/whyrusleeping/go-smux-multistream"
+	yamux "github.com/whyrusleeping/go-smux-yamux"
+)
+
+// newTestNode builds a node listening on a loopback tcp port
+func newTestNode(t *testing.T, props *Props) *Node {
+	priv, pub, err := lCrypt.GenerateKeyPair(lCrypt.Ed25519, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, err := peer.IDFromPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ps := pstoremem.NewPeerstore()
+	if err := ps.AddPrivKey(pid, priv); err != nil {
+		t.Fatal(err)
+	}
+	if err := ps.AddPubKey(pid, pub); err != nil {
+		t.Fatal(err)
+	}
+
+	n := swarm.NewSwarm(context.Background(), pid, ps, nil)
+	secMuxer := new(csms.SSMuxer)
+	secMuxer.AddTransport(secio.ID, &secio.Transport{LocalID: pid, PrivateKey: priv})
+	stMuxer := msmux.NewBlankTransport()
+	stMuxer.AddTransport("/yamux/1.0.0", yamux.DefaultTransport)
+	if err := n.AddTransport(tcp.NewTCPTransport(&tptu.Upgrader{
+		Secure:  secMuxer,
+		Muxer:   stMuxer,
+		Filters: n.Filters,
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	addr, err := ma.NewMultiaddr("/ip4/127.0.0.1/tcp/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.AddListenAddr(addr); err != nil {
+		t.Fatal(err)
+	}
+
+	props.Host = bhost.New(n)
+	node, err := NewNode(props)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+func connect(t *testing.T, a, b *Node) {
+	if err := a.Connect(context.Background(), peerstore.PeerInfo{ID: b.ID(), Addrs: b.Addrs()}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRequestsResolve(t *testing.T) {
+	reqs := newRequests()
+	ch := reqs.add("1", "a")
+
+	resp := &pb.EchoResponse{MessageData: &pb.MessageData{Id: "1"}}
+	if ok := reqs.resolve("b", resp); ok {
+		t.Error("expected a response from another peer to be dropped")
+	}
+	if ok := reqs.resolve("a", &pb.EchoResponse{MessageData: &pb.MessageData{Id: "2"}}); ok {
+		t.Error("expected a response to an unknown request to be dropped")
+	}
+	if ok := reqs.resolve("a", resp); !ok {
+		t.Fatal("expected the response to be delivered")
+	}
+	if received := <-ch; received != resp {
+		t.Errorf("expected %v; received %v", resp, received)
+	}
+	if ok := reqs.resolve("a", resp); ok {
+		t.Error("expected a duplicate response to be dropped")
+	}
+
+	reqs.add("3", "a")
+	reqs.remove("3")
+	if ok := reqs.resolve("a", &pb.EchoResponse{MessageData: &pb.MessageData{Id: "3"}}); ok {
+		t.Error("expected a response to a removed request to be dropped")
+	}
+}
+
+func TestConcurrentRequests(t *testing.T) {
+	server := newTestNode(t, &Props{
+		GetBlocksFN: func(fromHeight, count uint64) ([][]byte, error) {
+			return [][]byte{[]byte(fmt.Sprint(fromHeight))}, nil
+		},
+	})
+	defer server.Close()
+	client := newTestNode(t, &Props{})
+	defer client.Close()
+	connect(t, client, server)
+
+	// note: the responses to concurrent requests to the same peer must reach the requests they answer
+	var wg sync.WaitGroup
+	for height := uint64(0); height < 32; height++ {
+		wg.Add(1)
+		go func(height uint64) {
+			defer wg.Done()
+
+			blocks, err := client.FetchBlocks(context.Background(), server.ID(), height, 1)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if len(blocks) != 1 || string(blocks[0]) != fmt.Sprint(height) {
+				t.Errorf("expected the block at height %v; received %q", height, blocks)
+			}
+		}(height)
+	}
+	wg.Wait()
+
+	msg, err := client.SendEcho(context.Background(), server.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := fmt.Sprintf("Echo from %s", client.ID()); msg != expected {
+		t.Errorf("expected %q; received %q", expected, msg)
+	}
+
+	if len(client.GetBlocks.requests.pending) != 0 || len(client.Echo.requests.pending) != 0 {
+		t.Error("expected no pending requests")
+	}
+}
+
+func TestRequestCanceled(t *testing.T) {
+	server := newTestNode(t, &Props{})
+	defer server.Close()
+	client := newTestNode(t, &Props{})
+	defer client.Close()
+	connect(t, client, server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.FetchBlocks(ctx, server.ID(), 0, 1); err != context.Canceled {
+		t.Errorf("expected %v; received %v", context.Canceled, err)
+	}
+	if len(client.GetBlocks.requests.pending) != 0 {
+		t.Error("expected the canceled request to be removed")
+	}
+}
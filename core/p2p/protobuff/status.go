@@ -0,0 +1,242 @@
+package protobuff
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	inet "github.com/libp2p/go-libp2p-net"
+	peer "github.com/libp2p/go-libp2p-peer"
+
+	pb "github.com/c3systems/c3-go/core/p2p/protobuff/pb"
+	"github.com/c3systems/c3-go/core/p2p/reputation"
+	protobufCodec "github.com/multiformats/go-multicodec/protobuf"
+)
+
+// pattern: /protocol-name/request-or-response-message/version
+const statusRequest = "/status/statusreq/0.0.1"
+const statusResponse = "/status/statusresp/0.0.1"
+
+// ProtocolVersion is the version of the node's p2p protocols; peers on another version are refused
+const ProtocolVersion = 1
+
+const (
+	// CapabilityHeaders is advertised by peers that serve their head block
+	CapabilityHeaders = "headers"
+	// CapabilityBlocks is advertised by peers that serve mined blocks
+	CapabilityBlocks = "blocks"
+	// CapabilityObjects is advertised by peers that serve objects by cid
+	CapabilityObjects = "objects"
+	// CapabilityMining is advertised by peers that mine blocks
+	CapabilityMining = "mining"
+)
+
+// IncompatibleError is returned when a peer's status doesn't match the local status
+type IncompatibleError struct {
+	Reason string
+}
+
+// Error ...
+func (e *IncompatibleError) Error() string {
+	return fmt.Sprintf("incompatible peer: %s", e.Reason)
+}
+
+func incompatible(format string, args ...interface{}) error {
+	return &IncompatibleError{Reason: fmt.Sprintf(format, args...)}
+}
+
+// PeerStatus is the status the nodes exchange on connect
+type PeerStatus struct {
+	ProtocolVersion uint64
+	ChainID         string
+	GenesisHash     string
+	HeadHeight      uint64
+	Capabilities    []string
+	CodecVersion    uint64 // note: the coder.Code blocks and transactions are serialized with
+}
+
+// Compatible returns an *IncompatibleError when the remote peer's status can't be talked to.
+// note: the head heights and capabilities of compatible peers may differ.
+func (p *PeerStatus) Compatible(remote *PeerStatus) error {
+	if remote == nil {
+		return incompatible("no status")
+	}
+	if remote.ProtocolVersion != p.ProtocolVersion {
+		return incompatible("protocol version %v; expected %v", remote.ProtocolVersion, p.ProtocolVersion)
+	}
+	if remote.CodecVersion != p.CodecVersion {
+		return incompatible("codec version %v; expected %v", remote.CodecVersion, p.CodecVersion)
+	}
+	if remote.ChainID != p.ChainID {
+		return incompatible("chain id %q; expected %q", remote.ChainID, p.ChainID)
+	}
+	if remote.GenesisHash != p.GenesisHash {
+		return incompatible("genesis hash %s; expected %s", remote.GenesisHash, p.GenesisHash)
+	}
+
+	return nil
+}
+
+// HasCapability returns true when the peer advertised the capability
+func (p *PeerStatus) HasCapability(capability string) bool {
+	for _, c := range p.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+
+	return false
+}
+
+// StatusFromRequest ...
+func StatusFromRequest(req *pb.StatusRequest) *PeerStatus {
+	return &PeerStatus{
+		ProtocolVersion: req.ProtocolVersion,
+		ChainID:         req.ChainID,
+		GenesisHash:     req.GenesisHash,
+		HeadHeight:      req.HeadHeight,
+		Capabilities:    req.Capabilities,
+		CodecVersion:    req.CodecVersion,
+	}
+}
+
+// StatusFromResponse ...
+func StatusFromResponse(resp *pb.StatusResponse) *PeerStatus {
+	return &PeerStatus{
+		ProtocolVersion: resp.ProtocolVersion,
+		ChainID:         resp.ChainID,
+		GenesisHash:     resp.GenesisHash,
+		HeadHeight:      resp.HeadHeight,
+		Capabilities:    resp.Capabilities,
+		CodecVersion:    resp.CodecVersion,
+	}
+}
+
+// Status exchanges the nodes' statuses on connect and refuses incompatible peers
+type Status struct {
+	node        *Node     // local host
+	requests    *requests // used to deliver responses to the requests awaiting them
+	getStatusFN func() (*PeerStatus, error)
+}
+
+// NewStatus ...
+func NewStatus(node *Node, getStatusFN func() (*PeerStatus, error)) *Status {
+	st := Status{
+		node:        node,
+		requests:    newRequests(),
+		getStatusFN: getStatusFN,
+	}
+	node.SetStreamHandler(statusRequest, st.onStatusRequest)
+	node.SetStreamHandler(statusResponse, st.onStatusResponse)
+
+	return &st
+}
+
+// remote peer requests handler
+func (st *Status) onStatusRequest(s inet.Stream) {
+	// get request data
+	data := &pb.StatusRequest{}
+	decoder := protobufCodec.Multicodec(nil).Decoder(bufio.NewReader(s))
+	if err := decoder.Decode(data); err != nil {
+		log.Errorf("[p2p] %s", err)
+		st.node.penalize(s.Conn().RemotePeer(), reputation.OffenseUndecodableMessage)
+		return
+	}
+
+	if valid := st.node.authenticateMessage(data, data.MessageData); !valid {
+		log.Error("[p2p] failed to authenticate message")
+		st.node.penalize(s.Conn().RemotePeer(), reputation.OffenseUndecodableMessage)
+		return
+	}
+
+	if st.getStatusFN == nil {
+		log.Error("[p2p] no status fn")
+		return
+	}
+	local, err := st.getStatusFN()
+	if err != nil {
+		log.Errorf("[p2p] err getting status\n%v", err)
+		return
+	}
+
+	resp := &pb.StatusResponse{
+		MessageData:     st.node.NewMessageData(data.MessageData.Id, false),
+		ProtocolVersion: local.ProtocolVersion,
+		ChainID:         local.ChainID,
+		GenesisHash:     local.GenesisHash,
+		HeadHeight:      local.HeadHeight,
+		Capabilities:    local.Capabilities,
+		CodecVersion:    local.CodecVersion,
+	}
+
+	// sign the data
+	signature, err := st.node.signProtoMessage(resp)
+	if err != nil {
+		log.Errorf("[p2p] failed to sign response\n%v", err)
+		return
+	}
+
+	// add the signature to the message
+	resp.MessageData.Sign = string(signature)
+
+	remotePeer := s.Conn().RemotePeer()
+	s, respErr := st.node.NewStream(context.Background(), remotePeer, statusResponse)
+	if respErr != nil {
+		log.Errorf("[p2p] %s", respErr)
+		return
+	}
+
+	// note: the status is sent to incompatible peers too, so they know why they're refused
+	if ok := st.node.sendProtoMessage(resp, s); ok {
+		log.Printf("[p2p] %s: status sent to %s.", s.Conn().LocalPeer().String(), remotePeer.String())
+	}
+
+	if err := local.Compatible(StatusFromRequest(data)); err != nil {
+		log.Warnf("[p2p] refusing peer %s\n%v", remotePeer.Pretty(), err)
+		if err := st.node.Network().ClosePeer(remotePeer); err != nil {
+			log.Errorf("[p2p] err closing incompatible peer's connection\n%v", err)
+		}
+	}
+}
+
+// remote peer response handler
+func (st *Status) onStatusResponse(s inet.Stream) {
+	st.node.onResponse(s, st.requests, &pb.StatusResponse{})
+}
+
+// ExchangeStatus sends the local status to the peer, returning the peer's status.
+// note: the statuses aren't checked for compatibility.
+func (st *Status) ExchangeStatus(ctx context.Context, peerID peer.ID) (*PeerStatus, error) {
+	if st.getStatusFN == nil {
+		return nil, errors.New("no status fn")
+	}
+	local, err := st.getStatusFN()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := st.node.newRequestData()
+	if err != nil {
+		return nil, err
+	}
+
+	req := &pb.StatusRequest{
+		MessageData:     data,
+		ProtocolVersion: local.ProtocolVersion,
+		ChainID:         local.ChainID,
+		GenesisHash:     local.GenesisHash,
+		HeadHeight:      local.HeadHeight,
+		Capabilities:    local.Capabilities,
+		CodecVersion:    local.CodecVersion,
+	}
+
+	resp, err := st.node.sendRequest(ctx, st.requests, peerID, statusRequest, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return StatusFromResponse(resp.(*pb.StatusResponse)), nil
+}
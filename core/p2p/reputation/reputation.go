@@ -0,0 +1,286 @@
+package reputation
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	peer "github.com/libp2p/go-libp2p-peer"
+	log "github.com/sirupsen/logrus"
+)
+
+// Offense is a misbehavior that lowers a peer's score
+type Offense int
+
+const (
+	// OffenseInvalidBlock is a mined block that fails verification
+	OffenseInvalidBlock Offense = iota
+	// OffenseInvalidTransaction is a transaction that fails verification
+	OffenseInvalidTransaction
+	// OffenseUndecodableMessage is a message that can't be decoded or authenticated
+	OffenseUndecodableMessage
+	// OffenseTimeout is a request the peer didn't respond to in time
+	OffenseTimeout
+)
+
+// penalties are the points each offense costs
+var penalties = map[Offense]int{
+	OffenseInvalidBlock:       50,
+	OffenseInvalidTransaction: 10,
+	OffenseUndecodableMessage: 20,
+	OffenseTimeout:            5,
+}
+
+// String ...
+func (o Offense) String() string {
+	switch o {
+	case OffenseInvalidBlock:
+		return "invalid block"
+	case OffenseInvalidTransaction:
+		return "invalid transaction"
+	case OffenseUndecodableMessage:
+		return "undecodable message"
+	case OffenseTimeout:
+		return "timeout"
+	default:
+		return "unknown offense"
+	}
+}
+
+const (
+	// DefaultThreshold is the score at or below which a peer is banned
+	DefaultThreshold = -100
+	// DefaultBanDuration ...
+	DefaultBanDuration = time.Hour
+	// DefaultRecoveryInterval is how often a penalized peer's score recovers by a point
+	DefaultRecoveryInterval = time.Minute
+)
+
+// Props ...
+type Props struct {
+	BansPath         string          // note: optional; the file the bans are persisted to
+	Threshold        int             // note: defaults to DefaultThreshold
+	BanDuration      time.Duration   // note: defaults to DefaultBanDuration
+	RecoveryInterval time.Duration   // note: defaults to DefaultRecoveryInterval
+	DisconnectFN     func(p peer.ID) // note: optional; called when a peer is banned
+}
+
+type score struct {
+	points  int
+	updated time.Time
+}
+
+// Manager scores the peers on their behavior and temporarily bans the ones that misbehave.
+// It implements the pubsub Blacklist, so the messages of banned peers are dropped.
+type Manager struct {
+	props  Props
+	mut    sync.Mutex
+	scores map[peer.ID]*score
+	bans   map[peer.ID]time.Time // note: peer id to ban expiry
+	now    func() time.Time
+}
+
+// New builds the manager, loading the persisted bans
+func New(props *Props) (*Manager, error) {
+	if props == nil {
+		return nil, errors.New("props are required")
+	}
+
+	p := *props
+	if p.Threshold == 0 {
+		p.Threshold = DefaultThreshold
+	}
+	if p.BanDuration == 0 {
+		p.BanDuration = DefaultBanDuration
+	}
+	if p.RecoveryInterval == 0 {
+		p.RecoveryInterval = DefaultRecoveryInterval
+	}
+
+	m := &Manager{
+		props:  p,
+		scores: make(map[peer.ID]*score),
+		bans:   make(map[peer.ID]time.Time),
+		now:    time.Now,
+	}
+	if err := m.load(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Penalize lowers the peer's score for the offense, banning the peer if the score falls to the threshold.
+// It returns whether the peer is banned.
+func (m *Manager) Penalize(p peer.ID, offense Offense) bool {
+	m.mut.Lock()
+	points := m.recover(p) - penalties[offense]
+	m.scores[p] = &score{points: points, updated: m.now()}
+	m.mut.Unlock()
+
+	log.Warnf("[reputation] peer %s penalized for %s; score %v", p.Pretty(), offense, points)
+	if points > m.props.Threshold {
+		return false
+	}
+
+	m.Ban(p, m.props.BanDuration)
+	return true
+}
+
+// Score is the peer's current score; 0 is neutral
+func (m *Manager) Score(p peer.ID) int {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	return m.recover(p)
+}
+
+// Ban bans the peer for the duration, disconnecting it
+func (m *Manager) Ban(p peer.ID, d time.Duration) {
+	m.mut.Lock()
+	m.bans[p] = m.now().Add(d)
+	delete(m.scores, p)
+	err := m.save()
+	m.mut.Unlock()
+
+	if err != nil {
+		log.Errorf("[reputation] err persisting bans\n%v", err)
+	}
+
+	log.Warnf("[reputation] banned peer %s for %v", p.Pretty(), d)
+	if m.props.DisconnectFN != nil {
+		m.props.DisconnectFN(p)
+	}
+}
+
+// Unban lifts the peer's ban
+func (m *Manager) Unban(p peer.ID) error {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	delete(m.bans, p)
+	return m.save()
+}
+
+// Banned returns whether the peer is banned
+func (m *Manager) Banned(p peer.ID) bool {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	expiry, ok := m.bans[p]
+	if !ok {
+		return false
+	}
+	if m.now().Before(expiry) {
+		return true
+	}
+
+	delete(m.bans, p)
+	return false
+}
+
+// Bans are the banned peers and the expiry of their bans
+func (m *Manager) Bans() map[peer.ID]time.Time {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	bans := make(map[peer.ID]time.Time)
+	for p, expiry := range m.bans {
+		if m.now().Before(expiry) {
+			bans[p] = expiry
+		}
+	}
+
+	return bans
+}
+
+// Add bans the peer for the ban duration
+// note: implements the pubsub Blacklist
+func (m *Manager) Add(p peer.ID) {
+	m.Ban(p, m.props.BanDuration)
+}
+
+// Contains ...
+// note: implements the pubsub Blacklist
+func (m *Manager) Contains(p peer.ID) bool {
+	return m.Banned(p)
+}
+
+// recover returns the peer's points after recovering a point per recovery interval since the last offense.
+// note: the mutex must be held
+func (m *Manager) recover(p peer.ID) int {
+	s, ok := m.scores[p]
+	if !ok {
+		return 0
+	}
+
+	points := s.points + int(m.now().Sub(s.updated)/m.props.RecoveryInterval)
+	if points >= 0 {
+		delete(m.scores, p)
+		return 0
+	}
+
+	return points
+}
+
+// bansFile is the persisted format of the bans: base58 peer ids to the unix expiry of their bans
+type bansFile map[string]int64
+
+// load reads the unexpired bans from the bans path
+func (m *Manager) load() error {
+	if m.props.BansPath == "" {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(m.props.BansPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var bans bansFile
+	if err := json.Unmarshal(data, &bans); err != nil {
+		return err
+	}
+
+	for id, expiry := range bans {
+		p, err := peer.IDB58Decode(id)
+		if err != nil {
+			log.Warnf("[reputation] err decoding banned peer %s\n%v", id, err)
+			continue
+		}
+
+		if t := time.Unix(expiry, 0); m.now().Before(t) {
+			m.bans[p] = t
+		}
+	}
+
+	return nil
+}
+
+// save writes the unexpired bans to the bans path
+// note: the mutex must be held
+func (m *Manager) save() error {
+	if m.props.BansPath == "" {
+		return nil
+	}
+
+	bans := make(bansFile)
+	for p, expiry := range m.bans {
+		if m.now().Before(expiry) {
+			bans[peer.IDB58Encode(p)] = expiry.Unix()
+		}
+	}
+
+	data, err := json.Marshal(bans)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(m.props.BansPath, data, 0644)
+}
@@ -0,0 +1,107 @@
+// +build unit
+
+package reputation
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	peer "github.com/libp2p/go-libp2p-peer"
+)
+
+func TestPenalize(t *testing.T) {
+	var disconnected []peer.ID
+	m, err := New(&Props{
+		DisconnectFN: func(p peer.ID) {
+			disconnected = append(disconnected, p)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	p := peer.ID("foo")
+	if m.Penalize(p, OffenseInvalidBlock) {
+		t.Fatal("expected the peer not to be banned after one offense")
+	}
+	if m.Score(p) != -50 {
+		t.Errorf("expected score -50; received %v", m.Score(p))
+	}
+
+	// note: the score recovers a point per interval
+	now = now.Add(10 * DefaultRecoveryInterval)
+	if m.Score(p) != -40 {
+		t.Errorf("expected score -40; received %v", m.Score(p))
+	}
+
+	if m.Penalize(p, OffenseInvalidBlock) {
+		t.Fatal("expected the peer not to be banned above the threshold")
+	}
+	if !m.Penalize(p, OffenseInvalidBlock) {
+		t.Fatal("expected the peer to be banned")
+	}
+	if !m.Banned(p) || !m.Contains(p) {
+		t.Error("expected the peer to be banned")
+	}
+	if len(disconnected) != 1 || disconnected[0] != p {
+		t.Errorf("expected the peer to be disconnected; received %v", disconnected)
+	}
+
+	now = now.Add(DefaultBanDuration)
+	if m.Banned(p) {
+		t.Error("expected the ban to expire")
+	}
+}
+
+func TestPersistBans(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reputation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "bans.json")
+	m, err := New(&Props{BansPath: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	banned, err := peer.IDB58Decode("QmcgpsyWgH8Y8ajJz1Cu72KnS5uo2Aa2LpzU7kinSupNKC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := peer.IDB58Decode("QmSoLPppuBtQSGwKDZT2M73ULpjvfd3aZ6ha4oFGL1KrGM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Ban(banned, time.Hour)
+	m.Ban(expired, -time.Second)
+
+	m, err = New(&Props{BansPath: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.Banned(banned) {
+		t.Error("expected the ban to be restored")
+	}
+	if m.Banned(expired) {
+		t.Error("expected the expired ban not to be restored")
+	}
+
+	if err := m.Unban(banned); err != nil {
+		t.Fatal(err)
+	}
+	m, err = New(&Props{BansPath: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Banned(banned) {
+		t.Error("expected the lifted ban not to be restored")
+	}
+}
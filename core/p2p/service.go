@@ -161,6 +161,20 @@ func (s Service) GetBytes(c *cid.Cid) ([]byte, error) {
 	return FetchBytes(s.peersOrLocal, c)
 }
 
+// GetLocalBytes gets the raw data of the cid from the local block store only, without asking peers
+func (s Service) GetLocalBytes(c *cid.Cid) ([]byte, error) {
+	if c == nil {
+		return nil, errors.New("cid cannot be nil")
+	}
+
+	data, err := s.local.Get(*c)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.RawData(), nil
+}
+
 // GetLatestBlock ...
 func (s Service) GetLatestBlock() (*mainchain.Block, error) {
 	c, err := GetBytesCID(latestMainchainBlockKey)
@@ -0,0 +1,231 @@
+package txrelay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/c3systems/c3-go/common/hexutil"
+	"github.com/c3systems/c3-go/core/chain/statechain"
+	"github.com/c3systems/c3-go/core/miner"
+	"github.com/c3systems/c3-go/core/p2p/reputation"
+
+	lru "github.com/hashicorp/golang-lru"
+	peer "github.com/libp2p/go-libp2p-peer"
+	floodsub "github.com/libp2p/go-libp2p-pubsub"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultSeenCacheSize is the number of recently seen tx hashes kept to drop duplicate announcements
+	DefaultSeenCacheSize = 16384
+	// DefaultTxCacheSize is the number of recently seen txs kept to serve the peers fetching them
+	DefaultTxCacheSize = 1024
+)
+
+var (
+	// ErrNilTx is returned when relaying a nil tx or a tx without a hash
+	ErrNilTx = errors.New("nil tx or tx hash")
+	// ErrTxNotServed is returned when the announcing peer doesn't serve the announced tx
+	ErrTxNotServed = errors.New("peer didn't serve the announced tx")
+	// ErrInvalidTx is returned when the announcing peer serves an invalid tx
+	ErrInvalidTx = errors.New("peer served an invalid tx")
+)
+
+// Props ...
+type Props struct {
+	Self          peer.ID
+	FetchFN       func(ctx context.Context, peerID peer.ID, hashes []string) ([][]byte, error) // note: fetches the serialized txs from the peer
+	GetTxFN       func(hash string) (*statechain.Transaction, error)                         // note: optional; looks up the txs that aren't cached, e.g. in the mempool
+	PenalizeFN    func(peerID peer.ID, offense reputation.Offense)                           // note: optional; called when a peer announces a malformed hash or serves an invalid tx
+	SeenCacheSize int                                                                        // note: defaults to DefaultSeenCacheSize
+	TxCacheSize   int                                                                        // note: defaults to DefaultTxCacheSize
+}
+
+// Relay gossips transactions by hash instead of in full.
+// A peer fetches each announced tx it hasn't seen from the peer that forwarded the announcement, and only then forwards the announcement itself.
+// So the peers receiving an announcement can always fetch the tx from the peer it came from, and every tx crosses each link at most once.
+type Relay struct {
+	props Props
+	seen  *lru.Cache // note: the hashes of the txs the node has or is fetching
+	txs   *lru.Cache // note: the serialized txs, by hash
+}
+
+// New ...
+func New(props *Props) (*Relay, error) {
+	if props == nil {
+		return nil, errors.New("props are required")
+	}
+	if props.FetchFN == nil {
+		return nil, errors.New("fetch fn is required")
+	}
+
+	p := *props
+	if p.SeenCacheSize == 0 {
+		p.SeenCacheSize = DefaultSeenCacheSize
+	}
+	if p.TxCacheSize == 0 {
+		p.TxCacheSize = DefaultTxCacheSize
+	}
+
+	seen, err := lru.New(p.SeenCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	txs, err := lru.New(p.TxCacheSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Relay{
+		props: p,
+		seen:  seen,
+		txs:   txs,
+	}, nil
+}
+
+// Add marks the tx as seen and keeps it to serve the peers fetching it.
+// It returns false when the tx was already seen, in which case it needn't be announced again.
+func (r *Relay) Add(tx *statechain.Transaction) (bool, error) {
+	if tx == nil || tx.Props().TxHash == nil {
+		return false, ErrNilTx
+	}
+
+	data, err := tx.Serialize()
+	if err != nil {
+		return false, err
+	}
+
+	hash := *tx.Props().TxHash
+	seen, _ := r.seen.ContainsOrAdd(hash, struct{}{})
+	r.txs.Add(hash, data)
+
+	return !seen, nil
+}
+
+// Seen returns true when the tx was recently added or fetched
+func (r *Relay) Seen(hash string) bool {
+	return r.seen.Contains(hash)
+}
+
+// Transaction returns the cached tx of the hash, or nil when it isn't cached
+func (r *Relay) Transaction(hash string) (*statechain.Transaction, error) {
+	data, ok := r.txs.Get(hash)
+	if !ok {
+		return nil, nil
+	}
+
+	tx := new(statechain.Transaction)
+	if err := tx.Deserialize(data.([]byte)); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// Transactions returns the serialized txs of the hashes, omitting the ones the node doesn't have.
+// note: serves the get transactions protocol
+func (r *Relay) Transactions(hashes []string) ([][]byte, error) {
+	var txs [][]byte
+	for _, hash := range hashes {
+		if data, ok := r.txs.Get(hash); ok {
+			txs = append(txs, data.([]byte))
+			continue
+		}
+		if r.props.GetTxFN == nil {
+			continue
+		}
+
+		tx, err := r.props.GetTxFN(hash)
+		if err != nil {
+			log.Warnf("[txrelay] err getting tx %s\n%v", hash, err)
+			continue
+		}
+		if tx == nil {
+			continue
+		}
+
+		data, err := tx.Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		txs = append(txs, data)
+	}
+
+	return txs, nil
+}
+
+// Validate validates a tx announcement, fetching the tx from the peer that forwarded it when it wasn't seen.
+// Announcements of seen txs are dropped, so they aren't forwarded again.
+// note: implements the pubsub topic validator
+func (r *Relay) Validate(ctx context.Context, from peer.ID, msg *floodsub.Message) bool {
+	hash := string(msg.GetData())
+	if !IsTxHash(hash) {
+		log.Warnf("[txrelay] dropping malformed tx announcement from %s", from.Pretty())
+		r.penalize(from, reputation.OffenseUndecodableMessage)
+		return false
+	}
+
+	// note: the node's own announcements are of txs it added
+	if from == r.props.Self {
+		return true
+	}
+
+	// note: marked seen while fetching, so the concurrent announcements of the tx are dropped
+	if seen, _ := r.seen.ContainsOrAdd(hash, struct{}{}); seen {
+		return false
+	}
+
+	data, err := r.fetch(ctx, from, hash)
+	if err != nil {
+		// note: forgotten, so the tx is fetched when another peer announces it
+		r.seen.Remove(hash)
+		log.Warnf("[txrelay] err fetching tx %s from %s\n%v", hash, from.Pretty(), err)
+		return false
+	}
+
+	r.txs.Add(hash, data)
+	return true
+}
+
+// fetch fetches the tx of the hash from the peer and verifies it
+func (r *Relay) fetch(ctx context.Context, peerID peer.ID, hash string) ([]byte, error) {
+	txs, err := r.props.FetchFN(ctx, peerID, []string{hash})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, data := range txs {
+		tx := new(statechain.Transaction)
+		if err := tx.Deserialize(data); err != nil {
+			r.penalize(peerID, reputation.OffenseUndecodableMessage)
+			return nil, fmt.Errorf("err deserializing tx\n%v", err)
+		}
+		if tx.Props().TxHash == nil || *tx.Props().TxHash != hash {
+			continue
+		}
+
+		ok, err := miner.VerifyTransaction(tx)
+		if err != nil || !ok {
+			r.penalize(peerID, reputation.OffenseInvalidTransaction)
+			return nil, ErrInvalidTx
+		}
+
+		return data, nil
+	}
+
+	return nil, ErrTxNotServed
+}
+
+func (r *Relay) penalize(peerID peer.ID, offense reputation.Offense) {
+	if r.props.PenalizeFN != nil {
+		r.props.PenalizeFN(peerID, offense)
+	}
+}
+
+// IsTxHash returns true when the string is a hex encoded tx hash
+func IsTxHash(hash string) bool {
+	b, err := hexutil.DecodeString(hash)
+	return err == nil && len(b) == 32
+}
@@ -0,0 +1,289 @@
+// +build unit
+
+package txrelay
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/c3systems/c3-go/common/c3crypto"
+	"github.com/c3systems/c3-go/core/chain/statechain"
+	"github.com/c3systems/c3-go/core/p2p/reputation"
+
+	peer "github.com/libp2p/go-libp2p-peer"
+	floodsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
+)
+
+func newAnnouncement(hash string) *floodsub.Message {
+	return &floodsub.Message{Message: &pubsubpb.Message{Data: []byte(hash)}}
+}
+
+func buildTx(t *testing.T, payload string, sign bool) *statechain.Transaction {
+	priv, pub, err := c3crypto.NewKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := c3crypto.EncodeAddress(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := statechain.NewTransaction(&statechain.TransactionProps{
+		ImageHash: "foo",
+		Method:    "c3_invokeMethod",
+		Payload:   []byte(payload),
+		From:      addr,
+	})
+	if err := tx.SetHash(); err != nil {
+		t.Fatal(err)
+	}
+	if sign {
+		if err := tx.Sign(priv); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !IsTxHash(*tx.Props().TxHash) {
+		t.Fatalf("expected %s to be a tx hash", *tx.Props().TxHash)
+	}
+
+	return tx
+}
+
+// testPeer serves its txs to the relay and records the fetches and penalties
+type testPeer struct {
+	txs       map[string][]byte
+	fetches   int
+	penalties map[peer.ID][]reputation.Offense
+}
+
+func newTestPeer(t *testing.T, txs ...*statechain.Transaction) *testPeer {
+	p := &testPeer{
+		txs:       make(map[string][]byte),
+		penalties: make(map[peer.ID][]reputation.Offense),
+	}
+	for _, tx := range txs {
+		data, err := tx.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		p.txs[*tx.Props().TxHash] = data
+	}
+
+	return p
+}
+
+func (p *testPeer) fetch(ctx context.Context, peerID peer.ID, hashes []string) ([][]byte, error) {
+	p.fetches++
+
+	var txs [][]byte
+	for _, hash := range hashes {
+		if data, ok := p.txs[hash]; ok {
+			txs = append(txs, data)
+		}
+	}
+
+	return txs, nil
+}
+
+func (p *testPeer) penalize(peerID peer.ID, offense reputation.Offense) {
+	p.penalties[peerID] = append(p.penalties[peerID], offense)
+}
+
+func newTestRelay(t *testing.T, p *testPeer, getTxFN func(hash string) (*statechain.Transaction, error)) *Relay {
+	r, err := New(&Props{
+		Self:       "self",
+		FetchFN:    p.fetch,
+		GetTxFN:    getTxFN,
+		PenalizeFN: p.penalize,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return r
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(nil); err == nil {
+		t.Error("expected an err without props")
+	}
+	if _, err := New(&Props{}); err == nil {
+		t.Error("expected an err without a fetch fn")
+	}
+}
+
+func TestAdd(t *testing.T) {
+	tx := buildTx(t, "bar", true)
+	r := newTestRelay(t, newTestPeer(t), nil)
+
+	isNew, err := r.Add(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isNew {
+		t.Error("expected the tx to be new")
+	}
+	if !r.Seen(*tx.Props().TxHash) {
+		t.Error("expected the tx to be seen")
+	}
+
+	isNew, err = r.Add(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isNew {
+		t.Error("expected the duplicate tx not to be new")
+	}
+
+	cached, err := r.Transaction(*tx.Props().TxHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached == nil || *cached.Props().TxHash != *tx.Props().TxHash {
+		t.Errorf("expected the cached tx %s; received %v", *tx.Props().TxHash, cached)
+	}
+
+	if _, err := r.Add(nil); err != ErrNilTx {
+		t.Errorf("expected %v; received %v", ErrNilTx, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tx := buildTx(t, "bar", true)
+	hash := *tx.Props().TxHash
+	p := newTestPeer(t, tx)
+	r := newTestRelay(t, p, nil)
+
+	if !r.Validate(context.Background(), "self", newAnnouncement(hash)) {
+		t.Error("expected the node's own announcement to be valid")
+	}
+	if p.fetches != 0 {
+		t.Error("expected the node's own tx not to be fetched")
+	}
+
+	if !r.Validate(context.Background(), "a", newAnnouncement(hash)) {
+		t.Fatal("expected the announcement of the unseen tx to be valid")
+	}
+	if p.fetches != 1 {
+		t.Errorf("expected 1 fetch; received %v", p.fetches)
+	}
+	if cached, err := r.Transaction(hash); err != nil || cached == nil {
+		t.Errorf("expected the fetched tx to be cached; received %v, %v", cached, err)
+	}
+
+	// note: the duplicate announcement is dropped, so it isn't forwarded again
+	if r.Validate(context.Background(), "b", newAnnouncement(hash)) {
+		t.Error("expected the announcement of the seen tx to be dropped")
+	}
+	if p.fetches != 1 {
+		t.Error("expected the seen tx not to be fetched again")
+	}
+	if len(p.penalties) != 0 {
+		t.Errorf("expected no penalties; received %v", p.penalties)
+	}
+
+	if r.Validate(context.Background(), "c", newAnnouncement("foo")) {
+		t.Error("expected the malformed announcement to be dropped")
+	}
+	if offenses := p.penalties["c"]; len(offenses) != 1 || offenses[0] != reputation.OffenseUndecodableMessage {
+		t.Errorf("expected the peer to be penalized for an undecodable message; received %v", offenses)
+	}
+}
+
+func TestValidateNotServed(t *testing.T) {
+	tx := buildTx(t, "bar", true)
+	hash := *tx.Props().TxHash
+	p := newTestPeer(t)
+	r := newTestRelay(t, p, nil)
+
+	if r.Validate(context.Background(), "a", newAnnouncement(hash)) {
+		t.Error("expected the announcement of the tx that isn't served to be dropped")
+	}
+	if r.Seen(hash) {
+		t.Error("expected the tx that wasn't fetched to be forgotten")
+	}
+
+	// note: fetched from the next peer announcing it
+	p.txs = newTestPeer(t, tx).txs
+	if !r.Validate(context.Background(), "b", newAnnouncement(hash)) {
+		t.Error("expected the announcement to be valid once the tx is served")
+	}
+	if p.fetches != 2 {
+		t.Errorf("expected 2 fetches; received %v", p.fetches)
+	}
+}
+
+func TestValidateFetchErr(t *testing.T) {
+	hash := *buildTx(t, "bar", true).Props().TxHash
+	r, err := New(&Props{
+		Self: "self",
+		FetchFN: func(ctx context.Context, peerID peer.ID, hashes []string) ([][]byte, error) {
+			return nil, errors.New("foo")
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Validate(context.Background(), "a", newAnnouncement(hash)) {
+		t.Error("expected the announcement to be dropped when the fetch fails")
+	}
+	if r.Seen(hash) {
+		t.Error("expected the tx that wasn't fetched to be forgotten")
+	}
+}
+
+func TestValidateInvalidTx(t *testing.T) {
+	tx := buildTx(t, "bar", false)
+	hash := *tx.Props().TxHash
+	p := newTestPeer(t, tx)
+	r := newTestRelay(t, p, nil)
+
+	if r.Validate(context.Background(), "a", newAnnouncement(hash)) {
+		t.Error("expected the announcement of the unsigned tx to be dropped")
+	}
+	if offenses := p.penalties["a"]; len(offenses) != 1 || offenses[0] != reputation.OffenseInvalidTransaction {
+		t.Errorf("expected the peer to be penalized for an invalid tx; received %v", offenses)
+	}
+	if cached, _ := r.Transaction(hash); cached != nil {
+		t.Error("expected the invalid tx not to be cached")
+	}
+}
+
+func TestTransactions(t *testing.T) {
+	cached := buildTx(t, "foo", true)
+	pending := buildTx(t, "bar", true)
+	missing := buildTx(t, "baz", true)
+
+	r := newTestRelay(t, newTestPeer(t), func(hash string) (*statechain.Transaction, error) {
+		if hash == *pending.Props().TxHash {
+			return pending, nil
+		}
+
+		return nil, nil
+	})
+	if _, err := r.Add(cached); err != nil {
+		t.Fatal(err)
+	}
+
+	txs, err := r.Transactions([]string{*cached.Props().TxHash, *missing.Props().TxHash, *pending.Props().TxHash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("expected 2 txs; received %v", len(txs))
+	}
+
+	for i, expected := range []*statechain.Transaction{cached, pending} {
+		tx := new(statechain.Transaction)
+		if err := tx.Deserialize(txs[i]); err != nil {
+			t.Fatal(err)
+		}
+		if *tx.Props().TxHash != *expected.Props().TxHash {
+			t.Errorf("expected tx %s; received %s", *expected.Props().TxHash, *tx.Props().TxHash)
+		}
+	}
+}
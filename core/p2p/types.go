@@ -1,10 +1,14 @@
 package p2p
 
 import (
+	"context"
 	"sync"
 
-	bstore "github.com/ipfs/go-ipfs-blockstore"
+	"github.com/c3systems/c3-go/state"
+	"github.com/c3systems/c3-go/trie"
+
 	bserv "github.com/ipfs/go-blockservice"
+	bstore "github.com/ipfs/go-ipfs-blockstore"
 	host "github.com/libp2p/go-libp2p-host"
 	routing "github.com/libp2p/go-libp2p-routing"
 	mh "github.com/multiformats/go-multihash"
@@ -25,6 +29,13 @@ type Props struct {
 	BlockStore bstore.Blockstore
 	Host       host.Host
 	Router     routing.ContentRouting
+	// StateDB is the trie db of the dApp states; it's optional and should store its nodes in the BlockStore so peers can fetch them
+	StateDB trie.Database
+	// StateCache is the cache of the reconstructed dApp states; it's optional
+	StateCache *state.Cache
+	// FetchObjectsFN fetches the objects of the cids from peers in a batch, keyed by cid; it's optional.
+	// note: the objects are checked against their cids
+	FetchObjectsFN func(ctx context.Context, cids []string) (map[string][]byte, error)
 }
 
 // Service ...
@@ -0,0 +1,71 @@
+package wstransport
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+
+	ma "github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr-net"
+)
+
+// Addr is the net.Addr of a websocket conn
+type Addr struct {
+	*url.URL
+}
+
+var _ net.Addr = (*Addr)(nil)
+
+// Network returns the network type of websocket addrs, "websocket"
+func (addr *Addr) Network() string {
+	return "websocket"
+}
+
+// NewAddr builds the websocket addr of the host
+func NewAddr(host string) *Addr {
+	return &Addr{
+		URL: &url.URL{
+			Host: host,
+		},
+	}
+}
+
+// ConvertWebsocketMultiaddrToNetAddr converts a /tcp/.../ws multiaddr to a websocket addr
+func ConvertWebsocketMultiaddrToNetAddr(maddr ma.Multiaddr) (net.Addr, error) {
+	_, host, err := manet.DialArgs(maddr.Decapsulate(wsMultiaddr))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAddr(host), nil
+}
+
+// ParseWebsocketNetAddr converts a websocket addr to a /tcp/.../ws multiaddr
+func ParseWebsocketNetAddr(a net.Addr) (ma.Multiaddr, error) {
+	wsa, ok := a.(*Addr)
+	if !ok {
+		return nil, fmt.Errorf("not a websocket address")
+	}
+
+	tcpaddr, err := net.ResolveTCPAddr("tcp", wsa.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	tcpma, err := manet.FromNetAddr(tcpaddr)
+	if err != nil {
+		return nil, err
+	}
+
+	return tcpma.Encapsulate(wsMultiaddr), nil
+}
+
+// parseMultiaddr returns the ws url of the multiaddr
+func parseMultiaddr(a ma.Multiaddr) (string, error) {
+	_, host, err := manet.DialArgs(a.Decapsulate(wsMultiaddr))
+	if err != nil {
+		return "", err
+	}
+
+	return "ws://" + host, nil
+}
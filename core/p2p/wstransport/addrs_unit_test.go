@@ -0,0 +1,82 @@
+// +build unit
+
+package wstransport
+
+import (
+	"net/url"
+	"testing"
+
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+type httpAddr struct {
+	*url.URL
+}
+
+func (addr *httpAddr) Network() string {
+	return "http"
+}
+
+func TestParseMultiaddr(t *testing.T) {
+	addr, err := ma.NewMultiaddr("/ip4/127.0.0.1/tcp/5555/ws")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wsurl, err := parseMultiaddr(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wsurl != "ws://127.0.0.1:5555" {
+		t.Errorf("expected ws://127.0.0.1:5555; received %s", wsurl)
+	}
+}
+
+func TestParseWebsocketNetAddr(t *testing.T) {
+	if _, err := ParseWebsocketNetAddr(&httpAddr{&url.URL{Host: "127.0.0.1:1234"}}); err == nil {
+		t.Error("expected an err parsing an http addr")
+	}
+
+	parsed, err := ParseWebsocketNetAddr(NewAddr("127.0.0.1:5555"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.String() != "/ip4/127.0.0.1/tcp/5555/ws" {
+		t.Errorf("expected /ip4/127.0.0.1/tcp/5555/ws; received %s", parsed)
+	}
+}
+
+func TestConvertWebsocketMultiaddrToNetAddr(t *testing.T) {
+	addr, err := ma.NewMultiaddr("/ip4/127.0.0.1/tcp/5555/ws")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wsaddr, err := ConvertWebsocketMultiaddrToNetAddr(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wsaddr.String() != "//127.0.0.1:5555" {
+		t.Errorf("expected //127.0.0.1:5555; received %s", wsaddr)
+	}
+	if wsaddr.Network() != "websocket" {
+		t.Errorf("expected the websocket network; received %s", wsaddr.Network())
+	}
+}
+
+func TestCanDial(t *testing.T) {
+	tpt := New(nil)
+	for addr, expected := range map[string]bool{
+		"/ip4/127.0.0.1/tcp/5555/ws": true,
+		"/ip4/127.0.0.1/tcp/5555":    false,
+		"/ip4/127.0.0.1/udp/5555":    false,
+	} {
+		maddr, err := ma.NewMultiaddr(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok := tpt.CanDial(maddr); ok != expected {
+			t.Errorf("%s: expected %v; received %v", addr, expected, ok)
+		}
+	}
+}
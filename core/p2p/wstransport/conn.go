@@ -0,0 +1,55 @@
+package wstransport
+
+import (
+	"net"
+	"sync"
+
+	"golang.org/x/net/websocket"
+)
+
+var _ net.Conn = (*Conn)(nil)
+
+// Conn is a websocket conn sending binary frames.
+// note: the addrs are the tcp conn's, since the websocket library reports the origin as the remote addr of server conns.
+type Conn struct {
+	*websocket.Conn
+
+	localAddr  net.Addr
+	remoteAddr net.Addr
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// NewConn wraps the websocket conn, which runs over a tcp conn between the addrs
+func NewConn(raw *websocket.Conn, localAddr, remoteAddr net.Addr) *Conn {
+	raw.PayloadType = websocket.BinaryFrame
+
+	return &Conn{
+		Conn:       raw,
+		localAddr:  NewAddr(localAddr.String()),
+		remoteAddr: NewAddr(remoteAddr.String()),
+		closed:     make(chan struct{}),
+	}
+}
+
+// Close closes the conn. Only the first call receives the close error.
+func (c *Conn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		err = c.Conn.Close()
+		close(c.closed)
+	})
+
+	return err
+}
+
+// LocalAddr ...
+func (c *Conn) LocalAddr() net.Addr {
+	return c.localAddr
+}
+
+// RemoteAddr ...
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
@@ -0,0 +1,74 @@
+package wstransport
+
+import (
+	"errors"
+	"net"
+	"net/http"
+
+	ma "github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr-net"
+	"golang.org/x/net/websocket"
+)
+
+// ErrListenerClosed is returned when accepting on a closed listener
+var ErrListenerClosed = errors.New("listener is closed")
+
+type listener struct {
+	net.Listener
+
+	laddr ma.Multiaddr
+
+	closed   chan struct{}
+	incoming chan *Conn
+}
+
+func (l *listener) serve() {
+	defer close(l.closed)
+
+	server := &websocket.Server{
+		// note: conns from every origin are accepted, so browsers can dial the node
+		Handshake: func(config *websocket.Config, req *http.Request) error {
+			return nil
+		},
+		Handler: l.handle,
+	}
+	_ = http.Serve(l.Listener, server)
+}
+
+// handle hands the conn to Accept.
+// note: the websocket library closes the conn once the handler returns, so it waits for the conn to close.
+func (l *listener) handle(raw *websocket.Conn) {
+	remoteAddr, err := net.ResolveTCPAddr("tcp", raw.Request().RemoteAddr)
+	if err != nil {
+		return
+	}
+
+	c := NewConn(raw, l.Addr(), remoteAddr)
+	select {
+	case l.incoming <- c:
+	case <-l.closed:
+		c.Close()
+		return
+	}
+
+	<-c.closed
+}
+
+func (l *listener) Accept() (manet.Conn, error) {
+	select {
+	case c := <-l.incoming:
+		mnc, err := manet.WrapNetConn(c)
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+
+		return mnc, nil
+	case <-l.closed:
+		return nil, ErrListenerClosed
+	}
+}
+
+func (l *listener) Multiaddr() ma.Multiaddr {
+	return l.laddr
+}
@@ -0,0 +1,167 @@
+// Package wstransport is a websocket transport for libp2p, so browsers and peers behind http-only firewalls can dial the node.
+// note: it's a port of github.com/libp2p/go-ws-transport to the libp2p interfaces vendored here, over golang.org/x/net/websocket.
+package wstransport
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	peer "github.com/libp2p/go-libp2p-peer"
+	transport "github.com/libp2p/go-libp2p-transport"
+	tptu "github.com/libp2p/go-libp2p-transport-upgrader"
+	ma "github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr-net"
+	mafmt "github.com/whyrusleeping/mafmt"
+	"golang.org/x/net/websocket"
+)
+
+// WsProtocol is the multiaddr protocol of the transport
+var WsProtocol = ma.Protocol{
+	Code:  477,
+	Name:  "ws",
+	VCode: ma.CodeToVarint(477),
+}
+
+// WsFmt matches the multiaddrs the transport dials, /ip4/.../tcp/.../ws
+var WsFmt = mafmt.And(mafmt.TCP, mafmt.Base(WsProtocol.Code))
+
+// WsCodec converts between websocket multiaddrs and net addrs
+var WsCodec = &manet.NetCodec{
+	NetAddrNetworks:  []string{"websocket"},
+	ProtocolName:     "ws",
+	ConvertMultiaddr: ConvertWebsocketMultiaddrToNetAddr,
+	ParseNetAddr:     ParseWebsocketNetAddr,
+}
+
+var wsMultiaddr ma.Multiaddr
+
+func init() {
+	if err := ma.AddProtocol(WsProtocol); err != nil {
+		panic(fmt.Errorf("error registering websocket protocol: %s", err))
+	}
+
+	manet.RegisterNetCodec(WsCodec)
+
+	var err error
+	if wsMultiaddr, err = ma.NewMultiaddr("/ws"); err != nil {
+		panic(err)
+	}
+}
+
+// Transport is the websocket libp2p transport
+type Transport struct {
+	Upgrader *tptu.Upgrader
+}
+
+var _ transport.Transport = (*Transport)(nil)
+
+// New builds the websocket transport, securing and multiplexing its conns with the upgrader
+func New(upgrader *tptu.Upgrader) *Transport {
+	return &Transport{
+		Upgrader: upgrader,
+	}
+}
+
+// CanDial ...
+func (t *Transport) CanDial(addr ma.Multiaddr) bool {
+	return WsFmt.Matches(addr)
+}
+
+// Protocols ...
+func (t *Transport) Protocols() []int {
+	return []int{WsProtocol.Code}
+}
+
+// Proxy ...
+func (t *Transport) Proxy() bool {
+	return false
+}
+
+func (t *Transport) maDial(ctx context.Context, raddr ma.Multiaddr) (manet.Conn, error) {
+	wsurl, err := parseMultiaddr(raddr)
+	if err != nil {
+		return nil, err
+	}
+	config, err := websocket.NewConfig(wsurl, "http://"+raddr.String())
+	if err != nil {
+		return nil, err
+	}
+
+	var dialer net.Dialer
+	tcpConn, err := dialer.DialContext(ctx, "tcp", config.Location.Host)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		tcpConn.SetDeadline(deadline)
+	}
+
+	raw, err := websocket.NewClient(config, tcpConn)
+	if err != nil {
+		tcpConn.Close()
+		return nil, err
+	}
+	// note: the upgrader sets its own deadlines for the handshakes
+	if err := tcpConn.SetDeadline(time.Time{}); err != nil {
+		raw.Close()
+		return nil, err
+	}
+
+	mnc, err := manet.WrapNetConn(NewConn(raw, tcpConn.LocalAddr(), tcpConn.RemoteAddr()))
+	if err != nil {
+		raw.Close()
+		return nil, err
+	}
+
+	return mnc, nil
+}
+
+// Dial dials the peer at the websocket multiaddr
+func (t *Transport) Dial(ctx context.Context, raddr ma.Multiaddr, p peer.ID) (transport.Conn, error) {
+	macon, err := t.maDial(ctx, raddr)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Upgrader.UpgradeOutbound(ctx, t, macon, p)
+}
+
+func (t *Transport) maListen(a ma.Multiaddr) (*listener, error) {
+	lnet, lnaddr, err := manet.DialArgs(a.Decapsulate(wsMultiaddr))
+	if err != nil {
+		return nil, err
+	}
+
+	nl, err := net.Listen(lnet, lnaddr)
+	if err != nil {
+		return nil, err
+	}
+
+	laddr, err := manet.FromNetAddr(nl.Addr())
+	if err != nil {
+		nl.Close()
+		return nil, err
+	}
+
+	l := &listener{
+		Listener: nl,
+		laddr:    laddr.Encapsulate(wsMultiaddr),
+		incoming: make(chan *Conn),
+		closed:   make(chan struct{}),
+	}
+	go l.serve()
+
+	return l, nil
+}
+
+// Listen listens on the websocket multiaddr
+func (t *Transport) Listen(laddr ma.Multiaddr) (transport.Listener, error) {
+	l, err := t.maListen(laddr)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Upgrader.UpgradeListener(t, l), nil
+}
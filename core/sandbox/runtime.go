@@ -0,0 +1,36 @@
+package sandbox
+
+import (
+	"io"
+)
+
+const (
+	// RuntimeDocker runs dApps as docker containers
+	RuntimeDocker = "docker"
+	// RuntimeProcess runs dApps as local processes; used for development and CI
+	RuntimeProcess = "process"
+)
+
+// Runtime runs dApp instances for the sandbox
+type Runtime interface {
+	// Create prepares an instance of the image and returns the instance ID
+	Create(imageID string, config *InstanceConfig) (string, error)
+	// WriteState writes the initial state file to the instance
+	WriteState(instanceID string, state []byte) error
+	// Start runs the dApp
+	Start(instanceID string) error
+	// ReadState reads the state file back from the instance
+	ReadState(instanceID string) (io.Reader, error)
+	// Stop kills the instance and cleans up after it
+	Stop(instanceID string) error
+	// Commit snapshots the instance into a new image and returns the image ID
+	Commit(instanceID string) (string, error)
+}
+
+// InstanceConfig ...
+type InstanceConfig struct {
+	// SocketDir is the host directory where the dApp opens its IPC socket
+	SocketDir string
+	// HostPort is the host port mapped to the dApp server port, when using the TCP transport
+	HostPort string
+}
@@ -0,0 +1,139 @@
+package sandbox
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	c3config "github.com/c3systems/c3-go/config"
+	"github.com/c3systems/c3-go/core/docker"
+	"github.com/c3systems/c3-go/registry"
+	regutil "github.com/c3systems/c3-go/registry/util"
+)
+
+// Ensure the struct implements the interface
+var _ Runtime = (*DockerRuntime)(nil)
+
+// DockerRuntime runs dApps as docker containers
+type DockerRuntime struct {
+	docker   docker.Interface
+	registry registry.Interface
+}
+
+// NewDockerRuntime ...
+func NewDockerRuntime(dockerSvc docker.Interface, reg registry.Interface) *DockerRuntime {
+	return &DockerRuntime{
+		docker:   dockerSvc,
+		registry: reg,
+	}
+}
+
+// Create pulls the image if required and creates a container for it
+func (r *DockerRuntime) Create(imageID string, config *InstanceConfig) (string, error) {
+	if config == nil {
+		return "", errors.New("config is required")
+	}
+
+	var dockerImageID = imageID
+	var fullDockerImageID = dockerImageID
+
+	// If it's an IPFS hash then pull it from IPFS
+	if strings.HasPrefix(imageID, "Qm") {
+		dockerizedHash := regutil.DockerizeHash(imageID)
+		hasImage, err := r.docker.HasImage(dockerizedHash)
+		if err != nil {
+			log.Printf("[sandbox] error checking if have docker image %s; %v", imageID, err)
+			return "", err
+		}
+		if hasImage {
+			log.Printf("[sandbox] using cached image %s", dockerizedHash)
+			dockerImageID = dockerizedHash
+		} else {
+			log.Printf("[sandbox] image not cached, pulling %s", imageID)
+			dockerImageID, err = r.registry.PullImage(imageID)
+			if err != nil {
+				log.Errorf("[sandbox] error pulling ipfs docker image %s; %v", imageID, err)
+				return "", err
+			}
+			fullDockerImageID = "127.0.0.1:9999/" + dockerImageID + ":latest"
+		}
+	}
+
+	log.Printf("[sandbox] running docker image %s", dockerImageID)
+
+	containerConfig := &docker.CreateContainerConfig{
+		Volumes: map[string]string{
+			// sock binding will be required for spawning sibling containers
+			// container:host
+			//"/var/run/docker.sock": "/var/run/docker.sock",
+			//"/tmp": tmpdir,
+		},
+		Ports: map[string]string{},
+		Env:   map[string]string{},
+	}
+
+	if config.HostPort != "" {
+		containerConfig.Ports[strconv.Itoa(c3config.DefaultServerPort)] = config.HostPort
+	}
+	if config.SocketDir != "" {
+		containerConfig.Volumes[c3config.TempContainerSocketPath] = config.SocketDir
+		containerConfig.Env[c3config.ContainerSocketEnvVar] = c3config.TempContainerSocketFilePath
+	}
+
+	// TODO: fix the tag name
+	containerID, err := r.docker.CreateContainer(fullDockerImageID, nil, containerConfig)
+	if err != nil {
+		log.Printf("[sandbox] error creating container for image %s; %v", dockerImageID, err)
+		return "", err
+	}
+
+	return containerID, nil
+}
+
+// WriteState copies the state file into the container
+func (r *DockerRuntime) WriteState(containerID string, state []byte) error {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	hdr := &tar.Header{
+		Name: c3config.TempContainerStateFileName,
+		Mode: 0600,
+		Size: int64(len(state)),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if _, err := tw.Write(state); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return r.docker.CopyToContainer(containerID, c3config.TempContainerStatePath, bytes.NewReader(buf.Bytes()))
+}
+
+// Start ...
+func (r *DockerRuntime) Start(containerID string) error {
+	return r.docker.StartContainer(containerID)
+}
+
+// ReadState reads the state file from the running container
+func (r *DockerRuntime) ReadState(containerID string) (io.Reader, error) {
+	cmd := []string{"bash", "-c", "cat " + c3config.TempContainerStateFilePath}
+	return r.docker.ContainerExec(containerID, cmd)
+}
+
+// Stop ...
+func (r *DockerRuntime) Stop(containerID string) error {
+	return r.docker.StopContainer(containerID)
+}
+
+// Commit ...
+func (r *DockerRuntime) Commit(containerID string) (string, error) {
+	return r.docker.CommitContainer(containerID)
+}
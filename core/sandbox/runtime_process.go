@@ -0,0 +1,242 @@
+package sandbox
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	c3config "github.com/c3systems/c3-go/config"
+)
+
+// Ensure the struct implements the interface
+var _ Runtime = (*ProcessRuntime)(nil)
+
+// ErrProcessRuntimeTCP is returned when the process runtime is asked to use the tcp transport
+var ErrProcessRuntimeTCP = errors.New("process runtime only supports the unix transport")
+
+// ProcessRuntime runs a dApp binary as a local process in its own temp dir.
+// On linux the process is started in new namespaces and under resource limits, when the node is allowed to do so.
+type ProcessRuntime struct {
+	mut       sync.Mutex
+	binDir    string // note: the dir of the dApp binaries, named by image hash
+	instances map[string]*processInstance
+}
+
+type processInstance struct {
+	binary    string
+	dir       string
+	socketDir string
+	cmd       *exec.Cmd
+}
+
+// NewProcessRuntime returns a runtime that resolves image hashes to the binaries of the same name in binDir.
+// Without a binDir, the image IDs are paths to the binaries, which is only useful for development.
+func NewProcessRuntime(binDir string) *ProcessRuntime {
+	return &ProcessRuntime{
+		binDir:    binDir,
+		instances: make(map[string]*processInstance),
+	}
+}
+
+// Create sets up a temp dir for the instance of the image's dApp binary
+func (r *ProcessRuntime) Create(imageID string, config *InstanceConfig) (string, error) {
+	if config == nil {
+		return "", errors.New("config is required")
+	}
+	if config.HostPort != "" {
+		return "", ErrProcessRuntimeTCP
+	}
+
+	binary, err := r.resolve(imageID)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(binary)
+	if err != nil {
+		log.Errorf("[sandbox] error finding dapp binary %s; %v", imageID, err)
+		return "", err
+	}
+	if info.IsDir() || info.Mode()&0111 == 0 {
+		return "", fmt.Errorf("dapp binary %s is not executable", binary)
+	}
+
+	dir, err := ioutil.TempDir("", "c3process")
+	if err != nil {
+		return "", err
+	}
+
+	instanceID := filepath.Base(dir)
+
+	r.mut.Lock()
+	r.instances[instanceID] = &processInstance{
+		binary:    binary,
+		dir:       dir,
+		socketDir: config.SocketDir,
+	}
+	r.mut.Unlock()
+
+	log.Printf("[sandbox] created process instance %s for binary %s", instanceID, binary)
+
+	return instanceID, nil
+}
+
+// WriteState writes the state file to the instance's temp dir
+func (r *ProcessRuntime) WriteState(instanceID string, state []byte) error {
+	instance, err := r.instance(instanceID)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(instance.statePath(), state, 0600)
+}
+
+// Start runs the dApp binary
+func (r *ProcessRuntime) Start(instanceID string) error {
+	instance, err := r.instance(instanceID)
+	if err != nil {
+		return err
+	}
+
+	newCmd := func() *exec.Cmd {
+		cmd := exec.Command(instance.binary)
+		cmd.Dir = instance.dir
+		cmd.Env = append(os.Environ(),
+			fmt.Sprintf("%s=%s", c3config.ContainerStateEnvVar, instance.statePath()),
+		)
+		if instance.socketDir != "" {
+			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", c3config.ContainerSocketEnvVar, filepath.Join(instance.socketDir, c3config.TempContainerSocketFileName)))
+		}
+
+		return cmd
+	}
+
+	cmd := newCmd()
+	cmd.SysProcAttr = isolatedSysProcAttr()
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	cmd.Stderr = cmd.Stdout
+
+	if err := cmd.Start(); err != nil {
+		if cmd.SysProcAttr == nil {
+			return err
+		}
+
+		// note: namespaces require privileges, so retry without isolation
+		log.Warnf("[sandbox] error starting isolated process, retrying without namespaces; %v", err)
+		cmd = newCmd()
+		if stdout, err = cmd.StdoutPipe(); err != nil {
+			return err
+		}
+		cmd.Stderr = cmd.Stdout
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+	}
+
+	if err := setResourceLimits(cmd.Process.Pid); err != nil {
+		log.Warnf("[sandbox] error setting resource limits for process %v; %v", cmd.Process.Pid, err)
+	}
+
+	r.mut.Lock()
+	instance.cmd = cmd
+	r.mut.Unlock()
+
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			log.Printf("[sandbox] [process %s] [log] %s\n", instanceID, scanner.Text())
+		}
+	}()
+
+	log.Printf("[sandbox] running process %s; pid %v", instanceID, cmd.Process.Pid)
+	return nil
+}
+
+// ReadState reads the state file from the instance's temp dir
+func (r *ProcessRuntime) ReadState(instanceID string) (io.Reader, error) {
+	instance, err := r.instance(instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(instance.statePath())
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(data), nil
+}
+
+// Stop kills the process and removes its temp dir
+func (r *ProcessRuntime) Stop(instanceID string) error {
+	instance, err := r.instance(instanceID)
+	if err != nil {
+		return err
+	}
+
+	r.mut.Lock()
+	delete(r.instances, instanceID)
+	r.mut.Unlock()
+
+	defer os.RemoveAll(instance.dir)
+
+	if instance.cmd == nil || instance.cmd.Process == nil {
+		return nil
+	}
+
+	if err := instance.cmd.Process.Kill(); err != nil {
+		log.Warnf("[sandbox] error killing process %s; %v", instanceID, err)
+	}
+
+	// note: reap the process; the exit error is expected after a kill
+	_ = instance.cmd.Wait()
+
+	log.Printf("[sandbox] process %s stopped", instanceID)
+	return nil
+}
+
+// Commit is not supported by the process runtime
+func (r *ProcessRuntime) Commit(instanceID string) (string, error) {
+	return "", errors.New("process runtime does not support commit")
+}
+
+// resolve returns the path of the image's dApp binary
+func (r *ProcessRuntime) resolve(imageID string) (string, error) {
+	if r.binDir == "" {
+		return filepath.Abs(imageID)
+	}
+
+	// note: the image hash must name a binary in the bin dir, not a path out of it
+	if imageID == "" || imageID != filepath.Base(imageID) || imageID == "." || imageID == ".." {
+		return "", fmt.Errorf("invalid image hash %q", imageID)
+	}
+
+	return filepath.Abs(filepath.Join(r.binDir, imageID))
+}
+
+func (r *ProcessRuntime) instance(instanceID string) (*processInstance, error) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	instance, ok := r.instances[instanceID]
+	if !ok {
+		return nil, fmt.Errorf("no process instance %s", instanceID)
+	}
+
+	return instance, nil
+}
+
+func (p *processInstance) statePath() string {
+	return filepath.Join(p.dir, c3config.TempContainerStateFileName)
+}
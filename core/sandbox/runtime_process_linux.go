@@ -0,0 +1,38 @@
+package sandbox
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// note: limits are applied per dApp process
+var processResourceLimits = map[int]uint64{
+	syscall.RLIMIT_AS:     2 << 30, // 2GB of address space
+	syscall.RLIMIT_CPU:    60,      // seconds, matches the sandbox timeout
+	syscall.RLIMIT_NOFILE: 1024,
+	syscall.RLIMIT_FSIZE:  512 << 20, // 512MB per file
+}
+
+func isolatedSysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		// note: the IPC socket is a file, so it still works from a new network namespace
+		Cloneflags: syscall.CLONE_NEWNS | syscall.CLONE_NEWUTS | syscall.CLONE_NEWIPC | syscall.CLONE_NEWPID | syscall.CLONE_NEWNET,
+		Pdeathsig:  syscall.SIGKILL,
+	}
+}
+
+func setResourceLimits(pid int) error {
+	for resource, max := range processResourceLimits {
+		limit := syscall.Rlimit{
+			Cur: max,
+			Max: max,
+		}
+
+		_, _, errno := syscall.RawSyscall6(syscall.SYS_PRLIMIT64, uintptr(pid), uintptr(resource), uintptr(unsafe.Pointer(&limit)), 0, 0, 0)
+		if errno != 0 {
+			return errno
+		}
+	}
+
+	return nil
+}
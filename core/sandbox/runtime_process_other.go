@@ -0,0 +1,16 @@
+// +build !linux
+
+package sandbox
+
+import (
+	"syscall"
+)
+
+// note: namespaces and per process limits are linux only
+func isolatedSysProcAttr() *syscall.SysProcAttr {
+	return nil
+}
+
+func setResourceLimits(pid int) error {
+	return nil
+}
@@ -0,0 +1,150 @@
+// +build unit
+
+package sandbox
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"syscall"
+	"testing"
+
+	"github.com/c3systems/c3-go/common/hexutil"
+	"github.com/c3systems/c3-go/common/txparamcoder"
+	c3config "github.com/c3systems/c3-go/config"
+)
+
+const testDAppEnvVar = "C3_SANDBOX_TEST_DAPP"
+
+// note: the test binary doubles as the dApp run by the process runtime
+func TestMain(m *testing.M) {
+	if os.Getenv(testDAppEnvVar) == "1" {
+		runTestDApp()
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// runTestDApp implements the setItem method of the hello world dApp
+func runTestDApp() {
+	state := make(map[string]string)
+	statePath := os.Getenv(c3config.ContainerStateEnvVar)
+	if data, err := ioutil.ReadFile(statePath); err == nil && len(data) > 0 {
+		if err := json.Unmarshal(data, &state); err != nil {
+			os.Exit(1)
+		}
+	}
+
+	ln, err := net.Listen("unix", os.Getenv(c3config.ContainerSocketEnvVar))
+	if err != nil {
+		os.Exit(1)
+	}
+
+	conn, err := ln.Accept()
+	if err != nil {
+		os.Exit(1)
+	}
+
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		os.Exit(1)
+	}
+
+	var params []string
+	if err := json.Unmarshal(line, &params); err != nil {
+		os.Exit(1)
+	}
+	if len(params) == 3 && params[0] == txparamcoder.EncodeMethodName("setItem") {
+		state[params[1]] = params[2]
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		os.Exit(1)
+	}
+	if err := ioutil.WriteFile(statePath, data, 0600); err != nil {
+		os.Exit(1)
+	}
+
+	// note: wait to be killed, like a dApp container
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGTERM, os.Interrupt)
+	<-sig
+}
+
+func TestProcessRuntimePlay(t *testing.T) {
+	if err := os.Setenv(testDAppEnvVar, "1"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv(testDAppEnvVar)
+
+	// note: the image hash resolves to the test binary in its dir
+	sb, err := New(&Config{
+		runtime: NewProcessRuntime(filepath.Dir(os.Args[0])),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	initialState, err := json.Marshal(map[string]string{
+		hexutil.EncodeToString([]byte("hello")): hexutil.EncodeToString([]byte("world")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newState, err := sb.Play(&PlayConfig{
+		ImageID: filepath.Base(os.Args[0]),
+		Payload: txparamcoder.ToJSONArray(
+			txparamcoder.EncodeMethodName("setItem"),
+			txparamcoder.EncodeParam("foo"),
+			txparamcoder.EncodeParam("bar"),
+		),
+		InitialState: initialState,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedState, err := json.Marshal(map[string]string{
+		hexutil.EncodeToString([]byte("foo")):   hexutil.EncodeToString([]byte("bar")),
+		hexutil.EncodeToString([]byte("hello")): hexutil.EncodeToString([]byte("world")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(newState, expectedState) {
+		t.Errorf("expected new state; got %s", string(newState))
+	}
+}
+
+func TestProcessRuntimeRejectsTCP(t *testing.T) {
+	r := NewProcessRuntime("")
+	if _, err := r.Create(os.Args[0], &InstanceConfig{HostPort: "3333"}); err != ErrProcessRuntimeTCP {
+		t.Errorf("expected %v; got %v", ErrProcessRuntimeTCP, err)
+	}
+}
+
+func TestProcessRuntimeResolve(t *testing.T) {
+	r := NewProcessRuntime("/opt/c3/dapps")
+	binary, err := r.resolve("QmSomeImageHash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "/opt/c3/dapps/QmSomeImageHash"; binary != expected {
+		t.Errorf("expected %s; got %s", expected, binary)
+	}
+
+	for _, imageID := range []string{"", ".", "..", "../foo", "/usr/bin/foo", "foo/bar"} {
+		if _, err := r.resolve(imageID); err == nil {
+			t.Errorf("expected an err resolving %q", imageID)
+		}
+	}
+}
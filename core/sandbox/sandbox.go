@@ -1,9 +1,6 @@
 package sandbox
 
 import (
-	"archive/tar"
-	"bytes"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -11,6 +8,7 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"syscall"
@@ -18,7 +16,6 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
-	"github.com/c3systems/c3-go/common/hexutil"
 	"github.com/c3systems/c3-go/common/netutil"
 	"github.com/c3systems/c3-go/common/stringutil"
 	c3config "github.com/c3systems/c3-go/config"
@@ -26,62 +23,100 @@ import (
 	colorlog "github.com/c3systems/c3-go/log/color"
 	loghooks "github.com/c3systems/c3-go/log/hooks"
 	"github.com/c3systems/c3-go/registry"
-	regutil "github.com/c3systems/c3-go/registry/util"
+	"github.com/c3systems/c3-go/state"
 )
 
 // Ensure the service implements the interface
 var _ Interface = (*Service)(nil)
 
+const (
+	// TransportUnix sends the payload to the dApp over a unix domain socket bind-mounted into the container
+	TransportUnix = "unix"
+	// TransportTCP sends the payload to the dApp over a free host port mapped to the container
+	TransportTCP = "tcp"
+)
+
+// dAppStartTimeout is how long to wait for the dApp to open its IPC socket
+const dAppStartTimeout = 30 * time.Second
+
 // Service ...
 type Service struct {
-	docker            docker.Interface
-	registry          registry.Interface
+	runtime           Runtime
 	sock              string
 	runningContainers map[string]bool
 	localIP           string
+	transport         string
 }
 
 // Config ...
 type Config struct {
-	docker   docker.Interface
-	registry registry.Interface
+	docker    docker.Interface
+	registry  registry.Interface
+	runtime   Runtime
+	transport string
 }
 
 // New ...
-func New(config *Config) *Service {
-	localIP, err := netutil.LocalIP()
+func New(config *Config) (*Service, error) {
+	// note: the local IP is only required by the docker registry and the tcp transport
+	var localIP string
+	ip, err := netutil.LocalIP()
 	if err != nil {
-		log.Fatalf("[sandbox] %s", err)
+		log.Warnf("[sandbox] error getting local IP; %v", err)
+	} else {
+		localIP = ip.String()
 	}
 
 	if config == nil {
-		dockerLocalRegistryHost := os.Getenv("DOCKER_LOCAL_REGISTRY_HOST")
-		if dockerLocalRegistryHost == "" {
-			dockerLocalRegistryHost = localIP.String()
+		config = &Config{
+			transport: os.Getenv("SANDBOX_TRANSPORT"),
 		}
 
-		docker := docker.NewClient()
-		reg := registry.NewRegistry(&registry.Config{
-			DockerLocalRegistryHost: dockerLocalRegistryHost,
-		})
+		switch strings.ToLower(os.Getenv("SANDBOX_RUNTIME")) {
+		case RuntimeProcess:
+			config.runtime = NewProcessRuntime(os.Getenv("SANDBOX_PROCESS_BIN_DIR"))
+		default:
+			dockerLocalRegistryHost := os.Getenv("DOCKER_LOCAL_REGISTRY_HOST")
+			if dockerLocalRegistryHost == "" {
+				if localIP == "" {
+					return nil, errors.New("DOCKER_LOCAL_REGISTRY_HOST is required when the local IP is unknown")
+				}
+				dockerLocalRegistryHost = localIP
+			}
 
-		config = &Config{
-			docker:   docker,
-			registry: reg,
+			config.docker = docker.NewClient()
+			config.registry = registry.NewRegistry(&registry.Config{
+				DockerLocalRegistryHost: dockerLocalRegistryHost,
+			})
 		}
 	}
 
+	runtime := config.runtime
+	if runtime == nil {
+		runtime = NewDockerRuntime(config.docker, config.registry)
+	}
+
+	// note: unix is the default, as the core server listens on the C3_SOCKET the sandbox sets.
+	// tcp is kept for dApps built before it did; the process runtime only supports unix.
+	transport := strings.ToLower(config.transport)
+	if transport == "" {
+		transport = TransportUnix
+	}
+	if transport != TransportTCP && transport != TransportUnix {
+		return nil, fmt.Errorf("unknown sandbox transport %s", config.transport)
+	}
+
 	sb := &Service{
-		docker:            config.docker,
-		registry:          config.registry,
+		runtime:           runtime,
 		sock:              "/var/run/docker.sock",
 		runningContainers: map[string]bool{},
-		localIP:           localIP.String(),
+		localIP:           localIP,
+		transport:         transport,
 	}
 
 	//go sb.cleanupOnExit()
 
-	return sb
+	return sb, nil
 }
 
 // PlayConfig ...
@@ -98,86 +133,61 @@ func (s *Service) Play(config *PlayConfig) ([]byte, error) {
 		return nil, errors.New("config is required")
 	}
 
-	var dockerImageID = config.ImageID
-	var fullDockerImageID = dockerImageID
-	var err error
-
-	// If it's an IPFS hash then pull it from IPFS
-	if strings.HasPrefix(config.ImageID, "Qm") {
-		dockerizedHash := regutil.DockerizeHash(config.ImageID)
-		hasImage, err := s.docker.HasImage(dockerizedHash)
+	var (
+		network, address string
+		instanceConfig   InstanceConfig
+	)
+	switch s.transport {
+	case TransportUnix:
+		sockDir, err := ioutil.TempDir("", "c3ipc")
 		if err != nil {
-			log.Printf("[sandbox] error checking if have docker image %s; %v", config.ImageID, err)
+			log.Errorf("[sandbox] error creating socket directory; %v", err)
 			return nil, err
 		}
-		if hasImage {
-			log.Printf("[sandbox] using cached image %s", dockerizedHash)
-			dockerImageID = dockerizedHash
-		} else {
-			log.Printf("[sandbox] image not cached, pulling %s", config.ImageID)
-			dockerImageID, err = s.registry.PullImage(config.ImageID)
-			if err != nil {
-				log.Errorf("[sandbox] error pulling ipfs docker image %s; %v", config.ImageID, err)
-				return nil, err
-			}
-			fullDockerImageID = "127.0.0.1:9999/" + dockerImageID + ":latest"
+		defer os.RemoveAll(sockDir)
+
+		// note: the dApp may run as a different user than the node
+		if err := os.Chmod(sockDir, 0777); err != nil {
+			return nil, err
 		}
-	}
 
-	log.Printf("[sandbox] running docker image %s", dockerImageID)
+		log.Printf("[sandbox] socket directory %s", sockDir)
 
-	hp, err := netutil.GetFreePort()
-	if err != nil {
-		log.Printf("[sandbox] error getting finding a port; %v", err)
-		return nil, err
-	}
+		instanceConfig.SocketDir = sockDir
+		network = "unix"
+		address = filepath.Join(sockDir, c3config.TempContainerSocketFileName)
+	default:
+		if s.localIP == "" {
+			return nil, errors.New("local IP is required for the tcp transport")
+		}
 
-	log.Printf("[sandbox] host port %v", hp)
-
-	hostPort := strconv.Itoa(hp)
-	// TODO: fix the tag name
-	containerID, err := s.docker.CreateContainer(fullDockerImageID, nil, &docker.CreateContainerConfig{
-		Volumes: map[string]string{
-		// sock binding will be required for spawning sibling containers
-		// container:host
-		//"/var/run/docker.sock": "/var/run/docker.sock",
-		//"/tmp": tmpdir,
-		},
-		Ports: map[string]string{
-			"3333": hostPort,
-		},
-	})
-	if err != nil {
-		log.Printf("[sandbox] error creating container for image %s; %v", dockerImageID, err)
-		return nil, err
-	}
+		hp, err := netutil.GetFreePort()
+		if err != nil {
+			log.Printf("[sandbox] error getting finding a port; %v", err)
+			return nil, err
+		}
 
-	var buf bytes.Buffer
-	body := config.InitialState
-	tw := tar.NewWriter(&buf)
-	hdr := &tar.Header{
-		Name: c3config.TempContainerStateFileName,
-		Mode: 0600,
-		Size: int64(len(body)),
-	}
-	if err := tw.WriteHeader(hdr); err != nil {
-		return nil, err
+		log.Printf("[sandbox] host port %v", hp)
+
+		instanceConfig.HostPort = strconv.Itoa(hp)
+		network = "tcp"
+		address = net.JoinHostPort(s.localIP, instanceConfig.HostPort)
 	}
-	if _, err := tw.Write([]byte(body)); err != nil {
+
+	containerID, err := s.runtime.Create(config.ImageID, &instanceConfig)
+	if err != nil {
 		return nil, err
 	}
-	defer tw.Close()
 
 	s.runningContainers[containerID] = true
 
-	r := bytes.NewReader(buf.Bytes())
-	err = s.docker.CopyToContainer(containerID, "/tmp", r)
-	if err != nil {
+	if err := s.runtime.WriteState(containerID, config.InitialState); err != nil {
+		s.cleanupContainer(containerID)
 		return nil, err
 	}
 
-	err = s.docker.StartContainer(containerID)
-	if err != nil {
+	if err := s.runtime.Start(containerID); err != nil {
+		s.cleanupContainer(containerID)
 		return nil, err
 	}
 
@@ -188,9 +198,13 @@ func (s *Service) Play(config *PlayConfig) ([]byte, error) {
 	go func() {
 		log.Printf("[sandbox] container ID: %s", containerID)
 		log.Println("[sandbox] waiting for dapp to start...")
-		// TODO: optimize
-		time.Sleep(10 * time.Second)
-		err := s.sendMessage(config.Payload, hostPort)
+		if err := waitForDApp(network, address); err != nil {
+			log.Errorf("[sandbox] error waiting for dapp; %v", err)
+			errEvent <- err
+			return
+		}
+
+		err := s.sendMessage(config.Payload, network, address)
 		if err != nil {
 			log.Errorf("[sandbox] error sending message; %v", err)
 			errEvent <- err
@@ -203,7 +217,7 @@ func (s *Service) Play(config *PlayConfig) ([]byte, error) {
 
 		if config.ContainerIDChannel != nil {
 			go func() {
-				log.Println("[sandbox] wrote to containe ID channel; %s", containerID)
+				log.Printf("[sandbox] wrote to container ID channel; %s", containerID)
 				config.ContainerIDChannel <- containerID
 			}()
 		}
@@ -248,10 +262,9 @@ func (s *Service) Play(config *PlayConfig) ([]byte, error) {
 		return nil, errors.New("timedout")
 	case <-done:
 		log.Println("[sandbox] reading new state...")
-		cmd := []string{"bash", "-c", "cat " + c3config.TempContainerStateFilePath}
-		resp, err := s.docker.ContainerExec(containerID, cmd)
+		resp, err := s.runtime.ReadState(containerID)
 		if err != nil {
-			log.Errorf("[sandbox] error calling exec on container; %v", err)
+			log.Errorf("[sandbox] error reading state from container; %v", err)
 			return nil, err
 		}
 
@@ -294,7 +307,7 @@ func (s *Service) CommitPlay(config *PlayConfig) (string, error) {
 	case <-timer.C:
 		return "", errors.New("timed out")
 	case containerID := <-ch:
-		imageID, err := s.docker.CommitContainer(containerID)
+		imageID, err := s.runtime.Commit(containerID)
 		if err != nil {
 			return "", err
 		}
@@ -307,16 +320,22 @@ func (s *Service) CommitPlay(config *PlayConfig) (string, error) {
 
 func (s *Service) killContainer(containerID string) error {
 	delete(s.runningContainers, containerID)
-	if err := s.docker.StopContainer(containerID); err != nil {
+	if err := s.runtime.Stop(containerID); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// cleanupContainer stops a container that failed to start
+func (s *Service) cleanupContainer(containerID string) {
+	if err := s.killContainer(containerID); err != nil {
+		log.Errorf("[sandbox] error cleaning up container %s; %v", containerID, err)
+	}
+}
+
 func parseNewState(reader io.Reader) ([]byte, error) {
 	log.Printf("[sandbox] parsing new state; %v", reader)
-	var state map[string]string
 
 	src, err := ioutil.ReadAll(reader)
 	if err != nil {
@@ -330,26 +349,42 @@ func parseNewState(reader io.Reader) ([]byte, error) {
 		return nil, err
 	}
 
-	err = json.Unmarshal(b, &state)
+	st, err := state.Parse(b)
 	if err != nil {
 		return nil, err
 	}
 
-	for key, value := range state {
-		k, _ := hexutil.DecodeString(key)
-		v, _ := hexutil.DecodeString(value)
-		log.Printf(colorlog.Magenta("[sandbox] state k/v %s=>%s", string(k), string(v)))
+	for _, key := range st.Keys() {
+		log.Printf(colorlog.Magenta("[sandbox] state k/v %s=>%s", key, st[key]))
+	}
+
+	// note: the state is re-serialized so that the same state always hashes the same
+	return st.Serialize()
+}
+
+// waitForDApp blocks until the dApp is ready to receive a message
+func waitForDApp(network, address string) error {
+	if network != "unix" {
+		// TODO: optimize
+		time.Sleep(10 * time.Second)
+		return nil
+	}
+
+	deadline := time.Now().Add(dAppStartTimeout)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(address); err == nil {
+			return nil
+		}
+
+		time.Sleep(100 * time.Millisecond)
 	}
 
-	log.Printf("[sandbox] new state %s", state)
-	return b, nil
+	return fmt.Errorf("timed out waiting for dapp socket %s", address)
 }
 
-func (s *Service) sendMessage(msg []byte, port string) error {
-	// TODO: communicate over IPC
-	host := fmt.Sprintf("%s:%s", s.localIP, port)
-	log.Printf("[sandbox] sending message to container on host %s", host)
-	conn, err := net.Dial("tcp", host)
+func (s *Service) sendMessage(msg []byte, network, host string) error {
+	log.Printf("[sandbox] sending message to container on %s host %s", network, host)
+	conn, err := net.Dial(network, host)
 	if err != nil {
 		log.Errorf("[sandbox] error sending message; %v", err)
 		return err
@@ -376,7 +411,7 @@ func (s *Service) cleanupOnExit() {
 
 func (s *Service) cleanup() {
 	for cid := range s.runningContainers {
-		err := s.docker.StopContainer(cid)
+		err := s.runtime.Stop(cid)
 		if err != nil {
 			log.Errorf("[server] error %s", err)
 		}
@@ -26,7 +26,10 @@ func init() {
 
 func TestNew(t *testing.T) {
 	t.Parallel()
-	sb := New(nil)
+	sb, err := New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if sb == nil {
 		t.Error("expected instance")
 	}
@@ -34,7 +37,10 @@ func TestNew(t *testing.T) {
 
 func TestPayload(t *testing.T) {
 	t.Parallel()
-	sb := New(nil)
+	sb, err := New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	payload := txparamcoder.ToJSONArray(
 		txparamcoder.EncodeMethodName("setItem"),
@@ -64,7 +70,10 @@ func TestPayload(t *testing.T) {
 
 func TestInitialState(t *testing.T) {
 	t.Parallel()
-	sb := New(nil)
+	sb, err := New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
 	payload := txparamcoder.ToJSONArray(
 		txparamcoder.EncodeMethodName("setItem"),
 		txparamcoder.EncodeParam("foo"),
@@ -106,7 +115,10 @@ func TestInitialState(t *testing.T) {
 
 func TestMultipleInputs(t *testing.T) {
 	t.Parallel()
-	sb := New(nil)
+	sb, err := New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
 	payload := txparamcoder.AppendJSONArrays(
 		txparamcoder.ToJSONArray(
 			txparamcoder.EncodeMethodName("setItem"),
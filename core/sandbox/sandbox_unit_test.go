@@ -0,0 +1,34 @@
+// +build unit
+
+package sandbox
+
+import "testing"
+
+func TestNewTransport(t *testing.T) {
+	tests := []struct {
+		runtime   Runtime
+		transport string
+		expected  string
+	}{
+		{NewDockerRuntime(nil, nil), "", TransportUnix},
+		{NewDockerRuntime(nil, nil), "TCP", TransportTCP},
+		{NewProcessRuntime(""), "", TransportUnix},
+	}
+
+	for _, tt := range tests {
+		sb, err := New(&Config{
+			runtime:   tt.runtime,
+			transport: tt.transport,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sb.transport != tt.expected {
+			t.Errorf("expected transport %s; received %s", tt.expected, sb.transport)
+		}
+	}
+
+	if _, err := New(&Config{runtime: NewProcessRuntime(""), transport: "foo"}); err == nil {
+		t.Error("expected an err for an unknown transport")
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"bufio"
 	"fmt"
 	"net"
+	"os"
 
+	c3config "github.com/c3systems/c3-go/config"
 	loghooks "github.com/c3systems/c3-go/log/hooks"
 	log "github.com/sirupsen/logrus"
 )
@@ -13,6 +15,7 @@ import (
 type Server struct {
 	host     string
 	port     int
+	socket   string
 	receiver chan []byte
 }
 
@@ -24,23 +27,31 @@ type Client struct {
 
 // Config ...
 type Config struct {
-	Host     string
-	Port     int
+	Host string
+	Port int
+	// Socket is the path of the unix domain socket to listen on; it defaults to the C3_SOCKET env var set by the sandbox
+	Socket   string
 	Receiver chan []byte
 }
 
 // NewServer ...
 func NewServer(config *Config) *Server {
+	socket := config.Socket
+	if socket == "" {
+		socket = os.Getenv(c3config.ContainerSocketEnvVar)
+	}
+
 	return &Server{
 		host:     config.Host,
 		port:     config.Port,
+		socket:   socket,
 		receiver: config.Receiver,
 	}
 }
 
-// Run ...
+// Run listens on the unix domain socket when there's one, else on the tcp host and port
 func (server *Server) Run() error {
-	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%v", server.host, server.port))
+	listener, err := server.listen()
 	if err != nil {
 		return err
 	}
@@ -60,6 +71,32 @@ func (server *Server) Run() error {
 	}
 }
 
+func (server *Server) listen() (net.Listener, error) {
+	if server.socket == "" {
+		return net.Listen("tcp", fmt.Sprintf("%s:%v", server.host, server.port))
+	}
+
+	// note: a socket file left by a previous run would fail the listen
+	if err := os.Remove(server.socket); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	listener, err := net.Listen("unix", server.socket)
+	if err != nil {
+		return nil, err
+	}
+
+	// note: the node may run as a different user than the dApp
+	if err := os.Chmod(server.socket, 0777); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	log.Printf("[server] listening on %s", server.socket)
+
+	return listener, nil
+}
+
 func (client *Client) handleRequest() {
 	reader := bufio.NewReader(client.conn)
 	for {
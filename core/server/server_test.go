@@ -1,6 +1,11 @@
 package server
 
 import (
+	"bufio"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -30,3 +35,50 @@ func TestRun(t *testing.T) {
 	go server.Run()
 	time.Sleep(1 * time.Second)
 }
+
+func TestRunUnix(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "c3server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	receiver := make(chan []byte, 1)
+	socket := filepath.Join(dir, "c3.sock")
+	server := NewServer(&Config{
+		Host:     Host,
+		Port:     Port,
+		Socket:   socket,
+		Receiver: receiver,
+	})
+	go server.Run()
+
+	var conn net.Conn
+	for i := 0; i < 50; i++ {
+		if conn, err = net.Dial("unix", socket); err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("hello\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bufio.NewReader(conn).ReadString('\n'); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-receiver:
+		if string(msg) != "hello\n" {
+			t.Errorf("expected %q; received %q", "hello\n", msg)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected the message")
+	}
+}
@@ -34,7 +34,7 @@ require (
 	github.com/google/shlex v0.0.0-20181106134648-c34317bd91bf // indirect
 	github.com/google/uuid v1.1.0 // indirect
 	github.com/gxed/pubsub v0.0.0-20180201040156-26ebdf44f824 // indirect
-	github.com/hashicorp/golang-lru v0.5.0 // indirect
+	github.com/hashicorp/golang-lru v0.5.0
 	github.com/huin/goupnp v1.0.0 // indirect
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
 	github.com/ipfs/bbloom v0.0.0-20180721065414-f6503c07a6c2 // indirect
@@ -71,12 +71,12 @@ require (
 	github.com/kardianos/govendor v1.0.9 // indirect
 	github.com/libp2p/go-addr-util v2.0.7+incompatible // indirect
 	github.com/libp2p/go-buffer-pool v0.1.1 // indirect
-	github.com/libp2p/go-conn-security v0.1.15 // indirect
+	github.com/libp2p/go-conn-security v0.1.15
 	github.com/libp2p/go-conn-security-multistream v0.1.15
 	github.com/libp2p/go-flow-metrics v0.2.0 // indirect
 	github.com/libp2p/go-libp2p v0.0.0-20190110080257-6547c0dc37dd
 	github.com/libp2p/go-libp2p-blankhost v0.0.0-20181218184614-aa9a19abd3f3
-	github.com/libp2p/go-libp2p-circuit v2.3.2+incompatible // indirect
+	github.com/libp2p/go-libp2p-circuit v2.3.2+incompatible
 	github.com/libp2p/go-libp2p-crypto v0.0.0-20181130162722-b150863d61f7
 	github.com/libp2p/go-libp2p-host v3.0.15+incompatible
 	github.com/libp2p/go-libp2p-interface-connmgr v0.0.21 // indirect
@@ -90,13 +90,13 @@ require (
 	github.com/libp2p/go-libp2p-netutil v0.0.0-20190110225159-d58056f931bd // indirect
 	github.com/libp2p/go-libp2p-peer v2.4.0+incompatible
 	github.com/libp2p/go-libp2p-peerstore v2.0.6+incompatible
-	github.com/libp2p/go-libp2p-protocol v1.0.0 // indirect
+	github.com/libp2p/go-libp2p-protocol v1.0.0
 	github.com/libp2p/go-libp2p-pubsub v0.0.0-20190121225156-d4589956d289
 	github.com/libp2p/go-libp2p-record v4.1.7+incompatible // indirect
 	github.com/libp2p/go-libp2p-routing v2.7.1+incompatible
 	github.com/libp2p/go-libp2p-secio v2.0.17+incompatible
 	github.com/libp2p/go-libp2p-swarm v3.0.22+incompatible
-	github.com/libp2p/go-libp2p-transport v3.0.15+incompatible // indirect
+	github.com/libp2p/go-libp2p-transport v3.0.15+incompatible
 	github.com/libp2p/go-libp2p-transport-upgrader v0.1.16
 	github.com/libp2p/go-maddr-filter v1.1.10 // indirect
 	github.com/libp2p/go-msgio v0.0.6 // indirect
@@ -109,6 +109,7 @@ require (
 	github.com/mitchellh/go-homedir v1.1.0 // indirect
 	github.com/mr-tron/base58 v1.1.0
 	github.com/multiformats/go-multiaddr v1.4.0
+	github.com/multiformats/go-multiaddr-net v1.7.1
 	github.com/multiformats/go-multibase v0.3.0 // indirect
 	github.com/multiformats/go-multicodec v0.1.6
 	github.com/multiformats/go-multihash v1.0.8
@@ -132,7 +133,7 @@ require (
 	github.com/whyrusleeping/go-notifier v0.0.0-20170827234753-097c5d47330f // indirect
 	github.com/whyrusleeping/go-smux-multistream v2.0.2+incompatible
 	github.com/whyrusleeping/go-smux-yamux v2.0.8+incompatible
-	github.com/whyrusleeping/mafmt v1.2.8 // indirect
+	github.com/whyrusleeping/mafmt v1.2.8
 	github.com/whyrusleeping/mdns v0.0.0-20180901202407-ef14215e6b30 // indirect
 	github.com/whyrusleeping/multiaddr-filter v0.0.0-20160516205228-e903e4adabd7 // indirect
 	github.com/whyrusleeping/tar-utils v0.0.0-20180509141711-8c6c8ba81d5c // indirect
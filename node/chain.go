@@ -0,0 +1,202 @@
+package node
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/c3systems/c3-go/common/hexutil"
+	"github.com/c3systems/c3-go/core/chain/mainchain"
+	"github.com/c3systems/c3-go/core/miner"
+
+	ds "github.com/ipfs/go-datastore"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// chainWorkPrefix is the datastore namespace of the cumulative work of the stored mainchain blocks
+	chainWorkPrefix = ds.NewKey("/chain/work")
+	// chainHeightsPrefix is the datastore namespace of the hashes of the head block's chain by height
+	chainHeightsPrefix = ds.NewKey("/chain/heights")
+)
+
+// chainIndex keeps the cumulative work of the verified mainchain blocks the node stored,
+// so competing chains are compared by the work that went into them rather than their height.
+// It also keeps the hashes of the head block's chain by height, so blocks are served without walking the chain.
+type chainIndex struct {
+	mut   sync.Mutex
+	store ds.Datastore
+}
+
+func newChainIndex(store ds.Datastore) *chainIndex {
+	if store == nil {
+		store = ds.NewMapDatastore()
+	}
+
+	return &chainIndex{
+		store: store,
+	}
+}
+
+// work returns the cumulative work of the chain ending at the block, or ErrBlockNotFound if the block isn't indexed
+func (c *chainIndex) work(hash string) (*big.Int, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	data, err := c.store.Get(chainWorkPrefix.ChildString(hash))
+	if err == ds.ErrNotFound {
+		return nil, ErrBlockNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return hexutil.DecodeBigInt(string(data))
+}
+
+// add indexes the block after its previous block and returns its cumulative work.
+// note: ErrBlockNotFound is returned when the previous block isn't indexed.
+func (c *chainIndex) add(block *mainchain.Block) (*big.Int, error) {
+	if block == nil || block.Props().BlockHash == nil {
+		return nil, miner.ErrNilBlock
+	}
+
+	work, err := miner.BlockWork(block)
+	if err != nil {
+		return nil, err
+	}
+	if block.Props().BlockNumber != mainchain.GenesisBlock.Props().BlockNumber {
+		prevWork, err := c.work(block.Props().PrevBlockHash)
+		if err != nil {
+			return nil, err
+		}
+
+		work.Add(work, prevWork)
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	return work, c.store.Put(chainWorkPrefix.ChildString(*block.Props().BlockHash), []byte(hexutil.EncodeBigInt(work)))
+}
+
+// hashAt returns the hash of the block at the height on the indexed chain, or ErrBlockNotFound
+func (c *chainIndex) hashAt(height uint64) (string, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	data, err := c.store.Get(chainHeightsPrefix.ChildString(hexutil.EncodeUint64(height)))
+	if err == ds.ErrNotFound {
+		return "", ErrBlockNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func (c *chainIndex) setHeight(height uint64, hash string) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	return c.store.Put(chainHeightsPrefix.ChildString(hexutil.EncodeUint64(height)), []byte(hash))
+}
+
+// indexHeights indexes the chain of the head block by height, walking back until the indexed chain joins it.
+// note: after a reorg the heights above the head block may still hold the abandoned chain; they're overwritten as the chain grows.
+func (s *Service) indexHeights(head *mainchain.Block) error {
+	block := head
+	for {
+		if block.Props().BlockHash == nil {
+			return miner.ErrNilBlock
+		}
+		height, err := hexutil.DecodeUint64(block.Props().BlockNumber)
+		if err != nil {
+			return err
+		}
+
+		hash, err := s.chain.hashAt(height)
+		if err != nil && err != ErrBlockNotFound {
+			return err
+		}
+		if hash == *block.Props().BlockHash {
+			return nil
+		}
+		if err := s.chain.setHeight(height, *block.Props().BlockHash); err != nil {
+			return err
+		}
+		if height == 0 {
+			return nil
+		}
+
+		if block, err = s.localMainchainBlock(block.Props().PrevBlockHash); err != nil {
+			return err
+		}
+	}
+}
+
+// chainWork returns the cumulative work of the chain ending at the stored block.
+// note: the blocks stored before they were indexed are indexed on the way.
+func (s *Service) chainWork(block *mainchain.Block) (*big.Int, error) {
+	var unindexed []*mainchain.Block
+	for {
+		if block.Props().BlockHash == nil {
+			return nil, miner.ErrNilBlock
+		}
+
+		work, err := s.chain.work(*block.Props().BlockHash)
+		if err == nil && len(unindexed) == 0 {
+			return work, nil
+		}
+		if err == nil {
+			break
+		}
+		if err != ErrBlockNotFound {
+			return nil, err
+		}
+
+		unindexed = append(unindexed, block)
+		if block.Props().BlockNumber == mainchain.GenesisBlock.Props().BlockNumber {
+			break
+		}
+		if block, err = s.localMainchainBlock(block.Props().PrevBlockHash); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		work *big.Int
+		err  error
+	)
+	for i := len(unindexed) - 1; i >= 0; i-- {
+		if work, err = s.chain.add(unindexed[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return work, nil
+}
+
+// preferWork is the fork choice rule: it returns true when the chain ending at the block has more work than the
+// chain ending at the other block. The lower block hash breaks ties so every node makes the same choice.
+func preferWork(work *big.Int, block *mainchain.Block, otherWork *big.Int, other *mainchain.Block) bool {
+	if c := work.Cmp(otherWork); c != 0 {
+		return c > 0
+	}
+
+	if block.Props().BlockHash == nil || other.Props().BlockHash == nil {
+		return false
+	}
+
+	return *block.Props().BlockHash < *other.Props().BlockHash
+}
+
+// meetsRequiredDifficulty returns true when the block was mined at the difficulty the node requires, or higher
+func (s *Service) meetsRequiredDifficulty(block *mainchain.Block) bool {
+	ok, err := miner.CheckRequiredDifficulty(block, uint64(s.props.BlockDifficulty))
+	if err != nil {
+		log.Warnf("[node] err checking block difficulty\n%v", err)
+	}
+
+	return ok
+}
@@ -0,0 +1,77 @@
+// +build unit
+
+package node
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/c3systems/c3-go/core/chain/mainchain"
+)
+
+func TestChainIndex(t *testing.T) {
+	c := newChainIndex(nil)
+
+	genesisWork, err := c.add(&mainchain.GenesisBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if genesisWork.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("expected the genesis block work to be 1; received %v", genesisWork)
+	}
+
+	// note: one block mined at a higher difficulty outweighs several easier blocks
+	hard := mineHeadBlock(t, 1, mainchain.GenesisBlockHash, testDifficulty+1)
+	easy1 := mineHeadBlock(t, 1, mainchain.GenesisBlockHash, testDifficulty)
+	easy2 := mineHeadBlock(t, 2, *easy1.Props().BlockHash, testDifficulty)
+
+	var works []*big.Int
+	for _, block := range []*mainchain.Block{hard, easy1, easy2} {
+		work, err := c.add(block)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		works = append(works, work)
+	}
+	if expected := big.NewInt(1 + 4096); works[0].Cmp(expected) != 0 {
+		t.Errorf("expected work %v; received %v", expected, works[0])
+	}
+	if expected := big.NewInt(1 + 256 + 256); works[2].Cmp(expected) != 0 {
+		t.Errorf("expected work %v; received %v", expected, works[2])
+	}
+
+	if work, err := c.work(*easy2.Props().BlockHash); err != nil || work.Cmp(works[2]) != 0 {
+		t.Errorf("expected work %v; received %v %v", works[2], work, err)
+	}
+	if !preferWork(works[0], hard, works[2], easy2) || preferWork(works[2], easy2, works[0], hard) {
+		t.Error("expected the chain with the most work to be preferred over the higher chain")
+	}
+
+	tied := mineHeadBlock(t, 1, mainchain.GenesisBlockHash, testDifficulty)
+	if preferWork(works[1], easy1, works[1], tied) == preferWork(works[1], tied, works[1], easy1) {
+		t.Error("expected the tie to be broken by block hash")
+	}
+	if preferWork(works[1], easy1, works[1], easy1) {
+		t.Error("expected a block not to be preferred over itself")
+	}
+
+	// note: a block is only indexed after its previous block
+	orphan := mineHeadBlock(t, 4, *tied.Props().BlockHash, testDifficulty)
+	if _, err := c.add(orphan); err != ErrBlockNotFound {
+		t.Errorf("expected %v; received %v", ErrBlockNotFound, err)
+	}
+	if _, err := c.work(*orphan.Props().BlockHash); err != ErrBlockNotFound {
+		t.Errorf("expected %v; received %v", ErrBlockNotFound, err)
+	}
+
+	if _, err := c.hashAt(1); err != ErrBlockNotFound {
+		t.Errorf("expected %v; received %v", ErrBlockNotFound, err)
+	}
+	if err := c.setHeight(1, *hard.Props().BlockHash); err != nil {
+		t.Fatal(err)
+	}
+	if hash, err := c.hashAt(1); err != nil || hash != *hard.Props().BlockHash {
+		t.Errorf("expected hash %s at height 1; received %s %v", *hard.Props().BlockHash, hash, err)
+	}
+}
@@ -0,0 +1,123 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	lCrypt "github.com/libp2p/go-libp2p-crypto"
+	peer "github.com/libp2p/go-libp2p-peer"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	pstoremem "github.com/libp2p/go-libp2p-peerstore/pstoremem"
+	swarm "github.com/libp2p/go-libp2p-swarm"
+	bhost "github.com/libp2p/go-libp2p/p2p/host/basic"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+const (
+	// maxConcurrentDialBacks is the number of peers dialed back at once; further requests are refused
+	maxConcurrentDialBacks = 4
+	// dialBackDialTimeout is how long dialing a peer back may take
+	dialBackDialTimeout = 15 * time.Second
+)
+
+// ErrTooManyDialBacks is returned when a dial back is requested while others are in flight
+var ErrTooManyDialBacks = errors.New("too many dial backs in flight")
+
+// dialBackFN dials peers back for their reachability checks.
+// The peer is dialed from a throwaway swarm, so the existing connection to it isn't reused.
+func dialBackFN(transportNames, channels []string) func(peerID peer.ID, addrs []ma.Multiaddr) ([]ma.Multiaddr, error) {
+	sem := make(chan struct{}, maxConcurrentDialBacks)
+
+	return func(peerID peer.ID, addrs []ma.Multiaddr) ([]ma.Multiaddr, error) {
+		select {
+		case sem <- struct{}{}:
+			defer func() { <-sem }()
+		default:
+			return nil, ErrTooManyDialBacks
+		}
+
+		dialer, err := newDialer(transportNames, channels)
+		if err != nil {
+			return nil, err
+		}
+		defer dialer.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), dialBackDialTimeout)
+		defer cancel()
+
+		dialer.Peerstore().AddAddrs(peerID, addrs, peerstore.TempAddrTTL)
+		conn, err := dialer.DialPeer(ctx, peerID)
+		if err != nil {
+			// note: the peer isn't reachable on its addrs, which isn't an error of the dial back
+			return nil, nil
+		}
+
+		return []ma.Multiaddr{conn.RemoteMultiaddr()}, nil
+	}
+}
+
+// newDialer builds a swarm with a random identity, which dials but doesn't listen
+func newDialer(transportNames, channels []string) (*swarm.Swarm, error) {
+	priv, pub, err := lCrypt.GenerateKeyPair(lCrypt.Ed25519, 0)
+	if err != nil {
+		return nil, err
+	}
+	pid, err := peer.IDFromPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	ps := pstoremem.NewPeerstore()
+	if err := ps.AddPrivKey(pid, priv); err != nil {
+		return nil, err
+	}
+	if err := ps.AddPubKey(pid, pub); err != nil {
+		return nil, err
+	}
+
+	dialer := swarm.NewSwarm(context.Background(), pid, ps, nil)
+	if err := addTransports(dialer, transportNames, channels); err != nil {
+		dialer.Close()
+		return nil, err
+	}
+
+	return dialer, nil
+}
+
+// requestDialBack asks the peer to dial the node back on the addrs, returning the addrs it was dialed on
+func (s *Service) requestDialBack(ctx context.Context, peerID peer.ID, addrs []ma.Multiaddr) ([]ma.Multiaddr, error) {
+	var addrStrs []string
+	for _, addr := range addrs {
+		addrStrs = append(addrStrs, addr.String())
+	}
+
+	dialedStrs, err := s.props.Protobyff.RequestDialBack(ctx, peerID, addrStrs)
+	if err != nil {
+		return nil, err
+	}
+
+	var dialed []ma.Multiaddr
+	for _, addrStr := range dialedStrs {
+		addr, err := ma.NewMultiaddr(addrStr)
+		if err != nil {
+			return nil, fmt.Errorf("err parsing dialed addr %s\n%v", addrStr, err)
+		}
+
+		dialed = append(dialed, addr)
+	}
+
+	return dialed, nil
+}
+
+// natAddrsFN returns the addrs mapped on the NAT device, once the NAT manager found it
+func natAddrsFN(natManager bhost.NATManager) func() []ma.Multiaddr {
+	return func() []ma.Multiaddr {
+		if natManager == nil || natManager.NAT() == nil {
+			return nil
+		}
+
+		return natManager.NAT().ExternalAddrs()
+	}
+}
@@ -0,0 +1,56 @@
+// +build unit
+
+package node
+
+import (
+	"testing"
+
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+func TestDialBackFN(t *testing.T) {
+	transportNames := []string{TransportTCP}
+	channels := []string{SecureChannelSecio}
+
+	n := newTestSwarm(t)
+	defer n.Close()
+	if err := addTransports(n, transportNames, channels); err != nil {
+		t.Fatal(err)
+	}
+	if err := listenOn(n, []string{"/ip4/127.0.0.1/tcp/0"}); err != nil {
+		t.Fatal(err)
+	}
+	addrs, err := n.InterfaceListenAddresses()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dialBack := dialBackFN(transportNames, channels)
+
+	dialed, err := dialBack(n.LocalPeer(), addrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dialed) != 1 || !dialed[0].Equal(addrs[0]) {
+		t.Errorf("expected to be dialed on %v; received %v", addrs, dialed)
+	}
+
+	// note: a peer that can't be dialed isn't an error
+	closed, err := ma.NewMultiaddr("/ip4/127.0.0.1/tcp/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dialed, err = dialBack(newTestSwarm(t).LocalPeer(), []ma.Multiaddr{closed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dialed) != 0 {
+		t.Errorf("expected no dialed addrs; received %v", dialed)
+	}
+}
+
+func TestNATAddrsFN(t *testing.T) {
+	if addrs := natAddrsFN(nil)(); addrs != nil {
+		t.Errorf("expected no nat addrs without a nat manager; received %v", addrs)
+	}
+}
@@ -0,0 +1,31 @@
+package node
+
+import (
+	"github.com/c3systems/c3-go/core/miner"
+	"github.com/c3systems/c3-go/core/p2p/reputation"
+
+	peer "github.com/libp2p/go-libp2p-peer"
+)
+
+// bansFilename is the file in the data dir the peer bans are persisted to
+const bansFilename = "bans.json"
+
+// receivedBlock is a mined block received over pubsub and the peer that published it, authenticated by the message signature
+type receivedBlock struct {
+	from       peer.ID
+	minedBlock *miner.MinedBlock
+}
+
+// penalizeFN reports peers' offenses to the reputation manager
+func penalizeFN(rep *reputation.Manager) func(peer.ID, reputation.Offense) {
+	return func(peerID peer.ID, offense reputation.Offense) {
+		if rep != nil && peerID != "" {
+			rep.Penalize(peerID, offense)
+		}
+	}
+}
+
+// penalize reports the peer's offense
+func (s *Service) penalize(peerID peer.ID, offense reputation.Offense) {
+	penalizeFN(s.props.Reputation)(peerID, offense)
+}
@@ -5,7 +5,9 @@ import (
 	"crypto/ecdsa"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/c3systems/c3-go/common/c3crypto"
@@ -17,8 +19,12 @@ import (
 	"github.com/c3systems/c3-go/core/ethereumclient"
 	"github.com/c3systems/c3-go/core/miner"
 	"github.com/c3systems/c3-go/core/p2p"
+	"github.com/c3systems/c3-go/core/p2p/autonat"
+	"github.com/c3systems/c3-go/core/p2p/peermanager"
 	"github.com/c3systems/c3-go/core/p2p/protobuff"
+	"github.com/c3systems/c3-go/core/p2p/reputation"
 	"github.com/c3systems/c3-go/core/p2p/store/leveldbstore"
+	"github.com/c3systems/c3-go/core/p2p/txrelay"
 	"github.com/c3systems/c3-go/core/sandbox"
 	colorlog "github.com/c3systems/c3-go/log/color"
 	loghooks "github.com/c3systems/c3-go/log/hooks"
@@ -26,26 +32,33 @@ import (
 	"github.com/c3systems/c3-go/node/store/redisstore"
 	"github.com/c3systems/c3-go/node/store/safemempool"
 	nodetypes "github.com/c3systems/c3-go/node/types"
+	"github.com/c3systems/c3-go/state"
 	redis "github.com/gomodule/redigo/redis"
-	ipfsaddr "github.com/ipfs/go-ipfs-addr"
 	bstore "github.com/ipfs/go-ipfs-blockstore"
+	circuit "github.com/libp2p/go-libp2p-circuit"
 	lCrypt "github.com/libp2p/go-libp2p-crypto"
 	host "github.com/libp2p/go-libp2p-host"
 	dht "github.com/libp2p/go-libp2p-kad-dht"
 	net "github.com/libp2p/go-libp2p-net"
 	peer "github.com/libp2p/go-libp2p-peer"
-	peerstore "github.com/libp2p/go-libp2p-peerstore"
 	pstoremem "github.com/libp2p/go-libp2p-peerstore/pstoremem"
 	floodsub "github.com/libp2p/go-libp2p-pubsub"
 	swarm "github.com/libp2p/go-libp2p-swarm"
 	discovery "github.com/libp2p/go-libp2p/p2p/discovery"
 	bhost "github.com/libp2p/go-libp2p/p2p/host/basic"
 	rhost "github.com/libp2p/go-libp2p/p2p/host/routed"
-	tcp "github.com/libp2p/go-tcp-transport"
-	ma "github.com/multiformats/go-multiaddr"
 	log "github.com/sirupsen/logrus"
 )
 
+// statePruneInterval is the number of blocks between prunes of the state tries no retained root reaches
+const statePruneInterval = 64
+
+// maxReorgDepth is the number of blocks walked back to find where a reorg's chains meet
+const maxReorgDepth = 256
+
+// peerstoreFilename is the file in the data dir the known peers are persisted to
+const peerstoreFilename = "peers.json"
+
 // Keys ...
 // note: any concern keeping these in memory? Maybe only fetch when needed?
 type Keys struct {
@@ -66,13 +79,34 @@ type Props struct {
 	Keys                Keys
 	Protobyff           protobuff.Interface
 	BlockDifficulty     int
+	MinerThreads        int // MinerThreads is the number of proof-of-work hashing workers
+	Mode                nodetypes.Mode
+	ChainID             string // ChainID namespaces the gossip topics, so nodes of different chains don't exchange messages
 	EOSClient           *eosclient.CheckpointClient
 	EthereumClient      *ethereumclient.CheckpointClient
+	StateGC             *state.GC // StateGC retains the state tries of the latest state blocks
+	Reputation          *reputation.Manager
+	PeerManager         *peermanager.Manager
+	AutoNAT             *autonat.Service // AutoNAT detects whether peers can dial the node
+	TxRelay             *txrelay.Relay   // TxRelay gossips transactions by hash and fetches the unseen ones
 }
 
 // Service ...
 type Service struct {
 	props Props
+
+	// minerSvc is the miner of the next block; its built states are resumed when it's canceled by a received block
+	minerMut sync.Mutex
+	minerSvc *miner.Service
+
+	// chain indexes the cumulative work of the stored blocks for the fork choice
+	chain *chainIndex
+	// statuses are the statuses negotiated with the connected peers; blocks are only synced from peers that serve them
+	statuses *peerStatuses
+
+	// syncing is whether the peers' chains are being synced; no miner runs until the sync spawns one on the head block
+	syncMut sync.Mutex
+	syncing bool
 }
 
 // newNode ...
@@ -95,6 +129,18 @@ func NewFullNode(cfg *nodetypes.Config) (*Service, error) {
 		return nil, errors.New("config is required to start the node")
 	}
 
+	mode, err := nodetypes.ParseMode(cfg.Mode.String())
+	if err != nil {
+		return nil, err
+	}
+	relayMode, err := nodetypes.ParseRelayMode(cfg.RelayMode.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Relays) > 0 && !relayMode.Enabled() {
+		return nil, fmt.Errorf("relays require the %s or %s relay mode", nodetypes.RelayModeClient, nodetypes.RelayModeServer)
+	}
+
 	var pwd *string
 	if cfg.Keys.Password != "" {
 		pwd = &cfg.Keys.Password
@@ -116,11 +162,6 @@ func NewFullNode(cfg *nodetypes.Config) (*Service, error) {
 		return nil, fmt.Errorf("err generating pid from public key\n%v", err)
 	}
 
-	listen, err := ma.NewMultiaddr(cfg.URI)
-	if err != nil {
-		return nil, fmt.Errorf("err parsing ipfs uri\n%v", err)
-	}
-
 	ps := pstoremem.NewPeerstore()
 	if err := ps.AddPrivKey(pid, wPriv); err != nil {
 		return nil, fmt.Errorf("err adding priv key\n%v", err)
@@ -131,14 +172,64 @@ func NewFullNode(cfg *nodetypes.Config) (*Service, error) {
 
 	ctx := context.Background()
 	swarmNet := swarm.NewSwarm(ctx, pid, ps, nil)
-	tcpTransport := tcp.NewTCPTransport(genUpgrader(swarmNet))
-	if err := swarmNet.AddTransport(tcpTransport); err != nil {
-		return nil, fmt.Errorf("err adding tcp transport\n%v", err)
+	transportNames := cfg.Transports
+	if len(transportNames) == 0 {
+		transportNames = config.DefaultTransports
+	}
+	channels := cfg.SecureChannels
+	if len(channels) == 0 {
+		channels = config.DefaultSecureChannels
+	}
+	if err := addTransports(swarmNet, transportNames, channels); err != nil {
+		return nil, fmt.Errorf("err adding transports\n%v", err)
+	}
+	if err := listenOn(swarmNet, append([]string{cfg.URI}, cfg.ListenAddrs...)); err != nil {
+		return nil, fmt.Errorf("err adding swam listen addrs\n%v", err)
+	}
+
+	var natManager bhost.NATManager
+	if cfg.NATPortMap {
+		natManager = bhost.NewNATManager(swarmNet)
 	}
-	if err := swarmNet.AddListenAddr(listen); err != nil {
-		return nil, fmt.Errorf("err adding swam listen addr\n%v", err)
+
+	n := new(Service)
+
+	// note: while the node isn't reachable, it advertises circuit addrs through its relays
+	autoNAT, err := autonat.New(&autonat.Props{
+		Network:    swarmNet,
+		DialBackFN: n.requestDialBack,
+		NATAddrsFN: natAddrsFN(natManager),
+		Relays:     cfg.Relays,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("err building autonat service\n%v", err)
+	}
+
+	hostOpts := &bhost.HostOpts{
+		AddrsFactory: autoNAT.AddrsFactory,
+	}
+	if natManager != nil {
+		hostOpts.NATManager = func(net.Network) bhost.NATManager { return natManager }
+	}
+	bNode, err := bhost.NewHost(ctx, swarmNet, hostOpts)
+	if err != nil {
+		return nil, fmt.Errorf("err building host\n%v", err)
+	}
+
+	if relayMode.Enabled() {
+		upgrader, err := genUpgrader(swarmNet, channels)
+		if err != nil {
+			return nil, fmt.Errorf("err building relay upgrader\n%v", err)
+		}
+
+		var relayOpts []circuit.RelayOpt
+		if relayMode.Hops() {
+			relayOpts = append(relayOpts, circuit.OptHop)
+		}
+		if err := circuit.AddRelayTransport(ctx, bNode, upgrader, relayOpts...); err != nil {
+			return nil, fmt.Errorf("err adding relay transport\n%v", err)
+		}
 	}
-	bNode := bhost.New(swarmNet)
 
 	dhtSvc, err := dht.New(ctx, bNode)
 	if err != nil {
@@ -151,40 +242,74 @@ func NewFullNode(cfg *nodetypes.Config) (*Service, error) {
 	newNode := rhost.Wrap(bNode, dhtSvc)
 	h = newNode
 
-	discoverySvc, err := discovery.NewMdnsService(ctx, newNode, time.Second, "c3")
+	// note: banned peers are disconnected and their messages dropped
+	rep, err := reputation.New(&reputation.Props{
+		BansPath: filepath.Join(cfg.DataDir, bansFilename),
+		DisconnectFN: func(p peer.ID) {
+			if err := newNode.Network().ClosePeer(p); err != nil {
+				log.Errorf("[node] err disconnecting banned peer %s\n%v", p.Pretty(), err)
+			}
+		},
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error starting discovery service\n%v", err)
+		return nil, fmt.Errorf("err loading peer bans\n%v", err)
 	}
-	discoverySvc.RegisterNotifee(&DiscoveryNotifee{newNode})
 
-	pubsub, err := floodsub.NewFloodSub(ctx, newNode)
+	// note: messages must be signed by their author, so a peer can't forge the author that's penalized for an invalid block
+	pubsub, err := floodsub.NewGossipSub(ctx, newNode, floodsub.WithBlacklist(rep), floodsub.WithMessageSigning(true), floodsub.WithStrictSignatureVerification(true))
 	if err != nil {
 		return nil, fmt.Errorf("err building new pubsub service\n%v", err)
 	}
 
+	chainID := cfg.ChainID
+	if chainID == "" {
+		chainID = config.DefaultChainID
+	}
+	relay, err := txrelay.New(&txrelay.Props{
+		Self:       newNode.ID(),
+		FetchFN:    n.fetchTransactions,
+		GetTxFN:    n.getPendingTx,
+		PenalizeFN: penalizeFN(rep),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("err building tx relay\n%v", err)
+	}
+	if err := registerTopicValidators(pubsub, chainID, uint64(cfg.BlockDifficulty), rep, relay); err != nil {
+		return nil, err
+	}
+
 	for i, addr := range newNode.Addrs() {
 		log.Printf(colorlog.Green("[node] %d: %s/ipfs/%s\n", i, addr, newNode.ID().Pretty()))
 	}
 
+	bootstrapPeers := cfg.BootstrapPeers
 	if cfg.Peer != "" {
-		addr, err := ipfsaddr.ParseString(cfg.Peer)
-		if err != nil {
-			return nil, fmt.Errorf("err parsing node uri flag: %s\n%v", cfg.URI, err)
-		}
+		bootstrapPeers = append([]string{cfg.Peer}, bootstrapPeers...)
+	}
+	// note: the relays are kept connected like the bootstrap peers
+	bootstrapPeers = append(bootstrapPeers, cfg.Relays...)
+	peerManager, err := peermanager.New(&peermanager.Props{
+		Host:           newNode,
+		BootstrapPeers: bootstrapPeers,
+		PeerstorePath:  filepath.Join(cfg.DataDir, peerstoreFilename),
+		MinPeers:       cfg.MinPeers,
+		MaxPeers:       cfg.MaxPeers,
+		BannedFN:       rep.Banned,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("err building peer manager\n%v", err)
+	}
+	if err := peerManager.Bootstrap(ctx); err != nil {
+		return nil, fmt.Errorf("[node] bootstrapping peers failed\n%v", err)
+	}
+	go peerManager.Run(ctx)
 
-		pinfo, err := peerstore.InfoFromP2pAddr(addr.Multiaddr())
+	if cfg.MDNS {
+		discoverySvc, err := discovery.NewMdnsService(ctx, newNode, time.Second, "c3")
 		if err != nil {
-			return nil, fmt.Errorf("err getting info from peerstore\n%v", err)
+			return nil, fmt.Errorf("error starting discovery service\n%v", err)
 		}
-
-		log.Println("[node] FULL", addr.String())
-		log.Println("[node] PIN INFO", pinfo)
-
-		if err := newNode.Connect(ctx, *pinfo); err != nil {
-			return nil, fmt.Errorf("[node] bootstrapping a peer failed\n%v", err)
-		}
-
-		newNode.Peerstore().AddAddrs(pinfo.ID, pinfo.Addrs, peerstore.PermanentAddrTTL)
+		discoverySvc.RegisterNotifee(peerManager)
 	}
 
 	var memPool nodestore.Interface
@@ -225,21 +350,43 @@ func NewFullNode(cfg *nodetypes.Config) (*Service, error) {
 	// TODO: implement metrics? https://github.com/ipfs/go-ds-measure
 	blocks := bstore.NewBlockstore(diskStore)
 
+	// note: trie nodes are stored as blocks so peers can fetch them; their cids and reference counts stay on disk
+	stateDB := state.NewBlockstoreDatabase(blocks, diskStore)
+	stateGC, err := state.NewGC(stateDB, state.DefaultRetainedRoots, diskStore)
+	if err != nil {
+		return nil, fmt.Errorf("[node] err building state gc\n%v", err)
+	}
+
+	stateCache, err := state.NewCache(state.DefaultCacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("[node] err building state cache\n%v", err)
+	}
+
 	p2pSvc, err := p2p.New(&p2p.Props{
 		BlockStore: blocks,
 		Host:       newNode,
 		Router:     dhtSvc,
+		StateDB:    stateDB,
+		StateCache: stateCache,
+		// note: the diffs and snapshots of the dApp states are fetched in batches from the peers serving objects
+		FetchObjectsFN: n.fetchObjects,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error starting ipfs p2p network\n%v", err)
 	}
 
-	n := new(Service)
+	getStatusFN := statusFN(chainID, mode, memPool)
 	pBuff, err := protobuff.NewNode(&protobuff.Props{
 		Host:                   newNode,
 		GetHeadBlockFN:         memPool.GetHeadBlock,
 		BroadcastTransactionFN: n.BroadcastTransaction,
 		AddPendingTxFN:         memPool.AddTx,
+		GetBlocksFN:            n.getBlocks,
+		GetObjectsFN:           n.getObjects,
+		PenalizeFN:             penalizeFN(rep),
+		GetStatusFN:            getStatusFN,
+		DialBackFN:             dialBackFN(transportNames, channels),
+		GetTransactionsFN:      relay.Transactions,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error starting protobuff node\n%v", err)
@@ -270,28 +417,53 @@ func NewFullNode(cfg *nodetypes.Config) (*Service, error) {
 
 	log.Printf("[miner] set mainchain genesis block with cid %v", c)
 
-	nextBlock := initialBlock
+	// note: the status handshake reports the stored head block until the peers' chains are synced
+	if err := memPool.SetHeadBlock(initialBlock); err != nil {
+		return nil, fmt.Errorf("err setting head block\n%v", err)
+	}
+
+	n.statuses = newPeerStatuses()
+	nb := &net.NotifyBundle{
+		ConnectedF: func(network net.Network, conn net.Conn) {
+			if rep.Banned(conn.RemotePeer()) {
+				log.Printf("[node] refusing banned peer %s", conn.RemotePeer().Pretty())
+				if err := conn.Close(); err != nil {
+					log.Errorf("[node] err closing banned peer's connection\n%v", err)
+				}
+				return
+			}
+
+			// note: the handshake opens streams, which can't be done from the notification
+			go func() {
+				if checkPeerStatus(network, conn.RemotePeer(), getStatusFN, pBuff, rep, n.statuses) {
+					onConn(network, conn)
+				}
+			}()
+		},
+		DisconnectedF: func(network net.Network, conn net.Conn) {
+			if len(network.ConnsToPeer(conn.RemotePeer())) == 0 {
+				n.statuses.remove(conn.RemotePeer())
+			}
+		},
+	}
+	newNode.Network().Notify(nb)
+
+	// note: the peers connected before the protobuff node was built; only compatible peers are synced from
+	peers := checkPeersStatus(newNode.Network(), newNode.Network().Peers(), getStatusFN, pBuff, rep, n.statuses)
 
-	peers := newNode.Peerstore().Peers()
-	if len(peers) > 1 {
+	var candidates []*headCandidate
+	if len(peers) > 0 {
 		if err := sendEcho(newNode.ID(), peers, pBuff); err != nil {
 			log.Errorln("error echoing peer; is peer online?")
 			return nil, fmt.Errorf("err echoing peer\n%v", err)
 		}
-		if err := fetchHeadBlock(newNode.ID(), nextBlock, peers, pBuff); err != nil {
+		candidates, err = fetchHeadBlocks(newNode.ID(), initialBlock, n.statuses.withCapability(peers, protobuff.CapabilityHeaders), pBuff, uint64(cfg.BlockDifficulty))
+		if err != nil {
 			return nil, fmt.Errorf("err fetching headblock\n%v", err)
 		}
 	}
 
-	if err := memPool.SetHeadBlock(nextBlock); err != nil {
-		return nil, fmt.Errorf("err setting head block\n%v", err)
-	}
-
-	nb := &net.NotifyBundle{
-		ConnectedF: onConn,
-	}
-	newNode.Network().Notify(nb)
-
+	n.chain = newChainIndex(diskStore)
 	n.props = Props{
 		Context:             ctx,
 		SubscriberChannel:   make(chan interface{}),
@@ -306,23 +478,38 @@ func NewFullNode(cfg *nodetypes.Config) (*Service, error) {
 			Pub:  pub,
 		},
 		BlockDifficulty: cfg.BlockDifficulty,
+		MinerThreads:    cfg.MinerThreads,
+		Mode:            mode,
+		ChainID:         chainID,
 		EOSClient:       cfg.EOSClient,
 		EthereumClient:  cfg.EthereumClient,
+		StateGC:         stateGC,
+		Reputation:      rep,
+		PeerManager:     peerManager,
+		AutoNAT:         autoNAT,
+		TxRelay:         relay,
 	}
 
 	if err := n.listenForEvents(); err != nil {
 		return nil, fmt.Errorf("error starting listener\n%v", err)
 	}
-	// TODO: add a cli flag to determine if the node mines
-	if err := n.spawnNextBlockMiner(nextBlock); err != nil {
-		return nil, fmt.Errorf("error starting miner in main start method\n%v", err)
+	go autoNAT.Run(ctx)
+	if len(candidates) > 0 {
+		// note: the peers' head blocks are adopted, and mined on, once their chains are synced and verified
+		n.syncing = true
+		go n.syncHeadBlock(candidates)
+	} else if mode.Mines() {
+		if err := n.spawnNextBlockMiner(initialBlock); err != nil {
+			return nil, fmt.Errorf("error starting miner in main start method\n%v", err)
+		}
 	}
-	log.Printf("[node] started %s", newNode.ID().Pretty())
+	log.Printf("[node] started %s in %s mode on chain %s", newNode.ID().Pretty(), mode, chainID)
 
 	return n, nil
 }
 
-func (s *Service) spawnNextBlockMiner(prevBlock *mainchain.Block) error {
+// spawnNextBlockMiner mines the block after the previous block, reusing the prebuilt states of a canceled miner
+func (s *Service) spawnNextBlockMiner(prevBlock *mainchain.Block, prebuilt ...*miner.ImageStates) error {
 	pendingTransactions, err := s.props.Store.GatherPendingTransactions()
 	if err != nil {
 		log.Errorf("[node] error gathering pending transactions; %v", err)
@@ -349,6 +536,12 @@ func (s *Service) spawnNextBlockMiner(prevBlock *mainchain.Block) error {
 
 	log.Printf("[node] block difficult level: %v", blockDifficulty)
 
+	sb, err := sandbox.New(nil)
+	if err != nil {
+		log.Errorf("[node] error building sandbox; %v", err)
+		return err
+	}
+
 	ch := make(chan interface{})
 	ctx, cancel := context.WithCancel(context.Background())
 	minerSvc, err := miner.New(&miner.Props{
@@ -358,11 +551,13 @@ func (s *Service) spawnNextBlockMiner(prevBlock *mainchain.Block) error {
 		Channel:             ch,
 		Async:               true, // TODO: need to make this a cli flag
 		P2P:                 s.props.P2P,
-		Sandbox:             sandbox.New(nil),
+		Sandbox:             sb,
 		EncodedMinerAddress: encMinerAddr,
 		PendingTransactions: pendingTransactions,
 		RemoveTx:            s.props.Store.RemoveTx,
 		Simulated:           simulated,
+		Threads:             s.props.MinerThreads,
+		PrebuiltStates:      prebuilt,
 	})
 	if err != nil {
 		log.Errorf("[node] err building miner\n%v", err)
@@ -371,6 +566,10 @@ func (s *Service) spawnNextBlockMiner(prevBlock *mainchain.Block) error {
 		return err
 	}
 
+	s.minerMut.Lock()
+	s.minerSvc = minerSvc
+	s.minerMut.Unlock()
+
 	if err := minerSvc.SpawnMiner(); err != nil {
 		log.Errorf("[node] err spawning miner\n%v", err)
 		cancel()
@@ -413,7 +612,7 @@ func (s *Service) spawnMinerListener(cancel context.CancelFunc, minerChan chan i
 								return
 							}
 
-							if err := s.spawnNextBlockMiner(&nextBlock); err != nil {
+							if err := s.spawnMinerAfterSync(&nextBlock); err != nil {
 								log.Errorf("[node] error starting miner\n%v", err)
 								return
 							}
@@ -422,6 +621,14 @@ func (s *Service) spawnMinerListener(cancel context.CancelFunc, minerChan chan i
 
 					minedBlock, _ := v.(*miner.MinedBlock)
 
+					// note: the state tries the miner built are unpinned by setMinedBlockData once the block is stored
+					stored := false
+					defer func() {
+						if !stored {
+							s.releaseStateRoots(minedBlock)
+						}
+					}()
+
 					pendingBlocks, err := s.props.Store.GetPendingMainchainBlocks()
 					if err != nil {
 						log.Errorf("[node] err checking pending mainchain blocks\n%v", err)
@@ -474,6 +681,7 @@ func (s *Service) spawnMinerListener(cancel context.CancelFunc, minerChan chan i
 
 					go s.checkpointBlock(minedBlock)
 
+					stored = true
 					go func() {
 						if err := s.setMinedBlockData(minedBlock); err != nil {
 							log.Errorf("[node] err setting mined block data\n%v", err)
@@ -509,8 +717,7 @@ func (s *Service) spawnMinerListener(cancel context.CancelFunc, minerChan chan i
 			}
 		case <-s.props.CancelMinersChannel:
 			{
-				// TODO: check if any of the transactions or state image hashes we're mining were included in the new block. If not, we can largely continue
-				// with an updated prev bloch hash, and number, etc.
+				// note: the states built for the images the new block didn't touch are resumed by the next miner
 				cancel()
 
 				return
@@ -530,7 +737,7 @@ func (s *Service) listenForEvents() error {
 }
 
 func (s *Service) spawnBlocksListener() error {
-	sub, err := s.props.Pubsub.Subscribe("blocks")
+	sub, err := s.props.Pubsub.Subscribe(blocksTopic(s.props.ChainID))
 	if err != nil {
 		return err
 	}
@@ -554,7 +761,10 @@ func (s *Service) spawnBlocksListener() error {
 				continue
 			}
 
-			s.props.SubscriberChannel <- block
+			s.props.SubscriberChannel <- &receivedBlock{
+				from:       msg.GetFrom(),
+				minedBlock: block,
+			}
 		}
 	}()
 
@@ -562,7 +772,7 @@ func (s *Service) spawnBlocksListener() error {
 }
 
 func (s *Service) spawnTransactionsListener() error {
-	sub, err := s.props.Pubsub.Subscribe("transactions")
+	sub, err := s.props.Pubsub.Subscribe(txAnnouncementsTopic(s.props.ChainID))
 	if err != nil {
 		return err
 	}
@@ -580,11 +790,17 @@ func (s *Service) spawnTransactionsListener() error {
 				continue
 			}
 
-			tx := new(statechain.Transaction)
-			if err := tx.Deserialize(msg.GetData()); err != nil {
+			// note: the tx was fetched when the announcement was validated
+			hash := string(msg.GetData())
+			tx, err := s.props.TxRelay.Transaction(hash)
+			if err != nil {
 				s.props.SubscriberChannel <- err
 				continue
 			}
+			if tx == nil {
+				log.Warnf("[node] announced tx %s is no longer cached", hash)
+				continue
+			}
 
 			s.props.SubscriberChannel <- tx
 		}
@@ -606,10 +822,11 @@ func (s *Service) BroadcastMinedBlock(minedBlock *miner.MinedBlock) error {
 		return err
 	}
 
-	return s.props.Pubsub.Publish("blocks", data)
+	return s.props.Pubsub.Publish(blocksTopic(s.props.ChainID), data)
 }
 
-// BroadcastTransaction ...
+// BroadcastTransaction announces the transaction's hash; peers fetch the transaction from the node.
+// note: a transaction the node has already seen isn't announced again
 func (s *Service) BroadcastTransaction(tx *statechain.Transaction) (*nodetypes.SendTxResponse, error) {
 	if tx == nil {
 		log.Errorln("error; cannot broadcast nil transaction")
@@ -618,20 +835,24 @@ func (s *Service) BroadcastTransaction(tx *statechain.Transaction) (*nodetypes.S
 
 	var res nodetypes.SendTxResponse
 
-	data, err := tx.Serialize()
+	isNew, err := s.props.TxRelay.Add(tx)
 	if err != nil {
-		log.Errorf("[node] error serializing transaction; %v", err)
+		log.Errorf("[node] error adding transaction to the relay; %v", err)
 		return nil, err
 	}
 
-	if err := s.props.Pubsub.Publish("transactions", data); err != nil {
-		log.Errorf("[node] error publishing transaction; %v", err)
-		return nil, err
+	res.TxHash = tx.Props().TxHash
+	if !isNew {
+		log.Printf("[node] transaction %s already announced", *tx.Props().TxHash)
+		return &res, nil
 	}
 
-	res.TxHash = tx.Props().TxHash
+	if err := s.props.Pubsub.Publish(txAnnouncementsTopic(s.props.ChainID), []byte(*tx.Props().TxHash)); err != nil {
+		log.Errorf("[node] error publishing transaction announcement; %v", err)
+		return nil, err
+	}
 
-	log.Printf("[node] transaction %s broadcasted", *tx.Props().TxHash)
+	log.Printf("[node] transaction %s announced", *tx.Props().TxHash)
 	return &res, nil
 }
 
@@ -649,7 +870,7 @@ func (s *Service) BroadcastTransaction(tx *statechain.Transaction) (*nodetypes.S
 //return &res, err
 //}
 
-func (s *Service) handleReceiptOfMinedBlock(minedBlock *miner.MinedBlock) {
+func (s *Service) handleReceiptOfMinedBlock(from peer.ID, minedBlock *miner.MinedBlock) {
 	log.Println("[node] handling receipt of mined block")
 
 	if minedBlock == nil {
@@ -667,6 +888,12 @@ func (s *Service) handleReceiptOfMinedBlock(minedBlock *miner.MinedBlock) {
 
 	log.Println(colorlog.Yellow("[node] received mined block on the channel\nblock number: %s", minedBlock.NextBlock.Props().BlockNumber))
 
+	if !s.meetsRequiredDifficulty(minedBlock.NextBlock) {
+		log.Errorf("[node] received block %s isn't mined at the required difficulty", minedBlock.NextBlock.Props().BlockNumber)
+		s.penalize(from, reputation.OffenseInvalidBlock)
+		return
+	}
+
 	if err := s.props.Store.SetPendingMainchainBlock(minedBlock.NextBlock); err != nil {
 		log.Errorf("[node] err setting pending mainchain block\n%v", err)
 		return
@@ -684,7 +911,25 @@ func (s *Service) handleReceiptOfMinedBlock(minedBlock *miner.MinedBlock) {
 	// note: timeout should be a cli flag
 	ctx, cancel := context.WithTimeout(context.Background(), config.MinedBlockVerificationTimeout)
 	defer cancel()
-	ok, err := miner.VerifyMinedBlock(ctx, s.props.P2P, sandbox.New(nil), minedBlock)
+	var (
+		ok  bool
+		err error
+	)
+	// note: the state tries built verifying the block are unpinned by setMinedBlockData once it's stored
+	stored := false
+	defer func() {
+		if !stored {
+			s.releaseStateRoots(minedBlock)
+		}
+	}()
+	if s.props.Mode.ExecutesDApps() {
+		var sb *sandbox.Service
+		if sb, err = sandbox.New(nil); err == nil {
+			ok, err = miner.VerifyMinedBlock(ctx, s.props.P2P, sb, minedBlock)
+		}
+	} else {
+		ok, err = miner.VerifyMinedBlockProofs(ctx, minedBlock)
+	}
 	if err != nil {
 		log.Errorf("[node] received err while verifying mined block\nblock: %v\nerr: %v", *minedBlock.NextBlock, err)
 		return
@@ -693,6 +938,7 @@ func (s *Service) handleReceiptOfMinedBlock(minedBlock *miner.MinedBlock) {
 	// note: ping the other nodes to tell them we didn't accept the block? See if they did?
 	if !ok {
 		log.Error("[node] received invalid mined block")
+		s.penalize(from, reputation.OffenseInvalidBlock)
 		return
 	}
 	log.Println("[node] mined block was validated")
@@ -722,7 +968,15 @@ func (s *Service) handleReceiptOfMinedBlock(minedBlock *miner.MinedBlock) {
 	}
 
 	// note: block is valid, keep it
-	s.props.CancelMinersChannel <- struct{}{}
+	var prebuilt []*miner.ImageStates
+	if s.props.Mode.Mines() {
+		prebuilt = s.resumableStates(minedBlock)
+		s.cancelMiner()
+	}
+
+	if localHeadBlock.Props().BlockHash != nil && minedBlock.NextBlock.Props().PrevBlockHash != *localHeadBlock.Props().BlockHash {
+		s.handleReorg(&localHeadBlock, minedBlock.NextBlock)
+	}
 
 	if err := s.props.Store.SetHeadBlock(minedBlock.NextBlock); err != nil {
 		log.Errorf("[node] err setting head block in node store\n%v", err)
@@ -733,6 +987,7 @@ func (s *Service) handleReceiptOfMinedBlock(minedBlock *miner.MinedBlock) {
 		return
 	}
 
+	stored = true
 	go func() {
 		if err := s.setMinedBlockData(minedBlock); err != nil {
 			log.Errorf("[node] err setting mined block data\n%v", err)
@@ -744,6 +999,10 @@ func (s *Service) handleReceiptOfMinedBlock(minedBlock *miner.MinedBlock) {
 		return
 	}
 
+	if !s.props.Mode.Mines() {
+		return
+	}
+
 	// note: start mining the next block, but don't start if there are still pending blocks
 	// TODO: if any of the above fails, we may never get here and may be stuck!
 	pendingBlocks, err := s.props.Store.GetPendingMainchainBlocks()
@@ -758,12 +1017,106 @@ func (s *Service) handleReceiptOfMinedBlock(minedBlock *miner.MinedBlock) {
 		return
 	}
 
-	if err := s.spawnNextBlockMiner(minedBlock.NextBlock); err != nil {
+	if err := s.spawnMinerAfterSync(minedBlock.NextBlock, prebuilt...); err != nil {
 		log.Errorf("err starting miner\n%v", err)
 		return
 	}
 }
 
+// cancelMiner cancels the running miner, if any.
+// note: the send doesn't block when no miner listener runs, e.g. while syncing, so no stale cancel is left for the next miner.
+func (s *Service) cancelMiner() {
+	select {
+	case s.props.CancelMinersChannel <- struct{}{}:
+	default:
+	}
+}
+
+// spawnMinerAfterSync spawns the miner of the block after the previous block, unless the peers' chains are being synced.
+// note: the sync spawns the miner on the head block once it's done.
+func (s *Service) spawnMinerAfterSync(prevBlock *mainchain.Block, prebuilt ...*miner.ImageStates) error {
+	s.syncMut.Lock()
+	defer s.syncMut.Unlock()
+
+	if s.syncing {
+		log.Println("[node] syncing the peers' chains; the miner starts once the sync is done")
+		return nil
+	}
+
+	return s.spawnNextBlockMiner(prevBlock, prebuilt...)
+}
+
+// finishSync marks the sync done and spawns the miner on the head block
+func (s *Service) finishSync() {
+	s.syncMut.Lock()
+	defer s.syncMut.Unlock()
+
+	s.syncing = false
+	if !s.props.Mode.Mines() {
+		return
+	}
+
+	head, err := s.props.Store.GetHeadBlock()
+	if err != nil {
+		log.Errorf("[node] err getting head block for miner\n%v", err)
+		return
+	}
+	if err := s.spawnNextBlockMiner(&head); err != nil {
+		log.Errorf("[node] error starting miner\n%v", err)
+	}
+}
+
+// resumableStates returns the states the current miner built for the images the received block didn't touch
+func (s *Service) resumableStates(minedBlock *miner.MinedBlock) []*miner.ImageStates {
+	s.minerMut.Lock()
+	minerSvc := s.minerSvc
+	s.minerMut.Unlock()
+	if minerSvc == nil {
+		return nil
+	}
+
+	// note: after a reorg the images' most recent state blocks may have changed
+	prevBlock := minerSvc.Props().PreviousBlock
+	if prevBlock == nil || prevBlock.Props().BlockHash == nil || *prevBlock.Props().BlockHash != minedBlock.NextBlock.Props().PrevBlockHash {
+		return nil
+	}
+
+	touched := make(map[string]bool)
+	for _, statechainBlock := range minedBlock.StatechainBlocksMap {
+		if statechainBlock != nil {
+			touched[statechainBlock.Props().ImageHash] = true
+		}
+	}
+
+	var resumable []*miner.ImageStates
+	for _, image := range minerSvc.BuiltStates() {
+		if touched[image.ImageHash] {
+			continue
+		}
+
+		// note: a transaction included by the received block can't be mined again
+		included := false
+		for _, tx := range image.Transactions {
+			if tx == nil || tx.Props().TxHash == nil {
+				included = true
+				break
+			}
+			if _, ok := minedBlock.TransactionsMap[*tx.Props().TxHash]; ok {
+				included = true
+				break
+			}
+		}
+		if included {
+			continue
+		}
+
+		resumable = append(resumable, image)
+	}
+
+	log.Printf("[node] resuming the state blocks of %v images", len(resumable))
+	return resumable
+}
+
 // HandleReceiptOfStatechainTransaction ...
 func (s *Service) HandleReceiptOfStatechainTransaction(tx *statechain.Transaction) {
 	if tx == nil {
@@ -827,41 +1180,58 @@ func (s *Service) setMinedBlockData(minedBlock *miner.MinedBlock) error {
 
 	log.Println(colorlog.Green("[node] storing mined block data\nblock number: %s\nblock hash: %s\nstate blocks merkle hash: %s\nstate chain blocks: %v\ntransactions: %v", blk.BlockNumber, *blk.BlockHash, blk.StateBlocksMerkleHash, len(minedBlock.StatechainBlocksMap), len(minedBlock.TransactionsMap)))
 
+	var cacheKeys []state.CacheKey
 	for _, statechainBlock := range minedBlock.StatechainBlocksMap {
 		if statechainBlock == nil {
 			log.Errorf("[node] mined block state chain block is nil, continuing")
 			continue
 		}
+		if statechainBlock.Props().BlockHash != nil {
+			cacheKeys = append(cacheKeys, state.CacheKey{ImageHash: statechainBlock.Props().ImageHash, BlockHash: *statechainBlock.Props().BlockHash})
+		}
 
 		if _, err := s.props.P2P.SetStatechainBlock(statechainBlock); err != nil {
 			log.Errorf("[node] error setting state chain block; %v", err)
 			return err
 		}
 
+		if s.props.StateGC != nil {
+			if err := s.props.StateGC.Add(statechainBlock.Props().ImageHash, statechainBlock.Props().StateCurrentHash); err != nil {
+				log.Errorf("[node] error retaining state root %s; %v", statechainBlock.Props().StateCurrentHash, err)
+			}
+		}
+		miner.ReleaseStateRoot(s.props.P2P, statechainBlock.Props().StateCurrentHash)
+
 		log.Println(colorlog.Green("[node] storing state chain block\nstate chain block number: %s\nstate chain block hash: %s\nstate current hash: %s\ntx hash: %s\nprev state block hash: %s\nprev state diff hash: %s", statechainBlock.Props().BlockNumber, *statechainBlock.Props().BlockHash, statechainBlock.Props().StateCurrentHash, statechainBlock.Props().TxHash, statechainBlock.Props().PrevBlockHash, statechainBlock.Props().StatePrevDiffHash))
 	}
 
-	for _, transaction := range minedBlock.TransactionsMap {
-		if transaction == nil {
-			log.Errorf("[node] mined block transaction is nil, continuing")
-			continue
-		}
+	// note: the cached states of the block's state blocks are invalidated if the block is abandoned on a reorg
+	miner.StateCache(s.props.P2P).Accept(*blk.BlockHash, cacheKeys...)
 
-		if _, err := s.props.P2P.SetStatechainTransaction(transaction); err != nil {
-			log.Errorf("[node] error setting state chain transaction; %v", err)
-			return err
-		}
-	}
+	// note: light nodes keep the headers and proofs, not the transactions and diffs the states are built from
+	if s.props.Mode.ExecutesDApps() {
+		for _, transaction := range minedBlock.TransactionsMap {
+			if transaction == nil {
+				log.Errorf("[node] mined block transaction is nil, continuing")
+				continue
+			}
 
-	for _, diff := range minedBlock.DiffsMap {
-		if diff == nil {
-			log.Errorf("[node] mined block diff is nil, continuing")
-			continue
+			if _, err := s.props.P2P.SetStatechainTransaction(transaction); err != nil {
+				log.Errorf("[node] error setting state chain transaction; %v", err)
+				return err
+			}
 		}
 
-		if _, err := s.props.P2P.SetStatechainDiff(diff); err != nil {
-			log.Errorf("[node] error setting state chain diff diff; %v", err)
-			return err
+		for _, diff := range minedBlock.DiffsMap {
+			if diff == nil {
+				log.Errorf("[node] mined block diff is nil, continuing")
+				continue
+			}
+
+			if _, err := s.props.P2P.SetStatechainDiff(diff); err != nil {
+				log.Errorf("[node] error setting state chain diff diff; %v", err)
+				return err
+			}
 		}
 	}
 
@@ -881,10 +1251,106 @@ func (s *Service) setMinedBlockData(minedBlock *miner.MinedBlock) error {
 		log.Errorf("[node] error setting main chain block; %v", err)
 		return err
 	}
+	if _, err := s.chainWork(minedBlock.NextBlock); err != nil {
+		log.Errorf("[node] error indexing main chain block; %v", err)
+	}
+
+	// note: e.g. the tries built for blocks that another miner won
+	if blockNumber, err := hexutil.DecodeInt(blk.BlockNumber); err == nil && s.props.StateGC != nil && blockNumber%statePruneInterval == 0 {
+		pruned, err := s.props.StateGC.Prune()
+		if err != nil {
+			log.Errorf("[node] error pruning state tries; %v", err)
+		} else {
+			log.Printf("[node] pruned %v state trie nodes", pruned)
+		}
+	}
 
 	return nil
 }
 
+// releaseStateRoots unpins the state tries built for the mined block, when it isn't stored
+func (s *Service) releaseStateRoots(minedBlock *miner.MinedBlock) {
+	for _, statechainBlock := range minedBlock.StatechainBlocksMap {
+		if statechainBlock != nil {
+			miner.ReleaseStateRoot(s.props.P2P, statechainBlock.Props().StateCurrentHash)
+		}
+	}
+}
+
+// handleReorg invalidates the cached states of the blocks abandoned by switching from the old head to the new head
+func (s *Service) handleReorg(oldHead, newHead *mainchain.Block) {
+	cache := miner.StateCache(s.props.P2P)
+	if cache == nil {
+		return
+	}
+
+	abandoned, err := s.abandonedBlocks(oldHead, newHead)
+	if err != nil {
+		log.Errorf("[node] err finding the blocks abandoned by the reorg; purging the state cache\n%v", err)
+		cache.Purge()
+		return
+	}
+
+	var invalidated int
+	for _, blockHash := range abandoned {
+		invalidated += cache.Invalidate(blockHash)
+	}
+
+	log.Printf("[node] reorg abandoned %v blocks; invalidated %v cached states", len(abandoned), invalidated)
+}
+
+// abandonedBlocks returns the hashes of the blocks on the old head's chain that aren't on the new head's chain
+func (s *Service) abandonedBlocks(oldHead, newHead *mainchain.Block) ([]string, error) {
+	var abandoned []string
+
+	oldBlock := oldHead
+	newBlock := newHead
+	for depth := 0; depth < maxReorgDepth; depth++ {
+		if oldBlock.Props().BlockHash == nil || newBlock.Props().BlockHash == nil {
+			return nil, errors.New("nil block hash")
+		}
+		if *oldBlock.Props().BlockHash == *newBlock.Props().BlockHash {
+			return abandoned, nil
+		}
+
+		oldNumber, err := hexutil.DecodeUint64(oldBlock.Props().BlockNumber)
+		if err != nil {
+			return nil, err
+		}
+		newNumber, err := hexutil.DecodeUint64(newBlock.Props().BlockNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		// note: the higher block is walked back, or both when they're at the same height
+		if oldNumber >= newNumber {
+			abandoned = append(abandoned, *oldBlock.Props().BlockHash)
+			if oldNumber == 0 {
+				return abandoned, nil
+			}
+			if oldBlock, err = s.fetchPrevBlock(oldBlock); err != nil {
+				return nil, err
+			}
+		}
+		if newNumber >= oldNumber && newNumber > 0 {
+			if newBlock, err = s.fetchPrevBlock(newBlock); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return nil, errors.New("reorg is deeper than the max reorg depth")
+}
+
+func (s *Service) fetchPrevBlock(block *mainchain.Block) (*mainchain.Block, error) {
+	prevCID, err := p2p.GetCIDByHash(block.Props().PrevBlockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.props.P2P.GetMainchainBlock(prevCID)
+}
+
 func (s *Service) removeMinedTxs(minedBlock *miner.MinedBlock) error {
 	log.Println("[node] removing mined transactions for block")
 	var txs []string
@@ -929,10 +1395,10 @@ func (s *Service) Start() error {
 			err, _ := v.(error)
 			log.Errorf("[node] received an error on the channel %s", err)
 
-		case *miner.MinedBlock:
+		case *receivedBlock:
 			log.Print("[node] received mined block")
-			b, _ := v.(*miner.MinedBlock)
-			go s.handleReceiptOfMinedBlock(b)
+			b, _ := v.(*receivedBlock)
+			go s.handleReceiptOfMinedBlock(b.from, b.minedBlock)
 
 		case *statechain.Transaction:
 			log.Print("[node] received statechain transaction")
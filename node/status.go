@@ -0,0 +1,176 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/c3systems/c3-go/common/coder"
+	"github.com/c3systems/c3-go/common/hexutil"
+	"github.com/c3systems/c3-go/core/chain/mainchain"
+	"github.com/c3systems/c3-go/core/p2p/protobuff"
+	"github.com/c3systems/c3-go/core/p2p/reputation"
+	nodestore "github.com/c3systems/c3-go/node/store"
+	nodetypes "github.com/c3systems/c3-go/node/types"
+
+	net "github.com/libp2p/go-libp2p-net"
+	peer "github.com/libp2p/go-libp2p-peer"
+	log "github.com/sirupsen/logrus"
+)
+
+// statusTimeout is how long a connected peer has to answer the status handshake
+const statusTimeout = 10 * time.Second
+
+// capabilities are what a node in the mode serves its peers
+func capabilities(mode nodetypes.Mode) []string {
+	caps := []string{protobuff.CapabilityHeaders}
+	if mode.ExecutesDApps() {
+		caps = append(caps, protobuff.CapabilityBlocks, protobuff.CapabilityObjects)
+	}
+	if mode.Mines() {
+		caps = append(caps, protobuff.CapabilityMining)
+	}
+
+	return caps
+}
+
+// statusFN builds the local status exchanged with peers on connect
+func statusFN(chainID string, mode nodetypes.Mode, store nodestore.Interface) func() (*protobuff.PeerStatus, error) {
+	return func() (*protobuff.PeerStatus, error) {
+		head, err := store.GetHeadBlock()
+		if err != nil {
+			return nil, err
+		}
+
+		headHeight, err := hexutil.DecodeUint64(head.Props().BlockNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		return &protobuff.PeerStatus{
+			ProtocolVersion: protobuff.ProtocolVersion,
+			ChainID:         chainID,
+			GenesisHash:     mainchain.GenesisBlockHash,
+			HeadHeight:      headHeight,
+			Capabilities:    capabilities(mode),
+			CodecVersion:    uint64(coder.CurrentCode),
+		}, nil
+	}
+}
+
+// peerStatuses keeps the statuses the connected peers negotiated in the status handshake
+type peerStatuses struct {
+	mut      sync.RWMutex
+	statuses map[peer.ID]*protobuff.PeerStatus
+}
+
+func newPeerStatuses() *peerStatuses {
+	return &peerStatuses{
+		statuses: make(map[peer.ID]*protobuff.PeerStatus),
+	}
+}
+
+func (p *peerStatuses) set(peerID peer.ID, status *protobuff.PeerStatus) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	p.statuses[peerID] = status
+}
+
+func (p *peerStatuses) remove(peerID peer.ID) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	delete(p.statuses, peerID)
+}
+
+// withCapability returns the peers whose negotiated status advertises the capability
+func (p *peerStatuses) withCapability(peers []peer.ID, capability string) []peer.ID {
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+
+	var capable []peer.ID
+	for _, peerID := range peers {
+		if status, ok := p.statuses[peerID]; ok && status.HasCapability(capability) {
+			capable = append(capable, peerID)
+		}
+	}
+
+	return capable
+}
+
+// exchangeStatus runs the status handshake with the peer, returning the peer's status.
+// It fails with a *protobuff.IncompatibleError when the peer can't be talked to.
+func exchangeStatus(peerID peer.ID, getStatusFN func() (*protobuff.PeerStatus, error), pBuff protobuff.Interface) (*protobuff.PeerStatus, error) {
+	local, err := getStatusFN()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+	defer cancel()
+
+	remote, err := pBuff.ExchangeStatus(ctx, peerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := local.Compatible(remote); err != nil {
+		return nil, err
+	}
+
+	return remote, nil
+}
+
+// checkPeerStatus runs the status handshake with a connected peer, refusing the peer when it's incompatible or doesn't answer.
+// The status of an accepted peer is kept, and it returns whether the peer was accepted.
+// note: incompatible peers are banned, so they aren't redialed; unresponsive peers are only disconnected.
+func checkPeerStatus(network net.Network, peerID peer.ID, getStatusFN func() (*protobuff.PeerStatus, error), pBuff protobuff.Interface, rep *reputation.Manager, statuses *peerStatuses) bool {
+	status, err := exchangeStatus(peerID, getStatusFN, pBuff)
+	if err == nil {
+		log.Printf("[node] peer %s is on chain %s at height %v with capabilities %v", peerID.Pretty(), status.ChainID, status.HeadHeight, status.Capabilities)
+		statuses.set(peerID, status)
+		return true
+	}
+
+	log.Warnf("[node] refusing peer %s\n%v", peerID.Pretty(), err)
+	if isIncompatible(err) && rep != nil {
+		rep.Ban(peerID, reputation.DefaultBanDuration)
+		return false
+	}
+
+	if err := network.ClosePeer(peerID); err != nil {
+		log.Errorf("[node] err closing refused peer's connection\n%v", err)
+	}
+
+	return false
+}
+
+// checkPeersStatus runs the status handshake with the peers concurrently, returning the accepted peers
+func checkPeersStatus(network net.Network, peers []peer.ID, getStatusFN func() (*protobuff.PeerStatus, error), pBuff protobuff.Interface, rep *reputation.Manager, statuses *peerStatuses) []peer.ID {
+	var (
+		wg       sync.WaitGroup
+		mut      sync.Mutex
+		accepted []peer.ID
+	)
+	for _, peerID := range peers {
+		wg.Add(1)
+		go func(peerID peer.ID) {
+			defer wg.Done()
+			if !checkPeerStatus(network, peerID, getStatusFN, pBuff, rep, statuses) {
+				return
+			}
+
+			mut.Lock()
+			accepted = append(accepted, peerID)
+			mut.Unlock()
+		}(peerID)
+	}
+	wg.Wait()
+
+	return accepted
+}
+
+func isIncompatible(err error) bool {
+	_, ok := err.(*protobuff.IncompatibleError)
+	return ok
+}
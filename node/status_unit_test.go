@@ -0,0 +1,141 @@
+// +build unit
+
+package node
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/c3systems/c3-go/common/coder"
+	"github.com/c3systems/c3-go/core/chain/mainchain"
+	"github.com/c3systems/c3-go/core/p2p/protobuff"
+	pb "github.com/c3systems/c3-go/core/p2p/protobuff/pb"
+	"github.com/c3systems/c3-go/node/store/safemempool"
+	nodetypes "github.com/c3systems/c3-go/node/types"
+	peer "github.com/libp2p/go-libp2p-peer"
+)
+
+func TestCapabilities(t *testing.T) {
+	tests := []struct {
+		mode     nodetypes.Mode
+		expected []string
+	}{
+		{nodetypes.ModeMiner, []string{protobuff.CapabilityHeaders, protobuff.CapabilityBlocks, protobuff.CapabilityObjects, protobuff.CapabilityMining}},
+		{nodetypes.ModeFull, []string{protobuff.CapabilityHeaders, protobuff.CapabilityBlocks, protobuff.CapabilityObjects}},
+		{nodetypes.ModeLight, []string{protobuff.CapabilityHeaders}},
+	}
+
+	for _, tt := range tests {
+		if caps := capabilities(tt.mode); !reflect.DeepEqual(caps, tt.expected) {
+			t.Errorf("%s: expected %v; received %v", tt.mode, tt.expected, caps)
+		}
+	}
+}
+
+func TestStatusFN(t *testing.T) {
+	memPool, err := safemempool.New(&safemempool.Props{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := memPool.SetHeadBlock(buildHeadBlock(t, 7)); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := statusFN("testnet", nodetypes.ModeLight, memPool)()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &protobuff.PeerStatus{
+		ProtocolVersion: protobuff.ProtocolVersion,
+		ChainID:         "testnet",
+		GenesisHash:     mainchain.GenesisBlockHash,
+		HeadHeight:      7,
+		Capabilities:    []string{protobuff.CapabilityHeaders},
+		CodecVersion:    uint64(coder.CurrentCode),
+	}
+	if !reflect.DeepEqual(status, expected) {
+		t.Errorf("expected %v; received %v", expected, status)
+	}
+}
+
+func TestExchangeStatus(t *testing.T) {
+	local := &protobuff.PeerStatus{
+		ProtocolVersion: protobuff.ProtocolVersion,
+		ChainID:         "testnet",
+		GenesisHash:     mainchain.GenesisBlockHash,
+		HeadHeight:      3,
+		Capabilities:    capabilities(nodetypes.ModeMiner),
+		CodecVersion:    uint64(coder.CurrentCode),
+	}
+	getStatusFN := func() (*protobuff.PeerStatus, error) { return local, nil }
+
+	respond := func(edit func(resp *pb.StatusResponse)) *pb.StatusResponse {
+		resp := &pb.StatusResponse{
+			ProtocolVersion: local.ProtocolVersion,
+			ChainID:         local.ChainID,
+			GenesisHash:     local.GenesisHash,
+			HeadHeight:      9,
+			Capabilities:    []string{protobuff.CapabilityHeaders},
+			CodecVersion:    local.CodecVersion,
+		}
+		edit(resp)
+		return resp
+	}
+
+	pBuff := &fakeHeadBlocks{
+		statuses: map[peer.ID]*pb.StatusResponse{
+			"compatible":  respond(func(resp *pb.StatusResponse) {}),
+			"protocol":    respond(func(resp *pb.StatusResponse) { resp.ProtocolVersion++ }),
+			"codec":       respond(func(resp *pb.StatusResponse) { resp.CodecVersion++ }),
+			"chain":       respond(func(resp *pb.StatusResponse) { resp.ChainID = "mainnet" }),
+			"genesis":     respond(func(resp *pb.StatusResponse) { resp.GenesisHash = "0x00" }),
+			"unsupported": nil,
+		},
+	}
+
+	status, err := exchangeStatus("compatible", getStatusFN, pBuff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.HeadHeight != 9 || !status.HasCapability(protobuff.CapabilityHeaders) || status.HasCapability(protobuff.CapabilityMining) {
+		t.Errorf("expected the peer's status; received %v", status)
+	}
+
+	for _, peerID := range []peer.ID{"protocol", "codec", "chain", "genesis"} {
+		_, err := exchangeStatus(peerID, getStatusFN, pBuff)
+		if !isIncompatible(err) {
+			t.Errorf("%s: expected an incompatible peer error; received %v", peerID, err)
+		}
+	}
+
+	// note: peers that don't speak the protocol are refused but not banned
+	_, err = exchangeStatus("unsupported", getStatusFN, pBuff)
+	if err == nil || isIncompatible(err) {
+		t.Errorf("expected a send error; received %v", err)
+	}
+}
+
+func TestPeerStatuses(t *testing.T) {
+	statuses := newPeerStatuses()
+	statuses.set("full", &protobuff.PeerStatus{Capabilities: capabilities(nodetypes.ModeFull)})
+	statuses.set("light", &protobuff.PeerStatus{Capabilities: capabilities(nodetypes.ModeLight)})
+	statuses.set("gone", &protobuff.PeerStatus{Capabilities: capabilities(nodetypes.ModeMiner)})
+	statuses.remove("gone")
+
+	peers := []peer.ID{"full", "light", "gone", "unknown"}
+	tests := []struct {
+		capability string
+		expected   []peer.ID
+	}{
+		{protobuff.CapabilityHeaders, []peer.ID{"full", "light"}},
+		{protobuff.CapabilityBlocks, []peer.ID{"full"}},
+		{protobuff.CapabilityMining, nil},
+	}
+
+	for _, tt := range tests {
+		if capable := statuses.withCapability(peers, tt.capability); !reflect.DeepEqual(capable, tt.expected) {
+			t.Errorf("%s: expected %v; received %v", tt.capability, tt.expected, capable)
+		}
+	}
+}
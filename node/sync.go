@@ -0,0 +1,647 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/c3systems/c3-go/common/hexutil"
+	"github.com/c3systems/c3-go/config"
+	"github.com/c3systems/c3-go/core/chain/mainchain"
+	"github.com/c3systems/c3-go/core/chain/merkle"
+	"github.com/c3systems/c3-go/core/chain/statechain"
+	"github.com/c3systems/c3-go/core/miner"
+	"github.com/c3systems/c3-go/core/p2p"
+	"github.com/c3systems/c3-go/core/p2p/protobuff"
+	"github.com/c3systems/c3-go/core/p2p/reputation"
+	"github.com/c3systems/c3-go/core/sandbox"
+
+	cid "github.com/ipfs/go-cid"
+	peer "github.com/libp2p/go-libp2p-peer"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrBlockNotFound is returned when a block isn't in the local store
+	ErrBlockNotFound = errors.New("block not found")
+	// ErrBrokenChain is returned when fetched blocks don't link to each other
+	ErrBrokenChain = errors.New("fetched blocks don't form a chain")
+	// ErrInvalidFetchedBlock is returned when a fetched block fails verification
+	ErrInvalidFetchedBlock = errors.New("fetched block is invalid")
+	// ErrInvalidFetchedObject is returned when a fetched object doesn't match its cid
+	ErrInvalidFetchedObject = errors.New("fetched object doesn't match its cid")
+	// ErrNoBlocksPeer is returned when none of the peers serve mined blocks
+	ErrNoBlocksPeer = errors.New("no peer serves mined blocks")
+	// ErrForkedChain is returned when fetched blocks don't extend the previous block, e.g. the peer's chain forked from it.
+	// note: unlike a broken chain, a forked chain isn't an offense.
+	ErrForkedChain = errors.New("fetched blocks don't extend the previous block")
+	// ErrForkTooDeep is returned when the peer's chain doesn't meet the local chain within the max reorg depth
+	ErrForkTooDeep = errors.New("peer's chain forked deeper than the max reorg depth")
+)
+
+// forkSearchBatch is the number of blocks fetched per step when walking back a peer's chain to the fork block
+const forkSearchBatch uint64 = 16
+
+// getBlocks serves the serialized mined blocks from the height, read from the local store only.
+// note: the batch ends early at the head block or at a block whose data isn't stored locally.
+func (s *Service) getBlocks(fromHeight, count uint64) ([][]byte, error) {
+	head, err := s.props.Store.GetHeadBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	headHeight, err := hexutil.DecodeUint64(head.Props().BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if fromHeight > headHeight {
+		return nil, nil
+	}
+
+	toHeight := fromHeight + count - 1
+	if toHeight > headHeight {
+		toHeight = headHeight
+	}
+
+	// note: the blocks are looked up by height, rather than walking back from the head block
+	if err := s.indexHeights(&head); err != nil {
+		return nil, err
+	}
+
+	var (
+		blocks [][]byte
+		prev   *mainchain.Block
+	)
+	for height := fromHeight; height <= toHeight; height++ {
+		hash, err := s.chain.hashAt(height)
+		if err != nil {
+			return nil, err
+		}
+		block, err := s.localMainchainBlock(hash)
+		if err != nil {
+			return nil, err
+		}
+		if height > 0 && prev == nil {
+			if prev, err = s.localMainchainBlock(block.Props().PrevBlockHash); err != nil {
+				return nil, err
+			}
+		}
+
+		minedBlock, err := s.localMinedBlock(block, prev)
+		if err != nil {
+			log.Warnf("[node] err reading mined block %s from the local store\n%v", block.Props().BlockNumber, err)
+			break
+		}
+		data, err := minedBlock.Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		blocks = append(blocks, data)
+		prev = block
+	}
+
+	return blocks, nil
+}
+
+// getObjects serves the data of the cids that are in the local store
+func (s *Service) getObjects(cids []string) ([]string, [][]byte, error) {
+	var (
+		found   []string
+		objects [][]byte
+	)
+	for _, cidStr := range cids {
+		c, err := cid.Decode(cidStr)
+		if err != nil {
+			log.Warnf("[node] err decoding requested cid %s\n%v", cidStr, err)
+			continue
+		}
+
+		data, err := s.props.P2P.GetLocalBytes(&c)
+		if err != nil {
+			continue
+		}
+
+		found = append(found, cidStr)
+		objects = append(objects, data)
+	}
+
+	return found, objects, nil
+}
+
+// localMainchainBlock reads the mainchain block with the hash from the local store
+func (s *Service) localMainchainBlock(hash string) (*mainchain.Block, error) {
+	c, err := p2p.GetCIDByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.props.P2P.GetLocalBytes(c)
+	if err != nil {
+		return nil, ErrBlockNotFound
+	}
+
+	block := new(mainchain.Block)
+	if err := block.Deserialize(data); err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// localMinedBlock reads the statechain blocks, transactions, diffs and merkle tree of the mainchain block from the local store
+func (s *Service) localMinedBlock(block, prev *mainchain.Block) (*miner.MinedBlock, error) {
+	minedBlock := &miner.MinedBlock{
+		NextBlock:           block,
+		PreviousBlock:       prev,
+		StatechainBlocksMap: make(map[string]*statechain.Block),
+		TransactionsMap:     make(map[string]*statechain.Transaction),
+		DiffsMap:            make(map[string]*statechain.Diff),
+		MerkleTreesMap:      make(map[string]*merkle.Tree),
+	}
+
+	merkleHash := block.Props().StateBlocksMerkleHash
+	if merkleHash == "" || merkleHash == hexutil.EncodeString("") {
+		return minedBlock, nil
+	}
+
+	tree := new(merkle.Tree)
+	if err := s.readLocal(merkleHash, tree.Deserialize); err != nil {
+		return nil, err
+	}
+	minedBlock.MerkleTreesMap[merkleHash] = tree
+
+	for _, hash := range tree.Props().Hashes {
+		statechainBlock := new(statechain.Block)
+		if err := s.readLocal(hash, statechainBlock.Deserialize); err != nil {
+			return nil, err
+		}
+		minedBlock.StatechainBlocksMap[hash] = statechainBlock
+
+		tx := new(statechain.Transaction)
+		if err := s.readLocal(statechainBlock.Props().TxHash, tx.Deserialize); err != nil {
+			return nil, err
+		}
+		minedBlock.TransactionsMap[statechainBlock.Props().TxHash] = tx
+
+		diff := new(statechain.Diff)
+		if err := s.readLocal(statechainBlock.Props().StatePrevDiffHash, diff.Deserialize); err != nil {
+			return nil, err
+		}
+		minedBlock.DiffsMap[statechainBlock.Props().StatePrevDiffHash] = diff
+	}
+
+	return minedBlock, nil
+}
+
+// readLocal deserializes the object with the hash from the local store
+func (s *Service) readLocal(hash string, deserialize func([]byte) error) error {
+	c, err := p2p.GetCIDByHash(hash)
+	if err != nil {
+		return err
+	}
+
+	data, err := s.props.P2P.GetLocalBytes(c)
+	if err != nil {
+		return fmt.Errorf("object %s not found locally\n%v", hash, err)
+	}
+
+	return deserialize(data)
+}
+
+// FetchBlocks fetches a batch of mined blocks from the peer and checks they form a chain after the previous block.
+// The previous block is a block the node verified; each fetched block is linked to it, rather than to the previous block the peer sent.
+// ErrForkedChain is returned when the batch doesn't extend the previous block, and ErrBrokenChain when its blocks don't link to each other.
+// note: the state blocks aren't verified; the blocks are verified like received blocks when they're synced.
+func (s *Service) FetchBlocks(ctx context.Context, peerID peer.ID, prev *mainchain.Block, count uint64) ([]*miner.MinedBlock, error) {
+	if prev == nil || prev.Props().BlockHash == nil {
+		return nil, miner.ErrNilBlock
+	}
+	prevHeight, err := hexutil.DecodeUint64(prev.Props().BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := s.props.Protobyff.FetchBlocks(ctx, peerID, prevHeight+1, count)
+	if err != nil {
+		return nil, err
+	}
+
+	var minedBlocks []*miner.MinedBlock
+	for i, data := range blocks {
+		minedBlock := new(miner.MinedBlock)
+		if err := minedBlock.Deserialize(data); err != nil {
+			return nil, err
+		}
+		if minedBlock.NextBlock == nil || minedBlock.NextBlock.Props().BlockHash == nil {
+			return nil, ErrBrokenChain
+		}
+
+		height, err := hexutil.DecodeUint64(minedBlock.NextBlock.Props().BlockNumber)
+		if err != nil {
+			return nil, err
+		}
+		if height != prevHeight+1+uint64(i) {
+			return nil, ErrBrokenChain
+		}
+		if minedBlock.NextBlock.Props().PrevBlockHash != *prev.Props().BlockHash {
+			if i == 0 {
+				return nil, ErrForkedChain
+			}
+
+			return nil, ErrBrokenChain
+		}
+
+		ok, err := miner.VerifyBlock(minedBlock.NextBlock)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrInvalidFetchedBlock
+		}
+
+		minedBlock.PreviousBlock = prev
+		minedBlocks = append(minedBlocks, minedBlock)
+		prev = minedBlock.NextBlock
+	}
+
+	return minedBlocks, nil
+}
+
+// FetchObjects fetches the objects of the cids from the peer, keyed by cid.
+// The objects are checked against their cids; the ones the peer doesn't have are missing from the map.
+func (s *Service) FetchObjects(ctx context.Context, peerID peer.ID, cids []string) (map[string][]byte, error) {
+	objects := make(map[string][]byte)
+	for start := 0; start < len(cids); start += protobuff.MaxObjectsPerRequest {
+		end := start + protobuff.MaxObjectsPerRequest
+		if end > len(cids) {
+			end = len(cids)
+		}
+
+		found, objs, err := s.props.Protobyff.FetchObjects(ctx, peerID, cids[start:end])
+		if err != nil {
+			return nil, err
+		}
+
+		for i, cidStr := range found {
+			c, err := cid.Decode(cidStr)
+			if err != nil {
+				return nil, err
+			}
+
+			sum, err := c.Prefix().Sum(objs[i])
+			if err != nil {
+				return nil, err
+			}
+			if !sum.Equals(c) {
+				log.Errorf("[node] object from peer %s doesn't match cid %s", peerID.Pretty(), cidStr)
+				return nil, ErrInvalidFetchedObject
+			}
+
+			objects[cidStr] = objs[i]
+		}
+	}
+
+	return objects, nil
+}
+
+// fetchObjects fetches the objects of the cids from the connected peers that serve objects, asking each peer for
+// the objects the peers before it didn't have. It's the p2p service's FetchObjectsFN.
+func (s *Service) fetchObjects(ctx context.Context, cids []string) (map[string][]byte, error) {
+	objects := make(map[string][]byte)
+	missing := cids
+	for _, peerID := range s.statuses.withCapability(s.props.Host.Network().Peers(), protobuff.CapabilityObjects) {
+		if len(missing) == 0 {
+			break
+		}
+		if ctx.Err() != nil {
+			return objects, ctx.Err()
+		}
+
+		fetched, err := s.FetchObjects(ctx, peerID, missing)
+		if err != nil {
+			log.Warnf("[node] err fetching objects from peer %s\n%v", peerID.Pretty(), err)
+			s.penalizeFetch(peerID, err)
+			continue
+		}
+
+		var rest []string
+		for _, cidStr := range missing {
+			if data, ok := fetched[cidStr]; ok {
+				objects[cidStr] = data
+				continue
+			}
+			rest = append(rest, cidStr)
+		}
+		missing = rest
+	}
+
+	return objects, nil
+}
+
+// findForkBlock returns the highest block of the peer's chain the node verified, walking the peer's chain back in batches
+// from below its head block. The peer's chain is synced from it, so chains that forked below the local head block are synced too.
+// note: the fork block is the peer's copy of the verified block; its hash is checked against its contents.
+func (s *Service) findForkBlock(ctx context.Context, peerID peer.ID, localHead, head *mainchain.Block) (*mainchain.Block, error) {
+	if _, err := s.chain.work(*head.Props().BlockHash); err == nil {
+		return head, nil
+	}
+
+	localHeight, err := hexutil.DecodeUint64(localHead.Props().BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	headHeight, err := hexutil.DecodeUint64(head.Props().BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if headHeight == 0 {
+		return nil, ErrForkTooDeep
+	}
+
+	top := headHeight - 1
+	if localHeight < top {
+		top = localHeight
+	}
+	for top+maxReorgDepth >= localHeight {
+		start := uint64(0)
+		if top >= forkSearchBatch {
+			start = top - forkSearchBatch + 1
+		}
+
+		blocks, err := s.props.Protobyff.FetchBlocks(ctx, peerID, start, top-start+1)
+		if err != nil {
+			return nil, err
+		}
+		// note: the peer's chain may have moved to a shorter fork since it reported its head block
+		if uint64(len(blocks)) != top-start+1 {
+			return nil, fmt.Errorf("peer served %v of the blocks %v to %v", len(blocks), start, top)
+		}
+
+		for i := len(blocks) - 1; i >= 0; i-- {
+			minedBlock := new(miner.MinedBlock)
+			if err := minedBlock.Deserialize(blocks[i]); err != nil {
+				return nil, err
+			}
+			block := minedBlock.NextBlock
+			if block == nil || block.Props().BlockHash == nil {
+				return nil, ErrBrokenChain
+			}
+
+			height, err := hexutil.DecodeUint64(block.Props().BlockNumber)
+			if err != nil {
+				return nil, err
+			}
+			if height != start+uint64(i) {
+				return nil, ErrBrokenChain
+			}
+			if _, err := s.chain.work(*block.Props().BlockHash); err == ErrBlockNotFound {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+
+			ok, err := miner.VerifyBlock(block)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return nil, ErrInvalidFetchedBlock
+			}
+
+			return block, nil
+		}
+
+		// note: the genesis block is always verified, so a chain that doesn't meet it is invalid
+		if start == 0 {
+			return nil, ErrBrokenChain
+		}
+		top = start - 1
+	}
+
+	return nil, ErrForkTooDeep
+}
+
+// syncBlocks catches up on the chain of the head block in batches, verifying and storing each of its blocks.
+// The chain is synced from its fork block, the highest of its blocks the node verified, which it returns.
+// The peers are tried in order until one serves the next batch; the batches must chain from the fork block and end at the head block.
+// note: peers whose chain changed since they reported the head block are skipped, but not penalized.
+func (s *Service) syncBlocks(peers []peer.ID, localHead, head *mainchain.Block) (*mainchain.Block, error) {
+	// note: light nodes only serve their head block
+	peers = s.statuses.withCapability(peers, protobuff.CapabilityBlocks)
+	if len(peers) == 0 {
+		return nil, ErrNoBlocksPeer
+	}
+
+	var from *mainchain.Block
+	for _, peerID := range peers {
+		if peerID == s.props.Host.ID() {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(s.props.Context, config.IPFSTimeout)
+		fork, err := s.findForkBlock(ctx, peerID, localHead, head)
+		cancel()
+		if err != nil {
+			log.Warnf("[node] err finding the fork block of peer %s\n%v", peerID.Pretty(), err)
+			s.penalizeFetch(peerID, err)
+			continue
+		}
+
+		from = fork
+		break
+	}
+	if from == nil {
+		return nil, fmt.Errorf("no peer served the fork block of head block %s", *head.Props().BlockHash)
+	}
+
+	fromHeight, err := hexutil.DecodeUint64(from.Props().BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	toHeight, err := hexutil.DecodeUint64(head.Props().BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[node] syncing blocks %v to %v from fork block %s", fromHeight+1, toHeight, *from.Props().BlockHash)
+
+	prev := from
+	for height := fromHeight + 1; height <= toHeight; {
+		count := toHeight - height + 1
+		if count > protobuff.MaxBlocksPerRequest {
+			count = protobuff.MaxBlocksPerRequest
+		}
+
+		var minedBlocks []*miner.MinedBlock
+		for _, peerID := range peers {
+			if peerID == s.props.Host.ID() {
+				continue
+			}
+
+			ctx, cancel := context.WithTimeout(s.props.Context, config.IPFSTimeout)
+			blocks, err := s.FetchBlocks(ctx, peerID, prev, count)
+			cancel()
+			// note: the last batch must end at the head block the peers reported
+			if err == nil && uint64(len(blocks)) == count && height+count-1 == toHeight && *blocks[len(blocks)-1].NextBlock.Props().BlockHash != *head.Props().BlockHash {
+				err = ErrForkedChain
+			}
+			if err != nil {
+				log.Warnf("[node] err fetching blocks from peer %s\n%v", peerID.Pretty(), err)
+				s.penalizeFetch(peerID, err)
+				continue
+			}
+			if len(blocks) > 0 {
+				minedBlocks = blocks
+				break
+			}
+		}
+		if len(minedBlocks) == 0 {
+			return nil, fmt.Errorf("no peer served the blocks from height %v", height)
+		}
+
+		for _, minedBlock := range minedBlocks {
+			if err := s.syncBlock(minedBlock); err != nil {
+				return nil, err
+			}
+		}
+
+		prev = minedBlocks[len(minedBlocks)-1].NextBlock
+		height += uint64(len(minedBlocks))
+	}
+
+	log.Printf("[node] synced blocks %v to %v", fromHeight+1, toHeight)
+	return from, nil
+}
+
+// penalizeFetch penalizes the peer for the err fetching blocks from it.
+// note: forked chains and deep forks aren't offenses; honest peers on another chain serve them.
+func (s *Service) penalizeFetch(peerID peer.ID, err error) {
+	switch err {
+	case context.DeadlineExceeded:
+		s.penalize(peerID, reputation.OffenseTimeout)
+	case ErrBrokenChain, ErrInvalidFetchedBlock, ErrInvalidFetchedObject:
+		s.penalize(peerID, reputation.OffenseInvalidBlock)
+	}
+}
+
+// syncHeadBlock syncs and verifies the chains of the peers' head blocks, including chains that forked from the local chain,
+// then adopts the one with the most work if it has more than the local head block, and starts mining on the head block.
+// note: the local head block is kept when none of the chains sync.
+func (s *Service) syncHeadBlock(candidates []*headCandidate) {
+	// note: the miner is started once the sync is done, on whichever block is the head block then
+	defer s.finishSync()
+
+	localHead, err := s.props.Store.GetHeadBlock()
+	if err != nil {
+		log.Errorf("[node] err getting head block\n%v", err)
+		return
+	}
+	// note: indexes the local chain, so the fork blocks of the peers' chains are found
+	localWork, err := s.chainWork(&localHead)
+	if err != nil {
+		log.Errorf("[node] err computing the work of head block %s\n%v", localHead.Props().BlockNumber, err)
+		return
+	}
+
+	var (
+		best, bestFork *mainchain.Block
+		bestWork       *big.Int
+	)
+	for _, candidate := range candidates {
+		// note: a head block the node already verified is only synced if its chain has more work than the local chain
+		if work, err := s.chain.work(*candidate.block.Props().BlockHash); err == nil && !preferWork(work, candidate.block, localWork, &localHead) {
+			continue
+		}
+
+		fork, err := s.syncBlocks(candidate.peers, &localHead, candidate.block)
+		if err != nil {
+			log.Errorf("[node] err syncing the chain of head block %s\n%v", *candidate.block.Props().BlockHash, err)
+			continue
+		}
+
+		// note: only the blocks that were verified are indexed
+		work, err := s.chain.work(*candidate.block.Props().BlockHash)
+		if err != nil {
+			log.Errorf("[node] synced chain doesn't end at head block %s\n%v", *candidate.block.Props().BlockHash, err)
+			continue
+		}
+		if best == nil || preferWork(work, candidate.block, bestWork, best) {
+			best, bestFork, bestWork = candidate.block, fork, work
+		}
+	}
+
+	if best != nil {
+		s.adoptHeadBlock(bestFork, best, bestWork)
+	}
+}
+
+// adoptHeadBlock sets the synced block as the head block if its chain has more work than the head block's.
+// The synced chain forked from the head block's chain at the fork block; the blocks above it are abandoned.
+func (s *Service) adoptHeadBlock(fork, block *mainchain.Block, work *big.Int) {
+	head, err := s.props.Store.GetHeadBlock()
+	if err != nil {
+		log.Errorf("[node] err getting head block\n%v", err)
+		return
+	}
+	headWork, err := s.chainWork(&head)
+	if err != nil {
+		log.Errorf("[node] err computing the work of head block %s\n%v", head.Props().BlockNumber, err)
+		return
+	}
+	if !preferWork(work, block, headWork, &head) {
+		log.Printf("[node] head block %s has more work than the synced head block %s", head.Props().BlockNumber, block.Props().BlockNumber)
+		return
+	}
+
+	// note: the head block is off the synced chain when the chain forked below it, or a block received while syncing moved it
+	if *head.Props().BlockHash != *fork.Props().BlockHash {
+		s.handleReorg(&head, block)
+	}
+
+	if err := s.props.Store.SetHeadBlock(block); err != nil {
+		log.Errorf("[node] err setting head block\n%v", err)
+		return
+	}
+
+	log.Printf("[node] synced to head block %s", block.Props().BlockNumber)
+}
+
+// syncBlock verifies the fetched block like a received block, and stores it
+func (s *Service) syncBlock(minedBlock *miner.MinedBlock) error {
+	// note: the block was already verified, e.g. it's on the chain of another peer's head block
+	if _, err := s.chain.work(*minedBlock.NextBlock.Props().BlockHash); err == nil {
+		return nil
+	}
+	if !s.meetsRequiredDifficulty(minedBlock.NextBlock) {
+		return fmt.Errorf("synced block %s isn't mined at the required difficulty", minedBlock.NextBlock.Props().BlockNumber)
+	}
+
+	ctx, cancel := context.WithTimeout(s.props.Context, config.MinedBlockVerificationTimeout)
+	defer cancel()
+
+	var (
+		ok  bool
+		err error
+	)
+	if s.props.Mode.ExecutesDApps() {
+		var sb *sandbox.Service
+		if sb, err = sandbox.New(nil); err == nil {
+			ok, err = miner.VerifyMinedBlock(ctx, s.props.P2P, sb, minedBlock)
+		}
+	} else {
+		ok, err = miner.VerifyMinedBlockProofs(ctx, minedBlock)
+	}
+	if err != nil || !ok {
+		s.releaseStateRoots(minedBlock)
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("synced block %s is invalid", minedBlock.NextBlock.Props().BlockNumber)
+	}
+
+	return s.setMinedBlockData(minedBlock)
+}
@@ -0,0 +1,124 @@
+// +build unit
+
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/c3systems/c3-go/core/chain/mainchain"
+	"github.com/c3systems/c3-go/core/miner"
+
+	peer "github.com/libp2p/go-libp2p-peer"
+)
+
+func serializeMinedBlocks(t *testing.T, prev *mainchain.Block, blocks ...*mainchain.Block) [][]byte {
+	var serialized [][]byte
+	for _, block := range blocks {
+		// note: the previous block a peer sends isn't trusted
+		data, err := (&miner.MinedBlock{NextBlock: block, PreviousBlock: prev}).Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		serialized = append(serialized, data)
+	}
+
+	return serialized
+}
+
+func TestFetchBlocks(t *testing.T) {
+	genesis := &mainchain.GenesisBlock
+	b1 := mineHeadBlock(t, 1, mainchain.GenesisBlockHash, testDifficulty)
+	b2 := mineHeadBlock(t, 2, *b1.Props().BlockHash, testDifficulty)
+	fork := mineHeadBlock(t, 1, mainchain.GenesisBlockHash, testDifficulty)
+	forged := mineHeadBlock(t, 0, mainchain.GenesisBlockHash, testDifficulty)
+
+	pBuff := &fakeHeadBlocks{
+		blocks: map[peer.ID][][]byte{
+			"a": serializeMinedBlocks(t, forged, b1, b2),
+			"b": serializeMinedBlocks(t, genesis, b2),
+			"c": serializeMinedBlocks(t, genesis, b1, fork),
+		},
+	}
+	s := &Service{props: Props{Protobyff: pBuff}}
+
+	minedBlocks, err := s.FetchBlocks(context.Background(), "a", genesis, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(minedBlocks) != 2 {
+		t.Fatalf("expected 2 blocks; received %v", len(minedBlocks))
+	}
+	// note: the blocks are linked to the node's previous block, not the one the peer sent
+	if *minedBlocks[0].PreviousBlock.Props().BlockHash != mainchain.GenesisBlockHash || *minedBlocks[1].PreviousBlock.Props().BlockHash != *b1.Props().BlockHash {
+		t.Error("expected the blocks to be anchored to the node's chain")
+	}
+
+	// note: the peer's chain forked from the node's previous block
+	if _, err := s.FetchBlocks(context.Background(), "a", fork, 1); err != ErrForkedChain {
+		t.Errorf("expected %v; received %v", ErrForkedChain, err)
+	}
+	if _, err := s.FetchBlocks(context.Background(), "b", fork, 1); err != ErrForkedChain {
+		t.Errorf("expected %v; received %v", ErrForkedChain, err)
+	}
+	// note: the batch's blocks don't chain from each other
+	if _, err := s.FetchBlocks(context.Background(), "c", genesis, 2); err != ErrBrokenChain {
+		t.Errorf("expected %v; received %v", ErrBrokenChain, err)
+	}
+}
+
+func TestFindForkBlock(t *testing.T) {
+	genesis := &mainchain.GenesisBlock
+	b1 := mineHeadBlock(t, 1, mainchain.GenesisBlockHash, testDifficulty)
+	b2 := mineHeadBlock(t, 2, *b1.Props().BlockHash, testDifficulty)
+	// note: the peers' chains forked below the local head block b2, one after b1 and one after the genesis block
+	p2 := mineHeadBlock(t, 2, *b1.Props().BlockHash, testDifficulty)
+	p3 := mineHeadBlock(t, 3, *p2.Props().BlockHash, testDifficulty)
+	q1 := mineHeadBlock(t, 1, mainchain.GenesisBlockHash, testDifficulty)
+	q2 := mineHeadBlock(t, 2, *q1.Props().BlockHash, testDifficulty)
+
+	pBuff := &fakeHeadBlocks{
+		blocks: map[peer.ID][][]byte{
+			"a": serializeMinedBlocks(t, genesis, genesis, b1, p2, p3),
+			"b": serializeMinedBlocks(t, genesis, genesis, q1, q2),
+			"c": serializeMinedBlocks(t, genesis, genesis, p2),
+		},
+	}
+	s := &Service{
+		props: Props{Protobyff: pBuff},
+		chain: newChainIndex(nil),
+	}
+	for _, block := range []*mainchain.Block{genesis, b1, b2} {
+		if _, err := s.chain.add(block); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		peerID   peer.ID
+		head     *mainchain.Block
+		expected string
+		err      error
+	}{
+		{"a", p3, *b1.Props().BlockHash, nil},
+		{"b", q2, mainchain.GenesisBlockHash, nil},
+		// note: the head block was already verified
+		{"a", b1, *b1.Props().BlockHash, nil},
+	}
+	for _, tt := range tests {
+		fork, err := s.findForkBlock(context.Background(), tt.peerID, b2, tt.head)
+		if err != tt.err {
+			t.Errorf("%s: expected %v; received %v", tt.peerID, tt.err, err)
+			continue
+		}
+		if *fork.Props().BlockHash != tt.expected {
+			t.Errorf("%s: expected fork block %s; received %s", tt.peerID, tt.expected, *fork.Props().BlockHash)
+		}
+	}
+
+	// note: the peer doesn't serve the blocks below its head block
+	if _, err := s.findForkBlock(context.Background(), "c", b2, p3); err == nil {
+		t.Error("expected an err when the peer doesn't serve its chain")
+	}
+}
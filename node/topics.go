@@ -0,0 +1,78 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/c3systems/c3-go/core/miner"
+	"github.com/c3systems/c3-go/core/p2p/reputation"
+	"github.com/c3systems/c3-go/core/p2p/txrelay"
+
+	peer "github.com/libp2p/go-libp2p-peer"
+	floodsub "github.com/libp2p/go-libp2p-pubsub"
+	log "github.com/sirupsen/logrus"
+)
+
+// topicVersion is bumped when the encoding of the gossiped messages changes
+const topicVersion = "1"
+
+// txFetchTimeout is how long validating a tx announcement, which fetches the announced tx, may take
+const txFetchTimeout = 10 * time.Second
+
+// blocksTopic is the topic mined blocks are gossiped on
+// pattern: /c3/chain-id/name/version
+func blocksTopic(chainID string) string {
+	return fmt.Sprintf("/c3/%s/blocks/%s", chainID, topicVersion)
+}
+
+// txAnnouncementsTopic is the topic the hashes of new transactions are gossiped on; the transactions are fetched by hash
+func txAnnouncementsTopic(chainID string) string {
+	return fmt.Sprintf("/c3/%s/txannouncements/%s", chainID, topicVersion)
+}
+
+// registerTopicValidators registers the validators that drop invalid messages before they're delivered or propagated.
+// The peers that forward invalid messages are penalized.
+func registerTopicValidators(pubsub *floodsub.PubSub, chainID string, difficulty uint64, rep *reputation.Manager, relay *txrelay.Relay) error {
+	if err := pubsub.RegisterTopicValidator(blocksTopic(chainID), penalizeInvalid(blockMessageValidator(difficulty), rep, reputation.OffenseInvalidBlock)); err != nil {
+		return fmt.Errorf("err registering blocks topic validator\n%v", err)
+	}
+	// note: the relay penalizes the peers itself, as dropped announcements of seen txs aren't offenses
+	if err := pubsub.RegisterTopicValidator(txAnnouncementsTopic(chainID), relay.Validate, floodsub.WithValidatorTimeout(txFetchTimeout)); err != nil {
+		return fmt.Errorf("err registering tx announcements topic validator\n%v", err)
+	}
+
+	return nil
+}
+
+// penalizeInvalid wraps the validator, penalizing the peers whose messages are invalid
+func penalizeInvalid(validator floodsub.Validator, rep *reputation.Manager, offense reputation.Offense) floodsub.Validator {
+	return func(ctx context.Context, from peer.ID, msg *floodsub.Message) bool {
+		if validator(ctx, from, msg) {
+			return true
+		}
+
+		penalizeFN(rep)(from, offense)
+		return false
+	}
+}
+
+// blockMessageValidator checks the mined block's hash, miner sig, and that it was mined at the required difficulty.
+// note: the state blocks are verified when the block is received, as that's costly.
+func blockMessageValidator(difficulty uint64) floodsub.Validator {
+	return func(ctx context.Context, from peer.ID, msg *floodsub.Message) bool {
+		minedBlock := new(miner.MinedBlock)
+		if err := minedBlock.Deserialize(msg.GetData()); err != nil {
+			log.Warnf("[node] dropping undecodable block from %s\n%v", from.Pretty(), err)
+			return false
+		}
+
+		ok, err := verifyHeadBlock(minedBlock.NextBlock, difficulty)
+		if err != nil || !ok {
+			log.Warnf("[node] dropping invalid block from %s\n%v", from.Pretty(), err)
+			return false
+		}
+
+		return true
+	}
+}
@@ -0,0 +1,65 @@
+// +build unit
+
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/c3systems/c3-go/core/chain/mainchain"
+	"github.com/c3systems/c3-go/core/miner"
+	floodsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
+)
+
+func newPubsubMessage(data []byte) *floodsub.Message {
+	return &floodsub.Message{Message: &pubsubpb.Message{Data: data}}
+}
+
+func TestTopics(t *testing.T) {
+	if blocksTopic("a") == blocksTopic("b") || txAnnouncementsTopic("a") == txAnnouncementsTopic("b") {
+		t.Error("expected the topics of different chains to differ")
+	}
+	if blocksTopic("a") == txAnnouncementsTopic("a") {
+		t.Error("expected the blocks and tx announcements topics to differ")
+	}
+}
+
+func TestBlockMessageValidator(t *testing.T) {
+	validate := blockMessageValidator(testDifficulty)
+
+	block := buildHeadBlock(t, 1)
+	data, err := (&miner.MinedBlock{NextBlock: block}).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !validate(context.Background(), "", newPubsubMessage(data)) {
+		t.Error("expected the block to be valid")
+	}
+
+	props := block.Props()
+	props.BlockTime = "0x2"
+	data, err = (&miner.MinedBlock{NextBlock: mainchain.New(&props)}).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if validate(context.Background(), "", newPubsubMessage(data)) {
+		t.Error("expected the tampered block to be invalid")
+	}
+
+	// note: the block's hash meets its own difficulty, but not the chain's
+	data, err = (&miner.MinedBlock{NextBlock: mineHeadBlock(t, 1, mainchain.GenesisBlockHash, testDifficulty-1)}).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if validate(context.Background(), "", newPubsubMessage(data)) {
+		t.Error("expected the block below the required difficulty to be invalid")
+	}
+
+	if validate(context.Background(), "", newPubsubMessage([]byte("foo"))) {
+		t.Error("expected undecodable data to be invalid")
+	}
+	if validate(context.Background(), "", newPubsubMessage(nil)) {
+		t.Error("expected an empty message to be invalid")
+	}
+}
@@ -0,0 +1,141 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/c3systems/c3-go/core/p2p/libp2ptls"
+	"github.com/c3systems/c3-go/core/p2p/wstransport"
+
+	connsec "github.com/libp2p/go-conn-security"
+	csms "github.com/libp2p/go-conn-security-multistream"
+	lCrypt "github.com/libp2p/go-libp2p-crypto"
+	peer "github.com/libp2p/go-libp2p-peer"
+	secio "github.com/libp2p/go-libp2p-secio"
+	swarm "github.com/libp2p/go-libp2p-swarm"
+	transport "github.com/libp2p/go-libp2p-transport"
+	tptu "github.com/libp2p/go-libp2p-transport-upgrader"
+	tcp "github.com/libp2p/go-tcp-transport"
+	ma "github.com/multiformats/go-multiaddr"
+	msmux "github.com/whyrusleeping/go-smux-multistream"
+	yamux "github.com/whyrusleeping/go-smux-yamux"
+)
+
+const (
+	// TransportTCP ...
+	TransportTCP = "tcp"
+	// TransportWS is the websocket transport browsers can dial, listening on /tcp/.../ws multiaddrs
+	TransportWS = "ws"
+
+	// SecureChannelSecio ...
+	SecureChannelSecio = "secio"
+	// SecureChannelTLS is the libp2p tls 1.3 secure channel
+	SecureChannelTLS = "tls"
+)
+
+// transports build the transports by name.
+// note: there's no quic transport for the libp2p version vendored here.
+var transports = map[string]func(upgrader *tptu.Upgrader) transport.Transport{
+	TransportTCP: func(upgrader *tptu.Upgrader) transport.Transport {
+		return tcp.NewTCPTransport(upgrader)
+	},
+	TransportWS: func(upgrader *tptu.Upgrader) transport.Transport {
+		return wstransport.New(upgrader)
+	},
+}
+
+// secureChannels build the secure channels by name, returning their multistream protocol ids.
+// note: there's no noise secure channel for the libp2p version vendored here.
+var secureChannels = map[string]func(id peer.ID, pk lCrypt.PrivKey) (string, connsec.Transport, error){
+	SecureChannelSecio: func(id peer.ID, pk lCrypt.PrivKey) (string, connsec.Transport, error) {
+		return secio.ID, &secio.Transport{
+			LocalID:    id,
+			PrivateKey: pk,
+		}, nil
+	},
+	SecureChannelTLS: func(id peer.ID, pk lCrypt.PrivKey) (string, connsec.Transport, error) {
+		sec, err := libp2ptls.New(pk)
+		return libp2ptls.ID, sec, err
+	},
+}
+
+// note: https://github.com/libp2p/go-libp2p-swarm/blob/da01184afe4c67bec58c5e73f3350ad80b624c0d/testing/testing.go#L39
+// genUpgrader builds the connection upgrader negotiating the secure channels, in order of preference
+func genUpgrader(n *swarm.Swarm, channels []string) (*tptu.Upgrader, error) {
+	if len(channels) == 0 {
+		return nil, errors.New("at least one secure channel is required")
+	}
+
+	id := n.LocalPeer()
+	pk := n.Peerstore().PrivKey(id)
+	secMuxer := new(csms.SSMuxer)
+	for _, name := range channels {
+		build, ok := secureChannels[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown secure channel %q", name)
+		}
+
+		path, sec, err := build(id, pk)
+		if err != nil {
+			return nil, fmt.Errorf("err building %s secure channel\n%v", name, err)
+		}
+		secMuxer.AddTransport(path, sec)
+	}
+
+	stMuxer := msmux.NewBlankTransport()
+	stMuxer.AddTransport("/yamux/1.0.0", yamux.DefaultTransport)
+
+	return &tptu.Upgrader{
+		Secure:  secMuxer,
+		Muxer:   stMuxer,
+		Filters: n.Filters,
+	}, nil
+}
+
+// addTransports adds the transports, secured by the secure channels, to the swarm
+func addTransports(n *swarm.Swarm, names, channels []string) error {
+	if len(names) == 0 {
+		return errors.New("at least one transport is required")
+	}
+
+	upgrader, err := genUpgrader(n, channels)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		build, ok := transports[name]
+		if !ok {
+			return fmt.Errorf("unknown transport %q", name)
+		}
+
+		if err := n.AddTransport(build(upgrader)); err != nil {
+			return fmt.Errorf("err adding %s transport\n%v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// listenOn listens on every multiaddr, each of which must be served by one of the swarm's transports
+func listenOn(n *swarm.Swarm, addrs []string) error {
+	if len(addrs) == 0 {
+		return errors.New("at least one listen addr is required")
+	}
+
+	for _, addr := range addrs {
+		maddr, err := ma.NewMultiaddr(addr)
+		if err != nil {
+			return fmt.Errorf("err parsing listen addr %s\n%v", addr, err)
+		}
+		if n.TransportForListening(maddr) == nil {
+			return fmt.Errorf("no enabled transport listens on %s", addr)
+		}
+
+		if err := n.AddListenAddr(maddr); err != nil {
+			return fmt.Errorf("err listening on %s\n%v", addr, err)
+		}
+	}
+
+	return nil
+}
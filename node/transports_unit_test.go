@@ -0,0 +1,122 @@
+// +build unit
+
+package node
+
+import (
+	"context"
+	"testing"
+
+	lCrypt "github.com/libp2p/go-libp2p-crypto"
+	peer "github.com/libp2p/go-libp2p-peer"
+	pstore "github.com/libp2p/go-libp2p-peerstore"
+	pstoremem "github.com/libp2p/go-libp2p-peerstore/pstoremem"
+	swarm "github.com/libp2p/go-libp2p-swarm"
+)
+
+func newTestSwarm(t *testing.T) *swarm.Swarm {
+	priv, pub, err := lCrypt.GenerateKeyPair(lCrypt.Ed25519, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, err := peer.IDFromPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ps := pstoremem.NewPeerstore()
+	if err := ps.AddPrivKey(pid, priv); err != nil {
+		t.Fatal(err)
+	}
+	if err := ps.AddPubKey(pid, pub); err != nil {
+		t.Fatal(err)
+	}
+
+	return swarm.NewSwarm(context.Background(), pid, ps, nil)
+}
+
+func TestAddTransports(t *testing.T) {
+	tests := []struct {
+		name       string
+		transports []string
+		channels   []string
+		ok         bool
+	}{
+		{"tcp secio", []string{TransportTCP}, []string{SecureChannelSecio}, true},
+		{"tcp ws tls secio", []string{TransportTCP, TransportWS}, []string{SecureChannelTLS, SecureChannelSecio}, true},
+		{"no transports", nil, []string{SecureChannelSecio}, false},
+		{"no secure channels", []string{TransportTCP}, nil, false},
+		{"unknown transport", []string{"udp"}, []string{SecureChannelSecio}, false},
+		{"unknown quic transport", []string{TransportTCP, "quic"}, []string{SecureChannelSecio}, false},
+		{"unknown secure channel", []string{TransportTCP}, []string{"plaintext"}, false},
+		{"unknown noise secure channel", []string{TransportTCP}, []string{"noise", SecureChannelSecio}, false},
+	}
+
+	for _, tt := range tests {
+		n := newTestSwarm(t)
+		err := addTransports(n, tt.transports, tt.channels)
+		if tt.ok && err != nil {
+			t.Errorf("%s: expected no error; received %v", tt.name, err)
+		}
+		if !tt.ok && err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+		n.Close()
+	}
+}
+
+func TestListenOn(t *testing.T) {
+	n := newTestSwarm(t)
+	defer n.Close()
+
+	if err := addTransports(n, []string{TransportTCP}, []string{SecureChannelSecio}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := listenOn(n, []string{"/ip4/127.0.0.1/tcp/0/ws"}); err == nil {
+		t.Error("expected an error listening without the websocket transport")
+	}
+	if err := listenOn(n, []string{"/ip4/127.0.0.1/tcp/0", "/ip4/127.0.0.1/tcp/0"}); err != nil {
+		t.Fatal(err)
+	}
+	if addrs := n.ListenAddresses(); len(addrs) != 2 {
+		t.Errorf("expected to listen on 2 addrs; received %v", addrs)
+	}
+}
+
+func TestDialTransports(t *testing.T) {
+	tests := []struct {
+		transport string
+		channel   string
+		addr      string
+	}{
+		{TransportTCP, SecureChannelTLS, "/ip4/127.0.0.1/tcp/0"},
+		{TransportWS, SecureChannelSecio, "/ip4/127.0.0.1/tcp/0/ws"},
+		{TransportWS, SecureChannelTLS, "/ip4/127.0.0.1/tcp/0/ws"},
+	}
+
+	for _, tt := range tests {
+		listener := newTestSwarm(t)
+		dialer := newTestSwarm(t)
+
+		if err := addTransports(listener, []string{tt.transport}, []string{tt.channel}); err != nil {
+			t.Fatal(err)
+		}
+		if err := addTransports(dialer, []string{tt.transport}, []string{tt.channel}); err != nil {
+			t.Fatal(err)
+		}
+		if err := listenOn(listener, []string{tt.addr}); err != nil {
+			t.Fatal(err)
+		}
+
+		dialer.Peerstore().AddAddrs(listener.LocalPeer(), listener.ListenAddresses(), pstore.PermanentAddrTTL)
+		conn, err := dialer.DialPeer(context.Background(), listener.LocalPeer())
+		if err != nil {
+			t.Errorf("%s %s: err dialing\n%v", tt.transport, tt.channel, err)
+		} else if conn.RemotePeer() != listener.LocalPeer() {
+			t.Errorf("%s %s: expected a conn to %s; received %s", tt.transport, tt.channel, listener.LocalPeer().Pretty(), conn.RemotePeer().Pretty())
+		}
+
+		dialer.Close()
+		listener.Close()
+	}
+}
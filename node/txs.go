@@ -0,0 +1,19 @@
+package node
+
+import (
+	"context"
+
+	"github.com/c3systems/c3-go/core/chain/statechain"
+
+	peer "github.com/libp2p/go-libp2p-peer"
+)
+
+// getPendingTx serves the mempool's txs to the tx relay
+func (s *Service) getPendingTx(hash string) (*statechain.Transaction, error) {
+	return s.props.Store.GetTx(hash)
+}
+
+// fetchTransactions fetches the announced txs from the peer over the get transactions protocol
+func (s *Service) fetchTransactions(ctx context.Context, peerID peer.ID, hashes []string) ([][]byte, error) {
+	return s.props.Protobyff.FetchTransactions(ctx, peerID, hashes)
+}
@@ -0,0 +1,55 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mode is the role a node plays in the network
+type Mode string
+
+const (
+	// ModeMiner validates blocks, executing the dApps, and mines blocks
+	ModeMiner Mode = "miner"
+	// ModeFull validates blocks, executing the dApps, but doesn't mine
+	ModeFull Mode = "full"
+	// ModeLight follows the mainchain headers and verifies the merkle proofs of their state blocks without executing the dApps
+	ModeLight Mode = "light"
+
+	// DefaultMode ...
+	DefaultMode = ModeMiner
+)
+
+// Modes are the valid node modes
+var Modes = []Mode{ModeMiner, ModeFull, ModeLight}
+
+// ParseMode parses a mode name; an empty name is the default mode
+func ParseMode(name string) (Mode, error) {
+	if name == "" {
+		return DefaultMode, nil
+	}
+
+	mode := Mode(strings.ToLower(name))
+	for _, m := range Modes {
+		if mode == m {
+			return mode, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown node mode %q; expected one of %v", name, Modes)
+}
+
+// Mines returns true when the node mines blocks
+func (m Mode) Mines() bool {
+	return m == ModeMiner
+}
+
+// ExecutesDApps returns true when the node runs the dApps to verify state blocks
+func (m Mode) ExecutesDApps() bool {
+	return m == ModeMiner || m == ModeFull
+}
+
+// String ...
+func (m Mode) String() string {
+	return string(m)
+}
@@ -0,0 +1,44 @@
+// +build unit
+
+package types
+
+import "testing"
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		name          string
+		expected      Mode
+		mines         bool
+		executesDApps bool
+		err           bool
+	}{
+		{"", ModeMiner, true, true, false},
+		{"miner", ModeMiner, true, true, false},
+		{"Full", ModeFull, false, true, false},
+		{"light", ModeLight, false, false, false},
+		{"archive", "", false, false, true},
+	}
+
+	for i, tt := range tests {
+		mode, err := ParseMode(tt.name)
+		if tt.err {
+			if err == nil {
+				t.Errorf("test %d: expected an error parsing %q", i, tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+
+		if mode != tt.expected {
+			t.Errorf("test %d: expected mode %s; received %s", i, tt.expected, mode)
+		}
+		if mode.Mines() != tt.mines {
+			t.Errorf("test %d: expected mines %v; received %v", i, tt.mines, mode.Mines())
+		}
+		if mode.ExecutesDApps() != tt.executesDApps {
+			t.Errorf("test %d: expected executes dApps %v; received %v", i, tt.executesDApps, mode.ExecutesDApps())
+		}
+	}
+}
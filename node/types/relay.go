@@ -0,0 +1,55 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RelayMode is how a node takes part in circuit relaying
+type RelayMode string
+
+const (
+	// RelayModeOff nodes neither dial through relays nor relay for others
+	RelayModeOff RelayMode = "off"
+	// RelayModeClient nodes dial and are dialed through relays, e.g. when they're behind a NAT
+	RelayModeClient RelayMode = "client"
+	// RelayModeServer nodes relay connections for others, as well as dialing through relays
+	RelayModeServer RelayMode = "server"
+
+	// DefaultRelayMode ...
+	DefaultRelayMode = RelayModeOff
+)
+
+// RelayModes are the valid relay modes
+var RelayModes = []RelayMode{RelayModeOff, RelayModeClient, RelayModeServer}
+
+// ParseRelayMode parses a relay mode name; an empty name is the default relay mode
+func ParseRelayMode(name string) (RelayMode, error) {
+	if name == "" {
+		return DefaultRelayMode, nil
+	}
+
+	mode := RelayMode(strings.ToLower(name))
+	for _, m := range RelayModes {
+		if mode == m {
+			return mode, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown relay mode %q; expected one of %v", name, RelayModes)
+}
+
+// Enabled returns true when the node dials through relays
+func (m RelayMode) Enabled() bool {
+	return m == RelayModeClient || m == RelayModeServer
+}
+
+// Hops returns true when the node relays connections for others
+func (m RelayMode) Hops() bool {
+	return m == RelayModeServer
+}
+
+// String ...
+func (m RelayMode) String() string {
+	return string(m)
+}
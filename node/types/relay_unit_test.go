@@ -0,0 +1,44 @@
+// +build unit
+
+package types
+
+import "testing"
+
+func TestParseRelayMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected RelayMode
+		enabled  bool
+		hops     bool
+		err      bool
+	}{
+		{"", RelayModeOff, false, false, false},
+		{"off", RelayModeOff, false, false, false},
+		{"Client", RelayModeClient, true, false, false},
+		{"server", RelayModeServer, true, true, false},
+		{"hop", "", false, false, true},
+	}
+
+	for i, tt := range tests {
+		mode, err := ParseRelayMode(tt.name)
+		if tt.err {
+			if err == nil {
+				t.Errorf("test %d: expected an error parsing %q", i, tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+
+		if mode != tt.expected {
+			t.Errorf("test %d: expected relay mode %s; received %s", i, tt.expected, mode)
+		}
+		if mode.Enabled() != tt.enabled {
+			t.Errorf("test %d: expected enabled %v; received %v", i, tt.enabled, mode.Enabled())
+		}
+		if mode.Hops() != tt.hops {
+			t.Errorf("test %d: expected hops %v; received %v", i, tt.hops, mode.Hops())
+		}
+	}
+}
@@ -29,10 +29,23 @@ type Keys struct {
 // Config ...
 type Config struct {
 	URI             string
+	ListenAddrs     []string // note: multiaddrs listened on in addition to the URI
+	Transports      []string // note: tcp or ws; defaults to config.DefaultTransports
+	SecureChannels  []string // note: secio or tls, in order of preference; defaults to config.DefaultSecureChannels
+	NATPortMap      bool     // note: map the listen ports on the NAT device with UPnP or NAT-PMP
+	RelayMode       RelayMode
+	Relays          []string // note: ipfs multiaddrs of the relays advertised while the node isn't reachable
 	Peer            string
 	DataDir         string
 	Keys            Keys
 	BlockDifficulty int
+	MinerThreads    int
+	Mode            Mode
+	ChainID         string
+	BootstrapPeers  []string
+	MinPeers        int
+	MaxPeers        int
+	MDNS            bool
 	MempoolType     string
 	RPCHost         string
 	EOSClient       *eosclient.CheckpointClient
@@ -3,99 +3,147 @@ package node
 import (
 	"context"
 	"errors"
-	"fmt"
+	"sort"
 
+	"github.com/c3systems/c3-go/common/hexutil"
 	"github.com/c3systems/c3-go/config"
 	"github.com/c3systems/c3-go/core/chain/mainchain"
+	"github.com/c3systems/c3-go/core/miner"
 	"github.com/c3systems/c3-go/core/p2p/protobuff"
+	host "github.com/libp2p/go-libp2p-host"
 	net "github.com/libp2p/go-libp2p-net"
 	peer "github.com/libp2p/go-libp2p-peer"
 	peerstore "github.com/libp2p/go-libp2p-peerstore"
 	log "github.com/sirupsen/logrus"
-
-	pb "github.com/c3systems/c3-go/core/p2p/protobuff/pb"
-	csms "github.com/libp2p/go-conn-security-multistream"
-	secio "github.com/libp2p/go-libp2p-secio"
-	swarm "github.com/libp2p/go-libp2p-swarm"
-	tptu "github.com/libp2p/go-libp2p-transport-upgrader"
-	msmux "github.com/whyrusleeping/go-smux-multistream"
-	yamux "github.com/whyrusleeping/go-smux-yamux"
 )
 
-// note: https://github.com/libp2p/go-libp2p-swarm/blob/da01184afe4c67bec58c5e73f3350ad80b624c0d/testing/testing.go#L39
-func genUpgrader(n *swarm.Swarm) *tptu.Upgrader {
-	id := n.LocalPeer()
-	pk := n.Peerstore().PrivKey(id)
-	secMuxer := new(csms.SSMuxer)
-	secMuxer.AddTransport(secio.ID, &secio.Transport{
-		LocalID:    id,
-		PrivateKey: pk,
-	})
+// maxHeadBlockPeers is the max number of peers asked for their head block at startup
+const maxHeadBlockPeers = 8
 
-	stMuxer := msmux.NewBlankTransport()
-	stMuxer.AddTransport("/yamux/1.0.0", yamux.DefaultTransport)
+// ErrNoHeadBlock is returned when no peer responds with a valid head block
+var ErrNoHeadBlock = errors.New("no peer responded with a valid head block")
 
-	return &tptu.Upgrader{
-		Secure:  secMuxer,
-		Muxer:   stMuxer,
-		Filters: n.Filters,
-	}
+// headCandidate is a head block of peers, along with the peers that reported it
+type headCandidate struct {
+	block *mainchain.Block
+	peers []peer.ID
 }
 
-func fetchHeadBlock(self peer.ID, headBlock *mainchain.Block, peers []peer.ID, pBuff protobuff.Interface) error {
-	// TODO: pass contexts to pBuff functions
-	ctx1, cancel := context.WithTimeout(context.Background(), config.IPFSTimeout)
+// fetchHeadBlocks asks several peers for their head block and returns the valid ones other than the local head block,
+// highest first. The heads are only candidates: their heights are only claims, so the fork choice is made by the work
+// of their chains once they're synced and verified. A lower chain mined at a higher difficulty may have more work.
+// note: no candidates are returned when the peers are all at the local head block.
+func fetchHeadBlocks(self peer.ID, localHead *mainchain.Block, peers []peer.ID, pBuff protobuff.Interface, difficulty uint64) ([]*headCandidate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.IPFSTimeout)
 	defer cancel()
-	ch := make(chan interface{})
 
-	var peer peer.ID
+	var others []peer.ID
 	for _, peerID := range peers {
-		if peerID != self {
-			peer = peerID
+		if peerID == self {
+			continue
+		}
+
+		others = append(others, peerID)
+		if len(others) == maxHeadBlockPeers {
 			break
 		}
 	}
-
-	if err := pBuff.FetchHeadBlock(peer, ch); err != nil {
-		return err
+	if len(others) == 0 {
+		return nil, ErrNoHeadBlock
 	}
 
-	select {
-	case v := <-ch:
-		switch v.(type) {
-		case error:
-			err, _ := v.(error)
-			return err
-
-		case *pb.HeadBlockResponse:
-			hb, _ := v.(*pb.HeadBlockResponse)
-			if hb == nil {
-				return errors.New("received nil headblock")
+	type result struct {
+		peerID peer.ID
+		block  *mainchain.Block
+	}
+	results := make(chan result, len(others))
+	for _, peerID := range others {
+		go func(peerID peer.ID) {
+			block, err := pBuff.FetchHeadBlock(ctx, peerID)
+			if err != nil {
+				log.Warnf("[node] err fetching head block from peer %s\n%v", peerID.Pretty(), err)
 			}
 
-			block := new(mainchain.Block)
-			if err := block.Deserialize(hb.HeadBlockBytes); err != nil {
-				return err
-			}
+			results <- result{peerID: peerID, block: block}
+		}(peerID)
+	}
 
-			headBlock = block
-			return nil
+	var (
+		valid      bool
+		candidates []*headCandidate
+	)
+	for range others {
+		res := <-results
+		if res.block == nil {
+			continue
+		}
+
+		ok, err := verifyHeadBlock(res.block, difficulty)
+		if err != nil || !ok {
+			log.Warnf("[node] received invalid head block %s from peer %s\n%v", res.block.Props().BlockNumber, res.peerID.Pretty(), err)
+			continue
+		}
+		valid = true
 
-		default:
-			return fmt.Errorf("received unknown type %T\n%v", v, v)
+		if localHead.Props().BlockHash != nil && *res.block.Props().BlockHash == *localHead.Props().BlockHash {
+			continue
+		}
 
+		candidate := findCandidate(candidates, *res.block.Props().BlockHash)
+		if candidate == nil {
+			candidate = &headCandidate{block: res.block}
+			candidates = append(candidates, candidate)
 		}
+		candidate.peers = append(candidate.peers, res.peerID)
+	}
+	if !valid {
+		return nil, ErrNoHeadBlock
+	}
+	if len(candidates) == 0 {
+		log.Printf("[node] peers are at the local head block %s", localHead.Props().BlockNumber)
+		return nil, nil
+	}
 
-	case <-ctx1.Done():
-		return errors.New("fetching headblock from peer timedout")
+	// note: the highest heads are synced first; the heights are only claims until the chains are verified
+	sort.Slice(candidates, func(i, j int) bool {
+		hi, _ := hexutil.DecodeUint64(candidates[i].block.Props().BlockNumber)
+		hj, _ := hexutil.DecodeUint64(candidates[j].block.Props().BlockNumber)
+		if hi != hj {
+			return hi > hj
+		}
 
+		return *candidates[i].block.Props().BlockHash < *candidates[j].block.Props().BlockHash
+	})
+
+	return candidates, nil
+}
+
+// verifyHeadBlock verifies a block of a peer on its own, and that it was mined at the required difficulty
+func verifyHeadBlock(block *mainchain.Block, difficulty uint64) (bool, error) {
+	ok, err := miner.VerifyBlock(block)
+	if err != nil || !ok {
+		return false, err
+	}
+	if _, err := hexutil.DecodeUint64(block.Props().BlockNumber); err != nil {
+		return false, err
+	}
+
+	return miner.CheckRequiredDifficulty(block, difficulty)
+}
+
+func findCandidate(candidates []*headCandidate, hash string) *headCandidate {
+	for _, candidate := range candidates {
+		if *candidate.block.Props().BlockHash == hash {
+			return candidate
+		}
 	}
+
+	return nil
 }
 
 func sendEcho(self peer.ID, peers []peer.ID, pBuff protobuff.Interface) error {
-	ctx1, cancel := context.WithTimeout(context.Background(), config.IPFSTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), config.IPFSTimeout)
 	defer cancel()
-	ch := make(chan interface{})
 
 	var peer peer.ID
 	for _, peerID := range peers {
@@ -105,34 +153,18 @@ func sendEcho(self peer.ID, peers []peer.ID, pBuff protobuff.Interface) error {
 		}
 	}
 
-	if err := pBuff.SendEcho(peer, ch); err != nil {
+	msg, err := pBuff.SendEcho(ctx, peer)
+	if err != nil {
 		return err
 	}
 
-	select {
-	case v := <-ch:
-		switch v.(type) {
-		case error:
-			err, _ := v.(error)
-			return err
-
-		case *pb.EchoResponse:
-			eb, _ := v.(*pb.EchoResponse)
-			log.Printf("[node] received echo response\n%v", eb)
-
-			return nil
-
-		default:
-			return fmt.Errorf("received unknown type %T\n%v", v, v)
-
-		}
-
-	case <-ctx1.Done():
-		return errors.New("echo timedout")
-
-	}
+	log.Printf("[node] received echo response\n%v", msg)
+	return nil
 }
 
+// h is the node's host; peers that connect are added to its peerstore
+var h host.Host
+
 func onConn(network net.Network, conn net.Conn) {
 	log.Printf("[node] peer did connect\nid %v peerAddr %v", conn.RemotePeer().Pretty(), conn.RemoteMultiaddr())
 
@@ -0,0 +1,230 @@
+// +build unit
+
+package node
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/c3systems/c3-go/common/c3crypto"
+	"github.com/c3systems/c3-go/common/hexutil"
+	"github.com/c3systems/c3-go/core/chain/mainchain"
+	"github.com/c3systems/c3-go/core/miner"
+	"github.com/c3systems/c3-go/core/p2p/protobuff"
+	pb "github.com/c3systems/c3-go/core/p2p/protobuff/pb"
+	peer "github.com/libp2p/go-libp2p-peer"
+)
+
+// fakeHeadBlocks responds to head block requests with the peers' head blocks
+type fakeHeadBlocks struct {
+	heads    map[peer.ID]*mainchain.Block
+	statuses map[peer.ID]*pb.StatusResponse
+	blocks   map[peer.ID][][]byte
+}
+
+func (f *fakeHeadBlocks) NewMessageData(messageID string, gossip bool) *pb.MessageData {
+	return &pb.MessageData{Id: messageID, Gossip: gossip}
+}
+
+func (f *fakeHeadBlocks) SendEcho(ctx context.Context, peerID peer.ID) (string, error) {
+	return "", nil
+}
+
+func (f *fakeHeadBlocks) FetchHeadBlock(ctx context.Context, peerID peer.ID) (*mainchain.Block, error) {
+	block, ok := f.heads[peerID]
+	if !ok {
+		// note: the peer never responds
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if block == nil {
+		return nil, errors.New("peer is unreachable")
+	}
+
+	// note: the block is round tripped, like a block received from a peer
+	data, err := block.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	received := new(mainchain.Block)
+	if err := received.Deserialize(data); err != nil {
+		return nil, err
+	}
+
+	return received, nil
+}
+
+func (f *fakeHeadBlocks) SendTransaction(ctx context.Context, peerID peer.ID, txBytes []byte) (string, error) {
+	return "", nil
+}
+
+// FetchBlocks serves the peer's blocks from the height, in the order they're listed
+func (f *fakeHeadBlocks) FetchBlocks(ctx context.Context, peerID peer.ID, fromHeight, count uint64) ([][]byte, error) {
+	var blocks [][]byte
+	for _, data := range f.blocks[peerID] {
+		minedBlock := new(miner.MinedBlock)
+		if err := minedBlock.Deserialize(data); err != nil {
+			return nil, err
+		}
+
+		height, err := hexutil.DecodeUint64(minedBlock.NextBlock.Props().BlockNumber)
+		if err != nil {
+			return nil, err
+		}
+		if height >= fromHeight && height < fromHeight+count {
+			blocks = append(blocks, data)
+		}
+	}
+
+	return blocks, nil
+}
+
+func (f *fakeHeadBlocks) FetchObjects(ctx context.Context, peerID peer.ID, cids []string) ([]string, [][]byte, error) {
+	return nil, nil, nil
+}
+
+func (f *fakeHeadBlocks) ExchangeStatus(ctx context.Context, peerID peer.ID) (*protobuff.PeerStatus, error) {
+	status, ok := f.statuses[peerID]
+	if !ok {
+		// note: the peer never responds
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if status == nil {
+		return nil, errors.New("protocol not supported")
+	}
+
+	return protobuff.StatusFromResponse(status), nil
+}
+
+func (f *fakeHeadBlocks) RequestDialBack(ctx context.Context, peerID peer.ID, addrs []string) ([]string, error) {
+	return nil, nil
+}
+
+func (f *fakeHeadBlocks) FetchTransactions(ctx context.Context, peerID peer.ID, hashes []string) ([][]byte, error) {
+	return nil, nil
+}
+
+// testDifficulty is the difficulty the test blocks are mined at
+const testDifficulty = 2
+
+func buildHeadBlock(t *testing.T, number uint64) *mainchain.Block {
+	return mineHeadBlock(t, number, mainchain.GenesisBlockHash, testDifficulty)
+}
+
+// mineHeadBlock mines and signs the block after the previous block at the difficulty
+func mineHeadBlock(t *testing.T, number uint64, prevBlockHash string, difficulty uint64) *mainchain.Block {
+	priv, pub, err := c3crypto.NewKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := c3crypto.EncodeAddress(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var block *mainchain.Block
+	for nonce := uint64(0); ; nonce++ {
+		block = mainchain.New(&mainchain.Props{
+			BlockNumber:           hexutil.EncodeUint64(number),
+			BlockTime:             hexutil.EncodeUint64(number),
+			ImageHash:             mainchain.ImageHash,
+			StateBlocksMerkleHash: "0x",
+			PrevBlockHash:         prevBlockHash,
+			Nonce:                 hexutil.EncodeUint64(nonce),
+			Difficulty:            hexutil.EncodeUint64(difficulty),
+			MinerAddress:          addr,
+		})
+		if err := block.SetHash(); err != nil {
+			t.Fatal(err)
+		}
+
+		if ok, err := miner.CheckBlockHashAgainstDifficulty(block); err != nil {
+			t.Fatal(err)
+		} else if ok {
+			break
+		}
+	}
+
+	r, s, err := c3crypto.Sign(priv, []byte(*block.Props().BlockHash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := block.SetMinerSig(&mainchain.MinerSig{R: hexutil.EncodeBigInt(r), S: hexutil.EncodeBigInt(s)}); err != nil {
+		t.Fatal(err)
+	}
+
+	return block
+}
+
+func TestFetchHeadBlocks(t *testing.T) {
+	local := buildHeadBlock(t, 2)
+	best := buildHeadBlock(t, 5)
+	next := buildHeadBlock(t, 3)
+
+	invalid := buildHeadBlock(t, 9)
+	invalidProps := invalid.Props()
+	invalidProps.BlockTime = hexutil.EncodeUint64(10)
+	invalid = mainchain.New(&invalidProps)
+
+	// note: a valid block mined below the required difficulty is rejected, however high it is
+	easy := mineHeadBlock(t, 20, mainchain.GenesisBlockHash, 0)
+	// note: a lower head block is still a candidate; its chain may have more work
+	lower := buildHeadBlock(t, 1)
+
+	self := peer.ID("self")
+	pBuff := &fakeHeadBlocks{
+		heads: map[peer.ID]*mainchain.Block{
+			"a": next,
+			"b": best,
+			"c": invalid,
+			"d": nil,
+			"e": best,
+			"f": easy,
+			"g": lower,
+			"h": local,
+		},
+	}
+
+	candidates, err := fetchHeadBlocks(self, local, []peer.ID{self, "a", "b", "c", "d", "e", "f", "g", "h"}, pBuff, testDifficulty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 3 {
+		t.Fatalf("expected 3 candidates; received %v", len(candidates))
+	}
+	if *candidates[0].block.Props().BlockHash != *best.Props().BlockHash || len(candidates[0].peers) != 2 {
+		t.Errorf("expected head block %s from 2 peers first; received %s from %v", best.Props().BlockNumber, candidates[0].block.Props().BlockNumber, candidates[0].peers)
+	}
+	if *candidates[1].block.Props().BlockHash != *next.Props().BlockHash || len(candidates[1].peers) != 1 || candidates[1].peers[0] != "a" {
+		t.Errorf("expected head block %s from peer a second; received %s from %v", next.Props().BlockNumber, candidates[1].block.Props().BlockNumber, candidates[1].peers)
+	}
+	if *candidates[2].block.Props().BlockHash != *lower.Props().BlockHash {
+		t.Errorf("expected the lower head block %s last; received %s", lower.Props().BlockNumber, candidates[2].block.Props().BlockNumber)
+	}
+
+	// note: without a required difficulty, the zero difficulty block is a candidate
+	candidates, err = fetchHeadBlocks(self, local, []peer.ID{self, "f"}, pBuff, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 1 || *candidates[0].block.Props().BlockHash != *easy.Props().BlockHash {
+		t.Errorf("expected the zero difficulty head block; received %v", candidates)
+	}
+
+	candidates, err = fetchHeadBlocks(self, local, []peer.ID{self, "h"}, pBuff, testDifficulty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 0 {
+		t.Errorf("expected no candidates when the peers are at the local head block; received %v", len(candidates))
+	}
+
+	if _, err := fetchHeadBlocks(self, local, []peer.ID{self, "c", "d", "f"}, pBuff, testDifficulty); err != ErrNoHeadBlock {
+		t.Errorf("expected %v; received %v", ErrNoHeadBlock, err)
+	}
+	if _, err := fetchHeadBlocks(self, local, []peer.ID{self}, pBuff, testDifficulty); err != ErrNoHeadBlock {
+		t.Errorf("expected %v; received %v", ErrNoHeadBlock, err)
+	}
+}
@@ -0,0 +1,99 @@
+package rpc
+
+import (
+	"github.com/c3systems/c3-go/common/hexutil"
+	"github.com/c3systems/c3-go/core/chain/merkle"
+	"github.com/c3systems/c3-go/core/p2p"
+	pb "github.com/c3systems/c3-go/rpc/pb"
+)
+
+// getProof returns the merkle audit path of a state block, or the state block of a tx, in the mainchain block.
+// params: mainchain block hash, state block hash or tx hash
+func (s *RPC) getProof(params []string) (*pb.ProofResponse, error) {
+	if len(params) < 2 {
+		return nil, ErrInvalidParams
+	}
+
+	blockCID, err := p2p.GetCIDByHash(params[0])
+	if err != nil {
+		return nil, err
+	}
+	block, err := s.p2p.GetMainchainBlock(blockCID)
+	if err != nil {
+		return nil, ErrBlockNotFound
+	}
+
+	root := block.Props().StateBlocksMerkleHash
+	if root == "" || root == hexutil.EncodeString("") {
+		return nil, ErrProofNotFound
+	}
+
+	treeCID, err := p2p.GetCIDByHash(root)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := s.p2p.GetMerkleTree(treeCID)
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := tree.Proof(params[1])
+	if err == merkle.ErrLeafNotFound {
+		// note: the leaves are state block hashes; a tx is proven through the state block that applied it
+		var stateBlockHash string
+		stateBlockHash, err = s.findStateBlockHashByTxHash(tree, params[1])
+		if err != nil {
+			return nil, err
+		}
+
+		proof, err = tree.Proof(stateBlockHash)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := merkle.VerifyProof(proof, root)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, merkle.ErrRootMismatch
+	}
+
+	var path []*pb.ProofStep
+	for _, step := range proof.Path {
+		path = append(path, &pb.ProofStep{
+			Hash: step.Hash,
+			Left: step.Left,
+		})
+	}
+
+	return &pb.ProofResponse{
+		BlockHash: *block.Props().BlockHash,
+		Root:      root,
+		Leaf:      proof.Leaf,
+		Index:     uint64(proof.Index),
+		Path:      path,
+	}, nil
+}
+
+// findStateBlockHashByTxHash ...
+func (s *RPC) findStateBlockHashByTxHash(tree *merkle.Tree, txHash string) (string, error) {
+	for _, stateBlockHash := range tree.Props().Hashes {
+		stateBlockCID, err := p2p.GetCIDByHash(stateBlockHash)
+		if err != nil {
+			return "", err
+		}
+
+		stateBlock, err := s.p2p.GetStatechainBlock(stateBlockCID)
+		if err != nil {
+			return "", err
+		}
+
+		if stateBlock.Props().TxHash == txHash {
+			return stateBlockHash, nil
+		}
+	}
+
+	return "", ErrProofNotFound
+}
@@ -0,0 +1,193 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/c3systems/c3-go/common/hexutil"
+	"github.com/c3systems/c3-go/core/chain/statechain"
+	"github.com/c3systems/c3-go/core/miner"
+	"github.com/c3systems/c3-go/core/p2p"
+	pb "github.com/c3systems/c3-go/rpc/pb"
+	"github.com/c3systems/c3-go/state"
+	"github.com/c3systems/c3-go/trie"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrStateRootMismatch is returned when the state rebuilt from the diffs doesn't match the state block's state root
+var ErrStateRootMismatch = errors.New("state doesn't match the state block's state root")
+
+// getState returns the value of a key in the image's state, with a trie proof against the state block's state root.
+// params: image hash, hex encoded key, optional state block number
+func (s *RPC) getState(params []string) (*pb.StateResponse, error) {
+	if len(params) < 2 {
+		return nil, ErrInvalidParams
+	}
+
+	key, err := hexutil.DecodeString(params[1])
+	if err != nil {
+		return nil, err
+	}
+
+	stateBlock, db, err := s.stateAt(params[0], optionalParam(params, 2))
+	if err != nil {
+		return nil, err
+	}
+
+	props := stateBlock.Props()
+	value, proof, err := state.Prove(db, props.StateCurrentHash, string(key))
+	if err != nil {
+		return nil, err
+	}
+
+	var encodedProof []string
+	for _, node := range proof {
+		encodedProof = append(encodedProof, hexutil.EncodeToString(node))
+	}
+
+	var encodedValue string
+	if value != "" {
+		encodedValue = hexutil.EncodeString(value)
+	}
+
+	return &pb.StateResponse{
+		ImageHash:        props.ImageHash,
+		BlockHash:        *props.BlockHash,
+		BlockNumber:      props.BlockNumber,
+		StateCurrentHash: props.StateCurrentHash,
+		Key:              hexutil.EncodeToString(key),
+		Value:            encodedValue,
+		Proof:            encodedProof,
+	}, nil
+}
+
+// getStateRange returns the keys starting with the prefix, and their values, in the image's state.
+// params: image hash, hex encoded key prefix, optional state block number
+func (s *RPC) getStateRange(params []string) (*pb.StateRangeResponse, error) {
+	if len(params) < 2 {
+		return nil, ErrInvalidParams
+	}
+
+	var prefix []byte
+	if params[1] != "" && params[1] != "0x" {
+		var err error
+		if prefix, err = hexutil.DecodeString(params[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	stateBlock, db, err := s.stateAt(params[0], optionalParam(params, 2))
+	if err != nil {
+		return nil, err
+	}
+
+	props := stateBlock.Props()
+	st, err := state.Range(db, props.StateCurrentHash, string(prefix))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := st.Keys()
+	sort.Strings(keys)
+
+	var entries []*pb.StateEntry
+	for _, key := range keys {
+		entries = append(entries, &pb.StateEntry{
+			Key:   hexutil.EncodeString(key),
+			Value: hexutil.EncodeString(st[key]),
+		})
+	}
+
+	return &pb.StateRangeResponse{
+		ImageHash:        props.ImageHash,
+		BlockHash:        *props.BlockHash,
+		BlockNumber:      props.BlockNumber,
+		StateCurrentHash: props.StateCurrentHash,
+		Entries:          entries,
+	}, nil
+}
+
+// stateAt returns the image's state block with the block number, or the most recent one, and the db holding its state trie
+func (s *RPC) stateAt(imageHash, blockNumber string) (*statechain.Block, trie.Database, error) {
+	headBlock, err := s.mempool.GetHeadBlock()
+	if err != nil {
+		return nil, nil, ErrBlockNotFound
+	}
+
+	stateBlock, err := s.p2p.FetchMostRecentStateBlock(imageHash, &headBlock)
+	if err != nil {
+		return nil, nil, err
+	}
+	if stateBlock == nil || stateBlock.Props().BlockHash == nil {
+		return nil, nil, ErrStateBlockNotFound
+	}
+
+	if blockNumber != "" {
+		wantStateBlockNumber, err := hexutil.DecodeInt(blockNumber)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for {
+			stateBlockNumber, err := hexutil.DecodeInt(stateBlock.Props().BlockNumber)
+			if err != nil {
+				return nil, nil, err
+			}
+			if stateBlockNumber == wantStateBlockNumber {
+				break
+			}
+			if stateBlockNumber < wantStateBlockNumber || stateBlockNumber <= 0 {
+				return nil, nil, ErrStateBlockNotFound
+			}
+
+			prevCID, err := p2p.GetCIDByHash(stateBlock.Props().PrevBlockHash)
+			if err != nil {
+				return nil, nil, err
+			}
+			if stateBlock, err = s.p2p.GetStatechainBlock(prevCID); err != nil {
+				return nil, nil, ErrStateBlockNotFound
+			}
+		}
+	}
+
+	db := miner.StateDatabase(s.p2p)
+	root := stateBlock.Props().StateCurrentHash
+	if state.HasRoot(db, root) {
+		return stateBlock, db, nil
+	}
+
+	// note: nodes that didn't mine or verify the block don't have its trie, so it's rebuilt from the diffs
+	log.Printf("[rpc] state root %s not found; rebuilding the state of image hash %s", root, imageHash)
+	ctx := context.Background()
+	st, diffs, err := miner.GatherDiffsSinceSnapshot(ctx, s.p2p, stateBlock)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(diffs) > 0 {
+		if st, err = miner.GenerateStateFromDiffs(ctx, imageHash, st, diffs); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// note: the rebuilt trie isn't retained, it stays pinned until the pin expires, so it's served to the next queries in the meantime
+	rebuiltRoot, err := state.RootFromBytes(db, st)
+	if err != nil {
+		return nil, nil, err
+	}
+	if rebuiltRoot != root {
+		log.Errorf("[rpc] rebuilt state root %s doesn't match state block root %s", rebuiltRoot, root)
+		return nil, nil, ErrStateRootMismatch
+	}
+
+	return stateBlock, db, nil
+}
+
+func optionalParam(params []string, idx int) string {
+	if len(params) > idx {
+		return params[idx]
+	}
+
+	return ""
+}
@@ -70,6 +70,7 @@ func (s *RPC) getStateblock(params []string) (*pb.StateBlockResponse, error) {
 			PrevBlockHash:     props.PrevBlockHash,
 			StatePrevDiffHash: props.StatePrevDiffHash,
 			StateCurrentHash:  props.StateCurrentHash,
+			StateSnapshotCID:  props.StateSnapshotCID,
 		}, nil
 	}
 }
@@ -505,6 +505,7 @@ type StateBlockResponse struct {
 	PrevBlockHash        string   `protobuf:"bytes,6,opt,name=prevBlockHash,proto3" json:"prevBlockHash,omitempty"`
 	StatePrevDiffHash    string   `protobuf:"bytes,7,opt,name=statePrevDiffHash,proto3" json:"statePrevDiffHash,omitempty"`
 	StateCurrentHash     string   `protobuf:"bytes,8,opt,name=stateCurrentHash,proto3" json:"stateCurrentHash,omitempty"`
+	StateSnapshotCID     string   `protobuf:"bytes,9,opt,name=stateSnapshotCID,proto3" json:"stateSnapshotCID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -590,6 +591,13 @@ func (m *StateBlockResponse) GetStateCurrentHash() string {
 	return ""
 }
 
+func (m *StateBlockResponse) GetStateSnapshotCID() string {
+	if m != nil {
+		return m.StateSnapshotCID
+	}
+	return ""
+}
+
 type ImageResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -658,6 +666,532 @@ func (m *InvokeMethodResponse) GetTxHash() string {
 	return ""
 }
 
+type ProofStep struct {
+	Hash                 string   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Left                 bool     `protobuf:"varint,2,opt,name=left,proto3" json:"left,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ProofStep) Reset()         { *m = ProofStep{} }
+func (m *ProofStep) String() string { return proto.CompactTextString(m) }
+func (*ProofStep) ProtoMessage()    {}
+func (*ProofStep) Descriptor() ([]byte, []int) {
+	return fileDescriptor_738f7cea0cc5ed23, []int{11}
+}
+func (m *ProofStep) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProofStep.Unmarshal(m, b)
+}
+func (m *ProofStep) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProofStep.Marshal(b, m, deterministic)
+}
+func (dst *ProofStep) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProofStep.Merge(dst, src)
+}
+func (m *ProofStep) XXX_Size() int {
+	return xxx_messageInfo_ProofStep.Size(m)
+}
+func (m *ProofStep) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProofStep.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProofStep proto.InternalMessageInfo
+
+func (m *ProofStep) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *ProofStep) GetLeft() bool {
+	if m != nil {
+		return m.Left
+	}
+	return false
+}
+
+type ProofResponse struct {
+	BlockHash            string       `protobuf:"bytes,1,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	Root                 string       `protobuf:"bytes,2,opt,name=root,proto3" json:"root,omitempty"`
+	Leaf                 string       `protobuf:"bytes,3,opt,name=leaf,proto3" json:"leaf,omitempty"`
+	Index                uint64       `protobuf:"varint,4,opt,name=index,proto3" json:"index,omitempty"`
+	Path                 []*ProofStep `protobuf:"bytes,5,rep,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ProofResponse) Reset()         { *m = ProofResponse{} }
+func (m *ProofResponse) String() string { return proto.CompactTextString(m) }
+func (*ProofResponse) ProtoMessage()    {}
+func (*ProofResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_738f7cea0cc5ed23, []int{12}
+}
+func (m *ProofResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProofResponse.Unmarshal(m, b)
+}
+func (m *ProofResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProofResponse.Marshal(b, m, deterministic)
+}
+func (dst *ProofResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProofResponse.Merge(dst, src)
+}
+func (m *ProofResponse) XXX_Size() int {
+	return xxx_messageInfo_ProofResponse.Size(m)
+}
+func (m *ProofResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProofResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProofResponse proto.InternalMessageInfo
+
+func (m *ProofResponse) GetBlockHash() string {
+	if m != nil {
+		return m.BlockHash
+	}
+	return ""
+}
+
+func (m *ProofResponse) GetRoot() string {
+	if m != nil {
+		return m.Root
+	}
+	return ""
+}
+
+func (m *ProofResponse) GetLeaf() string {
+	if m != nil {
+		return m.Leaf
+	}
+	return ""
+}
+
+func (m *ProofResponse) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *ProofResponse) GetPath() []*ProofStep {
+	if m != nil {
+		return m.Path
+	}
+	return nil
+}
+
+type StateResponse struct {
+	ImageHash            string   `protobuf:"bytes,1,opt,name=imageHash,proto3" json:"imageHash,omitempty"`
+	BlockHash            string   `protobuf:"bytes,2,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	BlockNumber          string   `protobuf:"bytes,3,opt,name=blockNumber,proto3" json:"blockNumber,omitempty"`
+	StateCurrentHash     string   `protobuf:"bytes,4,opt,name=stateCurrentHash,proto3" json:"stateCurrentHash,omitempty"`
+	Key                  string   `protobuf:"bytes,5,opt,name=key,proto3" json:"key,omitempty"`
+	Value                string   `protobuf:"bytes,6,opt,name=value,proto3" json:"value,omitempty"`
+	Proof                []string `protobuf:"bytes,7,rep,name=proof,proto3" json:"proof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StateResponse) Reset()         { *m = StateResponse{} }
+func (m *StateResponse) String() string { return proto.CompactTextString(m) }
+func (*StateResponse) ProtoMessage()    {}
+func (*StateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_738f7cea0cc5ed23, []int{13}
+}
+func (m *StateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateResponse.Unmarshal(m, b)
+}
+func (m *StateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateResponse.Marshal(b, m, deterministic)
+}
+func (dst *StateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateResponse.Merge(dst, src)
+}
+func (m *StateResponse) XXX_Size() int {
+	return xxx_messageInfo_StateResponse.Size(m)
+}
+func (m *StateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateResponse proto.InternalMessageInfo
+
+func (m *StateResponse) GetImageHash() string {
+	if m != nil {
+		return m.ImageHash
+	}
+	return ""
+}
+
+func (m *StateResponse) GetBlockHash() string {
+	if m != nil {
+		return m.BlockHash
+	}
+	return ""
+}
+
+func (m *StateResponse) GetBlockNumber() string {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return ""
+}
+
+func (m *StateResponse) GetStateCurrentHash() string {
+	if m != nil {
+		return m.StateCurrentHash
+	}
+	return ""
+}
+
+func (m *StateResponse) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *StateResponse) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *StateResponse) GetProof() []string {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+type StateEntry struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value                string   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StateEntry) Reset()         { *m = StateEntry{} }
+func (m *StateEntry) String() string { return proto.CompactTextString(m) }
+func (*StateEntry) ProtoMessage()    {}
+func (*StateEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_738f7cea0cc5ed23, []int{14}
+}
+func (m *StateEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateEntry.Unmarshal(m, b)
+}
+func (m *StateEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateEntry.Marshal(b, m, deterministic)
+}
+func (dst *StateEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateEntry.Merge(dst, src)
+}
+func (m *StateEntry) XXX_Size() int {
+	return xxx_messageInfo_StateEntry.Size(m)
+}
+func (m *StateEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateEntry proto.InternalMessageInfo
+
+func (m *StateEntry) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *StateEntry) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+type StateRangeResponse struct {
+	ImageHash            string        `protobuf:"bytes,1,opt,name=imageHash,proto3" json:"imageHash,omitempty"`
+	BlockHash            string        `protobuf:"bytes,2,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	BlockNumber          string        `protobuf:"bytes,3,opt,name=blockNumber,proto3" json:"blockNumber,omitempty"`
+	StateCurrentHash     string        `protobuf:"bytes,4,opt,name=stateCurrentHash,proto3" json:"stateCurrentHash,omitempty"`
+	Entries              []*StateEntry `protobuf:"bytes,5,rep,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *StateRangeResponse) Reset()         { *m = StateRangeResponse{} }
+func (m *StateRangeResponse) String() string { return proto.CompactTextString(m) }
+func (*StateRangeResponse) ProtoMessage()    {}
+func (*StateRangeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_738f7cea0cc5ed23, []int{15}
+}
+func (m *StateRangeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateRangeResponse.Unmarshal(m, b)
+}
+func (m *StateRangeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateRangeResponse.Marshal(b, m, deterministic)
+}
+func (dst *StateRangeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateRangeResponse.Merge(dst, src)
+}
+func (m *StateRangeResponse) XXX_Size() int {
+	return xxx_messageInfo_StateRangeResponse.Size(m)
+}
+func (m *StateRangeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateRangeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateRangeResponse proto.InternalMessageInfo
+
+func (m *StateRangeResponse) GetImageHash() string {
+	if m != nil {
+		return m.ImageHash
+	}
+	return ""
+}
+
+func (m *StateRangeResponse) GetBlockHash() string {
+	if m != nil {
+		return m.BlockHash
+	}
+	return ""
+}
+
+func (m *StateRangeResponse) GetBlockNumber() string {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return ""
+}
+
+func (m *StateRangeResponse) GetStateCurrentHash() string {
+	if m != nil {
+		return m.StateCurrentHash
+	}
+	return ""
+}
+
+func (m *StateRangeResponse) GetEntries() []*StateEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+type PeerInfo struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Addrs                []string `protobuf:"bytes,2,rep,name=addrs,proto3" json:"addrs,omitempty"`
+	Connected            bool     `protobuf:"varint,3,opt,name=connected,proto3" json:"connected,omitempty"`
+	Bootstrap            bool     `protobuf:"varint,4,opt,name=bootstrap,proto3" json:"bootstrap,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PeerInfo) Reset()         { *m = PeerInfo{} }
+func (m *PeerInfo) String() string { return proto.CompactTextString(m) }
+func (*PeerInfo) ProtoMessage()    {}
+func (*PeerInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_738f7cea0cc5ed23, []int{16}
+}
+func (m *PeerInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeerInfo.Unmarshal(m, b)
+}
+func (m *PeerInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeerInfo.Marshal(b, m, deterministic)
+}
+func (dst *PeerInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerInfo.Merge(dst, src)
+}
+func (m *PeerInfo) XXX_Size() int {
+	return xxx_messageInfo_PeerInfo.Size(m)
+}
+func (m *PeerInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerInfo proto.InternalMessageInfo
+
+func (m *PeerInfo) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *PeerInfo) GetAddrs() []string {
+	if m != nil {
+		return m.Addrs
+	}
+	return nil
+}
+
+func (m *PeerInfo) GetConnected() bool {
+	if m != nil {
+		return m.Connected
+	}
+	return false
+}
+
+func (m *PeerInfo) GetBootstrap() bool {
+	if m != nil {
+		return m.Bootstrap
+	}
+	return false
+}
+
+type PeersResponse struct {
+	Peers                []*PeerInfo `protobuf:"bytes,1,rep,name=peers,proto3" json:"peers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *PeersResponse) Reset()         { *m = PeersResponse{} }
+func (m *PeersResponse) String() string { return proto.CompactTextString(m) }
+func (*PeersResponse) ProtoMessage()    {}
+func (*PeersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_738f7cea0cc5ed23, []int{17}
+}
+func (m *PeersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeersResponse.Unmarshal(m, b)
+}
+func (m *PeersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeersResponse.Marshal(b, m, deterministic)
+}
+func (dst *PeersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeersResponse.Merge(dst, src)
+}
+func (m *PeersResponse) XXX_Size() int {
+	return xxx_messageInfo_PeersResponse.Size(m)
+}
+func (m *PeersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeersResponse proto.InternalMessageInfo
+
+func (m *PeersResponse) GetPeers() []*PeerInfo {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+type ConnectPeerResponse struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConnectPeerResponse) Reset()         { *m = ConnectPeerResponse{} }
+func (m *ConnectPeerResponse) String() string { return proto.CompactTextString(m) }
+func (*ConnectPeerResponse) ProtoMessage()    {}
+func (*ConnectPeerResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_738f7cea0cc5ed23, []int{18}
+}
+func (m *ConnectPeerResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConnectPeerResponse.Unmarshal(m, b)
+}
+func (m *ConnectPeerResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConnectPeerResponse.Marshal(b, m, deterministic)
+}
+func (dst *ConnectPeerResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConnectPeerResponse.Merge(dst, src)
+}
+func (m *ConnectPeerResponse) XXX_Size() int {
+	return xxx_messageInfo_ConnectPeerResponse.Size(m)
+}
+func (m *ConnectPeerResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConnectPeerResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConnectPeerResponse proto.InternalMessageInfo
+
+func (m *ConnectPeerResponse) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type ReachabilityResponse struct {
+	Reachability         string   `protobuf:"bytes,1,opt,name=reachability,proto3" json:"reachability,omitempty"`
+	PublicAddrs          []string `protobuf:"bytes,2,rep,name=publicAddrs,proto3" json:"publicAddrs,omitempty"`
+	NatAddrs             []string `protobuf:"bytes,3,rep,name=natAddrs,proto3" json:"natAddrs,omitempty"`
+	RelayAddrs           []string `protobuf:"bytes,4,rep,name=relayAddrs,proto3" json:"relayAddrs,omitempty"`
+	Checked              int64    `protobuf:"varint,5,opt,name=checked,proto3" json:"checked,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReachabilityResponse) Reset()         { *m = ReachabilityResponse{} }
+func (m *ReachabilityResponse) String() string { return proto.CompactTextString(m) }
+func (*ReachabilityResponse) ProtoMessage()    {}
+func (*ReachabilityResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_738f7cea0cc5ed23, []int{19}
+}
+func (m *ReachabilityResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReachabilityResponse.Unmarshal(m, b)
+}
+func (m *ReachabilityResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReachabilityResponse.Marshal(b, m, deterministic)
+}
+func (dst *ReachabilityResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReachabilityResponse.Merge(dst, src)
+}
+func (m *ReachabilityResponse) XXX_Size() int {
+	return xxx_messageInfo_ReachabilityResponse.Size(m)
+}
+func (m *ReachabilityResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReachabilityResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReachabilityResponse proto.InternalMessageInfo
+
+func (m *ReachabilityResponse) GetReachability() string {
+	if m != nil {
+		return m.Reachability
+	}
+	return ""
+}
+
+func (m *ReachabilityResponse) GetPublicAddrs() []string {
+	if m != nil {
+		return m.PublicAddrs
+	}
+	return nil
+}
+
+func (m *ReachabilityResponse) GetNatAddrs() []string {
+	if m != nil {
+		return m.NatAddrs
+	}
+	return nil
+}
+
+func (m *ReachabilityResponse) GetRelayAddrs() []string {
+	if m != nil {
+		return m.RelayAddrs
+	}
+	return nil
+}
+
+func (m *ReachabilityResponse) GetChecked() int64 {
+	if m != nil {
+		return m.Checked
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Request)(nil), "protos.Request")
 	proto.RegisterType((*Response)(nil), "protos.Response")
@@ -670,6 +1204,15 @@ func init() {
 	proto.RegisterType((*StateBlockResponse)(nil), "protos.StateBlockResponse")
 	proto.RegisterType((*ImageResponse)(nil), "protos.ImageResponse")
 	proto.RegisterType((*InvokeMethodResponse)(nil), "protos.InvokeMethodResponse")
+	proto.RegisterType((*ProofStep)(nil), "protos.ProofStep")
+	proto.RegisterType((*ProofResponse)(nil), "protos.ProofResponse")
+	proto.RegisterType((*StateResponse)(nil), "protos.StateResponse")
+	proto.RegisterType((*StateEntry)(nil), "protos.StateEntry")
+	proto.RegisterType((*StateRangeResponse)(nil), "protos.StateRangeResponse")
+	proto.RegisterType((*PeerInfo)(nil), "protos.PeerInfo")
+	proto.RegisterType((*PeersResponse)(nil), "protos.PeersResponse")
+	proto.RegisterType((*ConnectPeerResponse)(nil), "protos.ConnectPeerResponse")
+	proto.RegisterType((*ReachabilityResponse)(nil), "protos.ReachabilityResponse")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -747,43 +1290,65 @@ var _C3Service_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("c3.proto", fileDescriptor_738f7cea0cc5ed23) }
 
 var fileDescriptor_738f7cea0cc5ed23 = []byte{
-	// 597 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x54, 0xdd, 0x6e, 0x13, 0x3d,
-	0x10, 0xfd, 0x36, 0x4d, 0xf3, 0x33, 0x6d, 0xbe, 0xb6, 0x6e, 0xa9, 0x96, 0x0a, 0xa1, 0xc8, 0x20,
-	0x51, 0xa0, 0xa4, 0x52, 0xcb, 0x05, 0x37, 0x5c, 0xb4, 0x05, 0x89, 0x4a, 0x14, 0x55, 0x9b, 0xbe,
-	0x80, 0xb3, 0x3b, 0xd9, 0x9a, 0x66, 0xed, 0x60, 0x7b, 0x23, 0xf2, 0x46, 0x3c, 0x01, 0xcf, 0xc5,
-	0x23, 0xa0, 0x9d, 0x75, 0x36, 0x09, 0x2d, 0x7f, 0x77, 0x5c, 0xad, 0xcf, 0x99, 0xe3, 0x9d, 0xf1,
-	0x99, 0xb1, 0xa1, 0x15, 0x1f, 0xf7, 0xc6, 0x46, 0x3b, 0xcd, 0x1a, 0xf4, 0xb1, 0x7b, 0xf7, 0x53,
-	0xad, 0xd3, 0x11, 0x1e, 0x12, 0x1c, 0xe4, 0xc3, 0x43, 0xa1, 0xa6, 0xa5, 0x84, 0xc7, 0xd0, 0x8c,
-	0xf0, 0x53, 0x8e, 0xd6, 0xb1, 0x10, 0x9a, 0x1f, 0xad, 0x56, 0x66, 0x1c, 0x87, 0x41, 0x37, 0xd8,
-	0x6f, 0x47, 0x33, 0xc8, 0xfe, 0x87, 0x9a, 0x4c, 0xc2, 0x5a, 0x37, 0xd8, 0xaf, 0x47, 0x35, 0x99,
-	0xb0, 0x5d, 0x68, 0x64, 0xe8, 0xae, 0x75, 0x12, 0xae, 0x90, 0xd0, 0xa3, 0x82, 0x1f, 0x0b, 0x23,
-	0x32, 0x1b, 0xd6, 0xbb, 0x2b, 0x05, 0x5f, 0x22, 0x3e, 0x80, 0x56, 0x84, 0x76, 0xac, 0x95, 0xc5,
-	0xbf, 0xc8, 0x72, 0x00, 0x0d, 0x83, 0x36, 0x1f, 0x39, 0xca, 0xb2, 0x76, 0xb4, 0xd3, 0x2b, 0x8f,
-	0xd1, 0x9b, 0x1d, 0xa3, 0x77, 0xa2, 0xa6, 0x91, 0xd7, 0xf0, 0xd7, 0xd0, 0x79, 0x6b, 0x8c, 0x36,
-	0x55, 0x22, 0x06, 0xf5, 0x58, 0x27, 0x48, 0x59, 0xea, 0x11, 0xad, 0x8b, 0xe4, 0x19, 0x5a, 0x2b,
-	0x52, 0xa4, 0x3c, 0xed, 0x68, 0x06, 0x39, 0x87, 0xf5, 0x4b, 0xa9, 0xd2, 0xc5, 0xdd, 0x89, 0x70,
-	0xc2, 0xd7, 0x48, 0x6b, 0xfe, 0x14, 0xb6, 0xdf, 0x0b, 0x87, 0xd6, 0x9d, 0x8e, 0x74, 0x7c, 0xf3,
-	0x4b, 0xe9, 0xb7, 0x1a, 0x74, 0x96, 0x55, 0x0f, 0xa0, 0x3d, 0x28, 0x88, 0x77, 0xc2, 0x5e, 0x7b,
-	0xe9, 0x9c, 0x60, 0x5d, 0x58, 0x23, 0xf0, 0x21, 0xcf, 0x06, 0x68, 0x7c, 0x71, 0x8b, 0x54, 0xb5,
-	0xff, 0x4a, 0x66, 0xe8, 0x6d, 0x9f, 0x13, 0x45, 0x54, 0x66, 0x22, 0x45, 0xfa, 0x7b, 0xbd, 0x8c,
-	0x56, 0x04, 0x7b, 0x09, 0xf7, 0xac, 0x13, 0x0e, 0xa9, 0x22, 0x7b, 0x81, 0xe6, 0x66, 0x54, 0x2a,
-	0x57, 0x49, 0x79, 0x77, 0x90, 0x3d, 0x86, 0xce, 0xd8, 0xe0, 0xe4, 0xb4, 0xaa, 0xba, 0x41, 0xea,
-	0x65, 0x92, 0xed, 0xc0, 0xaa, 0xd2, 0x2a, 0xc6, 0xb0, 0x49, 0xd1, 0x12, 0xb0, 0x87, 0x00, 0x89,
-	0x1c, 0x0e, 0x65, 0x9c, 0x8f, 0xdc, 0x34, 0x6c, 0x51, 0x68, 0x81, 0x61, 0x1c, 0xd6, 0x33, 0xa9,
-	0xd0, 0x9c, 0x24, 0x89, 0x41, 0x6b, 0xc3, 0x36, 0x29, 0x96, 0x38, 0xf6, 0x02, 0x5a, 0x84, 0xfb,
-	0x32, 0x0d, 0x81, 0x26, 0x60, 0xab, 0x6c, 0xbd, 0xed, 0xf5, 0x65, 0xaa, 0x84, 0xcb, 0x0d, 0x46,
-	0x95, 0x84, 0x3f, 0x81, 0x76, 0x45, 0xb3, 0x75, 0x08, 0x8c, 0x77, 0x39, 0x30, 0x05, 0xb2, 0xde,
-	0xd3, 0xc0, 0xf2, 0xaf, 0x01, 0x6c, 0x5f, 0x19, 0xa1, 0xac, 0x88, 0x9d, 0xd4, 0xaa, 0xea, 0xd0,
-	0x2e, 0x34, 0xdc, 0xe7, 0x85, 0xf6, 0x78, 0xb4, 0xec, 0x6d, 0xed, 0x47, 0x6f, 0x7f, 0x76, 0x17,
-	0x42, 0x68, 0x8e, 0xc5, 0x74, 0xa4, 0x45, 0xe2, 0x2f, 0xc3, 0x0c, 0x16, 0xf3, 0x32, 0x34, 0x3a,
-	0xf3, 0xe6, 0xd3, 0x9a, 0x3d, 0x82, 0x15, 0x2b, 0x53, 0x72, 0xf8, 0xce, 0x63, 0x16, 0x51, 0xfe,
-	0xa5, 0x06, 0xac, 0x5f, 0xb5, 0xea, 0x9f, 0x98, 0xac, 0xb9, 0x67, 0xab, 0x4b, 0x9e, 0xfd, 0xd9,
-	0xec, 0x1c, 0xc0, 0x16, 0x8d, 0xde, 0xa5, 0xc1, 0xc9, 0x1b, 0x39, 0x1c, 0x92, 0xb2, 0x9c, 0xa3,
-	0xdb, 0x01, 0xf6, 0x0c, 0x36, 0x89, 0x3c, 0xcb, 0x8d, 0x41, 0xe5, 0x48, 0x5c, 0x4e, 0xd6, 0x2d,
-	0x9e, 0x6f, 0x40, 0xe7, 0xbc, 0x28, 0x72, 0x66, 0x12, 0xef, 0xc1, 0xce, 0xb9, 0x9a, 0xe8, 0x1b,
-	0xbc, 0xa0, 0xf6, 0xfc, 0xae, 0xe9, 0x47, 0xaf, 0xa0, 0x7d, 0x76, 0xdc, 0x47, 0x33, 0x91, 0x31,
-	0xb2, 0xe7, 0x50, 0xef, 0xa3, 0x4a, 0xd8, 0xc6, 0xac, 0x31, 0xfe, 0xc9, 0xdc, 0xdb, 0x9c, 0x13,
-	0x3e, 0xcf, 0x7f, 0x83, 0xf2, 0xd1, 0x3d, 0xfe, 0x1e, 0x00, 0x00, 0xff, 0xff, 0x91, 0x1f, 0x31,
-	0xd9, 0x87, 0x05, 0x00, 0x00,
+	// 948 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x56, 0xdd, 0x6e, 0x1b, 0x45,
+	0x14, 0x66, 0xed, 0x8d, 0x7f, 0x4e, 0x63, 0x9a, 0x4e, 0x42, 0xb5, 0x44, 0x08, 0x45, 0x03, 0x85,
+	0x02, 0xc1, 0x95, 0x9a, 0x4a, 0x70, 0xc3, 0x45, 0x9a, 0x56, 0x22, 0x12, 0x45, 0xd1, 0xba, 0x2f,
+	0x30, 0xde, 0x3d, 0x6b, 0x2f, 0x59, 0xcf, 0x2c, 0x33, 0xe3, 0xa8, 0x7e, 0x0d, 0x1e, 0x85, 0x0b,
+	0x9e, 0x82, 0x4b, 0x24, 0x5e, 0x83, 0x47, 0x40, 0x73, 0x66, 0xbc, 0x5e, 0xe7, 0x87, 0x96, 0xbb,
+	0x5e, 0x79, 0xce, 0x77, 0xce, 0xcc, 0xf9, 0xf9, 0xbe, 0x99, 0x35, 0x0c, 0xb2, 0x93, 0x71, 0xad,
+	0x95, 0x55, 0xac, 0x47, 0x3f, 0xe6, 0xf0, 0xe3, 0x99, 0x52, 0xb3, 0x0a, 0x9f, 0x90, 0x39, 0x5d,
+	0x16, 0x4f, 0x84, 0x5c, 0xf9, 0x10, 0x9e, 0x41, 0x3f, 0xc5, 0x5f, 0x97, 0x68, 0x2c, 0x4b, 0xa0,
+	0xff, 0x8b, 0x51, 0x52, 0xd7, 0x59, 0x12, 0x1d, 0x45, 0x8f, 0x87, 0xe9, 0xda, 0x64, 0x1f, 0x42,
+	0xa7, 0xcc, 0x93, 0xce, 0x51, 0xf4, 0x38, 0x4e, 0x3b, 0x65, 0xce, 0x1e, 0x42, 0x6f, 0x81, 0x76,
+	0xae, 0xf2, 0xa4, 0x4b, 0x81, 0xc1, 0x72, 0x78, 0x2d, 0xb4, 0x58, 0x98, 0x24, 0x3e, 0xea, 0x3a,
+	0xdc, 0x5b, 0x7c, 0x0a, 0x83, 0x14, 0x4d, 0xad, 0xa4, 0xc1, 0xff, 0x91, 0xe5, 0x18, 0x7a, 0x1a,
+	0xcd, 0xb2, 0xb2, 0x94, 0xe5, 0xde, 0xd3, 0x83, 0xb1, 0x6f, 0x63, 0xbc, 0x6e, 0x63, 0x7c, 0x2a,
+	0x57, 0x69, 0x88, 0xe1, 0x3f, 0xc0, 0xe8, 0xa5, 0xd6, 0x4a, 0x37, 0x89, 0x18, 0xc4, 0x99, 0xca,
+	0x91, 0xb2, 0xc4, 0x29, 0xad, 0x5d, 0xf2, 0x05, 0x1a, 0x23, 0x66, 0x48, 0x79, 0x86, 0xe9, 0xda,
+	0xe4, 0x1c, 0x76, 0x2f, 0x4a, 0x39, 0x6b, 0xef, 0xce, 0x85, 0x15, 0xa1, 0x46, 0x5a, 0xf3, 0xaf,
+	0x60, 0xff, 0x27, 0x61, 0xd1, 0xd8, 0xe7, 0x95, 0xca, 0x2e, 0xff, 0x33, 0xf4, 0x9f, 0x0e, 0x8c,
+	0xb6, 0xa3, 0x3e, 0x81, 0xe1, 0xd4, 0x01, 0x3f, 0x0a, 0x33, 0x0f, 0xa1, 0x1b, 0x80, 0x1d, 0xc1,
+	0x3d, 0x32, 0x7e, 0x5e, 0x2e, 0xa6, 0xa8, 0x43, 0x71, 0x6d, 0xa8, 0xd9, 0xff, 0xba, 0x5c, 0x60,
+	0x18, 0xfb, 0x06, 0x70, 0xde, 0x72, 0x21, 0x66, 0x48, 0xa7, 0xc7, 0xde, 0xdb, 0x00, 0xec, 0x19,
+	0x7c, 0x64, 0xac, 0xb0, 0x48, 0x15, 0x99, 0x57, 0xa8, 0x2f, 0x2b, 0x1f, 0xb9, 0x43, 0x91, 0xb7,
+	0x3b, 0xd9, 0xe7, 0x30, 0xaa, 0x35, 0x5e, 0x3d, 0x6f, 0xaa, 0xee, 0x51, 0xf4, 0x36, 0xc8, 0x0e,
+	0x60, 0x47, 0x2a, 0x99, 0x61, 0xd2, 0x27, 0xaf, 0x37, 0xd8, 0xa7, 0x00, 0x79, 0x59, 0x14, 0x65,
+	0xb6, 0xac, 0xec, 0x2a, 0x19, 0x90, 0xab, 0x85, 0x30, 0x0e, 0xbb, 0x8b, 0x52, 0xa2, 0x3e, 0xcd,
+	0x73, 0x8d, 0xc6, 0x24, 0x43, 0x8a, 0xd8, 0xc2, 0xd8, 0xb7, 0x30, 0x20, 0x7b, 0x52, 0xce, 0x12,
+	0x20, 0x05, 0x3c, 0xf0, 0xd4, 0x9b, 0xf1, 0xa4, 0x9c, 0x49, 0x61, 0x97, 0x1a, 0xd3, 0x26, 0x84,
+	0x7f, 0x09, 0xc3, 0x06, 0x66, 0xbb, 0x10, 0xe9, 0x30, 0xe5, 0x48, 0x3b, 0xcb, 0x84, 0x99, 0x46,
+	0x86, 0xff, 0x11, 0xc1, 0xfe, 0x6b, 0x2d, 0xa4, 0x11, 0x99, 0x2d, 0x95, 0x6c, 0x18, 0x7a, 0x08,
+	0x3d, 0xfb, 0xa6, 0x45, 0x4f, 0xb0, 0xb6, 0x67, 0xdb, 0xb9, 0x3e, 0xdb, 0xbb, 0xee, 0x42, 0x02,
+	0xfd, 0x5a, 0xac, 0x2a, 0x25, 0xf2, 0x70, 0x19, 0xd6, 0xa6, 0xd3, 0x4b, 0xa1, 0xd5, 0x22, 0x0c,
+	0x9f, 0xd6, 0xec, 0x33, 0xe8, 0x9a, 0x72, 0x96, 0xf4, 0xee, 0x6a, 0xd3, 0x79, 0xf9, 0x5f, 0x1d,
+	0x60, 0x93, 0x86, 0xaa, 0xf7, 0x42, 0x59, 0x9b, 0x99, 0xed, 0x6c, 0xcd, 0xec, 0xdd, 0xb4, 0x73,
+	0x0c, 0x0f, 0x48, 0x7a, 0x17, 0x1a, 0xaf, 0x5e, 0x94, 0x45, 0x41, 0x91, 0x5e, 0x47, 0x37, 0x1d,
+	0xec, 0x6b, 0xd8, 0x23, 0xf0, 0x6c, 0xa9, 0x35, 0x4a, 0x4b, 0xc1, 0x5e, 0x59, 0x37, 0xf0, 0x26,
+	0x76, 0x22, 0x45, 0x6d, 0xe6, 0xca, 0x9e, 0x9d, 0xbf, 0x08, 0x1a, 0xbb, 0x81, 0xf3, 0xfb, 0x30,
+	0x3a, 0x77, 0x0d, 0xad, 0x07, 0xca, 0xc7, 0x70, 0x70, 0x2e, 0xaf, 0xd4, 0x25, 0xbe, 0x22, 0x2a,
+	0xdf, 0x26, 0x10, 0x7e, 0x02, 0xc3, 0x0b, 0xad, 0x54, 0x31, 0xb1, 0x58, 0x3b, 0x76, 0xe7, 0x9b,
+	0x10, 0x5a, 0x3b, 0xac, 0xc2, 0xc2, 0xd2, 0xf0, 0x07, 0x29, 0xad, 0xf9, 0x6f, 0x11, 0x8c, 0x68,
+	0xd7, 0x3b, 0xf2, 0xc8, 0x20, 0xd6, 0x4a, 0xd9, 0x40, 0x20, 0xad, 0xfd, 0xb9, 0xa2, 0x08, 0xa4,
+	0xd1, 0xda, 0xdd, 0xc7, 0x52, 0xe6, 0xf8, 0x86, 0xb8, 0x8a, 0x53, 0x6f, 0xb0, 0x47, 0x10, 0xd7,
+	0xc2, 0x3a, 0x96, 0xba, 0x6d, 0x81, 0x35, 0x65, 0xa7, 0xe4, 0xe6, 0x7f, 0x47, 0x30, 0x22, 0x85,
+	0xb5, 0x8b, 0xda, 0xd0, 0x1f, 0x5d, 0xa7, 0x7f, 0xab, 0xe4, 0xce, 0x5b, 0xa4, 0xd7, 0xbd, 0x29,
+	0xbd, 0xdb, 0x28, 0x8d, 0xef, 0xa0, 0x74, 0x0f, 0xba, 0x97, 0xb8, 0x0a, 0x3a, 0x73, 0x4b, 0xd7,
+	0xea, 0x95, 0xa8, 0x96, 0x18, 0xc4, 0xe5, 0x0d, 0x87, 0xd6, 0xae, 0xad, 0xa4, 0x4f, 0xd7, 0xce,
+	0x1b, 0xfc, 0x19, 0x00, 0x35, 0xf6, 0x52, 0x5a, 0xbd, 0x5a, 0x9f, 0x15, 0xdd, 0x72, 0x56, 0xa7,
+	0x75, 0x16, 0xff, 0x33, 0x0a, 0x37, 0x2e, 0x15, 0x72, 0xf6, 0x3e, 0x0e, 0xe5, 0x18, 0xfa, 0x28,
+	0xad, 0x2e, 0xd1, 0x04, 0x6a, 0x59, 0xf3, 0x76, 0x34, 0xdd, 0xa6, 0xeb, 0x10, 0x5e, 0xc1, 0xe0,
+	0x02, 0x51, 0x9f, 0xcb, 0x42, 0x85, 0xaf, 0xad, 0x2f, 0xde, 0x7d, 0x6d, 0x0f, 0x60, 0x47, 0xe4,
+	0xb9, 0x76, 0xef, 0x24, 0x8d, 0x8d, 0x0c, 0xd7, 0x4b, 0xa6, 0xa4, 0xc4, 0xcc, 0xa2, 0x7f, 0xe0,
+	0x06, 0xe9, 0x06, 0xa0, 0x4e, 0x95, 0xb2, 0xc6, 0x6a, 0x51, 0x53, 0x89, 0x83, 0x74, 0x03, 0xf0,
+	0xef, 0x60, 0xe4, 0xb2, 0x99, 0x66, 0x6c, 0x5f, 0xc0, 0x4e, 0xed, 0x80, 0x24, 0xa2, 0x52, 0xf7,
+	0x1a, 0x15, 0x86, 0x9a, 0x52, 0xef, 0xe6, 0x8f, 0x60, 0xff, 0xcc, 0xe7, 0x70, 0x9e, 0x66, 0xfb,
+	0xb5, 0x8a, 0xf9, 0xef, 0x11, 0x1c, 0xa4, 0x28, 0xb2, 0xb9, 0x98, 0x96, 0x55, 0x69, 0x57, 0x4d,
+	0x20, 0x87, 0x5d, 0xdd, 0xc2, 0xc3, 0x96, 0x2d, 0xcc, 0xd1, 0x50, 0x2f, 0xa7, 0x55, 0x99, 0x9d,
+	0xb6, 0x9a, 0x6e, 0x43, 0xec, 0x10, 0x06, 0x52, 0x58, 0xef, 0xee, 0x92, 0xbb, 0xb1, 0xdd, 0xe7,
+	0x4d, 0x63, 0x25, 0x56, 0xde, 0xeb, 0xdf, 0xf7, 0x16, 0xe2, 0x1e, 0xff, 0x6c, 0x8e, 0xd9, 0x25,
+	0xe6, 0xa4, 0xd7, 0x6e, 0xba, 0x36, 0x9f, 0x7e, 0x0f, 0xc3, 0xb3, 0x93, 0x09, 0xea, 0xab, 0x32,
+	0x43, 0xf6, 0x0d, 0xc4, 0x13, 0x94, 0x39, 0xbb, 0xbf, 0x9e, 0x44, 0xf8, 0x2b, 0x76, 0xb8, 0xb7,
+	0x01, 0xc2, 0x9b, 0xf4, 0xc1, 0xd4, 0xff, 0x99, 0x3b, 0xf9, 0x77, 0x00, 0x13, 0x2b, 0x1d, 0xfb,
+	0xdf, 0x09, 0x00, 0x00,
 }
@@ -0,0 +1,55 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	pb "github.com/c3systems/c3-go/rpc/pb"
+)
+
+// ErrNoPeerManager is returned when the node doesn't manage its peers
+var ErrNoPeerManager = errors.New("node has no peer manager")
+
+// listPeers returns the node's known peers
+func (s *RPC) listPeers() (*pb.PeersResponse, error) {
+	if s.node == nil || s.node.Props().PeerManager == nil {
+		return nil, ErrNoPeerManager
+	}
+
+	resp := &pb.PeersResponse{}
+	for _, info := range s.node.Props().PeerManager.Peers() {
+		var addrs []string
+		for _, addr := range info.Addrs {
+			addrs = append(addrs, addr.String())
+		}
+
+		resp.Peers = append(resp.Peers, &pb.PeerInfo{
+			Id:        info.ID.Pretty(),
+			Addrs:     addrs,
+			Connected: info.Connected,
+			Bootstrap: info.Bootstrap,
+		})
+	}
+
+	return resp, nil
+}
+
+// connectPeer connects the node to a peer.
+// params: ipfs multiaddr of the peer, e.g. /ip4/127.0.0.1/tcp/9000/ipfs/Qm...
+func (s *RPC) connectPeer(params []string) (*pb.ConnectPeerResponse, error) {
+	if len(params) < 1 {
+		return nil, ErrInvalidParams
+	}
+	if s.node == nil || s.node.Props().PeerManager == nil {
+		return nil, ErrNoPeerManager
+	}
+
+	id, err := s.node.Props().PeerManager.Connect(context.Background(), params[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ConnectPeerResponse{
+		Id: id.Pretty(),
+	}, nil
+}
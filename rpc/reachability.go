@@ -0,0 +1,40 @@
+package rpc
+
+import (
+	"errors"
+
+	pb "github.com/c3systems/c3-go/rpc/pb"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// ErrNoAutoNAT is returned when the node doesn't check its reachability
+var ErrNoAutoNAT = errors.New("node has no autonat service")
+
+// reachability returns whether the node's peers can dial it, with the addrs it's reachable on
+func (s *RPC) reachability() (*pb.ReachabilityResponse, error) {
+	if s.node == nil || s.node.Props().AutoNAT == nil {
+		return nil, ErrNoAutoNAT
+	}
+
+	status := s.node.Props().AutoNAT.Status()
+	resp := &pb.ReachabilityResponse{
+		Reachability: string(status.Reachability),
+		PublicAddrs:  addrStrings(status.PublicAddrs),
+		NatAddrs:     addrStrings(status.NATAddrs),
+		RelayAddrs:   addrStrings(status.RelayAddrs),
+	}
+	if !status.Checked.IsZero() {
+		resp.Checked = status.Checked.Unix()
+	}
+
+	return resp, nil
+}
+
+func addrStrings(addrs []ma.Multiaddr) []string {
+	var strs []string
+	for _, addr := range addrs {
+		strs = append(strs, addr.String())
+	}
+
+	return strs
+}
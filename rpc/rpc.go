@@ -27,6 +27,10 @@ var (
 	ErrBlockNotFound = errors.New("block not found")
 	// ErrStateBlockNotFound ...
 	ErrStateBlockNotFound = errors.New("state block not found")
+	// ErrProofNotFound ...
+	ErrProofNotFound = errors.New("state block or transaction not found in block")
+	// ErrInvalidParams ...
+	ErrInvalidParams = errors.New("invalid params")
 )
 
 // RPC ...
@@ -129,6 +133,33 @@ func (s *Server) handleRequest(method string, r *pb.Request) (*any.Any, error) {
 			})
 		}
 		return ptypes.MarshalAny(result)
+	case "c3_getproof":
+		result, err := s.service.getProof(r.Params)
+		if err != nil {
+			return ptypes.MarshalAny(&pb.ErrorResponse{
+				Code:    400,
+				Message: err.Error(),
+			})
+		}
+		return ptypes.MarshalAny(result)
+	case "c3_getstate":
+		result, err := s.service.getState(r.Params)
+		if err != nil {
+			return ptypes.MarshalAny(&pb.ErrorResponse{
+				Code:    400,
+				Message: err.Error(),
+			})
+		}
+		return ptypes.MarshalAny(result)
+	case "c3_getstaterange":
+		result, err := s.service.getStateRange(r.Params)
+		if err != nil {
+			return ptypes.MarshalAny(&pb.ErrorResponse{
+				Code:    400,
+				Message: err.Error(),
+			})
+		}
+		return ptypes.MarshalAny(result)
 	case "c3_invokemethod":
 		result, err := s.service.invokeMethod(r.Params)
 		if err != nil {
@@ -138,6 +169,33 @@ func (s *Server) handleRequest(method string, r *pb.Request) (*any.Any, error) {
 			})
 		}
 		return ptypes.MarshalAny(result)
+	case "c3_listpeers":
+		result, err := s.service.listPeers()
+		if err != nil {
+			return ptypes.MarshalAny(&pb.ErrorResponse{
+				Code:    400,
+				Message: err.Error(),
+			})
+		}
+		return ptypes.MarshalAny(result)
+	case "c3_reachability":
+		result, err := s.service.reachability()
+		if err != nil {
+			return ptypes.MarshalAny(&pb.ErrorResponse{
+				Code:    400,
+				Message: err.Error(),
+			})
+		}
+		return ptypes.MarshalAny(result)
+	case "c3_connectpeer":
+		result, err := s.service.connectPeer(r.Params)
+		if err != nil {
+			return ptypes.MarshalAny(&pb.ErrorResponse{
+				Code:    400,
+				Message: err.Error(),
+			})
+		}
+		return ptypes.MarshalAny(result)
 	default:
 		return ptypes.MarshalAny(&pb.ErrorResponse{
 			Code:    400,
@@ -33,14 +33,17 @@ type Config struct {
 }
 
 // New ...
-func New(cfg *Config) *Service {
-	sb := sandbox.New(nil)
+func New(cfg *Config) (*Service, error) {
+	sb, err := sandbox.New(nil)
+	if err != nil {
+		return nil, err
+	}
 
 	return &Service{
 		P2P:     cfg.P2P,
 		Mempool: cfg.Mempool,
 		Sandbox: sb,
-	}
+	}, nil
 }
 
 // Snapshot ...
@@ -66,17 +69,7 @@ func (s *Service) Snapshot(imageHash string, stateBlockNumber int) (string, erro
 
 	ts := time.Now().Unix()
 
-	minr, err := miner.New(&miner.Props{
-		Context:             context.Background(),
-		PreviousBlock:       &headBlock,
-		P2P:                 s.P2P,
-		PendingTransactions: nil,
-	})
-	if err != nil {
-		return "", err
-	}
-
-	diffs, err := minr.GatherDiffs(prevStateBlock)
+	state, diffs, err := miner.GatherDiffsSinceSnapshot(context.Background(), s.P2P, prevStateBlock)
 	if err != nil {
 		return "", err
 	}
@@ -86,10 +79,11 @@ func (s *Service) Snapshot(imageHash string, stateBlockNumber int) (string, erro
 		log.Printf("diff %v\n%s", i, diffs[i].Props().Data)
 	}
 
-	var genesisState []byte
-	state, err := miner.GenerateStateFromDiffs(context.Background(), imageHash, genesisState, diffs)
-	if err != nil {
-		return "", err
+	if len(diffs) > 0 {
+		state, err = miner.GenerateStateFromDiffs(context.Background(), imageHash, state, diffs)
+		if err != nil {
+			return "", err
+		}
 	}
 
 	sta, err := stringutil.CompactJSON(state)
@@ -32,10 +32,13 @@ func TestSnapshot(t *testing.T) {
 		t.Error(err)
 	}
 
-	svc := New(&Config{
+	svc, err := New(&Config{
 		P2P:     n.Props().P2P,
 		Mempool: n.Props().Store,
 	})
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	imageHash := "d50ada614c01"
 	stateBlockNumber := 2
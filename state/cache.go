@@ -0,0 +1,152 @@
+package state
+
+import (
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	// DefaultCacheSize is the number of states a cache keeps by default
+	DefaultCacheSize = 256
+
+	// maxAcceptedBlocks is the number of recent mainchain blocks whose states can be invalidated
+	maxAcceptedBlocks = 256
+)
+
+// CacheKey identifies the state of an image after a state block
+type CacheKey struct {
+	ImageHash string
+	BlockHash string
+}
+
+// Cache is a node local cache of the serialized states of images, keyed by image hash and state block hash.
+// The states of the state blocks in an accepted mainchain block are tracked, so they're invalidated when the
+// mainchain block is abandoned on a reorg.
+// note: a nil cache is valid and caches nothing.
+type Cache struct {
+	mut      sync.Mutex
+	states   *lru.Cache
+	accepted map[string][]CacheKey
+	order    []string
+}
+
+// NewCache returns a cache of the most recently used states
+func NewCache(size int) (*Cache, error) {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+
+	states, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cache{
+		states:   states,
+		accepted: make(map[string][]CacheKey),
+	}, nil
+}
+
+// Get returns the state of the image after the state block
+func (c *Cache) Get(imageHash, blockHash string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	v, ok := c.states.Get(CacheKey{ImageHash: imageHash, BlockHash: blockHash})
+	if !ok {
+		return nil, false
+	}
+
+	st, _ := v.([]byte)
+	return copyBytes(st), true
+}
+
+// Add caches the state of the image after the state block
+func (c *Cache) Add(imageHash, blockHash string, st []byte) {
+	if c == nil || st == nil {
+		return
+	}
+
+	c.states.Add(CacheKey{ImageHash: imageHash, BlockHash: blockHash}, copyBytes(st))
+}
+
+// Accept tracks the states of the state blocks in the accepted mainchain block
+func (c *Cache) Accept(mainchainBlockHash string, keys ...CacheKey) {
+	if c == nil {
+		return
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if _, ok := c.accepted[mainchainBlockHash]; !ok {
+		c.order = append(c.order, mainchainBlockHash)
+	}
+	c.accepted[mainchainBlockHash] = append(c.accepted[mainchainBlockHash], keys...)
+
+	// note: blocks older than a reorg can reach are no longer tracked
+	for len(c.order) > maxAcceptedBlocks {
+		delete(c.accepted, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+// Invalidate removes the states of the state blocks in the abandoned mainchain block and returns how many were removed
+func (c *Cache) Invalidate(mainchainBlockHash string) int {
+	if c == nil {
+		return 0
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	var removed int
+	for _, key := range c.accepted[mainchainBlockHash] {
+		if c.states.Contains(key) {
+			c.states.Remove(key)
+			removed++
+		}
+	}
+	delete(c.accepted, mainchainBlockHash)
+	for i, hash := range c.order {
+		if hash == mainchainBlockHash {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+
+	return removed
+}
+
+// Purge removes every state
+func (c *Cache) Purge() {
+	if c == nil {
+		return
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.states.Purge()
+	c.accepted = make(map[string][]CacheKey)
+	c.order = nil
+}
+
+// Len returns the number of cached states
+func (c *Cache) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	return c.states.Len()
+}
+
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+
+	return append([]byte{}, b...)
+}
@@ -0,0 +1,86 @@
+// +build unit
+
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	cache, err := NewCache(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	st := []byte(`{"foo":"bar"}`)
+	cache.Add("image", "block1", st)
+	// note: the cache keeps its own copy
+	st[2] = 'x'
+
+	got, ok := cache.Get("image", "block1")
+	if !ok || !bytes.Equal(got, []byte(`{"foo":"bar"}`)) {
+		t.Errorf("expected %s\nreceived %s %v", `{"foo":"bar"}`, got, ok)
+	}
+	if _, ok := cache.Get("other", "block1"); ok {
+		t.Error("expected a miss for another image")
+	}
+
+	// note: the least recently used states are evicted
+	for i := 2; i <= 5; i++ {
+		cache.Add("image", fmt.Sprintf("block%d", i), []byte(fmt.Sprintf("state%d", i)))
+	}
+	if _, ok := cache.Get("image", "block1"); ok {
+		t.Error("expected block1 to be evicted")
+	}
+	if cache.Len() != 4 {
+		t.Errorf("expected %v\nreceived %v", 4, cache.Len())
+	}
+
+	cache.Accept("mainchain1", CacheKey{ImageHash: "image", BlockHash: "block2"}, CacheKey{ImageHash: "image", BlockHash: "block3"})
+	cache.Accept("mainchain2", CacheKey{ImageHash: "image", BlockHash: "block4"})
+
+	if removed := cache.Invalidate("mainchain1"); removed != 2 {
+		t.Errorf("expected %v\nreceived %v", 2, removed)
+	}
+	for _, blockHash := range []string{"block2", "block3"} {
+		if _, ok := cache.Get("image", blockHash); ok {
+			t.Errorf("expected %s to be invalidated", blockHash)
+		}
+	}
+	if _, ok := cache.Get("image", "block4"); !ok {
+		t.Error("expected block4 to be cached")
+	}
+	if removed := cache.Invalidate("mainchain1"); removed != 0 {
+		t.Errorf("expected %v\nreceived %v", 0, removed)
+	}
+
+	cache.Purge()
+	if cache.Len() != 0 {
+		t.Errorf("expected %v\nreceived %v", 0, cache.Len())
+	}
+	if removed := cache.Invalidate("mainchain2"); removed != 0 {
+		t.Errorf("expected %v\nreceived %v", 0, removed)
+	}
+}
+
+func TestNilCache(t *testing.T) {
+	t.Parallel()
+
+	var cache *Cache
+	cache.Add("image", "block", []byte("state"))
+	cache.Accept("mainchain", CacheKey{ImageHash: "image", BlockHash: "block"})
+	if _, ok := cache.Get("image", "block"); ok {
+		t.Error("expected a nil cache to cache nothing")
+	}
+	if removed := cache.Invalidate("mainchain"); removed != 0 {
+		t.Errorf("expected %v\nreceived %v", 0, removed)
+	}
+	cache.Purge()
+	if cache.Len() != 0 {
+		t.Errorf("expected %v\nreceived %v", 0, cache.Len())
+	}
+}
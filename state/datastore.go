@@ -0,0 +1,95 @@
+package state
+
+import (
+	"io"
+
+	"github.com/c3systems/c3-go/common/hexutil"
+	"github.com/c3systems/c3-go/core/p2p/store/leveldbstore"
+	"github.com/c3systems/c3-go/trie"
+
+	ds "github.com/ipfs/go-datastore"
+	log "github.com/sirupsen/logrus"
+)
+
+// Ensure the struct implements the interfaces
+var (
+	_ trie.Database   = (*DatastoreDatabase)(nil)
+	_ trie.RefCounter = (*DatastoreDatabase)(nil)
+)
+
+// nodesPrefix is the datastore namespace of the trie nodes
+var nodesPrefix = ds.NewKey("/trie/nodes")
+
+// DatastoreDatabase stores trie nodes, and their reference counts, in a node local datastore.
+// note: peers can't fetch the nodes; BlockstoreDatabase stores them as blocks they can.
+type DatastoreDatabase struct {
+	*refCounter
+	datastore ds.Datastore
+}
+
+// NewDatastoreDatabase ...
+func NewDatastoreDatabase(datastore ds.Datastore) *DatastoreDatabase {
+	return &DatastoreDatabase{
+		refCounter: newRefCounter(&datastoreNodes{datastore: datastore}, datastore),
+		datastore:  datastore,
+	}
+}
+
+// NewLevelDBDatabase opens, or creates, the LevelDB at the path and returns the db storing trie nodes in it
+func NewLevelDBDatabase(path string) (*DatastoreDatabase, error) {
+	datastore, err := leveldbstore.New(path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDatastoreDatabase(datastore), nil
+}
+
+// LastKnownTD ...
+func (db *DatastoreDatabase) LastKnownTD() []byte {
+	return nil
+}
+
+// Close ...
+func (db *DatastoreDatabase) Close() {
+	closer, ok := db.datastore.(io.Closer)
+	if !ok {
+		return
+	}
+
+	if err := closer.Close(); err != nil {
+		log.Errorf("[state] error closing trie datastore; %s", err)
+	}
+}
+
+// Print ...
+func (db *DatastoreDatabase) Print() {}
+
+// datastoreNodes ...
+type datastoreNodes struct {
+	datastore ds.Datastore
+}
+
+func (d *datastoreNodes) has(key []byte) (bool, error) {
+	return d.datastore.Has(nodeKey(key))
+}
+
+func (d *datastoreNodes) get(key []byte) ([]byte, error) {
+	return d.datastore.Get(nodeKey(key))
+}
+
+func (d *datastoreNodes) put(key, value []byte) error {
+	return d.datastore.Put(nodeKey(key), value)
+}
+
+func (d *datastoreNodes) delete(key []byte) error {
+	if err := d.datastore.Delete(nodeKey(key)); err != nil && err != ds.ErrNotFound {
+		return err
+	}
+
+	return nil
+}
+
+func nodeKey(key []byte) ds.Key {
+	return nodesPrefix.ChildString(hexutil.EncodeToString(key))
+}
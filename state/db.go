@@ -0,0 +1,164 @@
+package state
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/c3systems/c3-go/common/hashutil"
+	"github.com/c3systems/c3-go/common/hexutil"
+	"github.com/c3systems/c3-go/trie"
+
+	blocks "github.com/ipfs/go-block-format"
+	cid "github.com/ipfs/go-cid"
+	ds "github.com/ipfs/go-datastore"
+	bstore "github.com/ipfs/go-ipfs-blockstore"
+	mh "github.com/multiformats/go-multihash"
+)
+
+// Ensure the struct implements the interfaces
+var (
+	_ trie.Database   = (*BlockstoreDatabase)(nil)
+	_ trie.RefCounter = (*BlockstoreDatabase)(nil)
+)
+
+// cidsPrefix is the datastore namespace of the index of the trie nodes' cids by trie key
+var cidsPrefix = ds.NewKey("/trie/cids")
+
+// ErrNodeMismatch is returned when a stored trie node doesn't match its trie key or cid
+var ErrNodeMismatch = errors.New("trie node doesn't match its key")
+
+// BlockstoreDatabase stores trie nodes as blocks in an ipfs blockstore, so peers can fetch them.
+// Each node is stored under the cid of its bytes, which peers verify the block against. The trie keys, the hashutil
+// hashes of the nodes, have no multihash code, so a node local index maps them to the cids.
+// note: the nodes read back are checked against both their cid and their trie key.
+type BlockstoreDatabase struct {
+	*refCounter
+	nodes *blockstoreNodes
+}
+
+// NewBlockstoreDatabase returns the db storing trie nodes in the blockstore, and their cids and reference counts in the datastore.
+// note: a nil datastore keeps the index and the counts in memory.
+func NewBlockstoreDatabase(blockstore bstore.Blockstore, datastore ds.Datastore) *BlockstoreDatabase {
+	if datastore == nil {
+		datastore = ds.NewMapDatastore()
+	}
+
+	nodes := &blockstoreNodes{
+		blockstore: blockstore,
+		index:      datastore,
+	}
+
+	return &BlockstoreDatabase{
+		refCounter: newRefCounter(nodes, datastore),
+		nodes:      nodes,
+	}
+}
+
+// NodeCID returns the cid of the block holding the trie node with the key, or ds.ErrNotFound
+func (db *BlockstoreDatabase) NodeCID(key []byte) (cid.Cid, error) {
+	return db.nodes.cid(key)
+}
+
+// BlockCID returns the cid of the block holding the trie node: its raw bytes, hashed with sha2-256
+func BlockCID(value []byte) (cid.Cid, error) {
+	hash, err := mh.Sum(value, mh.SHA2_256, -1)
+	if err != nil {
+		return cid.Cid{}, err
+	}
+
+	return cid.NewCidV1(cid.Raw, hash), nil
+}
+
+// LastKnownTD ...
+func (db *BlockstoreDatabase) LastKnownTD() []byte {
+	return nil
+}
+
+// Close ...
+func (db *BlockstoreDatabase) Close() {}
+
+// Print ...
+func (db *BlockstoreDatabase) Print() {}
+
+// blockstoreNodes ...
+type blockstoreNodes struct {
+	blockstore bstore.Blockstore
+	index      ds.Datastore
+}
+
+func (b *blockstoreNodes) cid(key []byte) (cid.Cid, error) {
+	data, err := b.index.Get(cidKey(key))
+	if err != nil {
+		return cid.Cid{}, err
+	}
+
+	return cid.Cast(data)
+}
+
+func (b *blockstoreNodes) has(key []byte) (bool, error) {
+	return b.index.Has(cidKey(key))
+}
+
+func (b *blockstoreNodes) get(key []byte) ([]byte, error) {
+	c, err := b.cid(key)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := b.blockstore.Get(c)
+	if err != nil {
+		return nil, err
+	}
+
+	data := block.RawData()
+	sum, err := c.Prefix().Sum(data)
+	if err != nil {
+		return nil, err
+	}
+	hash := hashutil.Hash(data)
+	if !sum.Equals(c) || !bytes.Equal(hash[:], key) {
+		return nil, ErrNodeMismatch
+	}
+
+	return data, nil
+}
+
+func (b *blockstoreNodes) put(key, value []byte) error {
+	c, err := BlockCID(value)
+	if err != nil {
+		return err
+	}
+
+	block, err := blocks.NewBlockWithCid(value, c)
+	if err != nil {
+		return err
+	}
+	if err := b.blockstore.Put(block); err != nil {
+		return err
+	}
+
+	return b.index.Put(cidKey(key), c.Bytes())
+}
+
+func (b *blockstoreNodes) delete(key []byte) error {
+	c, err := b.cid(key)
+	if err == ds.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := b.blockstore.DeleteBlock(c); err != nil && err != bstore.ErrNotFound {
+		return err
+	}
+	if err := b.index.Delete(cidKey(key)); err != nil && err != ds.ErrNotFound {
+		return err
+	}
+
+	return nil
+}
+
+func cidKey(key []byte) ds.Key {
+	return cidsPrefix.ChildString(hexutil.EncodeToString(key))
+}
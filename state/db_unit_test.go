@@ -0,0 +1,355 @@
+// +build unit
+
+package state
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/c3systems/c3-go/trie"
+
+	ds "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+	dssync "github.com/ipfs/go-datastore/sync"
+	bstore "github.com/ipfs/go-ipfs-blockstore"
+)
+
+type refCountedDatabase interface {
+	trie.Database
+	trie.RefCounter
+	RefCount(key []byte) (int, error)
+}
+
+func TestBlockstoreDatabaseRefCounts(t *testing.T) {
+	t.Parallel()
+
+	datastore := dssync.MutexWrap(ds.NewMapDatastore())
+	db := NewBlockstoreDatabase(bstore.NewBlockstore(datastore), datastore)
+	testRefCounts(t, db, datastore)
+
+	// note: the blocks and the cid index are deleted along with the nodes
+	res, err := datastore.Query(dsq.Query{KeysOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := res.Rest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no blocks or cids; received %d entries", len(entries))
+	}
+}
+
+func TestBlockstoreDatabaseCIDs(t *testing.T) {
+	t.Parallel()
+
+	blocks := bstore.NewBlockstore(dssync.MutexWrap(ds.NewMapDatastore()))
+	db := NewBlockstoreDatabase(blocks, nil)
+
+	s := testState(0, 10)
+	root := s.Root(db)
+	key, err := rootKey(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// note: the root node is stored as a block under the cid of its bytes, so peers can fetch and verify it
+	c, err := db.NodeCID(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := blocks.Get(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := BlockCID(block.RawData())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Equals(expected) {
+		t.Errorf("expected the block cid %s; received %s", expected, c)
+	}
+	checkState(t, db, root, s)
+
+	// note: a node whose block doesn't match its trie key isn't read
+	other := testState(10, 20)
+	otherKey, err := rootKey(other.Root(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCID, err := db.NodeCID(otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.nodes.index.Put(cidKey(key), otherCID.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Get(key); err != ErrNodeMismatch {
+		t.Errorf("expected %v; received %v", ErrNodeMismatch, err)
+	}
+}
+
+func TestDatastoreDatabaseRefCounts(t *testing.T) {
+	t.Parallel()
+
+	datastore := dssync.MutexWrap(ds.NewMapDatastore())
+	db := NewDatastoreDatabase(datastore)
+	testRefCounts(t, db, datastore)
+}
+
+func TestLevelDBDatabase(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "c3-trie")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := NewLevelDBDatabase(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := testState(0, 50)
+	root := s.Root(db)
+	rootBytes, err := rootKey(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Reference(rootBytes); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	// note: the trie and its counts survive reopening the db
+	db, err = NewLevelDBDatabase(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	checkState(t, db, root, s)
+	if count, err := db.RefCount(rootBytes); err != nil || count != 1 {
+		t.Errorf("expected 1 reference to the root; received %d %v", count, err)
+	}
+}
+
+func testRefCounts(t *testing.T, db refCountedDatabase, refs ds.Datastore) {
+	s1 := testState(0, 40)
+	s2 := testState(20, 60)
+
+	root1 := s1.Root(db)
+	root2 := s2.Root(db)
+	for _, root := range []string{root1, root2} {
+		key, err := rootKey(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Reference(key); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Release(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// note: the nodes written while building the tries, that neither root reaches, are pruned once their pins expire
+	for i := 0; i <= pinExpiry; i++ {
+		if _, err := db.Prune(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkState(t, db, root1, s1)
+	checkState(t, db, root2, s2)
+
+	before := countRefs(t, refs)
+	key1, _ := rootKey(root1)
+	if err := db.Dereference(key1); err != nil {
+		t.Fatal(err)
+	}
+	if HasRoot(db, root1) {
+		t.Error("expected the dereferenced root to be deleted")
+	}
+	if after := countRefs(t, refs); after >= before {
+		t.Errorf("expected fewer nodes after dereferencing; received %d and %d", before, after)
+	}
+	checkState(t, db, root2, s2)
+
+	// note: the shared nodes are only deleted with the last root
+	key2, _ := rootKey(root2)
+	if err := db.Dereference(key2); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRefs(t, refs); n != 0 {
+		t.Errorf("expected no nodes; received %d", n)
+	}
+	if pruned, err := db.Prune(); err != nil || pruned != 0 {
+		t.Errorf("expected nothing to prune; received %d %v", pruned, err)
+	}
+}
+
+func TestPrunePinned(t *testing.T) {
+	t.Parallel()
+
+	db := NewDatastoreDatabase(dssync.MutexWrap(ds.NewMapDatastore()))
+	s1 := testState(0, 40)
+	root1 := s1.Root(db)
+	key1, _ := rootKey(root1)
+
+	// note: the trie is pinned until it's released, e.g. while it's verified
+	if _, err := db.Prune(); err != nil {
+		t.Fatal(err)
+	}
+	checkState(t, db, root1, s1)
+
+	// note: a pinned root isn't deleted when its last reference is dropped
+	if err := db.Reference(key1); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Dereference(key1); err != nil {
+		t.Fatal(err)
+	}
+	checkState(t, db, root1, s1)
+
+	// note: rebuilding a trie pins its stored nodes again, so releasing one write keeps them
+	if root := s1.Root(db); root != root1 {
+		t.Fatalf("expected root %s; received %s", root1, root)
+	}
+	if err := db.Release(key1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Prune(); err != nil {
+		t.Fatal(err)
+	}
+	checkState(t, db, root1, s1)
+
+	if err := ReleaseRoot(db, root1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Prune(); err != nil {
+		t.Fatal(err)
+	}
+	if HasRoot(db, root1) {
+		t.Error("expected the released root to be pruned")
+	}
+
+	// note: the pins of tries that are never released expire
+	s2 := testState(40, 80)
+	root2 := s2.Root(db)
+	for i := 0; i < pinExpiry; i++ {
+		if _, err := db.Prune(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkState(t, db, root2, s2)
+	if _, err := db.Prune(); err != nil {
+		t.Fatal(err)
+	}
+	if HasRoot(db, root2) {
+		t.Error("expected the root to be pruned once its pin expired")
+	}
+}
+
+func testState(from, to int) State {
+	s := New()
+	for i := from; i < to; i++ {
+		s.Set([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("a value long enough to not be inlined %d", i)))
+	}
+
+	return s
+}
+
+func checkState(t *testing.T, db trie.Database, root string, s State) {
+	tr, err := OpenTrie(db, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range s.Keys() {
+		if v := tr.Get(key); v != s[key] {
+			t.Fatalf("expected %s for %s; received %s", s[key], key, v)
+		}
+	}
+}
+
+func countRefs(t *testing.T, refs ds.Datastore) int {
+	results, err := refs.Query(dsq.Query{Prefix: refsPrefix.String(), KeysOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := results.Rest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return len(entries)
+}
+
+func TestGC(t *testing.T) {
+	t.Parallel()
+
+	datastore := dssync.MutexWrap(ds.NewMapDatastore())
+	db := NewDatastoreDatabase(datastore)
+	gc, err := NewGC(db, 2, datastore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var roots []string
+	for i := 0; i < 4; i++ {
+		root := testState(i*10, i*10+30).Root(db)
+		if err := gc.Add("image", root); err != nil {
+			t.Fatal(err)
+		}
+		// note: adding a root twice doesn't retain it twice
+		if err := gc.Add("image", root); err != nil {
+			t.Fatal(err)
+		}
+		if err := ReleaseRoot(db, root); err != nil {
+			t.Fatal(err)
+		}
+
+		roots = append(roots, root)
+	}
+
+	if _, err := gc.Prune(); err != nil {
+		t.Fatal(err)
+	}
+
+	retained, err := gc.Roots("image")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(roots[2:], retained) {
+		t.Errorf("expected %v\nreceived %v", roots[2:], retained)
+	}
+	for idx, root := range roots {
+		if has := HasRoot(db, root); has != (idx >= 2) {
+			t.Errorf("root %d failed\nexpected the db to have it: %v", idx+1, idx >= 2)
+		}
+	}
+	checkState(t, db, roots[3], testState(30, 60))
+
+	// note: the retained roots survive restarts
+	restarted, err := NewGC(db, 2, datastore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retained, err := restarted.Roots("image"); err != nil || !reflect.DeepEqual(roots[2:], retained) {
+		t.Errorf("expected %v\nreceived %v %v", roots[2:], retained, err)
+	}
+
+	mem, err := trie.NewMemDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewGC(mem, 2, nil); err != ErrNotRefCounted {
+		t.Errorf("expected %v; received %v", ErrNotRefCounted, err)
+	}
+}
@@ -0,0 +1,178 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/c3systems/c3-go/trie"
+
+	ds "github.com/ipfs/go-datastore"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrNotRefCounted is returned when the db doesn't count references to its trie nodes
+var ErrNotRefCounted = errors.New("trie db is not reference counted")
+
+// DefaultRetainedRoots is the number of state roots kept per image by default
+const DefaultRetainedRoots = 128
+
+// rootsPrefix is the datastore namespace of the roots retained per image
+var rootsPrefix = ds.NewKey("/trie/roots")
+
+// GC keeps the tries of the last retained state roots of every image, releasing the older ones
+type GC struct {
+	mut      sync.Mutex
+	db       trie.RefCounter
+	retained int
+	roots    map[string][]string
+	store    ds.Datastore
+}
+
+// NewGC returns the GC of the db's tries. The retained roots are kept in store, if set, so they survive restarts.
+func NewGC(db trie.Database, retained int, store ds.Datastore) (*GC, error) {
+	refCounter, ok := db.(trie.RefCounter)
+	if !ok {
+		return nil, ErrNotRefCounted
+	}
+	if retained <= 0 {
+		retained = DefaultRetainedRoots
+	}
+
+	return &GC{
+		db:       refCounter,
+		retained: retained,
+		roots:    make(map[string][]string),
+		store:    store,
+	}, nil
+}
+
+// Add retains the image's state root, releasing the image's oldest root when more than the retained roots are kept
+func (g *GC) Add(imageHash, rootHash string) error {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+
+	if rootHash == EmptyRootHash {
+		return nil
+	}
+
+	roots, err := g.load(imageHash)
+	if err != nil {
+		return err
+	}
+	// note: a root is only retained once, e.g. when a block is received more than once
+	for _, root := range roots {
+		if root == rootHash {
+			return nil
+		}
+	}
+
+	key, err := rootKey(rootHash)
+	if err != nil {
+		return err
+	}
+	if err := g.db.Reference(key); err != nil {
+		return err
+	}
+
+	roots = append(roots, rootHash)
+	for len(roots) > g.retained {
+		if err := g.release(roots[0]); err != nil {
+			log.Errorf("[state] error releasing root %s of image hash %s; %s", roots[0], imageHash, err)
+			return err
+		}
+
+		roots = roots[1:]
+	}
+
+	return g.save(imageHash, roots)
+}
+
+// Roots returns the image's retained roots, oldest first
+func (g *GC) Roots(imageHash string) ([]string, error) {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+
+	roots, err := g.load(imageHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]string{}, roots...), nil
+}
+
+// Prune deletes the released trie nodes that no retained root reaches, e.g. the tries of blocks that weren't mined
+func (g *GC) Prune() (int, error) {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+
+	return g.db.Prune()
+}
+
+func (g *GC) release(rootHash string) error {
+	key, err := rootKey(rootHash)
+	if err != nil {
+		return err
+	}
+
+	return g.db.Dereference(key)
+}
+
+// ReleaseRoot unpins the trie of the state root in the db, if the db counts references to its nodes
+func ReleaseRoot(db trie.Database, rootHash string) error {
+	refCounter, ok := db.(trie.RefCounter)
+	if !ok {
+		return nil
+	}
+
+	return releaseRoot(refCounter, rootHash)
+}
+
+func releaseRoot(db trie.RefCounter, rootHash string) error {
+	if rootHash == EmptyRootHash {
+		return nil
+	}
+
+	key, err := rootKey(rootHash)
+	if err != nil {
+		return err
+	}
+
+	return db.Release(key)
+}
+
+func (g *GC) load(imageHash string) ([]string, error) {
+	if roots, ok := g.roots[imageHash]; ok || g.store == nil {
+		return roots, nil
+	}
+
+	data, err := g.store.Get(rootsPrefix.ChildString(imageHash))
+	if err == ds.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var roots []string
+	if err := json.Unmarshal(data, &roots); err != nil {
+		return nil, err
+	}
+
+	g.roots[imageHash] = roots
+	return roots, nil
+}
+
+func (g *GC) save(imageHash string, roots []string) error {
+	g.roots[imageHash] = roots
+	if g.store == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(roots)
+	if err != nil {
+		return err
+	}
+
+	return g.store.Put(rootsPrefix.ChildString(imageHash), data)
+}
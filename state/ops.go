@@ -0,0 +1,158 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/c3systems/c3-go/common/hexutil"
+)
+
+const (
+	// OpSet sets the key to the value
+	OpSet = "set"
+	// OpDelete removes the key
+	OpDelete = "del"
+)
+
+// ErrInvalidOps ...
+var ErrInvalidOps = errors.New("invalid state ops")
+
+// Op is a single state transition on a key
+type Op struct {
+	Kind  string
+	Key   string
+	Value string
+}
+
+// Ops are the state transitions between two states, sorted by key
+type Ops []Op
+
+type encodedOp struct {
+	Kind  string `json:"op"`
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+}
+
+// Diff returns the ops that transition prev into next
+func Diff(prev, next State) Ops {
+	var ops Ops
+	for k, v := range next {
+		if pv, ok := prev[k]; !ok || pv != v {
+			ops = append(ops, Op{Kind: OpSet, Key: k, Value: v})
+		}
+	}
+	for k := range prev {
+		if _, ok := next[k]; !ok {
+			ops = append(ops, Op{Kind: OpDelete, Key: k})
+		}
+	}
+
+	ops.sort()
+	return ops
+}
+
+// Combine returns the ops equivalent to applying first and then second
+func Combine(first, second Ops) Ops {
+	byKey := make(map[string]Op, len(first)+len(second))
+	for _, op := range first {
+		byKey[op.Key] = op
+	}
+	for _, op := range second {
+		byKey[op.Key] = op
+	}
+
+	ops := make(Ops, 0, len(byKey))
+	for _, op := range byKey {
+		ops = append(ops, op)
+	}
+
+	ops.sort()
+	return ops
+}
+
+// Apply applies the ops to the state, in place.
+// note: deleting a missing key is a no-op so that combined ops apply the same as the ops they were built from
+func (s State) Apply(ops Ops) error {
+	for _, op := range ops {
+		switch op.Kind {
+		case OpSet:
+			s[op.Key] = op.Value
+		case OpDelete:
+			delete(s, op.Key)
+		default:
+			return ErrInvalidOps
+		}
+	}
+
+	return nil
+}
+
+// Encode returns the deterministic encoding of the ops
+func (o Ops) Encode() ([]byte, error) {
+	sorted := make(Ops, len(o))
+	copy(sorted, o)
+	sorted.sort()
+
+	encoded := make([]encodedOp, 0, len(sorted))
+	for _, op := range sorted {
+		e := encodedOp{
+			Kind: op.Kind,
+			Key:  hexutil.EncodeToString([]byte(op.Key)),
+		}
+		if op.Kind == OpSet {
+			e.Value = hexutil.EncodeToString([]byte(op.Value))
+		}
+
+		encoded = append(encoded, e)
+	}
+
+	return json.Marshal(encoded)
+}
+
+// DecodeOps ...
+func DecodeOps(data []byte) (Ops, error) {
+	var encoded []encodedOp
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, ErrInvalidOps
+	}
+
+	ops := make(Ops, 0, len(encoded))
+	for _, e := range encoded {
+		if e.Kind != OpSet && e.Kind != OpDelete {
+			return nil, ErrInvalidOps
+		}
+
+		key, err := hexutil.DecodeString(e.Key)
+		if err != nil {
+			return nil, ErrInvalidOps
+		}
+
+		op := Op{
+			Kind: e.Kind,
+			Key:  string(key),
+		}
+		if e.Kind == OpSet {
+			value, err := hexutil.DecodeString(e.Value)
+			if err != nil {
+				return nil, ErrInvalidOps
+			}
+
+			op.Value = string(value)
+		}
+
+		ops = append(ops, op)
+	}
+
+	return ops, nil
+}
+
+// IsOps reports whether diff data holds encoded ops, rather than a legacy unified patch
+func IsOps(data string) bool {
+	return strings.HasPrefix(strings.TrimSpace(data), "[")
+}
+
+func (o Ops) sort() {
+	sort.Slice(o, func(i, j int) bool { return o[i].Key < o[j].Key })
+}
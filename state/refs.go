@@ -0,0 +1,330 @@
+package state
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/c3systems/c3-go/common/hexutil"
+	"github.com/c3systems/c3-go/trie"
+
+	ds "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+	log "github.com/sirupsen/logrus"
+)
+
+// refsPrefix is the datastore namespace of the trie node reference counts
+var refsPrefix = ds.NewKey("/trie/refs")
+
+// pinExpiry is the number of prunes a pin outlives, so the nodes of tries that are never released,
+// e.g. the states of a canceled miner or the intermediate nodes of a trie, are eventually pruned
+const pinExpiry = 4
+
+// nodeStore is where a reference counted database keeps its trie nodes
+type nodeStore interface {
+	has(key []byte) (bool, error)
+	get(key []byte) ([]byte, error)
+	put(key, value []byte) error
+	delete(key []byte) error
+}
+
+// refCounter counts the references to the trie nodes of a node store.
+// A node is referenced by every stored node that has it as a child and by every Reference of it as a root.
+// note: every stored node has a count, so nodes that were never referenced can be found and pruned.
+// Written nodes are pinned until their root is released, so a trie that's still being built, or
+// verified before its root is referenced, isn't pruned. The pins are kept in memory; they don't survive restarts.
+type refCounter struct {
+	mut    sync.Mutex
+	nodes  nodeStore
+	refs   ds.Datastore
+	pins   map[string]pin
+	prunes int
+}
+
+// pin counts the writes of a node that haven't been released, and when it was last written
+type pin struct {
+	count int
+	prune int
+}
+
+func newRefCounter(nodes nodeStore, refs ds.Datastore) *refCounter {
+	if refs == nil {
+		refs = ds.NewMapDatastore()
+	}
+
+	return &refCounter{
+		nodes: nodes,
+		refs:  refs,
+		pins:  make(map[string]pin),
+	}
+}
+
+// Put stores the node, and counts the references to its children if it's new. The node is pinned until it's released.
+func (r *refCounter) Put(key []byte, value []byte) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	// note: a stored node is pinned too, the trie being written may be the only one that will reference it
+	p := r.pins[string(key)]
+	p.count++
+	p.prune = r.prunes
+	r.pins[string(key)] = p
+
+	if err := r.put(key, value); err != nil {
+		log.Errorf("[state] error storing trie node; %s", err)
+	}
+}
+
+func (r *refCounter) put(key []byte, value []byte) error {
+	ok, err := r.nodes.has(key)
+	if err != nil {
+		return err
+	}
+	// note: nodes are content addressed, so a stored node already counted its children
+	if ok {
+		return nil
+	}
+
+	if err := r.nodes.put(key, value); err != nil {
+		return err
+	}
+
+	if _, err := r.add(key, 0); err != nil {
+		return err
+	}
+	for _, child := range trie.NodeChildren(value) {
+		if _, err := r.add(child, 1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get ...
+func (r *refCounter) Get(key []byte) ([]byte, error) {
+	return r.nodes.get(key)
+}
+
+// Delete deletes the node regardless of its references, releasing its children
+func (r *refCounter) Delete(key []byte) error {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	_, err := r.deleteNode(key)
+	return err
+}
+
+// Reference ...
+func (r *refCounter) Reference(root []byte) error {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	_, err := r.add(root, 1)
+	return err
+}
+
+// Dereference ...
+func (r *refCounter) Dereference(root []byte) error {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	_, err := r.release(root)
+	return err
+}
+
+// Release unpins the nodes written for the root, down to the nodes that aren't pinned
+func (r *refCounter) Release(root []byte) error {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	queue := [][]byte{root}
+	released := make(map[string]bool)
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		if released[string(key)] || !r.unpin(key) {
+			continue
+		}
+		released[string(key)] = true
+
+		value, err := r.nodes.get(key)
+		if err != nil {
+			// note: the node was pinned by a write that failed
+			continue
+		}
+		queue = append(queue, trie.NodeChildren(value)...)
+	}
+
+	return nil
+}
+
+// RefCount returns the number of references to the node
+func (r *refCounter) RefCount(key []byte) (int, error) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	return r.count(key)
+}
+
+// Prune deletes the unpinned nodes that aren't referenced, and expires the pins of nodes that were never released
+func (r *refCounter) Prune() (int, error) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	for key, p := range r.pins {
+		if r.prunes-p.prune >= pinExpiry {
+			delete(r.pins, key)
+		}
+	}
+	r.prunes++
+
+	unreferenced, err := r.unreferenced()
+	if err != nil {
+		return 0, err
+	}
+
+	var pruned int
+	for _, key := range unreferenced {
+		// note: the node may have been deleted while releasing another unreferenced node
+		if ok, err := r.refs.Has(refKey(key)); err != nil || !ok {
+			continue
+		}
+		if _, ok := r.pins[string(key)]; ok {
+			continue
+		}
+
+		n, err := r.deleteNode(key)
+		pruned += n
+		if err != nil {
+			return pruned, err
+		}
+	}
+
+	return pruned, nil
+}
+
+func (r *refCounter) unreferenced() ([][]byte, error) {
+	results, err := r.refs.Query(dsq.Query{
+		Prefix: refsPrefix.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := results.Rest()
+	if err != nil {
+		return nil, err
+	}
+
+	var keys [][]byte
+	for _, entry := range entries {
+		if count, err := strconv.Atoi(string(entry.Value)); err != nil || count > 0 {
+			continue
+		}
+
+		key, err := hexutil.DecodeString(ds.RawKey(entry.Key).BaseNamespace())
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// release drops a reference to the node, deleting it when it's no longer referenced.
+// note: a pinned node is kept, and pruned once it's released if it's still unreferenced.
+func (r *refCounter) release(key []byte) (int, error) {
+	count, err := r.add(key, -1)
+	if err != nil || count > 0 {
+		return 0, err
+	}
+	if _, ok := r.pins[string(key)]; ok {
+		return 0, nil
+	}
+
+	return r.deleteNode(key)
+}
+
+// unpin drops a write of the node and returns false if it wasn't pinned
+func (r *refCounter) unpin(key []byte) bool {
+	p, ok := r.pins[string(key)]
+	if !ok {
+		return false
+	}
+
+	p.count--
+	if p.count > 0 {
+		r.pins[string(key)] = p
+	} else {
+		delete(r.pins, string(key))
+	}
+
+	return true
+}
+
+// deleteNode deletes the node and its count, releases its children and returns the number of nodes deleted
+func (r *refCounter) deleteNode(key []byte) (int, error) {
+	value, err := r.nodes.get(key)
+	if err != nil {
+		// note: the node was referenced by a parent but never stored
+		return 0, r.deleteCount(key)
+	}
+
+	if err := r.nodes.delete(key); err != nil {
+		return 0, err
+	}
+	if err := r.deleteCount(key); err != nil {
+		return 0, err
+	}
+
+	deleted := 1
+	for _, child := range trie.NodeChildren(value) {
+		n, err := r.release(child)
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+	}
+
+	return deleted, nil
+}
+
+func (r *refCounter) count(key []byte) (int, error) {
+	data, err := r.refs.Get(refKey(key))
+	if err == ds.ErrNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(string(data))
+}
+
+func (r *refCounter) add(key []byte, delta int) (int, error) {
+	count, err := r.count(key)
+	if err != nil {
+		return 0, err
+	}
+
+	count += delta
+	if count < 0 {
+		count = 0
+	}
+
+	return count, r.refs.Put(refKey(key), []byte(strconv.Itoa(count)))
+}
+
+func (r *refCounter) deleteCount(key []byte) error {
+	if err := r.refs.Delete(refKey(key)); err != nil && err != ds.ErrNotFound {
+		return err
+	}
+
+	return nil
+}
+
+func refKey(key []byte) ds.Key {
+	return refsPrefix.ChildString(hexutil.EncodeToString(key))
+}
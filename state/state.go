@@ -1,8 +1,110 @@
 package state
 
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/c3systems/c3-go/common/hexutil"
+	"github.com/c3systems/c3-go/trie"
+)
+
 // StateObject ...
 type StateObject interface {
-	State() *Trie
+	State() *trie.Trie
 	Sync()
 	Undo()
 }
+
+// ErrInvalidState ...
+var ErrInvalidState = errors.New("state is not a valid key/value map")
+
+// State is the canonical key/value representation of a dApp's state.
+// note: keys and values hold the raw bytes; they're only hex encoded when serialized.
+type State map[string]string
+
+// New ...
+func New() State {
+	return make(State)
+}
+
+// Parse reads the JSON map of hex encoded keys and values written by dApps
+func Parse(data []byte) (State, error) {
+	s := New()
+	if len(bytes.TrimSpace(data)) == 0 {
+		return s, nil
+	}
+
+	var encoded map[string]string
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, ErrInvalidState
+	}
+
+	for k, v := range encoded {
+		key, err := hexutil.DecodeString(k)
+		if err != nil {
+			return nil, ErrInvalidState
+		}
+		value, err := hexutil.DecodeString(v)
+		if err != nil {
+			return nil, ErrInvalidState
+		}
+
+		s[string(key)] = string(value)
+	}
+
+	return s, nil
+}
+
+// Serialize returns the deterministic encoding of the state; the same state always serializes to the same bytes
+func (s State) Serialize() ([]byte, error) {
+	encoded := make(map[string]string, len(s))
+	for k, v := range s {
+		encoded[hexutil.EncodeToString([]byte(k))] = hexutil.EncodeToString([]byte(v))
+	}
+
+	// note: encoding/json sorts map keys
+	return json.Marshal(encoded)
+}
+
+// Get ...
+func (s State) Get(key []byte) ([]byte, bool) {
+	v, ok := s[string(key)]
+	if !ok {
+		return nil, false
+	}
+
+	return []byte(v), true
+}
+
+// Set ...
+func (s State) Set(key, value []byte) {
+	s[string(key)] = string(value)
+}
+
+// Delete ...
+func (s State) Delete(key []byte) {
+	delete(s, string(key))
+}
+
+// Keys returns the sorted keys of the state
+func (s State) Keys() []string {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Copy ...
+func (s State) Copy() State {
+	c := make(State, len(s))
+	for k, v := range s {
+		c[k] = v
+	}
+
+	return c
+}
@@ -0,0 +1,428 @@
+// +build unit
+
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/c3systems/c3-go/common/hexutil"
+	"github.com/c3systems/c3-go/trie"
+
+	ds "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	bstore "github.com/ipfs/go-ipfs-blockstore"
+)
+
+func TestParseSerialize(t *testing.T) {
+	t.Parallel()
+
+	inputs := []struct {
+		data     string
+		expected string
+	}{
+		{"", "{}"},
+		{"{}", "{}"},
+		{
+			`{"` + hexutil.EncodeString("foo") + `": "` + hexutil.EncodeString("bar") + `", "` + hexutil.EncodeString("bar") + `": "` + hexutil.EncodeString("baz") + `"}`,
+			`{"` + hexutil.EncodeString("bar") + `":"` + hexutil.EncodeString("baz") + `","` + hexutil.EncodeString("foo") + `":"` + hexutil.EncodeString("bar") + `"}`,
+		},
+		// note: upper case hex is canonicalized
+		{`{"0x666F6F":"0x626172"}`, `{"0x666f6f":"0x626172"}`},
+	}
+
+	for idx, input := range inputs {
+		s, err := Parse([]byte(input.data))
+		if err != nil {
+			t.Fatalf("test %d failed\n%v", idx+1, err)
+		}
+
+		out, err := s.Serialize()
+		if err != nil {
+			t.Fatalf("test %d failed\n%v", idx+1, err)
+		}
+
+		if string(out) != input.expected {
+			t.Errorf("test %d failed\nexpected: %s\nreceived: %s", idx+1, input.expected, string(out))
+		}
+	}
+
+	if _, err := Parse([]byte(`{"foo":"bar"}`)); err != ErrInvalidState {
+		t.Errorf("expected %v; received %v", ErrInvalidState, err)
+	}
+	if _, err := Parse([]byte(`["foo"]`)); err != ErrInvalidState {
+		t.Errorf("expected %v; received %v", ErrInvalidState, err)
+	}
+}
+
+func TestDiffApply(t *testing.T) {
+	t.Parallel()
+
+	prev := State{"foo": "bar", "hello": "world", "gone": "soon"}
+	next := State{"foo": "baz", "hello": "world", "new": "key"}
+
+	ops := Diff(prev, next)
+	expected := Ops{
+		{Kind: OpDelete, Key: "gone"},
+		{Kind: OpSet, Key: "foo", Value: "baz"},
+		{Kind: OpSet, Key: "new", Value: "key"},
+	}
+	expected.sort()
+	if !reflect.DeepEqual(expected, ops) {
+		t.Fatalf("expected %v\nreceived %v", expected, ops)
+	}
+
+	applied := prev.Copy()
+	if err := applied.Apply(ops); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(next, applied) {
+		t.Errorf("expected %v\nreceived %v", next, applied)
+	}
+
+	if ops := Diff(next, next); len(ops) != 0 {
+		t.Errorf("expected no ops; received %v", ops)
+	}
+}
+
+func TestCombine(t *testing.T) {
+	t.Parallel()
+
+	s0 := State{"a": "1", "b": "2"}
+	s1 := State{"a": "1", "c": "3"}
+	s2 := State{"c": "4", "b": "5"}
+
+	combined := Combine(Diff(s0, s1), Diff(s1, s2))
+
+	applied := s0.Copy()
+	if err := applied.Apply(combined); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s2, applied) {
+		t.Errorf("expected %v\nreceived %v", s2, applied)
+	}
+}
+
+func TestEncodeDecodeOps(t *testing.T) {
+	t.Parallel()
+
+	ops := Ops{
+		{Kind: OpSet, Key: "foo", Value: "bar"},
+		{Kind: OpDelete, Key: "baz"},
+	}
+
+	data, err := ops.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsOps(string(data)) {
+		t.Errorf("expected encoded ops; received %s", string(data))
+	}
+
+	decoded, err := DecodeOps(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ops.sort()
+	if !reflect.DeepEqual(ops, decoded) {
+		t.Errorf("expected %v\nreceived %v", ops, decoded)
+	}
+
+	// note: the encoding doesn't depend on the order of the ops
+	reversed := Ops{ops[1], ops[0]}
+	data2, err := reversed.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(data2) {
+		t.Errorf("expected %s\nreceived %s", string(data), string(data2))
+	}
+
+	if IsOps("--- state.txt\n+++ state.txt") {
+		t.Error("expected unified patch to not be ops")
+	}
+	if _, err := DecodeOps([]byte(`[{"op":"nope","key":"0x00"}]`)); err != ErrInvalidOps {
+		t.Errorf("expected %v; received %v", ErrInvalidOps, err)
+	}
+}
+
+func TestRoot(t *testing.T) {
+	t.Parallel()
+
+	db, err := trie.NewMemDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if root := New().Root(db); root != EmptyRootHash {
+		t.Errorf("expected %s\nreceived %s", EmptyRootHash, root)
+	}
+
+	s1 := State{"foo": "bar", "hello": "world"}
+	s2 := State{"hello": "world", "foo": "bar"}
+	if s1.Root(db) != s2.Root(db) {
+		t.Error("expected the same state to have the same root")
+	}
+
+	s3 := State{"foo": "baz", "hello": "world"}
+	if s1.Root(db) == s3.Root(db) {
+		t.Error("expected different states to have different roots")
+	}
+}
+
+func TestApplyToTrie(t *testing.T) {
+	t.Parallel()
+
+	db, err := trie.NewMemDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prev := State{"foo": "bar", "hello": "world", "gone": "soon", "a longer key than the others": "value"}
+	next := State{"foo": "baz", "hello": "world", "new": "key", "a longer key than the others": "other value"}
+
+	tr, err := OpenTrie(db, prev.Root(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyToTrie(tr, Diff(prev, next)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := next.Root(db)
+	if root := RootHash(tr); root != expected {
+		t.Errorf("expected %s\nreceived %s", expected, root)
+	}
+	for _, key := range next.Keys() {
+		if v := tr.Get(key); v != next[key] {
+			t.Errorf("expected %s for %s; received %s", next[key], key, v)
+		}
+	}
+
+	// note: deleting every key returns to the empty root
+	if err := ApplyToTrie(tr, Diff(next, New())); err != nil {
+		t.Fatal(err)
+	}
+	if root := RootHash(tr); root != EmptyRootHash {
+		t.Errorf("expected %s\nreceived %s", EmptyRootHash, root)
+	}
+}
+
+func TestApplyToTrieRandom(t *testing.T) {
+	t.Parallel()
+
+	db, err := trie.NewMemDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	prev := New()
+	tr, err := OpenTrie(db, EmptyRootHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 200; i++ {
+		next := prev.Copy()
+		for j := r.Intn(8); j > 0; j-- {
+			key := fmt.Sprintf("key%d", r.Intn(40))
+			if r.Intn(3) == 0 {
+				next.Delete([]byte(key))
+			} else {
+				next.Set([]byte(key), []byte(fmt.Sprintf("value%d", r.Intn(1000))))
+			}
+		}
+
+		if err := ApplyToTrie(tr, Diff(prev, next)); err != nil {
+			t.Fatal(err)
+		}
+		tr.Sync()
+
+		if expected, root := next.Root(db), RootHash(tr); expected != root {
+			t.Fatalf("test %d failed\nexpected %s\nreceived %s", i+1, expected, root)
+		}
+
+		// note: reopen the trie so the next ops read its nodes back from the db
+		if tr, err = OpenTrie(db, RootHash(tr)); err != nil {
+			t.Fatal(err)
+		}
+		prev = next
+	}
+}
+
+func TestBlockstoreDatabase(t *testing.T) {
+	t.Parallel()
+
+	db := NewBlockstoreDatabase(bstore.NewBlockstore(dssync.MutexWrap(ds.NewMapDatastore())), nil)
+
+	s := State{"foo": "bar", "hello": "world", "a longer key than the others": "with a longer value than the others"}
+	tr, err := OpenTrie(db, s.Root(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range s.Keys() {
+		if v := tr.Get(key); v != s[key] {
+			t.Errorf("expected %s for %s; received %s", s[key], key, v)
+		}
+	}
+}
+
+func TestDatastoreDatabase(t *testing.T) {
+	t.Parallel()
+
+	db := NewDatastoreDatabase(dssync.MutexWrap(ds.NewMapDatastore()))
+
+	s := State{"foo": "bar", "hello": "world", "a longer key than the others": "with a longer value than the others"}
+	tr, err := OpenTrie(db, s.Root(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range s.Keys() {
+		if v := tr.Get(key); v != s[key] {
+			t.Errorf("expected %s for %s; received %s", s[key], key, v)
+		}
+	}
+}
+
+func TestProve(t *testing.T) {
+	t.Parallel()
+
+	db, err := trie.NewMemDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := State{"foo": "bar", "hello": "world", "a longer key than the others": "with a longer value than the others"}
+	root := s.Root(db)
+	if !HasRoot(db, root) {
+		t.Fatalf("expected the db to have root %s", root)
+	}
+
+	for _, key := range append(s.Keys(), "missing") {
+		value, proof, err := Prove(db, root, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		verified, err := VerifyProof(root, key, proof)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value != s[key] || verified != s[key] {
+			t.Errorf("expected %s for %s; received %s and %s", s[key], key, value, verified)
+		}
+	}
+
+	other, err := trie.NewMemDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if HasRoot(other, root) {
+		t.Errorf("expected the db to not have root %s", root)
+	}
+}
+
+func TestRange(t *testing.T) {
+	t.Parallel()
+
+	db, err := trie.NewMemDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := State{"user:1": "alice", "user:2": "bob", "user:10": "carol", "post:1": "hello"}
+	root := s.Root(db)
+
+	inputs := []struct {
+		prefix   string
+		expected State
+	}{
+		{"", s},
+		{"user:", State{"user:1": "alice", "user:2": "bob", "user:10": "carol"}},
+		{"user:1", State{"user:1": "alice", "user:10": "carol"}},
+		{"post:", State{"post:1": "hello"}},
+		{"nope", New()},
+	}
+
+	for idx, input := range inputs {
+		actual, err := Range(db, root, input.prefix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(input.expected, actual) {
+			t.Errorf("test %d failed\nexpected %v\nreceived %v", idx+1, input.expected, actual)
+		}
+	}
+
+	empty, err := Range(db, EmptyRootHash, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(empty) != 0 {
+		t.Errorf("expected an empty range; received %v", empty)
+	}
+}
+
+func TestDiffRoots(t *testing.T) {
+	t.Parallel()
+
+	db, err := trie.NewMemDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := rand.New(rand.NewSource(2))
+	prev := New()
+	for i := 0; i < 50; i++ {
+		next := prev.Copy()
+		for j := r.Intn(8); j > 0; j-- {
+			key := fmt.Sprintf("key%d", r.Intn(40))
+			if r.Intn(3) == 0 {
+				next.Delete([]byte(key))
+			} else {
+				next.Set([]byte(key), []byte(fmt.Sprintf("value%d", r.Intn(1000))))
+			}
+		}
+
+		ops, err := DiffRoots(db, prev.Root(db), next.Root(db))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := Diff(prev, next); !reflect.DeepEqual(expected, ops) {
+			t.Fatalf("test %d failed\nexpected %v\nreceived %v", i+1, expected, ops)
+		}
+
+		prev = next
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	t.Parallel()
+
+	db, err := trie.NewMemDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := State{"foo": "bar", "hello": "world", "a longer key than the others": "with a longer value than the others"}
+	root := s.Root(db)
+
+	var buf bytes.Buffer
+	if _, err := Export(db, root, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	other := NewDatastoreDatabase(dssync.MutexWrap(ds.NewMapDatastore()))
+	if _, err := Import(other, root, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	checkState(t, other, root, s)
+}
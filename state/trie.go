@@ -0,0 +1,205 @@
+package state
+
+import (
+	"errors"
+	"io"
+
+	"github.com/c3systems/c3-go/common/hashutil"
+	"github.com/c3systems/c3-go/common/hexutil"
+	"github.com/c3systems/c3-go/trie"
+)
+
+// EmptyRootHash is the root hash of a state without any keys
+var EmptyRootHash = hashutil.HashToHexString(trie.NewValue("").Encode())
+
+// ErrInvalidRoot ...
+var ErrInvalidRoot = errors.New("invalid state root hash")
+
+// Trie builds the Merkle-Patricia trie of the state.
+// note: the trie isn't synced; call Sync to write its nodes to the db.
+func (s State) Trie(db trie.Database) *trie.Trie {
+	t := trie.NewTrie(db, "")
+	for _, key := range s.Keys() {
+		t.Update(key, s[key])
+	}
+
+	return t
+}
+
+// Root builds the trie of the state, writes its nodes to the db and returns the root hash
+func (s State) Root(db trie.Database) string {
+	t := s.Trie(db)
+	t.Sync()
+
+	return RootHash(t)
+}
+
+// RootHash returns the hex encoded root of the trie
+func RootHash(t *trie.Trie) string {
+	root := trie.NewValue(t.Root).Bytes()
+	if len(root) == 0 {
+		return EmptyRootHash
+	}
+
+	return hexutil.EncodeToString(root)
+}
+
+// OpenTrie opens the trie with the root hash, reading its nodes from the db
+func OpenTrie(db trie.Database, rootHash string) (*trie.Trie, error) {
+	if rootHash == EmptyRootHash {
+		return trie.NewTrie(db, ""), nil
+	}
+
+	root, err := rootKey(rootHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return trie.NewTrie(db, root), nil
+}
+
+// rootKey returns the db key of the root node
+func rootKey(rootHash string) ([]byte, error) {
+	root, err := hexutil.DecodeString(rootHash)
+	if err != nil || len(root) == 0 {
+		return nil, ErrInvalidRoot
+	}
+
+	return root, nil
+}
+
+// ApplyToTrie applies the ops to the trie
+func ApplyToTrie(t *trie.Trie, ops Ops) error {
+	for _, op := range ops {
+		switch op.Kind {
+		case OpSet:
+			t.Update(op.Key, op.Value)
+		case OpDelete:
+			t.Delete(op.Key)
+		default:
+			return ErrInvalidOps
+		}
+	}
+
+	return nil
+}
+
+// RootFromBytes parses the serialized state and returns its root hash, writing the trie nodes to the db
+func RootFromBytes(db trie.Database, data []byte) (string, error) {
+	s, err := Parse(data)
+	if err != nil {
+		return "", err
+	}
+
+	return s.Root(db), nil
+}
+
+// HasRoot reports whether the root node of the trie is in the db
+func HasRoot(db trie.Database, rootHash string) bool {
+	if rootHash == EmptyRootHash {
+		return true
+	}
+
+	root, err := rootKey(rootHash)
+	if err != nil {
+		return false
+	}
+
+	data, err := db.Get(root)
+	return err == nil && len(data) > 0
+}
+
+// Prove returns the value of the key in the trie with the root hash, and the trie nodes proving it
+func Prove(db trie.Database, rootHash, key string) (string, [][]byte, error) {
+	t, err := OpenTrie(db, rootHash)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return t.Get(key), t.Prove(key), nil
+}
+
+// VerifyProof returns the value of the key proven by the trie nodes against the root hash
+func VerifyProof(rootHash, key string, proof [][]byte) (string, error) {
+	if rootHash == EmptyRootHash {
+		return "", nil
+	}
+
+	root, err := rootKey(rootHash)
+	if err != nil {
+		return "", err
+	}
+
+	return trie.VerifyProof(root, key, proof)
+}
+
+// Range returns the keys starting with the prefix, and their values, in the trie with the root hash
+func Range(db trie.Database, rootHash, prefix string) (State, error) {
+	t, err := OpenTrie(db, rootHash)
+	if err != nil {
+		return nil, err
+	}
+
+	s := New()
+	if rootHash == EmptyRootHash {
+		return s, nil
+	}
+
+	t.NewIterator().EachWithPrefix(prefix, func(key string, node *trie.Value) {
+		s[key] = node.Str()
+	})
+
+	return s, nil
+}
+
+// DiffRoots returns the ops turning the state with the from root into the state with the to root
+func DiffRoots(db trie.Database, fromRoot, toRoot string) (Ops, error) {
+	from, err := OpenTrie(db, fromRoot)
+	if err != nil {
+		return nil, err
+	}
+	to, err := OpenTrie(db, toRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var ops Ops
+	for _, change := range trie.Changes(from, to) {
+		if change.To == "" {
+			ops = append(ops, Op{Kind: OpDelete, Key: change.Key})
+		} else {
+			ops = append(ops, Op{Kind: OpSet, Key: change.Key, Value: change.To})
+		}
+	}
+
+	ops.sort()
+	return ops, nil
+}
+
+// Export writes the trie with the root hash to w as a flat stream of nodes
+func Export(db trie.Database, rootHash string, w io.Writer) (int, error) {
+	if rootHash == EmptyRootHash {
+		return 0, nil
+	}
+
+	root, err := rootKey(rootHash)
+	if err != nil {
+		return 0, err
+	}
+
+	return trie.Export(db, root, w)
+}
+
+// Import stores the trie with the root hash read from a stream written by Export
+func Import(db trie.Database, rootHash string, r io.Reader) (int, error) {
+	if rootHash == EmptyRootHash {
+		return 0, nil
+	}
+
+	root, err := rootKey(rootHash)
+	if err != nil {
+		return 0, err
+	}
+
+	return trie.Import(db, root, r)
+}
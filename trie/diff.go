@@ -0,0 +1,142 @@
+package trie
+
+import (
+	"bytes"
+	"sort"
+)
+
+// Change is a key whose value differs between two tries; an empty value means the key isn't in that trie
+type Change struct {
+	Key  string
+	From string
+	To   string
+}
+
+// Changes returns the keys whose values differ between the tries, sorted by key.
+// note: the tries are walked side by side and subtrees with the same hash are skipped,
+// so the cost depends on the size of the difference rather than the size of the tries.
+func Changes(from, to *Trie) []Change {
+	from.mut.RLock()
+	defer from.mut.RUnlock()
+	to.mut.RLock()
+	defer to.mut.RUnlock()
+
+	var changes []Change
+	diffNodes(from, to, from.Root, to.Root, nil, &changes)
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes
+}
+
+// shortNode is a 2 item node split at its first nibble, so both tries branch on the same nibbles
+type shortNode struct {
+	key   []int
+	value interface{}
+}
+
+func diffNodes(from, to *Trie, a, b interface{}, path []int, changes *[]Change) {
+	aHash, aIsHash := hashRef(a)
+	bHash, bIsHash := hashRef(b)
+	if aIsHash && bIsHash && bytes.Equal(aHash, bHash) {
+		return
+	}
+	if isEmptyRef(a) && isEmptyRef(b) {
+		return
+	}
+
+	aValue, aChildren := from.branches(a)
+	bValue, bChildren := to.branches(b)
+	if aValue != bValue {
+		*changes = append(*changes, Change{
+			Key:  DecodeCompact(path),
+			From: aValue,
+			To:   bValue,
+		})
+	}
+
+	for i := 0; i < maxSize-1; i++ {
+		if aChildren[i] == nil && bChildren[i] == nil {
+			continue
+		}
+
+		childPath := append(append([]int{}, path...), i)
+		diffNodes(from, to, aChildren[i], bChildren[i], childPath, changes)
+	}
+}
+
+// branches returns the value of the key ending at the node and the node's children by nibble
+func (t *Trie) branches(ref interface{}) (string, [16]interface{}) {
+	var children [16]interface{}
+	if isEmptyRef(ref) {
+		return "", children
+	}
+
+	var key []int
+	var value interface{}
+	if short, ok := ref.(shortNode); ok {
+		key, value = short.key, short.value
+	} else {
+		node := t.resolve(ref)
+		switch node.Size() {
+		case 2:
+			key, value = CompactDecode(node.Get(0).Str()), node.Get(1).Raw()
+		case maxSize:
+			for i := 0; i < maxSize-1; i++ {
+				if child := node.Get(i).Raw(); !isEmptyRef(child) {
+					children[i] = child
+				}
+			}
+
+			return node.Get(maxSize - 1).Str(), children
+		default:
+			return "", children
+		}
+	}
+
+	if len(key) == 0 {
+		return "", children
+	}
+	if key[0] == 16 {
+		return NewValue(value).Str(), children
+	}
+
+	if len(key) == 1 {
+		children[key[0]] = value
+	} else {
+		children[key[0]] = shortNode{key: key[1:], value: value}
+	}
+
+	return "", children
+}
+
+// resolve returns the node the reference points to
+func (t *Trie) resolve(ref interface{}) *Value {
+	if hash, ok := hashRef(ref); ok {
+		return t.cache.Get(hash)
+	}
+
+	return t.getNode(ref)
+}
+
+// hashRef returns the hash of the node when the reference is a hash
+func hashRef(ref interface{}) ([]byte, bool) {
+	switch r := ref.(type) {
+	case []byte:
+		return r, len(r) >= hashLength
+	case string:
+		return []byte(r), len(r) >= hashLength
+	}
+
+	return nil, false
+}
+
+func isEmptyRef(ref interface{}) bool {
+	if _, ok := ref.(shortNode); ok {
+		return false
+	}
+
+	return isEmptyNode(ref) || NewValue(ref).Size() == 0
+}
@@ -0,0 +1,109 @@
+// +build unit
+
+package trie
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestChanges(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for i := 0; i < 100; i++ {
+		db, from, values := randomTrie(r, r.Intn(60))
+
+		// note: the second trie shares its nodes with the first, except on the changed paths
+		to := NewTrie(db, copyRoot(from.Root))
+		next := make(map[string]string)
+		for key, value := range values {
+			next[key] = value
+		}
+		for j := r.Intn(10); j > 0; j-- {
+			key := fmt.Sprintf("key%d", r.Intn(1000))
+			if r.Intn(3) == 0 {
+				to.Delete(key)
+				delete(next, key)
+			} else {
+				value := fmt.Sprintf("changed%d", r.Intn(1000))
+				to.Update(key, value)
+				next[key] = value
+			}
+		}
+
+		var expected []Change
+		for key, value := range values {
+			if next[key] != value {
+				expected = append(expected, Change{Key: key, From: value, To: next[key]})
+			}
+		}
+		for key, value := range next {
+			if _, ok := values[key]; !ok {
+				expected = append(expected, Change{Key: key, To: value})
+			}
+		}
+		sort.Slice(expected, func(i, j int) bool {
+			return expected[i].Key < expected[j].Key
+		})
+
+		if actual := Changes(from, to); !reflect.DeepEqual(expected, actual) {
+			t.Fatalf("test %d failed\nexpected %v\nreceived %v", i+1, expected, actual)
+		}
+	}
+
+	_, empty := New()
+	_, tr, values := randomTrie(r, 10)
+	if changes := Changes(empty, tr); len(changes) != len(values) {
+		t.Errorf("expected %d changes; received %d", len(values), len(changes))
+	}
+	if changes := Changes(tr, tr); len(changes) != 0 {
+		t.Errorf("expected no changes; received %v", changes)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+	for i := 0; i < 20; i++ {
+		db, tr, values := randomTrie(r, 1+r.Intn(60))
+		root := NewValue(tr.Root).Bytes()
+
+		var buf bytes.Buffer
+		written, err := Export(db, root, &buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		other, _ := NewMemDatabase()
+		read, err := Import(other, root, bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if read != written {
+			t.Errorf("test %d failed\nexpected %d nodes; received %d", i+1, written, read)
+		}
+
+		imported := NewTrie(other, root)
+		for key, value := range values {
+			if v := imported.Get(key); v != value {
+				t.Fatalf("test %d failed\nexpected %s for %s; received %s", i+1, value, key, v)
+			}
+		}
+
+		// note: a stream without the root's nodes isn't imported
+		incomplete, _ := NewMemDatabase()
+		if _, err := Import(incomplete, root, bytes.NewReader(nil)); err != ErrIncompleteImport {
+			t.Errorf("expected %v; received %v", ErrIncompleteImport, err)
+		}
+		if len(incomplete.db) != 0 {
+			t.Errorf("expected no nodes to be stored; received %d", len(incomplete.db))
+		}
+	}
+
+	empty, _ := NewMemDatabase()
+	if _, err := Export(empty, []byte("a root that isn't in the database"), &bytes.Buffer{}); err != ErrMissingNode {
+		t.Errorf("expected %v; received %v", ErrMissingNode, err)
+	}
+}
@@ -0,0 +1,130 @@
+package trie
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	hashutil "github.com/c3systems/c3-go/common/hashutil"
+)
+
+var (
+	// ErrMissingNode is returned when a node reachable from the root isn't in the db
+	ErrMissingNode = errors.New("trie node not found")
+
+	// ErrIncompleteImport is returned when an imported stream doesn't have every node reachable from the root
+	ErrIncompleteImport = errors.New("imported trie is missing nodes")
+
+	// ErrNodeTooLarge is returned when a node in an imported stream is larger than maxNodeSize
+	ErrNodeTooLarge = errors.New("trie node is too large")
+)
+
+// maxNodeSize bounds the size of a node read from a stream
+const maxNodeSize = 1 << 24
+
+// Export writes every node reachable from the root to w and returns the number of nodes written.
+// The stream is flat: each encoded node is prefixed with its length as a uvarint, starting with the root.
+// note: only synced nodes are in the db.
+func Export(db Database, root []byte, w io.Writer) (int, error) {
+	if len(root) == 0 {
+		return 0, nil
+	}
+
+	var (
+		buf     [binary.MaxVarintLen64]byte
+		written int
+		seen    = make(map[string]bool)
+		queue   = [][]byte{root}
+	)
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		if seen[string(key)] {
+			continue
+		}
+		seen[string(key)] = true
+
+		node, err := db.Get(key)
+		if err != nil {
+			return written, err
+		}
+		if len(node) == 0 {
+			return written, ErrMissingNode
+		}
+
+		n := binary.PutUvarint(buf[:], uint64(len(node)))
+		if _, err := w.Write(buf[:n]); err != nil {
+			return written, err
+		}
+		if _, err := w.Write(node); err != nil {
+			return written, err
+		}
+
+		written++
+		queue = append(queue, NodeChildren(node)...)
+	}
+
+	return written, nil
+}
+
+// Import reads a stream written by Export, stores its nodes in the db and returns the number of nodes read.
+// note: nodes are keyed by their own hash, so a stream can't store a node under another key; the stream is
+// checked to have every node reachable from the root.
+func Import(db Database, root []byte, r io.Reader) (int, error) {
+	var (
+		br    = bufio.NewReader(r)
+		read  int
+		nodes = make(map[string][]byte)
+	)
+	for {
+		size, err := binary.ReadUvarint(br)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return read, err
+		}
+		if size > maxNodeSize {
+			return read, ErrNodeTooLarge
+		}
+
+		node := make([]byte, size)
+		if _, err := io.ReadFull(br, node); err != nil {
+			return read, err
+		}
+
+		hash := hashutil.Hash(node)
+		nodes[string(hash[:])] = node
+		read++
+	}
+
+	if len(root) == 0 {
+		return read, nil
+	}
+
+	// note: only the nodes reachable from the root are stored
+	queue := [][]byte{root}
+	reachable := make(map[string][]byte)
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		if _, ok := reachable[string(key)]; ok {
+			continue
+		}
+
+		node, ok := nodes[string(key)]
+		if !ok {
+			return read, ErrIncompleteImport
+		}
+
+		reachable[string(key)] = node
+		queue = append(queue, NodeChildren(node)...)
+	}
+
+	for key, node := range reachable {
+		db.Put([]byte(key), node)
+	}
+
+	return read, nil
+}
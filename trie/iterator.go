@@ -91,42 +91,70 @@ type EachCallback func(key string, node *Value)
 
 // Each ...
 func (it *TrieIterator) Each(cb EachCallback) {
-	it.fetchNode(nil, NewValue(it.trie.Root).Bytes(), cb)
+	it.fetchNode(nil, NewValue(it.trie.Root).Bytes(), nil, cb)
 }
 
-func (it *TrieIterator) fetchNode(key []int, node []byte, cb EachCallback) {
-	it.iterateNode(key, it.trie.cache.Get(node), cb)
+// EachWithPrefix calls cb for every key starting with the prefix.
+// note: only the nodes on the prefix path are fetched.
+func (it *TrieIterator) EachWithPrefix(prefix string, cb EachCallback) {
+	nibbles := CompactHexDecode(prefix)
+
+	it.fetchNode(nil, NewValue(it.trie.Root).Bytes(), nibbles[:len(nibbles)-1], cb)
+}
+
+func (it *TrieIterator) fetchNode(key []int, node []byte, prefix []int, cb EachCallback) {
+	it.iterateNode(key, it.trie.cache.Get(node), prefix, cb)
 }
 
-func (it *TrieIterator) iterateNode(key []int, currentNode *Value, cb EachCallback) {
+func (it *TrieIterator) iterateNode(key []int, currentNode *Value, prefix []int, cb EachCallback) {
+	if !hasNibblePrefix(key, prefix) {
+		return
+	}
+
 	if currentNode.Size() == 2 {
 		k := CompactDecode(currentNode.Get(0).Str())
 
 		pk := append(key, k...)
 		if currentNode.Get(1).Size() != 0 && currentNode.Get(1).Str() == "" {
-			it.iterateNode(pk, currentNode.Get(1), cb)
+			it.iterateNode(pk, currentNode.Get(1), prefix, cb)
 		} else {
 			if k[len(k)-1] == 16 {
-				cb(DecodeCompact(pk), currentNode.Get(1))
+				if hasNibblePrefix(pk, prefix) {
+					cb(DecodeCompact(pk), currentNode.Get(1))
+				}
 			} else {
-				it.fetchNode(pk, currentNode.Get(1).Bytes(), cb)
+				it.fetchNode(pk, currentNode.Get(1).Bytes(), prefix, cb)
 			}
 		}
 	} else {
 		for i := 0; i < currentNode.Size(); i++ {
 			pk := append(key, i)
 			if i == 16 && currentNode.Get(i).Size() != 0 {
-				cb(DecodeCompact(pk), currentNode.Get(i))
+				if hasNibblePrefix(pk, prefix) {
+					cb(DecodeCompact(pk), currentNode.Get(i))
+				}
 			} else {
 				if currentNode.Get(i).Size() != 0 && currentNode.Get(i).Str() == "" {
-					it.iterateNode(pk, currentNode.Get(i), cb)
+					it.iterateNode(pk, currentNode.Get(i), prefix, cb)
 				} else {
 					val := currentNode.Get(i).Str()
 					if val != "" {
-						it.fetchNode(pk, []byte(val), cb)
+						it.fetchNode(pk, []byte(val), prefix, cb)
 					}
 				}
 			}
 		}
 	}
 }
+
+// hasNibblePrefix reports whether the key path matches the prefix for as long as both go.
+// note: a path terminated before the end of the prefix is a shorter key and doesn't match.
+func hasNibblePrefix(path, prefix []int) bool {
+	for i := 0; i < len(path) && i < len(prefix); i++ {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,49 @@
+package trie
+
+import "sync"
+
+// MemDatabase is an in memory Database
+type MemDatabase struct {
+	mut sync.RWMutex
+	db  map[string][]byte
+}
+
+// NewMemDatabase ...
+func NewMemDatabase() (*MemDatabase, error) {
+	db := &MemDatabase{db: make(map[string][]byte)}
+	return db, nil
+}
+
+// Put ...
+func (db *MemDatabase) Put(key []byte, value []byte) {
+	db.mut.Lock()
+	defer db.mut.Unlock()
+
+	db.db[string(key)] = value
+}
+
+// Get ...
+func (db *MemDatabase) Get(key []byte) ([]byte, error) {
+	db.mut.RLock()
+	defer db.mut.RUnlock()
+
+	return db.db[string(key)], nil
+}
+
+// Delete ...
+func (db *MemDatabase) Delete(key []byte) error {
+	db.mut.Lock()
+	defer db.mut.Unlock()
+
+	delete(db.db, string(key))
+	return nil
+}
+
+// Print ...
+func (db *MemDatabase) Print() {}
+
+// Close ...
+func (db *MemDatabase) Close() {}
+
+// LastKnownTD ...
+func (db *MemDatabase) LastKnownTD() []byte { return nil }
@@ -0,0 +1,110 @@
+package trie
+
+import (
+	"errors"
+	"sync"
+
+	hashutil "github.com/c3systems/c3-go/common/hashutil"
+)
+
+// ErrMissingProofNode is returned when a proof doesn't have every node on the path to the key
+var ErrMissingProofNode = errors.New("proof is missing a node")
+
+// Prove returns the encoded nodes on the path from the root to the key.
+// note: inline nodes are part of their parent's encoding, so only the nodes referenced by hash are returned.
+func (t *Trie) Prove(key string) [][]byte {
+	t.mut.RLock()
+	defer t.mut.RUnlock()
+
+	var proof [][]byte
+	node := t.Root
+	k := CompactHexDecode(key)
+	for {
+		n := NewValue(node)
+		if len(k) == 0 || n.IsNil() || n.Size() == 0 {
+			return proof
+		}
+
+		currentNode := t.getNode(node)
+		if n.Get(0).IsNil() && len(n.Str()) >= 32 {
+			proof = append(proof, currentNode.Encode())
+		}
+
+		switch currentNode.Size() {
+		case 2:
+			nk := CompactDecode(currentNode.Get(0).Str())
+			if len(k) < len(nk) || !CompareIntSlice(nk, k[:len(nk)]) {
+				return proof
+			}
+
+			node = currentNode.Get(1).Raw()
+			k = k[len(nk):]
+		case maxSize:
+			node = currentNode.Get(k[0]).Raw()
+			k = k[1:]
+		default:
+			return proof
+		}
+	}
+}
+
+// VerifyProof returns the value of the key in the trie with the root, reading only the proof nodes.
+// An empty value proves the key isn't in the trie.
+func VerifyProof(root []byte, key string, proof [][]byte) (string, error) {
+	if len(root) == 0 {
+		return "", nil
+	}
+
+	db := &proofDatabase{
+		nodes: make(map[string][]byte),
+	}
+	for _, node := range proof {
+		hash := hashutil.Hash(node)
+		db.nodes[string(hash[:])] = node
+	}
+
+	value := NewTrie(db, root).Get(key)
+	if db.missing {
+		return "", ErrMissingProofNode
+	}
+
+	return value, nil
+}
+
+// proofDatabase is a read only Database of proof nodes that records lookups of nodes not in the proof
+type proofDatabase struct {
+	mut     sync.Mutex
+	nodes   map[string][]byte
+	missing bool
+}
+
+// Put ...
+func (db *proofDatabase) Put(key []byte, value []byte) {}
+
+// Get ...
+func (db *proofDatabase) Get(key []byte) ([]byte, error) {
+	db.mut.Lock()
+	defer db.mut.Unlock()
+
+	node, ok := db.nodes[string(key)]
+	if !ok {
+		db.missing = true
+		return nil, ErrMissingProofNode
+	}
+
+	return node, nil
+}
+
+// Delete ...
+func (db *proofDatabase) Delete(key []byte) error {
+	return nil
+}
+
+// LastKnownTD ...
+func (db *proofDatabase) LastKnownTD() []byte { return nil }
+
+// Close ...
+func (db *proofDatabase) Close() {}
+
+// Print ...
+func (db *proofDatabase) Print() {}
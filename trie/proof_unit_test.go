@@ -0,0 +1,103 @@
+// +build unit
+
+package trie
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func randomTrie(r *rand.Rand, n int) (*MemDatabase, *Trie, map[string]string) {
+	db, trie := New()
+	values := make(map[string]string)
+	for i := 0; i < n; i++ {
+		key := fmt.Sprintf("key%d", r.Intn(1000))
+		value := fmt.Sprintf("value%d", r.Intn(1000))
+		if r.Intn(4) == 0 {
+			value += longWord
+		}
+
+		trie.Update(key, value)
+		values[key] = value
+	}
+	trie.Sync()
+
+	return db, trie, values
+}
+
+func TestProve(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		db, trie, values := randomTrie(r, 1+r.Intn(60))
+		root := NewValue(trie.Root).Bytes()
+
+		// note: reopen the trie so the proof nodes are read back from the db
+		trie = NewTrie(db, root)
+		for key, value := range values {
+			actual, err := VerifyProof(root, key, trie.Prove(key))
+			if err != nil {
+				t.Fatalf("test %d key %s failed\n%v", i+1, key, err)
+			}
+			if actual != value {
+				t.Fatalf("test %d key %s failed\nexpected %s\nreceived %s", i+1, key, value, actual)
+			}
+		}
+
+		actual, err := VerifyProof(root, "missing", trie.Prove("missing"))
+		if err != nil {
+			t.Fatalf("test %d failed\n%v", i+1, err)
+		}
+		if actual != "" {
+			t.Errorf("test %d failed\nexpected no value for a missing key; received %s", i+1, actual)
+		}
+	}
+}
+
+func TestVerifyProofMissingNode(t *testing.T) {
+	_, trie, values := randomTrie(rand.New(rand.NewSource(2)), 40)
+	root := NewValue(trie.Root).Bytes()
+
+	for key := range values {
+		proof := trie.Prove(key)
+		if len(proof) == 0 {
+			t.Fatalf("expected a proof for %s", key)
+		}
+		if _, err := VerifyProof(root, key, proof[:len(proof)-1]); err != ErrMissingProofNode {
+			t.Errorf("expected %v; received %v", ErrMissingProofNode, err)
+		}
+	}
+}
+
+func TestEachWithPrefix(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 50; i++ {
+		_, trie, values := randomTrie(r, r.Intn(60))
+
+		for _, prefix := range []string{"", "k", "key", "key1", "key12", "key123", "key1234", "nope"} {
+			var expected []string
+			for key := range values {
+				if strings.HasPrefix(key, prefix) {
+					expected = append(expected, key)
+				}
+			}
+
+			var actual []string
+			trie.NewIterator().EachWithPrefix(prefix, func(key string, v *Value) {
+				if v.Str() != values[key] {
+					t.Errorf("test %d failed\nexpected %s for %s; received %s", i+1, values[key], key, v.Str())
+				}
+				actual = append(actual, key)
+			})
+
+			sort.Strings(expected)
+			sort.Strings(actual)
+			if !reflect.DeepEqual(expected, actual) {
+				t.Fatalf("test %d prefix %q failed\nexpected %v\nreceived %v", i+1, prefix, expected, actual)
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package trie
+
+// hashLength is the length of the hash a node is referenced by
+const hashLength = 32
+
+// RefCounter is implemented by the databases that count references to trie nodes,
+// so the nodes no referenced root reaches can be deleted.
+type RefCounter interface {
+	// Reference keeps the root, and the nodes it reaches, in the db
+	Reference(root []byte) error
+	// Dereference releases the root; nodes no longer referenced are deleted
+	Dereference(root []byte) error
+	// Release unpins the nodes written for the root, once it's referenced or no longer needed
+	Release(root []byte) error
+	// Prune deletes the unpinned nodes no referenced root reaches and returns how many were deleted
+	Prune() (int, error)
+}
+
+// NodeChildren returns the hashes of the nodes referenced by the encoded node.
+// note: inline nodes are walked, since their children are referenced by the node that holds them.
+func NodeChildren(data []byte) [][]byte {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var children [][]byte
+	collectChildren(NewValueFromBytes(data), &children)
+
+	return children
+}
+
+func collectChildren(node *Value, children *[][]byte) {
+	switch node.Size() {
+	case 2:
+		if node.Get(0).Str() == "" {
+			return
+		}
+
+		// note: the second item of a leaf is its value
+		k := CompactDecode(node.Get(0).Str())
+		if len(k) > 0 && k[len(k)-1] == 16 {
+			return
+		}
+
+		collectRef(node.Get(1), children)
+	case maxSize:
+		// note: the last item is the value of the key ending at the node
+		for i := 0; i < maxSize-1; i++ {
+			collectRef(node.Get(i), children)
+		}
+	}
+}
+
+func collectRef(ref *Value, children *[][]byte) {
+	if ref.IsList() {
+		collectChildren(ref, children)
+		return
+	}
+
+	if b := ref.Bytes(); len(b) == hashLength {
+		*children = append(*children, CopyBytes(b))
+	}
+}
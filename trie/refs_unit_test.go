@@ -0,0 +1,49 @@
+// +build unit
+
+package trie
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestNodeChildren(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	for i := 0; i < 20; i++ {
+		db, trie, values := randomTrie(r, 1+r.Intn(60))
+		root := NewValue(trie.Root).Bytes()
+
+		// note: every node reachable from the root is stored
+		reachable := make(map[string]bool)
+		queue := [][]byte{root}
+		for len(queue) > 0 {
+			key := queue[0]
+			queue = queue[1:]
+			if reachable[string(key)] {
+				continue
+			}
+
+			data, _ := db.Get(key)
+			if len(data) == 0 {
+				t.Fatalf("test %d failed\nnode %x is not stored", i+1, key)
+			}
+
+			reachable[string(key)] = true
+			queue = append(queue, NodeChildren(data)...)
+		}
+
+		// note: every node of a proof is reachable from the root
+		for key := range values {
+			for _, node := range trie.Prove(key) {
+				hash := trie.cache.hashBytes(node)
+				if !reachable[string(hash)] {
+					t.Fatalf("test %d failed\nproof node %x of %s is not reachable", i+1, hash, key)
+				}
+			}
+		}
+	}
+
+	if children := NodeChildren(nil); children != nil {
+		t.Errorf("expected no children; received %v", children)
+	}
+}
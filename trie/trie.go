@@ -257,8 +257,11 @@ func (t *Trie) deleteState(node interface{}, key []int) interface{} {
 
 		if CompareIntSlice(k, key) {
 			return ""
-		} else if CompareIntSlice(key[:len(k)], k) {
+		} else if len(key) >= len(k) && CompareIntSlice(key[:len(k)], k) {
 			hash := t.deleteState(v, key[len(k):])
+			if isEmptyNode(hash) {
+				return ""
+			}
 			child := t.getNode(hash)
 
 			var newNode []interface{}
@@ -288,7 +291,7 @@ func (t *Trie) deleteState(node interface{}, key []int) interface{} {
 	n[key[0]] = t.deleteState(n[key[0]], key[1:])
 	amount := -1
 	for i := 0; i < maxSize; i++ {
-		if n[i] != "" {
+		if !isEmptyNode(n[i]) {
 			if amount == -1 {
 				amount = i
 			} else {
@@ -296,7 +299,10 @@ func (t *Trie) deleteState(node interface{}, key []int) interface{} {
 			}
 		}
 	}
-	if amount == 16 {
+	if amount == -1 {
+		// note: the last key under the node was deleted
+		return ""
+	} else if amount == 16 {
 		newNode = []interface{}{CompactEncode([]int{16}), n[amount]}
 	} else if amount >= 0 {
 		child := t.getNode(n[amount])
@@ -312,3 +318,18 @@ func (t *Trie) deleteState(node interface{}, key []int) interface{} {
 
 	return t.Put(newNode)
 }
+
+// isEmptyNode reports whether the node is an empty slot.
+// note: slots read back from the db decode as empty byte slices rather than empty strings.
+func isEmptyNode(node interface{}) bool {
+	switch n := node.(type) {
+	case nil:
+		return true
+	case string:
+		return n == ""
+	case []byte:
+		return len(n) == 0
+	}
+
+	return false
+}